@@ -0,0 +1,144 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ParseAnnotations parses check run annotations from either a list of annotations (in the format
+// used by the Checks API), a SARIF log or the JSON output of golangci-lint. The format is detected
+// from the content.
+func ParseAnnotations(content []byte) ([]CheckRunAnnotation, error) {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 {
+		return nil, nil
+	}
+	if content[0] == '[' {
+		var annotations []CheckRunAnnotation
+		if err := json.Unmarshal(content, &annotations); err != nil {
+			return nil, err
+		}
+		return annotations, nil
+	}
+
+	var report struct {
+		Runs   []sarifRun      `json:"runs"`
+		Issues []golangciIssue `json:"Issues"`
+	}
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case report.Runs != nil:
+		return sarifAnnotations(report.Runs), nil
+	case report.Issues != nil:
+		return golangciAnnotations(report.Issues), nil
+	}
+	return nil, errors.New("unknown annotations format")
+}
+
+// sarifRun is the subset of a SARIF run that we translate into annotations.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifRun struct {
+	Results []struct {
+		RuleID  string `json:"ruleId"`
+		Level   string `json:"level"`
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+		Locations []struct {
+			PhysicalLocation struct {
+				ArtifactLocation struct {
+					URI string `json:"uri"`
+				} `json:"artifactLocation"`
+				Region struct {
+					StartLine   int `json:"startLine"`
+					EndLine     int `json:"endLine"`
+					StartColumn int `json:"startColumn"`
+					EndColumn   int `json:"endColumn"`
+				} `json:"region"`
+			} `json:"physicalLocation"`
+		} `json:"locations"`
+	} `json:"results"`
+}
+
+func sarifAnnotations(runs []sarifRun) []CheckRunAnnotation {
+	var annotations []CheckRunAnnotation
+	for _, run := range runs {
+		for _, r := range run.Results {
+			if len(r.Locations) == 0 {
+				continue
+			}
+			l := r.Locations[0].PhysicalLocation
+			a := CheckRunAnnotation{
+				Path:            strings.TrimPrefix(strings.TrimPrefix(l.ArtifactLocation.URI, "file://"), "./"),
+				StartLine:       l.Region.StartLine,
+				EndLine:         l.Region.EndLine,
+				AnnotationLevel: annotationLevel(r.Level),
+				Message:         r.Message.Text,
+				Title:           r.RuleID,
+			}
+			if a.EndLine < a.StartLine {
+				a.EndLine = a.StartLine
+			}
+			// Columns are only supported by Github for annotations on a single line,
+			// and the end column is exclusive in SARIF.
+			if a.StartLine == a.EndLine && l.Region.StartColumn > 0 {
+				a.StartColumn = l.Region.StartColumn
+				a.EndColumn = a.StartColumn
+				if l.Region.EndColumn > a.StartColumn {
+					a.EndColumn = l.Region.EndColumn - 1
+				}
+			}
+			annotations = append(annotations, a)
+		}
+	}
+	return annotations
+}
+
+// golangciIssue is the subset of an issue in the JSON output of golangci-lint that we translate into annotations.
+type golangciIssue struct {
+	FromLinter string `json:"FromLinter"`
+	Text       string `json:"Text"`
+	Severity   string `json:"Severity"`
+	Pos        struct {
+		Filename string `json:"Filename"`
+		Line     int    `json:"Line"`
+		Column   int    `json:"Column"`
+	} `json:"Pos"`
+}
+
+func golangciAnnotations(issues []golangciIssue) []CheckRunAnnotation {
+	var annotations []CheckRunAnnotation
+	for _, i := range issues {
+		a := CheckRunAnnotation{
+			Path:            i.Pos.Filename,
+			StartLine:       i.Pos.Line,
+			EndLine:         i.Pos.Line,
+			AnnotationLevel: annotationLevel(i.Severity),
+			Message:         i.Text,
+			Title:           i.FromLinter,
+		}
+		if i.Pos.Column > 0 {
+			a.StartColumn = i.Pos.Column
+			a.EndColumn = i.Pos.Column
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations
+}
+
+// annotationLevel translates the severity of a finding into an annotation level (notice, warning or failure).
+func annotationLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "error", "failure":
+		return "failure"
+	case "note", "none", "info", "notice":
+		return "notice"
+	default:
+		return "warning"
+	}
+}
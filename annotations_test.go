@@ -0,0 +1,86 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	tests := []struct {
+		description string
+		content     string
+		want        []resource.CheckRunAnnotation
+	}{
+		{
+			description: "parses annotations in the format used by the Checks API",
+			content:     `[{"path": "main.go", "start_line": 1, "end_line": 2, "annotation_level": "warning", "message": "unused"}]`,
+			want: []resource.CheckRunAnnotation{
+				{Path: "main.go", StartLine: 1, EndLine: 2, AnnotationLevel: "warning", Message: "unused"},
+			},
+		},
+		{
+			description: "parses SARIF logs",
+			content: `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec"}},
+    "results": [
+      {
+        "ruleId": "G104",
+        "level": "error",
+        "message": {"text": "Errors unhandled."},
+        "locations": [{"physicalLocation": {"artifactLocation": {"uri": "./cmd/main.go"}, "region": {"startLine": 12, "startColumn": 2, "endColumn": 10}}}]
+      },
+      {
+        "ruleId": "G101",
+        "message": {"text": "Potential hardcoded credentials"},
+        "locations": [{"physicalLocation": {"artifactLocation": {"uri": "config.go"}, "region": {"startLine": 3, "endLine": 5, "startColumn": 1}}}]
+      },
+      {
+        "ruleId": "G000",
+        "level": "note",
+        "message": {"text": "No location"}
+      }
+    ]
+  }]
+}`,
+			want: []resource.CheckRunAnnotation{
+				{Path: "cmd/main.go", StartLine: 12, EndLine: 12, StartColumn: 2, EndColumn: 9, AnnotationLevel: "failure", Message: "Errors unhandled.", Title: "G104"},
+				{Path: "config.go", StartLine: 3, EndLine: 5, AnnotationLevel: "warning", Message: "Potential hardcoded credentials", Title: "G101"},
+			},
+		},
+		{
+			description: "parses golangci-lint output",
+			content: `{
+  "Issues": [
+    {"FromLinter": "errcheck", "Text": "Error return value is not checked", "Severity": "", "Pos": {"Filename": "out.go", "Line": 42, "Column": 7}},
+    {"FromLinter": "godot", "Text": "Comment should end in a period", "Severity": "info", "Pos": {"Filename": "in.go", "Line": 1}}
+  ],
+  "Report": {}
+}`,
+			want: []resource.CheckRunAnnotation{
+				{Path: "out.go", StartLine: 42, EndLine: 42, StartColumn: 7, EndColumn: 7, AnnotationLevel: "warning", Message: "Error return value is not checked", Title: "errcheck"},
+				{Path: "in.go", StartLine: 1, EndLine: 1, AnnotationLevel: "notice", Message: "Comment should end in a period", Title: "godot"},
+			},
+		},
+		{
+			description: "golangci-lint output without issues",
+			content:     `{"Issues": [], "Report": {}}`,
+			want:        nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := resource.ParseAnnotations([]byte(tc.content))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := resource.ParseAnnotations([]byte(`{"foo": "bar"}`))
+	assert.EqualError(t, err, "unknown annotations format")
+}
@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// APIError is an error returned by the Github API, with the details needed to act on it.
+type APIError struct {
+	// StatusCode of the response, which is 200 for GraphQL errors.
+	StatusCode       int
+	Message          string
+	Details          []string
+	DocumentationURL string
+	RequestID        string
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	var parts []string
+	if e.StatusCode != http.StatusOK {
+		parts = append(parts, fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)))
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	if len(e.Details) > 0 {
+		parts = append(parts, strings.Join(e.Details, "; "))
+	}
+	msg := strings.Join(parts, ": ")
+
+	var context []string
+	if e.RequestID != "" {
+		context = append(context, "request ID: "+e.RequestID)
+	}
+	if e.DocumentationURL != "" {
+		context = append(context, "see "+e.DocumentationURL)
+	}
+	if len(context) > 0 {
+		msg += " (" + strings.Join(context, ", ") + ")"
+	}
+	return msg
+}
+
+// apiErrorTransport turns error responses from the Github API (and GraphQL responses with errors) into an
+// APIError, instead of only the status. Not found responses to the V3 API are left as is, since they are
+// expected in some cases (e.g. when deleting comments which have already been deleted).
+type apiErrorTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *apiErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	graphql := rateLimitResource(req) == "graphql"
+	if resp.StatusCode < 400 && !graphql || resp.StatusCode == http.StatusNotFound && !graphql {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	apiErr := parseAPIError(resp, body)
+	if resp.StatusCode < 400 && len(apiErr.Details) == 0 {
+		return resp, nil
+	}
+	return nil, apiErr
+}
+
+// parseAPIError parses the body of an error response from the V3 API, or of a GraphQL response.
+func parseAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-GitHub-Request-Id"),
+	}
+
+	var response struct {
+		Message          string            `json:"message"`
+		DocumentationURL string            `json:"documentation_url"`
+		Errors           []json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		if resp.StatusCode >= 400 {
+			apiErr.Message = strings.TrimSpace(string(body))
+		}
+		return apiErr
+	}
+	apiErr.Message = response.Message
+	apiErr.DocumentationURL = response.DocumentationURL
+
+	for _, raw := range response.Errors {
+		var detail string
+		if err := json.Unmarshal(raw, &detail); err == nil {
+			apiErr.Details = append(apiErr.Details, detail)
+			continue
+		}
+		var e struct {
+			// V3 API
+			Resource string `json:"resource"`
+			Field    string `json:"field"`
+			Code     string `json:"code"`
+			// GraphQL
+			Type string        `json:"type"`
+			Path []interface{} `json:"path"`
+
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(raw, &e); err != nil {
+			continue
+		}
+
+		var parts []string
+		if e.Type != "" {
+			parts = append(parts, e.Type)
+		}
+		if e.Field != "" {
+			parts = append(parts, strings.TrimPrefix(e.Resource+"."+e.Field, "."))
+		}
+		if e.Code != "" && e.Code != "custom" {
+			parts = append(parts, e.Code)
+		}
+		if e.Message != "" {
+			parts = append(parts, e.Message)
+		}
+		detail = strings.Join(parts, ": ")
+		if len(e.Path) > 0 {
+			path := make([]string, len(e.Path))
+			for i, p := range e.Path {
+				path[i] = fmt.Sprint(p)
+			}
+			detail += " (path: " + strings.Join(path, ".") + ")"
+		}
+		apiErr.Details = append(apiErr.Details, detail)
+	}
+	return apiErr
+}
@@ -0,0 +1,75 @@
+package resource_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestAPIErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		status      int
+		body        string
+		call        func(*resource.GithubClient) error
+		expected    string
+	}{
+		{
+			description: "graphql errors include the type, path and request ID",
+			status:      http.StatusOK,
+			body:        `{"data":null,"errors":[{"type":"NOT_FOUND","path":["repository","pullRequest"],"message":"Could not resolve to a PullRequest with the number of 7."}]}`,
+			call: func(c *resource.GithubClient) error {
+				_, err := c.GetPullRequest("7", "sha")
+				return err
+			},
+			expected: "NOT_FOUND: Could not resolve to a PullRequest with the number of 7. (path: repository.pullRequest) (request ID: ABCD:1234)",
+		},
+		{
+			description: "v3 errors include the message, details and documentation url",
+			status:      http.StatusUnprocessableEntity,
+			body:        `{"message":"Validation Failed","errors":[{"resource":"Status","field":"context","code":"custom","message":"context is too long"}],"documentation_url":"https://docs.github.com/rest/commits/statuses"}`,
+			call: func(c *resource.GithubClient) error {
+				return c.UpdateCommitStatus("sha", "", "concourse-ci/status", "success", "", "")
+			},
+			expected: "422 Unprocessable Entity: Validation Failed: Status.context: context is too long (request ID: ABCD:1234, see https://docs.github.com/rest/commits/statuses)",
+		},
+		{
+			description: "graphql error responses include the message",
+			status:      http.StatusUnauthorized,
+			body:        `{"message":"Bad credentials","documentation_url":"https://docs.github.com/graphql"}`,
+			call: func(c *resource.GithubClient) error {
+				_, err := c.GetPullRequest("7", "sha")
+				return err
+			},
+			expected: "401 Unauthorized: Bad credentials (request ID: ABCD:1234, see https://docs.github.com/graphql)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-GitHub-Request-Id", "ABCD:1234")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+			})
+			require.NoError(t, err)
+
+			err = tc.call(client)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
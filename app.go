@@ -0,0 +1,157 @@
+package resource
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// appTokenSource mints installation access tokens for a Github App.
+// https://docs.github.com/en/developers/apps/building-github-apps/authenticating-with-github-apps
+type appTokenSource struct {
+	appID          int64
+	key            *rsa.PrivateKey
+	installationID int64
+	owner          string
+	repository     string
+	endpoint       string
+	client         *http.Client
+
+	// permissions of the installation, as of the last token that was created
+	permissions map[string]string
+}
+
+// newAppTokenSource returns a token source for the installation of the Github App on the repository.
+// It creates a new installation token on every call, and should be wrapped in oauth2.ReuseTokenSource.
+func newAppTokenSource(s *Source, owner, repository string, client *http.Client) (*appTokenSource, error) {
+	key, err := parsePrivateKey(s.AppPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse app private key: %s", err)
+	}
+
+	endpoint, _ := s.Endpoints()
+	if endpoint == "" {
+		endpoint = "https://api.github.com/"
+	}
+
+	return &appTokenSource{
+		appID:          s.AppID,
+		key:            key,
+		installationID: s.AppInstallationID,
+		owner:          owner,
+		repository:     repository,
+		endpoint:       endpoint,
+		client:         client,
+	}, nil
+}
+
+// Token implements oauth2.TokenSource.
+func (a *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := appJWT(a.appID, a.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Look up the installation for the repository, unless it has been configured
+	if a.installationID == 0 {
+		var installation struct {
+			ID int64 `json:"id"`
+		}
+		if err := a.do("GET", fmt.Sprintf("repos/%s/%s/installation", a.owner, a.repository), jwt, &installation); err != nil {
+			return nil, fmt.Errorf("failed to find app installation: %s", err)
+		}
+		a.installationID = installation.ID
+	}
+
+	var token struct {
+		Token       string            `json:"token"`
+		ExpiresAt   time.Time         `json:"expires_at"`
+		Permissions map[string]string `json:"permissions"`
+	}
+	if err := a.do("POST", fmt.Sprintf("app/installations/%d/access_tokens", a.installationID), jwt, &token); err != nil {
+		return nil, fmt.Errorf("failed to create installation token: %s", err)
+	}
+	a.permissions = token.Permissions
+	addSecret(token.Token)
+	return &oauth2.Token{
+		AccessToken: token.Token,
+		Expiry:      token.ExpiresAt,
+	}, nil
+}
+
+func (a *appTokenSource) do(method, path, jwt string, v interface{}) error {
+	req, err := http.NewRequest(method, a.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.machine-man-preview+json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// appJWT creates the JSON Web Token used to authenticate as a Github App, which is valid for 10 minutes
+// (allowing for some clock drift).
+func appJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses a PEM encoded RSA private key (in PKCS#1 or PKCS#8 format).
+func parsePrivateKey(s string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,99 @@
+package resource_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientAppAuthentication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	tests := []struct {
+		description          string
+		installationID       int64
+		expiresIn            time.Duration
+		expectedLookups      int
+		expectedTokenCreates int
+	}{
+		{
+			description:          "installation is looked up from the repository",
+			expiresIn:            time.Hour,
+			expectedLookups:      1,
+			expectedTokenCreates: 1,
+		},
+		{
+			description:          "configured installation is used",
+			installationID:       67890,
+			expiresIn:            time.Hour,
+			expectedTokenCreates: 1,
+		},
+		{
+			description:          "expired tokens are renewed",
+			installationID:       67890,
+			expiresIn:            time.Second,
+			expectedTokenCreates: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var lookups, tokenCreates int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/repos/itsdalmo/test-repository/installation":
+					assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+					lookups++
+					w.Write([]byte(`{"id":67890}`))
+				case r.URL.Path == "/app/installations/67890/access_tokens":
+					assert.Equal(t, http.MethodPost, r.Method)
+					assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+					tokenCreates++
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"token":"installationtoken","expires_at":"` + time.Now().Add(tc.expiresIn).Format(time.RFC3339) + `"}`))
+				case r.URL.Path == "/repos/itsdalmo/test-repository/issues/1/comments":
+					assert.Equal(t, "Bearer installationtoken", r.Header.Get("Authorization"))
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{}`))
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:        "itsdalmo/test-repository",
+				AppID:             12345,
+				AppPrivateKey:     privateKey,
+				AppInstallationID: tc.installationID,
+				V3Endpoint:        server.URL + "/",
+				V4Endpoint:        server.URL + "/graphql",
+			})
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				_, err = client.PostComment("1", "comment")
+				require.NoError(t, err)
+			}
+			token, err := client.Token()
+			require.NoError(t, err)
+			assert.Equal(t, "installationtoken", token)
+
+			assert.Equal(t, tc.expectedLookups, lookups)
+			assert.Equal(t, tc.expectedTokenCreates, tokenCreates)
+		})
+	}
+}
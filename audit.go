@@ -0,0 +1,205 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry is a request to the Github API which changed something (e.g. set a status, posted a comment
+// or merged a pull request), as recorded by put when audit_file or audit_log is set.
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+	// Endpoint is the method and URL of the request, e.g. "POST https://api.github.com/repos/owner/repo/statuses/<sha>".
+	Endpoint string `json:"endpoint"`
+	// Action is e.g. "create_status" for the V3 API, or the name of the mutation (e.g. "addComment") for GraphQL.
+	Action string `json:"action"`
+	// Target is the path of the resource within the repository (e.g. "issues/1/labels") for the V3 API,
+	// or the node ID from the input of the mutation for GraphQL.
+	Target     string `json:"target"`
+	StatusCode int    `json:"status_code"`
+	RequestID  string `json:"request_id,omitempty"`
+	// ResponseID is the ID of the object returned by the V3 API (e.g. the comment which was created).
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+// auditLog records the changes made through the Github API. A nil *auditLog records nothing.
+type auditLog struct {
+	// output receives each entry as a line of JSON, if it is set.
+	output io.Writer
+
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// audit is the audit log of put (if enabled), and is recorded by the auditTransport of the client.
+var audit *auditLog
+
+// record adds an entry to the audit log.
+func (a *auditLog) record(e AuditEntry) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, e)
+	if a.output != nil {
+		if b, err := json.Marshal(e); err == nil {
+			fmt.Fprintf(a.output, "%s\n", b)
+		}
+	}
+}
+
+// write the entries to a file as a JSON array.
+func (a *auditLog) write(path string) error {
+	a.mu.Lock()
+	entries := append([]AuditEntry{}, a.entries...)
+	a.mu.Unlock()
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// auditActions name the changes made through the V3 API, by method and path within the repository.
+// Requests which do not match any of them are named after the method and path.
+var auditActions = []struct {
+	method string
+	path   *regexp.Regexp
+	action string
+}{
+	{"POST", regexp.MustCompile(`^statuses/`), "create_status"},
+	{"POST", regexp.MustCompile(`^check-runs$`), "create_check_run"},
+	{"PATCH", regexp.MustCompile(`^check-runs/\d+$`), "update_check_run"},
+	{"POST", regexp.MustCompile(`^issues/\d+/comments$`), "create_comment"},
+	{"PATCH", regexp.MustCompile(`^issues/comments/\d+$`), "update_comment"},
+	{"DELETE", regexp.MustCompile(`^issues/comments/\d+$`), "delete_comment"},
+	{"POST", regexp.MustCompile(`^commits/[^/]+/comments$`), "create_commit_comment"},
+	{"POST", regexp.MustCompile(`^issues/\d+/labels$`), "add_labels"},
+	{"DELETE", regexp.MustCompile(`^issues/\d+/labels/`), "remove_label"},
+	{"POST", regexp.MustCompile(`^issues/\d+/assignees$`), "add_assignees"},
+	{"POST", regexp.MustCompile(`^pulls/\d+/requested_reviewers$`), "request_reviewers"},
+	{"POST", regexp.MustCompile(`^pulls/\d+/reviews$`), "create_review"},
+	{"PUT", regexp.MustCompile(`^pulls/\d+/reviews/\d+/dismissals$`), "dismiss_review"},
+	{"PUT", regexp.MustCompile(`^pulls/\d+/merge$`), "merge"},
+	{"PATCH", regexp.MustCompile(`^(pulls|issues)/\d+$`), "update_pull_request"},
+	{"PUT", regexp.MustCompile(`^issues/\d+/lock$`), "lock"},
+	{"DELETE", regexp.MustCompile(`^issues/\d+/lock$`), "unlock"},
+	{"POST", regexp.MustCompile(`^git/refs$`), "create_ref"},
+	{"DELETE", regexp.MustCompile(`^git/refs/`), "delete_ref"},
+	{"POST", regexp.MustCompile(`^releases$`), "create_release"},
+	{"POST", regexp.MustCompile(`^deployments$`), "create_deployment"},
+	{"POST", regexp.MustCompile(`^deployments/\d+/statuses$`), "create_deployment_status"},
+	{"POST", regexp.MustCompile(`^dispatches$`), "dispatch"},
+}
+
+// repositoryPath matches the path of requests for a repository in the V3 API (which is prefixed by /api/v3
+// for Github Enterprise Server).
+var repositoryPath = regexp.MustCompile(`/repos/[^/]+/[^/]+/(.+)$`)
+
+// auditTransport records the requests which change something in the audit log.
+type auditTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if audit == nil || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	entry := AuditEntry{
+		Time:     time.Now().UTC(),
+		Endpoint: req.Method + " " + sanitizeURL(req.URL),
+	}
+	if rateLimitResource(req) == "graphql" {
+		operation := graphqlOperation(req)
+		if !strings.HasPrefix(operation, "mutation ") {
+			return t.base.RoundTrip(req)
+		}
+		entry.Action = strings.TrimPrefix(operation, "mutation ")
+		entry.Target = graphqlMutationTarget(req)
+	} else {
+		target := req.URL.Path
+		if m := repositoryPath.FindStringSubmatch(req.URL.Path); m != nil {
+			target = m[1]
+		}
+		entry.Target = target
+		entry.Action = strings.ToLower(req.Method) + " " + target
+		for _, a := range auditActions {
+			if a.method == req.Method && a.path.MatchString(target) {
+				entry.Action = a.action
+				break
+			}
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		audit.record(entry)
+		return nil, err
+	}
+	entry.StatusCode = resp.StatusCode
+	entry.RequestID = resp.Header.Get("X-GitHub-Request-Id")
+
+	// The ID of the object in the response (e.g. the comment which was created)
+	if rateLimitResource(req) != "graphql" {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		var object struct {
+			ID interface{} `json:"id"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if decoder.Decode(&object) == nil && object.ID != nil {
+			entry.ResponseID = fmt.Sprint(object.ID)
+		}
+	}
+	audit.record(entry)
+	return resp, nil
+}
+
+// graphqlMutationTarget returns the ID of the node which is changed by a GraphQL mutation, from the
+// fields of its input (e.g. pullRequestId or subjectId).
+func graphqlMutationTarget(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	var graphql struct {
+		Variables struct {
+			Input map[string]interface{} `json:"input"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(body).Decode(&graphql); err != nil {
+		return ""
+	}
+	for _, field := range []string{"pullRequestId", "subjectId", "labelableId", "assignableId", "lockableId", "issueId", "itemId", "id"} {
+		if id, ok := graphql.Variables.Input[field].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
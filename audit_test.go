@@ -0,0 +1,83 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestPutAuditFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-GitHub-Request-Id", "ABCD:"+r.Method)
+		switch r.URL.Path {
+		case "/repos/itsdalmo/test-repository":
+			w.Header().Set("X-OAuth-Scopes", "repo")
+			w.Write([]byte(`{"private":false}`))
+		case "/repos/itsdalmo/test-repository/statuses/commit1":
+			w.Write([]byte(`{"id":123456789012}`))
+		case "/repos/itsdalmo/test-repository/issues/1/comments":
+			w.Write([]byte(`{"id":42,"html_url":"https://github.com/itsdalmo/test-repository/pull/1#issuecomment-42"}`))
+		case "/repos/itsdalmo/test-repository/issues/1/labels":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Validation Failed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".git", "resource")
+	require.NoError(t, os.MkdirAll(path, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "version.json"), []byte(`{"pr":"1","commit":"commit1"}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "metadata.json"), []byte(`[]`), 0644))
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+		MaxRetries:  intPtr(0),
+	}
+	require.NoError(t, source.Validate())
+	client, err := resource.NewGithubClient(&source)
+	require.NoError(t, err)
+
+	_, err = resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+		Status:    "success",
+		Comment:   "hello",
+		AddLabels: []string{"approved"},
+		AuditFile: "audit/audit.json",
+	}}, client, dir)
+	require.Error(t, err)
+
+	// The audit file is written even though put failed
+	var entries []resource.AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(readTestFile(t, filepath.Join(dir, "audit", "audit.json"))), &entries))
+	require.Len(t, entries, 3)
+
+	type entry struct {
+		Endpoint, Action, Target, RequestID, ResponseID string
+		StatusCode                                      int
+	}
+	var actual []entry
+	for _, e := range entries {
+		assert.False(t, e.Time.IsZero())
+		actual = append(actual, entry{e.Endpoint, e.Action, e.Target, e.RequestID, e.ResponseID, e.StatusCode})
+	}
+	assert.Equal(t, []entry{
+		{"POST " + server.URL + "/repos/itsdalmo/test-repository/statuses/commit1", "create_status", "statuses/commit1", "ABCD:POST", "123456789012", 200},
+		{"POST " + server.URL + "/repos/itsdalmo/test-repository/issues/1/comments", "create_comment", "issues/1/comments", "ABCD:POST", "42", 200},
+		{"POST " + server.URL + "/repos/itsdalmo/test-repository/issues/1/labels", "add_labels", "issues/1/labels", "ABCD:POST", "", 422},
+	}, actual)
+}
@@ -0,0 +1,61 @@
+package resource
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// BuildVersion and BuildCommit identify the build of the resource, and are set when building the binaries, e.g.:
+// -ldflags "-X github.com/telia-oss/github-pr-resource.BuildVersion=v0.23.0 -X github.com/telia-oss/github-pr-resource.BuildCommit=abc1234"
+var (
+	BuildVersion = "dev"
+	BuildCommit  = "unknown"
+)
+
+// BuildInfo describes the build of the resource.
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	GoVersion string
+
+	// Features lists the source configuration options supported by the build.
+	Features []string
+}
+
+// GetBuildInfo returns the build info of the running binary.
+func GetBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:   BuildVersion,
+		Commit:    BuildCommit,
+		GoVersion: runtime.Version(),
+		Features:  sourceOptions(),
+	}
+}
+
+// String returns the build info as printed by --version.
+func (b BuildInfo) String() string {
+	return fmt.Sprintf("github-pr-resource %s (commit %s, %s)\nfeatures: %s",
+		b.Version, b.Commit, b.GoVersion, strings.Join(b.Features, ", "))
+}
+
+// Log writes the build info to the log (at info level).
+func (b BuildInfo) Log() {
+	logger.Info("github-pr-resource", "version", b.Version, "commit", b.Commit, "go", b.GoVersion,
+		"features", strings.Join(b.Features, ","))
+}
+
+// sourceOptions returns the names of the options in the source configuration.
+func sourceOptions() []string {
+	t := reflect.TypeOf(Source{})
+	options := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		options = append(options, name)
+	}
+	return options
+}
@@ -0,0 +1,32 @@
+package resource_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestBuildInfo(t *testing.T) {
+	info := resource.GetBuildInfo()
+	assert.Equal(t, "dev", info.Version)
+	assert.Equal(t, "unknown", info.Commit)
+	assert.True(t, strings.HasPrefix(info.GoVersion, "go"))
+	assert.Contains(t, info.Features, "repository")
+	assert.Contains(t, info.Features, "max_versions")
+	assert.NotContains(t, info.Features, "")
+
+	assert.True(t, strings.HasPrefix(info.String(), "github-pr-resource dev (commit unknown, go"))
+	assert.Contains(t, info.String(), "features: repository, access_token,")
+
+	var buf bytes.Buffer
+	resource.SetLogger(resource.NewLogger(&buf, resource.LogLevelInfo))
+	defer resource.SetLogger(resource.NewLogger(os.Stderr, resource.LogLevelInfo))
+	info.Log()
+	require.Contains(t, buf.String(), `msg=github-pr-resource version=dev commit=unknown go=go`)
+	assert.Contains(t, buf.String(), "features=repository,access_token,")
+}
@@ -0,0 +1,169 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment variables which select a cassette for the Github API: requests and responses are either
+// recorded to the file, or responses are replayed from it (without making requests, or needing credentials).
+const (
+	cassetteEnv     = "GPR_CASSETTE"
+	cassetteModeEnv = "GPR_CASSETTE_MODE"
+)
+
+// cassette is the content of a cassette file.
+type cassette struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header"`
+		Body       string      `json:"body"`
+	} `json:"response"`
+
+	replayed bool
+}
+
+// cassetteTransport records or replays requests to the Github API.
+type cassetteTransport struct {
+	base   http.RoundTripper
+	path   string
+	record bool
+
+	mu       sync.Mutex
+	cassette cassette
+}
+
+// newCassetteTransport returns a transport for the cassette in GPR_CASSETTE (if it is set), which replays
+// responses unless GPR_CASSETTE_MODE is "record". Otherwise, base is returned.
+func newCassetteTransport(base http.RoundTripper) (http.RoundTripper, error) {
+	path := os.Getenv(cassetteEnv)
+	if path == "" {
+		return base, nil
+	}
+	t := &cassetteTransport{base: base, path: path}
+	switch mode := os.Getenv(cassetteModeEnv); mode {
+	case "record":
+		t.record = true
+	case "", "replay":
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %s", err)
+		}
+		if err := json.Unmarshal(b, &t.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %s", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown %s: %s (must be record or replay)", cassetteModeEnv, mode)
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	if t.record {
+		return t.recordRequest(req, body)
+	}
+	return t.replay(req, body)
+}
+
+func (t *cassetteTransport) recordRequest(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	// Credentials are redacted from the responses (e.g. Github App installation tokens)
+	i := &interaction{}
+	i.Request.Method = req.Method
+	i.Request.URL = req.URL.String()
+	i.Request.Body = string(body)
+	i.Response.StatusCode = resp.StatusCode
+	i.Response.Header = resp.Header
+	i.Response.Body = Redact(string(b))
+
+	// The cassette is written after every request, since the commands exit on errors
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, i)
+	out, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(t.path, out, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write cassette: %s", err)
+	}
+	return resp, nil
+}
+
+// notRecordedError is returned when replaying a request which is not in the cassette (and is not retried).
+type notRecordedError struct {
+	path, method, url, body string
+}
+
+func (e *notRecordedError) Error() string {
+	return fmt.Sprintf("no response recorded in %s for %s %s %s", e.path, e.method, e.url, e.body)
+}
+
+// replay returns the first recorded response (which has not been replayed) for a request with the same
+// method, URL and body. When all of them have been replayed, the last one is repeated.
+func (t *cassetteTransport) replay(req *http.Request, body []byte) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var match *interaction
+	for _, i := range t.cassette.Interactions {
+		if i.Request.Method != req.Method || i.Request.URL != req.URL.String() || i.Request.Body != string(body) {
+			continue
+		}
+		match = i
+		if !i.replayed {
+			break
+		}
+	}
+	if match == nil {
+		return nil, &notRecordedError{path: t.path, method: req.Method, url: req.URL.String(), body: strings.TrimSpace(string(body))}
+	}
+	match.replayed = true
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		StatusCode:    match.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        match.Response.Header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(match.Response.Body)),
+		ContentLength: int64(len(match.Response.Body)),
+		Request:       req,
+	}, nil
+}
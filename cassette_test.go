@@ -0,0 +1,81 @@
+package resource_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientCassette(t *testing.T) {
+	dir, err := ioutil.TempDir("", "github-pr-resource")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.Write([]byte(`{"data":{"repository":{"pullRequests":{"edges":[{"node":{"number":1,"commits":{"edges":[{"node":{"commit":{"oid":"sha1"}}}]}}}],"pageInfo":{"hasNextPage":false}}}}}`))
+			return
+		}
+		w.Write([]byte(`[{"filename":"README.md"}]`))
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	run := func() ([]*resource.PullRequest, []string, error) {
+		client, err := resource.NewGithubClient(&source)
+		if err != nil {
+			return nil, nil, err
+		}
+		pulls, err := client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{}, time.Time{})
+		if err != nil {
+			return nil, nil, err
+		}
+		files, err := client.ListModifiedFiles(1)
+		return pulls, files, err
+	}
+
+	os.Setenv("GPR_CASSETTE", path)
+	defer os.Unsetenv("GPR_CASSETTE")
+	os.Setenv("GPR_CASSETTE_MODE", "record")
+	defer os.Unsetenv("GPR_CASSETTE_MODE")
+	recordedPulls, recordedFiles, err := run()
+	require.NoError(t, err)
+	server.Close()
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "oauthtoken")
+
+	// Responses are replayed without the server
+	os.Setenv("GPR_CASSETTE_MODE", "replay")
+	pulls, files, err := run()
+	require.NoError(t, err)
+	assert.Equal(t, recordedPulls, pulls)
+	assert.Equal(t, recordedFiles, files)
+	assert.Equal(t, []string{"README.md"}, files)
+
+	// Requests which were not recorded fail
+	source.Repository = "itsdalmo/other-repository"
+	_, _, err = run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no response recorded in "+path+" for POST "+server.URL+"/graphql")
+
+	os.Setenv("GPR_CASSETTE_MODE", "rewind")
+	_, err = resource.NewGithubClient(&source)
+	assert.EqualError(t, err, "unknown GPR_CASSETTE_MODE: rewind (must be record or replay)")
+}
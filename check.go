@@ -1,15 +1,24 @@
 package resource
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 )
 
+// checkConcurrency is the number of pull requests for which the modified files are listed concurrently.
+const checkConcurrency = 10
+
 // Check (business logic)
 func Check(request CheckRequest, manager Github) (CheckResponse, error) {
 	var response CheckResponse
@@ -20,32 +29,68 @@ func Check(request CheckRequest, manager Github) (CheckResponse, error) {
 		filterStates = request.Source.States
 	}
 
-	pulls, err := manager.ListPullRequests(filterStates)
+	// Pull requests which have not been updated since the last version are skipped. Without a version, those
+	// before the cursor are skipped instead, but not the cursor itself, which is only returned (as the latest
+	// version) if it still matches the filters.
+	last := request.Version.date()
+	if request.Version.PR == "" && request.Cursor != nil {
+		last = request.Cursor.date().Add(-time.Nanosecond)
+	}
+
+	// Pull requests can become ready to merge without being updated (e.g. when a status is set),
+	// so all of them are listed when when_ready_to_merge is set.
+	since := last
+	if request.Source.WhenReadyToMerge {
+		since = time.Time{}
+	}
+
+	filterPaths := len(request.Source.Paths) > 0 || len(request.Source.IgnorePaths) > 0
+	pulls, err := manager.ListPullRequests(filterStates, PullRequestFields{
+		Labels:    request.Source.fetches("labels"),
+		Reviews:   request.Source.fetches("reviews"),
+		Files:     filterPaths,
+		Readiness: request.Source.WhenReadyToMerge,
+	}, since)
 	if err != nil {
+		// Explain the failure (instead of an opaque 404) if the repository is not accessible
+		if _, tokenErr := manager.GetTokenInfo(); tokenErr != nil {
+			return nil, fmt.Errorf("failed to verify access token: %s", tokenErr)
+		}
 		return nil, fmt.Errorf("failed to get last commits: %s", err)
 	}
 
 	disableSkipCI := request.Source.DisableCISkip
+	var candidates []*PullRequest
+
+	// Explain why pull requests are skipped at debug level
+	skip := func(p *PullRequest, reason string) {
+		logger.Debug("skipping pull request", "pr", p.Number, "commit", p.Tip.OID, "reason", reason)
+	}
+	logger.Debug("listed pull requests", "count", len(pulls), "since", last)
 
 Loop:
 	for _, p := range pulls {
 		// [ci skip]/[skip ci] in Pull request title
 		if !disableSkipCI && ContainsSkipCI(p.Title) {
+			skip(p, "title contains [ci skip]")
 			continue
 		}
 
 		// [ci skip]/[skip ci] in Commit message
 		if !disableSkipCI && ContainsSkipCI(p.Tip.Message) {
+			skip(p, "commit message contains [ci skip]")
 			continue
 		}
 
 		// Filter pull request if the BaseBranch does not match the one specified in source
 		if request.Source.BaseBranch != "" && p.PullRequestObject.BaseRefName != request.Source.BaseBranch {
+			skip(p, "base branch is "+p.BaseRefName)
 			continue
 		}
 
 		// Filter out commits that are too old.
-		if !p.UpdatedDate().Time.After(request.Version.CommittedDate) {
+		if !versionDate(p, request.Source).After(last) {
+			skip(p, "not updated since the last version")
 			continue
 		}
 
@@ -64,53 +109,106 @@ Loop:
 			}
 
 			if !labelFound {
+				skip(p, "none of the labels")
 				continue Loop
 			}
 		}
 
 		// Filter out forks.
 		if request.Source.DisableForks && p.IsCrossRepository {
+			skip(p, "fork")
 			continue
 		}
 
 		// Filter out drafts.
 		if request.Source.IgnoreDrafts && p.IsDraft {
+			skip(p, "draft")
 			continue
 		}
 
 		// Filter pull request if it does not have the required number of approved review(s).
 		if p.ApprovedReviewCount < request.Source.RequiredReviewApprovals {
+			skip(p, fmt.Sprintf("%d of %d required approvals", p.ApprovedReviewCount, request.Source.RequiredReviewApprovals))
 			continue
 		}
 
-		// Fetch files once if paths/ignore_paths are specified.
-		var files []string
+		// Filter pull request if it is not ready to merge.
+		if request.Source.WhenReadyToMerge {
+			if ready, reason := p.readyToMerge(request.Source.ReadyContexts); !ready {
+				skip(p, reason)
+				continue
+			}
+		}
 
-		if len(request.Source.Paths) > 0 || len(request.Source.IgnorePaths) > 0 {
-			files, err = manager.ListModifiedFiles(p.Number)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list modified files: %s", err)
+		candidates = append(candidates, p)
+	}
+
+	// Filter out pull requests which are too far behind their base if max_behind_by is specified.
+	if max := request.Source.MaxBehindBy; max != nil {
+		comparisons, err := compareCommits(manager, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare commits: %s", err)
+		}
+		var upToDate []*PullRequest
+		for i, p := range candidates {
+			if behind := comparisons[i].BehindBy; behind > *max {
+				skip(p, fmt.Sprintf("%d commits behind %s", behind, p.BaseRefName))
+				continue
 			}
+			upToDate = append(upToDate, p)
+		}
+		candidates = upToDate
+	}
+
+	// Filter out pull requests with commits which would be rejected by branch protection. These are logged
+	// at info level, since the pull requests cannot be merged without being fixed.
+	if request.Source.RequireSignedCommits || request.Source.RequireLinearHistory {
+		commits, err := listCommits(manager, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits: %s", err)
+		}
+		var conforming []*PullRequest
+		for i, p := range candidates {
+			if reason := nonConformingCommit(commits[i], request.Source); reason != "" {
+				logger.Info("skipping pull request", "pr", p.Number, "commit", p.Tip.OID, "reason", reason)
+				continue
+			}
+			conforming = append(conforming, p)
+		}
+		candidates = conforming
+	}
+
+	// Fetch the files of the remaining pull requests if paths/ignore_paths are specified, and
+	// they were not fetched along with the pull request.
+	var files [][]string
+	if filterPaths {
+		files, err = listModifiedFiles(manager, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list modified files: %s", err)
 		}
+	}
 
+PathLoop:
+	for i, p := range candidates {
 		// Skip version if no files match the specified paths.
 		if len(request.Source.Paths) > 0 {
 			var wanted []string
 			for _, pattern := range request.Source.Paths {
-				w, err := FilterPath(files, pattern)
+				w, err := FilterPath(files[i], pattern)
 				if err != nil {
 					return nil, fmt.Errorf("path match failed: %s", err)
 				}
 				wanted = append(wanted, w...)
 			}
 			if len(wanted) == 0 {
-				continue Loop
+				skip(p, "no files match paths")
+				continue PathLoop
 			}
 		}
 
 		// Skip version if all files are ignored.
 		if len(request.Source.IgnorePaths) > 0 {
-			wanted := files
+			wanted := files[i]
 			for _, pattern := range request.Source.IgnorePaths {
 				wanted, err = FilterIgnorePath(wanted, pattern)
 				if err != nil {
@@ -118,18 +216,35 @@ Loop:
 				}
 			}
 			if len(wanted) == 0 {
-				continue Loop
+				skip(p, "all files match ignore_paths")
+				continue PathLoop
 			}
 		}
-		response = append(response, NewVersion(p))
+		response = append(response, newVersion(p, request.Source))
 	}
 
 	// Sort the commits by date
 	sort.Sort(response)
 
-	// If there are no new but an old version = return the old
+	// Only keep the newest versions if there are more than the cap
+	if limit := request.Source.MaxVersions; limit > 0 && len(response) > limit {
+		response = append(CheckResponse(nil), response[len(response)-limit:]...)
+	}
+
+	// If there are no new but an old version = return the old (unless it has been pruned)
 	if len(response) == 0 && request.Version.PR != "" {
-		response = append(response, request.Version)
+		exists := true
+		if request.Source.PruneVersions {
+			exists, err = versionExists(manager, request.Version, filterStates)
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify version: %s", err)
+			}
+		}
+		if exists {
+			response = append(response, request.Version)
+		} else {
+			logger.Info("pruned version", "pr", request.Version.PR, "commit", request.Version.Commit)
+		}
 	}
 	// If there are new versions and no previous = return just the latest
 	if len(response) != 0 && request.Version.PR == "" {
@@ -138,6 +253,58 @@ Loop:
 	return response, nil
 }
 
+// newVersion returns the version of a pull request, with the dates given by order_by, include_updated_at
+// and when_ready_to_merge.
+func newVersion(p *PullRequest, s Source) Version {
+	version := NewVersion(p)
+	version.CommittedDate = commitDate(p, s).UTC()
+	if s.IncludeUpdatedAt {
+		updated := versionDate(p, s).UTC()
+		version.UpdatedDate = &updated
+	}
+	return version
+}
+
+// versionDate returns the date of the version for a pull request, which is the last time it was updated
+// if include_updated_at is set (e.g. when labels are changed), and otherwise the commit date.
+func versionDate(p *PullRequest, s Source) time.Time {
+	date := commitDate(p, s)
+	if s.IncludeUpdatedAt && p.UpdatedAt.After(date) {
+		date = p.UpdatedAt.Time
+	}
+	return date
+}
+
+// commitDate returns the date given by order_by, or the time the pull request became ready to merge
+// if it was later and when_ready_to_merge is set.
+func commitDate(p *PullRequest, s Source) time.Time {
+	date := p.orderDate(s.OrderBy).Time
+	if s.WhenReadyToMerge {
+		if ready := p.readyDate(s.ReadyContexts); ready.After(date) {
+			date = ready.Time
+		}
+	}
+	return date
+}
+
+// versionExists returns true if the commit of a version is still part of its pull request, and the pull
+// request still has one of the given states.
+func versionExists(manager Github, version Version, states []githubv4.PullRequestState) (bool, error) {
+	pull, err := manager.GetPullRequest(version.PR, version.Commit)
+	if err != nil {
+		if _, ok := err.(*CommitNotFoundError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, state := range states {
+		if pull.State == state {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ContainsSkipCI returns true if a string contains [ci skip] or [skip ci].
 func ContainsSkipCI(s string) bool {
 	re := regexp.MustCompile("(?i)\\[(ci skip|skip ci)\\]")
@@ -193,21 +360,160 @@ func IsInsidePath(parent, child string) bool {
 	return strings.HasPrefix(child, parentWithTrailingSlash)
 }
 
+// listModifiedFiles lists the modified files of each pull request (unless they are already known), with a
+// bounded number of concurrent requests.
+func listModifiedFiles(manager Github, pulls []*PullRequest) ([][]string, error) {
+	files := make([][]string, len(pulls))
+	errs := make([]error, len(pulls))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, checkConcurrency)
+	for i, p := range pulls {
+		if p.Files != nil {
+			files[i] = p.Files
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i, number int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			files[i], errs[i] = manager.ListModifiedFiles(number)
+		}(i, p.Number)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// compareCommits compares the tip of each pull request with its base, with a bounded number of concurrent requests.
+func compareCommits(manager Github, pulls []*PullRequest) ([]*CommitComparison, error) {
+	comparisons := make([]*CommitComparison, len(pulls))
+	errs := make([]error, len(pulls))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, checkConcurrency)
+	for i, p := range pulls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, base, head string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			comparisons[i], errs[i] = manager.CompareCommits(base, head)
+		}(i, p.BaseRefName, p.Tip.OID)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return comparisons, nil
+}
+
+// listCommits lists the commits of each pull request, with a bounded number of concurrent requests.
+func listCommits(manager Github, pulls []*PullRequest) ([][]PullRequestCommit, error) {
+	commits := make([][]PullRequestCommit, len(pulls))
+	errs := make([]error, len(pulls))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, checkConcurrency)
+	for i, p := range pulls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i, number int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			commits[i], errs[i] = manager.ListCommits(number)
+		}(i, p.Number)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return commits, nil
+}
+
+// nonConformingCommit returns the reason the first commit which does not conform to require_signed_commits
+// or require_linear_history does not, or an empty string if all of them do.
+func nonConformingCommit(commits []PullRequestCommit, s Source) string {
+	for _, c := range commits {
+		if s.RequireSignedCommits && !c.Verified {
+			reason := c.VerificationReason
+			if reason == "" {
+				reason = "unsigned"
+			}
+			return fmt.Sprintf("commit %s does not have a verified signature (%s)", c.OID, reason)
+		}
+		if s.RequireLinearHistory && c.Parents > 1 {
+			return fmt.Sprintf("commit %s is a merge commit", c.OID)
+		}
+	}
+	return ""
+}
+
 // CheckRequest ...
 type CheckRequest struct {
 	Source  Source  `json:"source"`
 	Version Version `json:"version"`
+
+	// Cursor is the last version returned by check (see LoadCheckCursor), which is used when there is no version.
+	Cursor *Version `json:"-"`
 }
 
 // CheckResponse ...
 type CheckResponse []Version
 
+// Encode writes the response as a JSON array, encoding one version at a time. Note that the versions
+// themselves are all held in memory (the number of them is only bounded by max_versions).
+func (r CheckResponse) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for i, v := range r {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		bw.Write(b)
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
 func (r CheckResponse) Len() int {
 	return len(r)
 }
 
+// Less orders versions by date, and versions with the same date by pull request and commit, so
+// that the order (and the response) does not depend on the order the pull requests are listed in.
 func (r CheckResponse) Less(i, j int) bool {
-	return r[j].CommittedDate.After(r[i].CommittedDate)
+	if a, b := r[i].date(), r[j].date(); !a.Equal(b) {
+		return b.After(a)
+	}
+	if r[i].PR != r[j].PR {
+		a, _ := strconv.Atoi(r[i].PR)
+		b, _ := strconv.Atoi(r[j].PR)
+		return a < b
+	}
+	return r[i].Commit < r[j].Commit
 }
 
 func (r CheckResponse) Swap(i, j int) {
@@ -1,10 +1,18 @@
 package resource_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	resource "github.com/telia-oss/github-pr-resource"
 	"github.com/telia-oss/github-pr-resource/fakes"
 )
@@ -31,7 +39,7 @@ func TestCheck(t *testing.T) {
 		description  string
 		source       resource.Source
 		version      resource.Version
-		files        [][]string
+		files        map[int][]string
 		pullRequests []*resource.PullRequest
 		expected     resource.CheckResponse
 	}{
@@ -43,7 +51,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.Version{},
 			pullRequests: testPullRequests,
-			files:        [][]string{},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[1]),
 			},
@@ -57,7 +64,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.NewVersion(testPullRequests[1]),
 			pullRequests: testPullRequests,
-			files:        [][]string{},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[1]),
 			},
@@ -71,7 +77,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.NewVersion(testPullRequests[3]),
 			pullRequests: testPullRequests,
-			files:        [][]string{},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[2]),
 				resource.NewVersion(testPullRequests[1]),
@@ -87,10 +92,10 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.NewVersion(testPullRequests[3]),
 			pullRequests: testPullRequests,
-			files: [][]string{
-				{"README.md", "travis.yml"},
-				{"terraform/modules/ecs/main.tf", "README.md"},
-				{"terraform/modules/variables.tf", "travis.yml"},
+			files: map[int][]string{
+				2: {"README.md", "travis.yml"},
+				3: {"terraform/modules/ecs/main.tf", "README.md"},
+				4: {"terraform/modules/variables.tf", "travis.yml"},
 			},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[2]),
@@ -106,10 +111,10 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.NewVersion(testPullRequests[3]),
 			pullRequests: testPullRequests,
-			files: [][]string{
-				{"README.md", "travis.yml"},
-				{"terraform/modules/ecs/main.tf", "README.md"},
-				{"terraform/modules/variables.tf", "travis.yml"},
+			files: map[int][]string{
+				2: {"README.md", "travis.yml"},
+				3: {"terraform/modules/ecs/main.tf", "README.md"},
+				4: {"terraform/modules/variables.tf", "travis.yml"},
 			},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[2]),
@@ -184,7 +189,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.Version{},
 			pullRequests: testPullRequests,
-			files:        [][]string{},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[6]),
 			},
@@ -213,7 +217,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.Version{},
 			pullRequests: testPullRequests,
-			files:        [][]string{},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[6]),
 			},
@@ -228,7 +231,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.Version{},
 			pullRequests: testPullRequests,
-			files:        [][]string{},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[9]),
 			},
@@ -243,7 +245,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.Version{},
 			pullRequests: testPullRequests[9:11],
-			files:        [][]string{},
 			expected:     resource.CheckResponse(nil),
 		},
 
@@ -256,7 +257,6 @@ func TestCheck(t *testing.T) {
 			},
 			version:      resource.NewVersion(testPullRequests[11]),
 			pullRequests: testPullRequests,
-			files:        [][]string{},
 			expected: resource.CheckResponse{
 				resource.NewVersion(testPullRequests[9]),
 				resource.NewVersion(testPullRequests[10]),
@@ -282,8 +282,8 @@ func TestCheck(t *testing.T) {
 			}
 			github.ListPullRequestsReturns(pullRequests, nil)
 
-			for i, file := range tc.files {
-				github.ListModifiedFilesReturnsOnCall(i, file, nil)
+			github.ListModifiedFilesStub = func(number int) ([]string, error) {
+				return tc.files[number], nil
 			}
 
 			input := resource.CheckRequest{Source: tc.source, Version: tc.version}
@@ -292,7 +292,73 @@ func TestCheck(t *testing.T) {
 			if assert.NoError(t, err) {
 				assert.Equal(t, tc.expected, output)
 			}
-			assert.Equal(t, 1, github.ListPullRequestsCallCount())
+			if assert.Equal(t, 1, github.ListPullRequestsCallCount()) {
+				_, fields, since := github.ListPullRequestsArgsForCall(0)
+				assert.Equal(t, tc.version.CommittedDate, since)
+				filterPaths := len(tc.source.Paths) > 0 || len(tc.source.IgnorePaths) > 0
+				assert.Equal(t, resource.PullRequestFields{Labels: true, Reviews: true, Files: filterPaths}, fields)
+			}
+		})
+	}
+}
+
+func TestCheckFetch(t *testing.T) {
+	tests := []struct {
+		description string
+		source      resource.Source
+		expected    resource.PullRequestFields
+		expectError string
+	}{
+		{
+			description: "everything is fetched by default",
+			source:      resource.Source{},
+			expected:    resource.PullRequestFields{Labels: true, Reviews: true},
+		},
+		{
+			description: "only the listed data is fetched",
+			source:      resource.Source{CheckFetch: []string{"reviews"}},
+			expected:    resource.PullRequestFields{Reviews: true},
+		},
+		{
+			description: "optional data can be skipped entirely",
+			source:      resource.Source{CheckFetch: []string{}},
+			expected:    resource.PullRequestFields{},
+		},
+		{
+			description: "labels are required for filtering by label",
+			source:      resource.Source{CheckFetch: []string{"reviews"}, Labels: []string{"bug"}},
+			expectError: "check_fetch must include labels when labels is set",
+		},
+		{
+			description: "reviews are required for required review approvals",
+			source:      resource.Source{CheckFetch: []string{"labels"}, RequiredReviewApprovals: 1},
+			expectError: "check_fetch must include reviews when required_review_approvals is set",
+		},
+		{
+			description: "unknown data is rejected",
+			source:      resource.Source{CheckFetch: []string{"statuses"}},
+			expectError: "check_fetch value \"statuses\" must be one of: labels, reviews",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			tc.source.Repository = "itsdalmo/test-repository"
+			tc.source.AccessToken = "oauthtoken"
+			if tc.expectError != "" {
+				assert.EqualError(t, tc.source.Validate(), tc.expectError)
+				return
+			}
+			require.NoError(t, tc.source.Validate())
+
+			github := new(fakes.FakeGithub)
+			_, err := resource.Check(resource.CheckRequest{Source: tc.source}, github)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.ListPullRequestsCallCount()) {
+				_, fields, _ := github.ListPullRequestsArgsForCall(0)
+				assert.Equal(t, tc.expected, fields)
+			}
 		})
 	}
 }
@@ -550,3 +616,601 @@ func TestIsInsidePath(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckListsModifiedFilesConcurrently(t *testing.T) {
+	var pullRequests []*resource.PullRequest
+	for i := 1; i <= 25; i++ {
+		pullRequests = append(pullRequests, createTestPR(i, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen))
+	}
+
+	var (
+		mu                sync.Mutex
+		inFlight, maxSeen int
+	)
+	github := new(fakes.FakeGithub)
+	github.ListPullRequestsReturns(pullRequests, nil)
+	github.ListModifiedFilesStub = func(number int) ([]string, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if number%2 == 0 {
+			return []string{"README.md"}, nil
+		}
+		return []string{"terraform/main.tf"}, nil
+	}
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", Paths: []string{"terraform/"}}
+	output, err := resource.Check(resource.CheckRequest{Source: source, Version: resource.NewVersion(pullRequests[24])}, github)
+	require.NoError(t, err)
+
+	assert.Equal(t, 24, github.ListModifiedFilesCallCount())
+	assert.Len(t, output, 12)
+	for _, v := range output {
+		n, err := strconv.Atoi(v.PR)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n%2)
+	}
+	assert.True(t, maxSeen > 1, "files should be listed concurrently")
+	assert.True(t, maxSeen <= 10, "concurrency should be bounded")
+}
+
+func TestCheckUsesPrefetchedFiles(t *testing.T) {
+	withFiles := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	withFiles.Files = []string{"terraform/main.tf"}
+	withoutFiles := createTestPR(2, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+
+	github := new(fakes.FakeGithub)
+	github.ListPullRequestsReturns([]*resource.PullRequest{withFiles, withoutFiles}, nil)
+	github.ListModifiedFilesReturns([]string{"README.md"}, nil)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", Paths: []string{"terraform/"}}
+	version := resource.NewVersion(createTestPR(3, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen))
+	output, err := resource.Check(resource.CheckRequest{Source: source, Version: version}, github)
+	require.NoError(t, err)
+
+	assert.Equal(t, resource.CheckResponse{resource.NewVersion(withFiles)}, output)
+	if assert.Equal(t, 1, github.ListModifiedFilesCallCount()) {
+		assert.Equal(t, 2, github.ListModifiedFilesArgsForCall(0))
+	}
+}
+
+func TestCheckMaxVersions(t *testing.T) {
+	var pullRequests []*resource.PullRequest
+	for i := 1; i <= 5; i++ {
+		pullRequests = append(pullRequests, createTestPR(i, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen))
+	}
+	github := new(fakes.FakeGithub)
+	github.ListPullRequestsReturns(pullRequests, nil)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", MaxVersions: 2}
+	require.NoError(t, source.Validate())
+
+	version := resource.NewVersion(createTestPR(6, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen))
+	output, err := resource.Check(resource.CheckRequest{Source: source, Version: version}, github)
+	require.NoError(t, err)
+
+	// The newest versions are kept
+	assert.Equal(t, resource.CheckResponse{resource.NewVersion(pullRequests[1]), resource.NewVersion(pullRequests[0])}, output)
+}
+
+func TestCheckForcePush(t *testing.T) {
+	commit := func(oid string, date time.Time) resource.CommitObject {
+		return resource.CommitObject{ID: oid, OID: oid, CommittedDate: githubv4.DateTime{Time: date}, Message: "commit " + oid}
+	}
+	now := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	github := fakes.NewMemoryGithub("itsdalmo", "test-repository")
+	github.AddPullRequest(1, "master", "feature-1", commit("oid1", now))
+	require.NoError(t, github.Push(1, commit("oid2", now.Add(time.Minute))))
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	versions, err := resource.Check(resource.CheckRequest{Source: source}, github)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, "oid2", versions[0].Commit)
+
+	// Rewriting the branch to an older commit produces a new version, ordered by the time of the push
+	require.NoError(t, github.ForcePush(1, commit("oid1", now)))
+	versions, err = resource.Check(resource.CheckRequest{Source: source, Version: versions[0]}, github)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, "oid1", versions[0].Commit)
+	assert.True(t, versions[0].CommittedDate.After(now.Add(time.Minute)))
+
+	// The same version is returned by put
+	pull, err := github.GetPullRequest("1", "oid1")
+	require.NoError(t, err)
+	assert.Equal(t, versions[0], resource.NewVersion(pull))
+}
+
+func TestCheckPruneVersions(t *testing.T) {
+	version := resource.NewVersion(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen))
+
+	tests := []struct {
+		description string
+		prune       bool
+		pull        *resource.PullRequest
+		err         error
+		expected    resource.CheckResponse
+	}{
+		{
+			description: "the version is returned when it is not pruned",
+			prune:       false,
+			err:         &resource.CommitNotFoundError{Ref: version.Commit},
+			expected:    resource.CheckResponse{version},
+		},
+		{
+			description: "the version is returned when it still exists",
+			prune:       true,
+			pull:        createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			expected:    resource.CheckResponse{version},
+		},
+		{
+			description: "the version is pruned when the commit no longer exists",
+			prune:       true,
+			err:         &resource.CommitNotFoundError{Ref: version.Commit},
+			expected:    resource.CheckResponse(nil),
+		},
+		{
+			description: "the version is pruned when the pull request does not have one of the states",
+			prune:       true,
+			pull:        createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateClosed),
+			expected:    resource.CheckResponse(nil),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(tc.pull, tc.err)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", PruneVersions: tc.prune}
+			output, err := resource.Check(resource.CheckRequest{Source: source, Version: version}, github)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, output)
+
+			if tc.prune && assert.Equal(t, 1, github.GetPullRequestCallCount()) {
+				pr, commit := github.GetPullRequestArgsForCall(0)
+				assert.Equal(t, version.PR, pr)
+				assert.Equal(t, version.Commit, commit)
+			}
+		})
+	}
+
+	t.Run("other errors fail the check", func(t *testing.T) {
+		github := new(fakes.FakeGithub)
+		github.GetPullRequestReturns(nil, errors.New("bad gateway"))
+
+		source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", PruneVersions: true}
+		_, err := resource.Check(resource.CheckRequest{Source: source, Version: version}, github)
+		assert.EqualError(t, err, "failed to verify version: bad gateway")
+	})
+}
+
+func TestCheckCursor(t *testing.T) {
+	pullRequests := []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		createTestPR(2, "master", false, false, 0, []string{"ready"}, false, githubv4.PullRequestStateOpen),
+		createTestPR(3, "master", false, false, 0, []string{"ready"}, false, githubv4.PullRequestStateOpen),
+	}
+	cursor := resource.NewVersion(pullRequests[1])
+
+	tests := []struct {
+		description string
+		labels      []string
+		pulls       []*resource.PullRequest
+		expected    resource.CheckResponse
+	}{
+		{
+			description: "the cursor is returned if it still matches the filters",
+			labels:      []string{"ready"},
+			pulls:       pullRequests[1:],
+			expected:    resource.CheckResponse{cursor},
+		},
+		{
+			description: "the cursor is not returned if it no longer matches the filters",
+			labels:      []string{"wontfix"},
+			pulls:       pullRequests[1:],
+			expected:    nil,
+		},
+		{
+			description: "newer pull requests are returned",
+			pulls:       pullRequests,
+			expected:    resource.CheckResponse{resource.NewVersion(pullRequests[0])},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsReturns(tc.pulls, nil)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", Labels: tc.labels}
+			output, err := resource.Check(resource.CheckRequest{Source: source, Cursor: &cursor}, github)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, output)
+
+			// Pull requests are listed from the cursor
+			_, _, since := github.ListPullRequestsArgsForCall(0)
+			assert.True(t, since.Before(cursor.CommittedDate))
+		})
+	}
+}
+
+func TestCheckVerifiesAccessOnFailure(t *testing.T) {
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+
+	t.Run("token is not verified when listing succeeds", func(t *testing.T) {
+		github := new(fakes.FakeGithub)
+		github.ListPullRequestsReturns(testPullRequests, nil)
+
+		_, err := resource.Check(resource.CheckRequest{Source: source}, github)
+		require.NoError(t, err)
+		assert.Equal(t, 0, github.GetTokenInfoCallCount())
+	})
+
+	t.Run("inaccessible repository", func(t *testing.T) {
+		github := new(fakes.FakeGithub)
+		github.ListPullRequestsReturns(nil, errors.New("Could not resolve to a Repository"))
+		github.GetTokenInfoReturns(nil, errors.New("repository itsdalmo/test-repository does not exist"))
+
+		_, err := resource.Check(resource.CheckRequest{Source: source}, github)
+		assert.EqualError(t, err, "failed to verify access token: repository itsdalmo/test-repository does not exist")
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		github := new(fakes.FakeGithub)
+		github.ListPullRequestsReturns(nil, errors.New("bad gateway"))
+		github.GetTokenInfoReturns(&resource.TokenInfo{}, nil)
+
+		_, err := resource.Check(resource.CheckRequest{Source: source}, github)
+		assert.EqualError(t, err, "failed to get last commits: bad gateway")
+	})
+}
+
+func TestCheckIncludeUpdatedAt(t *testing.T) {
+	now := time.Now().Add(-time.Hour).Truncate(time.Second)
+	github := fakes.NewMemoryGithub("itsdalmo", "test-repository")
+	github.AddPullRequest(1, "master", "feature-1", resource.CommitObject{ID: "oid1", OID: "oid1", CommittedDate: githubv4.DateTime{Time: now}})
+	github.PullRequests[1].UpdatedAt = now
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	versions, err := resource.Check(resource.CheckRequest{Source: source}, github)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Nil(t, versions[0].UpdatedDate)
+
+	withUpdatedAt := source
+	withUpdatedAt.IncludeUpdatedAt = true
+	versions, err = resource.Check(resource.CheckRequest{Source: withUpdatedAt}, github)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.NotNil(t, versions[0].UpdatedDate)
+	assert.True(t, now.Equal(*versions[0].UpdatedDate))
+
+	// Changing the labels only produces a new version with include_updated_at
+	github.PullRequests[1].Labels = []string{"ready"}
+	github.PullRequests[1].UpdatedAt = now.Add(time.Minute)
+
+	version := resource.Version{PR: "1", Commit: "oid1", CommittedDate: now}
+	output, err := resource.Check(resource.CheckRequest{Source: source, Version: version}, github)
+	require.NoError(t, err)
+	assert.Equal(t, resource.CheckResponse{version}, output)
+
+	output, err = resource.Check(resource.CheckRequest{Source: withUpdatedAt, Version: versions[0]}, github)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	assert.Equal(t, "oid1", output[0].Commit)
+	require.NotNil(t, output[0].UpdatedDate)
+	assert.True(t, now.Add(time.Minute).Equal(*output[0].UpdatedDate))
+}
+
+func TestCheckOrderBy(t *testing.T) {
+	now := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	date := func(minutes int) githubv4.DateTime {
+		return githubv4.DateTime{Time: now.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	// A rebased pull request (authored first, but committed and pushed last)
+	rebased := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	rebased.Tip.AuthoredDate, rebased.Tip.CommittedDate, rebased.ForcePushedDate = date(1), date(3), date(5)
+	other := createTestPR(2, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	other.Tip.AuthoredDate, other.Tip.CommittedDate = date(2), date(4)
+
+	tests := []struct {
+		orderBy  string
+		expected []string
+		dates    []time.Time
+	}{
+		{orderBy: "", expected: []string{"2", "1"}, dates: []time.Time{date(4).Time, date(5).Time}},
+		{orderBy: "pushed", expected: []string{"2", "1"}, dates: []time.Time{date(4).Time, date(5).Time}},
+		{orderBy: "committed", expected: []string{"1", "2"}, dates: []time.Time{date(3).Time, date(4).Time}},
+		{orderBy: "authored", expected: []string{"1", "2"}, dates: []time.Time{date(1).Time, date(2).Time}},
+	}
+
+	for _, tc := range tests {
+		t.Run("order by "+tc.orderBy, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsReturns([]*resource.PullRequest{other, rebased}, nil)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", OrderBy: tc.orderBy}
+			require.NoError(t, source.Validate())
+			output, err := resource.Check(resource.CheckRequest{Source: source, Version: resource.Version{PR: "3", CommittedDate: now}}, github)
+			require.NoError(t, err)
+
+			var prs []string
+			var dates []time.Time
+			for _, v := range output {
+				prs = append(prs, v.PR)
+				dates = append(dates, v.CommittedDate)
+			}
+			assert.Equal(t, tc.expected, prs)
+			assert.Equal(t, tc.dates, dates)
+		})
+	}
+}
+
+func TestCheckWhenReadyToMerge(t *testing.T) {
+	now := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	date := func(minutes int) githubv4.DateTime {
+		return githubv4.DateTime{Time: now.Add(time.Duration(minutes) * time.Minute)}
+	}
+	pr := func(number, approvals int, approved githubv4.DateTime, contexts ...resource.CommitContext) *resource.PullRequest {
+		p := createTestPR(number, "master", false, false, approvals, nil, false, githubv4.PullRequestStateOpen)
+		p.Tip.CommittedDate = date(-10)
+		p.ApprovedDate = approved
+		p.Contexts = contexts
+		return p
+	}
+	ci := func(state string, minutes int) resource.CommitContext {
+		return resource.CommitContext{Name: "ci", State: state, Date: date(minutes)}
+	}
+
+	changesRequested := pr(2, 1, date(1), ci("success", 1))
+	changesRequested.ChangesRequestedCount = 1
+	reviewRequired := pr(3, 1, date(1), ci("success", 1))
+	reviewRequired.ReviewDecision = githubv4.PullRequestReviewDecisionReviewRequired
+	pulls := []*resource.PullRequest{
+		pr(1, 1, date(5), ci("success", 3)),
+		changesRequested,
+		reviewRequired,
+		pr(4, 0, githubv4.DateTime{}, ci("success", 1)),
+		pr(5, 1, date(1), ci("failure", 1)),
+		pr(6, 1, date(-5), ci("success", -4)),
+		pr(7, 1, date(1), ci("success", 2), resource.CommitContext{Name: "lint", State: "pending"}),
+	}
+
+	tests := []struct {
+		description   string
+		readyContexts []string
+		expected      []string
+	}{
+		{
+			description: "all contexts must be successful",
+			expected:    []string{"1"},
+		},
+		{
+			description:   "only the ready contexts must be successful",
+			readyContexts: []string{"ci"},
+			expected:      []string{"7", "1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsReturns(pulls, nil)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", WhenReadyToMerge: true, ReadyContexts: tc.readyContexts}
+			require.NoError(t, source.Validate())
+			output, err := resource.Check(resource.CheckRequest{Source: source, Version: resource.Version{PR: "9", CommittedDate: now}}, github)
+			require.NoError(t, err)
+
+			var prs []string
+			for _, v := range output {
+				prs = append(prs, v.PR)
+			}
+			assert.Equal(t, tc.expected, prs)
+
+			require.Equal(t, 1, github.ListPullRequestsCallCount())
+			_, fields, since := github.ListPullRequestsArgsForCall(0)
+			assert.True(t, fields.Readiness)
+			assert.True(t, since.IsZero())
+		})
+	}
+}
+
+func TestCheckMaxBehindBy(t *testing.T) {
+	pulls := []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		createTestPR(2, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		createTestPR(3, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+	}
+	behindBy := map[string]int{"oid1": 0, "oid2": 10, "oid3": 400}
+
+	tests := []struct {
+		description string
+		maxBehindBy *int
+		expected    []string
+	}{
+		{
+			description: "pull requests are not compared by default",
+			expected:    []string{"1", "2", "3"},
+		},
+		{
+			description: "pull requests which are too far behind are skipped",
+			maxBehindBy: intPtr(10),
+			expected:    []string{"1", "2"},
+		},
+		{
+			description: "pull requests must be up to date with a max of 0",
+			maxBehindBy: intPtr(0),
+			expected:    []string{"1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsReturns(pulls, nil)
+			github.CompareCommitsStub = func(base, head string) (*resource.CommitComparison, error) {
+				assert.Equal(t, "master", base)
+				return &resource.CommitComparison{AheadBy: 1, BehindBy: behindBy[head]}, nil
+			}
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", MaxBehindBy: tc.maxBehindBy}
+			require.NoError(t, source.Validate())
+			output, err := resource.Check(resource.CheckRequest{Source: source, Version: resource.Version{PR: "9", CommittedDate: time.Time{}.Add(time.Hour)}}, github)
+			require.NoError(t, err)
+
+			var prs []string
+			for _, v := range output {
+				prs = append(prs, v.PR)
+			}
+			assert.ElementsMatch(t, tc.expected, prs)
+			if tc.maxBehindBy == nil {
+				assert.Equal(t, 0, github.CompareCommitsCallCount())
+			}
+		})
+	}
+}
+
+func TestCheckBranchProtection(t *testing.T) {
+	pulls := []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		createTestPR(2, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		createTestPR(3, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+	}
+	commits := map[int][]resource.PullRequestCommit{
+		1: {{OID: "a", Verified: true, VerificationReason: "valid", Parents: 1}},
+		2: {{OID: "b", Verified: false, VerificationReason: "unsigned", Parents: 1}},
+		3: {{OID: "c", Verified: true, VerificationReason: "valid", Parents: 1}, {OID: "d", Verified: true, VerificationReason: "valid", Parents: 2}},
+	}
+
+	tests := []struct {
+		description    string
+		signedCommits  bool
+		linearHistory  bool
+		expected       []string
+		expectListCall bool
+	}{
+		{
+			description: "commits are not listed by default",
+			expected:    []string{"1", "2", "3"},
+		},
+		{
+			description:    "pull requests with unsigned commits are skipped",
+			signedCommits:  true,
+			expected:       []string{"1", "3"},
+			expectListCall: true,
+		},
+		{
+			description:    "pull requests with merge commits are skipped",
+			linearHistory:  true,
+			expected:       []string{"1", "2"},
+			expectListCall: true,
+		},
+		{
+			description:    "both rules can be required",
+			signedCommits:  true,
+			linearHistory:  true,
+			expected:       []string{"1"},
+			expectListCall: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsReturns(pulls, nil)
+			github.ListCommitsStub = func(number int) ([]resource.PullRequestCommit, error) {
+				return commits[number], nil
+			}
+
+			source := resource.Source{
+				Repository:           "itsdalmo/test-repository",
+				AccessToken:          "oauthtoken",
+				RequireSignedCommits: tc.signedCommits,
+				RequireLinearHistory: tc.linearHistory,
+			}
+			require.NoError(t, source.Validate())
+			output, err := resource.Check(resource.CheckRequest{Source: source, Version: resource.Version{PR: "9", CommittedDate: time.Time{}.Add(time.Hour)}}, github)
+			require.NoError(t, err)
+
+			var prs []string
+			for _, v := range output {
+				prs = append(prs, v.PR)
+			}
+			assert.ElementsMatch(t, tc.expected, prs)
+			assert.Equal(t, tc.expectListCall, github.ListCommitsCallCount() > 0)
+		})
+	}
+}
+
+func TestCheckStableResponse(t *testing.T) {
+	date := time.Date(2020, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	var pullRequests []*resource.PullRequest
+	for i := 1; i <= 4; i++ {
+		p := createTestPR(i, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+		p.Tip.CommittedDate = githubv4.DateTime{Time: date}
+		pullRequests = append(pullRequests, p)
+	}
+	reversed := []*resource.PullRequest{pullRequests[3], pullRequests[2], pullRequests[1], pullRequests[0]}
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "5", Commit: "oid5", CommittedDate: date.Add(-time.Hour)}
+
+	var encoded []string
+	for _, pulls := range [][]*resource.PullRequest{pullRequests, reversed} {
+		github := new(fakes.FakeGithub)
+		github.ListPullRequestsReturns(pulls, nil)
+		output, err := resource.Check(resource.CheckRequest{Source: source, Version: version}, github)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, output.Encode(&b))
+		encoded = append(encoded, b.String())
+	}
+	assert.Equal(t, encoded[0], encoded[1])
+	assert.Contains(t, encoded[0], `"committed":"2020-01-01T11:00:00Z"`)
+	assert.True(t, strings.Index(encoded[0], `"pr":"1"`) < strings.Index(encoded[0], `"pr":"4"`))
+}
+
+func TestCheckResponseEncode(t *testing.T) {
+	tests := []struct {
+		description string
+		response    resource.CheckResponse
+	}{
+		{
+			description: "empty response",
+			response:    resource.CheckResponse{},
+		},
+		{
+			description: "multiple versions",
+			response: resource.CheckResponse{
+				resource.NewVersion(createTestPR(2, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)),
+				resource.NewVersion(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, tc.response.Encode(&b))
+
+			expected, err := json.Marshal(tc.response)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected)+"\n", b.String())
+		})
+	}
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/telia-oss/github-pr-resource"
 )
@@ -11,6 +13,15 @@ import (
 func main() {
 	var request resource.CheckRequest
 
+	// Secrets are redacted from errors and other output
+	log.SetOutput(resource.NewRedactingWriter(os.Stderr))
+
+	if len(os.Args) > 1 && os.Args[1] == "--version" {
+		fmt.Println(resource.GetBuildInfo())
+		return
+	}
+	resource.GetBuildInfo().Log()
+
 	decoder := json.NewDecoder(os.Stdin)
 	decoder.DisallowUnknownFields()
 
@@ -21,16 +32,44 @@ func main() {
 	if err := request.Source.Validate(); err != nil {
 		log.Fatalf("invalid source configuration: %s", err)
 	}
+	resource.SetLogger(resource.NewSourceLogger(&request.Source, os.Stderr))
+	tracer := resource.NewTracer(&request.Source, "check")
+	resource.SetTracer(tracer)
 	github, err := resource.NewGithubClient(&request.Source)
 	if err != nil {
 		log.Fatalf("failed to create github manager: %s", err)
 	}
+	state, err := resource.NewStateStore(&request.Source)
+	if err != nil {
+		log.Fatalf("failed to create state store: %s", err)
+	}
+	if err := resource.LoadCheckCursor(state, &request); err != nil {
+		log.Fatalf("failed to load check cursor: %s", err)
+	}
+	metrics := resource.NewMetrics(&request.Source, "check")
+	start := time.Now()
 	response, err := resource.Check(request, github)
+	usage := github.APIUsage()
+	usage.Log()
+	metrics.Duration(time.Since(start), err)
+	metrics.AddAPIUsage(usage)
+	if err == nil {
+		metrics.Gauge("versions", float64(len(response)))
+	}
+	if err := metrics.Flush(); err != nil {
+		log.Printf("failed to send metrics: %s", err)
+	}
+	if traceErr := tracer.Finish(err); traceErr != nil {
+		log.Printf("failed to export traces: %s", traceErr)
+	}
 	if err != nil {
 		log.Fatalf("check failed: %s", err)
 	}
+	if err := resource.SaveCheckCursor(state, request.Source, response); err != nil {
+		log.Fatalf("failed to save check cursor: %s", err)
+	}
 
-	if err := json.NewEncoder(os.Stdout).Encode(response); err != nil {
+	if err := response.Encode(os.Stdout); err != nil {
 		log.Fatalf("failed to marshal response: %s", err)
 	}
 }
@@ -0,0 +1,202 @@
+// Command gpr runs check, in or out locally (outside of Concourse), which is useful when testing
+// changes to the configuration of a pipeline. The source, version and parameters are read from
+// a YAML (or JSON) file, where environment variables like $GITHUB_TOKEN are expanded:
+//
+//	source:
+//	  repository: itsdalmo/test-repository
+//	  access_token: $GITHUB_TOKEN
+//	version: {pr: "1", commit: ...}
+//	get: {integration_tool: checkout}
+//	put: {path: pull-request, status: success}
+//
+// Usage:
+//
+//	gpr [-config gpr.yml] [-dir path] check|in|out
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/telia-oss/github-pr-resource"
+	"gopkg.in/yaml.v2"
+)
+
+// config is the content of the configuration file.
+type config struct {
+	Source  resource.Source        `json:"source"`
+	Version *resource.Version      `json:"version"`
+	Get     resource.GetParameters `json:"get"`
+	Put     resource.PutParameters `json:"put"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetOutput(resource.NewRedactingWriter(os.Stderr))
+
+	configPath := flag.String("config", "gpr.yml", "path to the configuration file")
+	dir := flag.String("dir", "", "directory for in (the output) and out (the sources), defaults to a temporary directory for in")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: gpr [flags] check|in|out\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := readConfig(*configPath)
+	if err != nil {
+		log.Fatalf("failed to read configuration: %s", err)
+	}
+	if err := cfg.Source.Validate(); err != nil {
+		log.Fatalf("invalid source configuration: %s", err)
+	}
+	resource.SetLogger(resource.NewSourceLogger(&cfg.Source, os.Stderr))
+	github, err := resource.NewGithubClient(&cfg.Source)
+	if err != nil {
+		log.Fatalf("failed to create github manager: %s", err)
+	}
+
+	start := time.Now()
+	var output interface{}
+	switch step := flag.Arg(0); step {
+	case "check":
+		output, err = check(cfg, github)
+	case "in":
+		output, err = get(cfg, github, *dir)
+	case "out":
+		output, err = put(cfg, github, *dir)
+	default:
+		log.Fatalf("unknown step: %s (must be check, in or out)", step)
+	}
+	github.APIUsage().Log()
+	if err != nil {
+		log.Fatalf("%s failed: %s", flag.Arg(0), err)
+	}
+	log.Printf("%s completed in %s", flag.Arg(0), time.Since(start).Round(time.Millisecond))
+
+	b, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		log.Fatalf("failed to marshal response: %s", err)
+	}
+	fmt.Println(string(b))
+}
+
+func readConfig(path string) (*config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(b))), &v); err != nil {
+		return nil, err
+	}
+
+	// The configuration is converted to JSON, since the resource types only have JSON tags
+	v, err = jsonValue(v)
+	if err != nil {
+		return nil, err
+	}
+	b, err = json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var cfg config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// jsonValue converts the maps decoded from YAML (which can have keys of any type) to maps with string keys.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported key: %v", key)
+			}
+			converted, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = converted
+		}
+		return m, nil
+	case []interface{}:
+		for i, value := range v {
+			converted, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+	}
+	return v, nil
+}
+
+func check(cfg *config, github *resource.GithubClient) (resource.CheckResponse, error) {
+	request := resource.CheckRequest{Source: cfg.Source}
+	if cfg.Version != nil {
+		request.Version = *cfg.Version
+	}
+	return resource.Check(request, github)
+}
+
+// get fetches the version in the configuration, or the latest version if there is none.
+func get(cfg *config, github *resource.GithubClient, dir string) (*resource.GetResponse, error) {
+	request := resource.GetRequest{Source: cfg.Source, Params: cfg.Get}
+	if cfg.Version != nil {
+		request.Version = *cfg.Version
+	} else {
+		versions, err := check(cfg, github)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the latest version: %s", err)
+		}
+		if len(versions) == 0 {
+			return nil, errors.New("no versions found")
+		}
+		request.Version = versions[len(versions)-1]
+		log.Printf("using the latest version: pr %s, commit %s", request.Version.PR, request.Version.Commit)
+	}
+
+	var err error
+	if dir == "" {
+		dir, err = ioutil.TempDir("", "gpr-")
+	} else {
+		dir, err = filepath.Abs(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("fetching into %s", dir)
+
+	source := request.EffectiveSource()
+	if source.AccessToken, err = github.Token(); err != nil {
+		return nil, fmt.Errorf("failed to get access token: %s", err)
+	}
+	git, err := resource.NewGitClient(&source, dir, os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git client: %s", err)
+	}
+	defer git.Close()
+	return resource.Get(request, github, git, dir)
+}
+
+func put(cfg *config, github *resource.GithubClient, dir string) (*resource.PutResponse, error) {
+	if dir == "" {
+		dir = "."
+	}
+	return resource.Put(resource.PutRequest{Source: cfg.Source, Params: cfg.Put}, github, dir)
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/telia-oss/github-pr-resource"
 )
@@ -11,6 +13,15 @@ import (
 func main() {
 	var request resource.GetRequest
 
+	// Secrets are redacted from errors and other output
+	log.SetOutput(resource.NewRedactingWriter(os.Stderr))
+
+	if len(os.Args) > 1 && os.Args[1] == "--version" {
+		fmt.Println(resource.GetBuildInfo())
+		return
+	}
+	resource.GetBuildInfo().Log()
+
 	decoder := json.NewDecoder(os.Stdin)
 	decoder.DisallowUnknownFields()
 
@@ -25,15 +36,38 @@ func main() {
 	if err := request.Source.Validate(); err != nil {
 		log.Fatalf("invalid source configuration: %s", err)
 	}
-	git, err := resource.NewGitClient(&request.Source, outputDir, os.Stderr)
-	if err != nil {
-		log.Fatalf("failed to create git client: %s", err)
-	}
+	resource.SetLogger(resource.NewSourceLogger(&request.Source, os.Stderr))
+	tracer := resource.NewTracer(&request.Source, "get")
+	resource.SetTracer(tracer)
 	github, err := resource.NewGithubClient(&request.Source)
 	if err != nil {
 		log.Fatalf("failed to create github manager: %s", err)
 	}
+	// Git uses the same token as the API (which is minted on demand when authenticating as a Github App)
+	source := request.EffectiveSource()
+	if source.AccessToken, err = github.Token(); err != nil {
+		log.Fatalf("failed to get access token: %s", err)
+	}
+	git, err := resource.NewGitClient(&source, outputDir, os.Stderr)
+	if err != nil {
+		log.Fatalf("failed to create git client: %s", err)
+	}
+	metrics := resource.NewMetrics(&request.Source, "get")
+	start := time.Now()
 	response, err := resource.Get(request, github, git, outputDir)
+	if closeErr := git.Close(); closeErr != nil {
+		log.Printf("failed to remove submodule credentials: %s", closeErr)
+	}
+	usage := github.APIUsage()
+	usage.Log()
+	metrics.Duration(time.Since(start), err)
+	metrics.AddAPIUsage(usage)
+	if err := metrics.Flush(); err != nil {
+		log.Printf("failed to send metrics: %s", err)
+	}
+	if traceErr := tracer.Finish(err); traceErr != nil {
+		log.Printf("failed to export traces: %s", traceErr)
+	}
 	if err != nil {
 		log.Fatalf("get failed: %s", err)
 	}
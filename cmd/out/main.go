@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/telia-oss/github-pr-resource"
 )
@@ -11,6 +13,15 @@ import (
 func main() {
 	var request resource.PutRequest
 
+	// Secrets are redacted from errors and other output
+	log.SetOutput(resource.NewRedactingWriter(os.Stderr))
+
+	if len(os.Args) > 1 && os.Args[1] == "--version" {
+		fmt.Println(resource.GetBuildInfo())
+		return
+	}
+	resource.GetBuildInfo().Log()
+
 	decoder := json.NewDecoder(os.Stdin)
 	decoder.DisallowUnknownFields()
 
@@ -25,11 +36,26 @@ func main() {
 	if err := request.Source.Validate(); err != nil {
 		log.Fatalf("invalid source configuration: %s", err)
 	}
+	resource.SetLogger(resource.NewSourceLogger(&request.Source, os.Stderr))
+	tracer := resource.NewTracer(&request.Source, "put")
+	resource.SetTracer(tracer)
 	github, err := resource.NewGithubClient(&request.Source)
 	if err != nil {
 		log.Fatalf("failed to create github manager: %s", err)
 	}
+	metrics := resource.NewMetrics(&request.Source, "put")
+	start := time.Now()
 	response, err := resource.Put(request, github, sourceDir)
+	usage := github.APIUsage()
+	usage.Log()
+	metrics.Duration(time.Since(start), err)
+	metrics.AddAPIUsage(usage)
+	if err := metrics.Flush(); err != nil {
+		log.Printf("failed to send metrics: %s", err)
+	}
+	if traceErr := tracer.Finish(err); traceErr != nil {
+		log.Printf("failed to export traces: %s", traceErr)
+	}
 	if err != nil {
 		log.Fatalf("put failed: %s", err)
 	}
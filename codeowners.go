@@ -0,0 +1,98 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeownersPaths are the locations (in order of precedence) where Github looks for a CODEOWNERS file.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Codeowners is a parsed CODEOWNERS file.
+// https://help.github.com/en/articles/about-code-owners
+type Codeowners []CodeownersRule
+
+// CodeownersRule is a single line in a CODEOWNERS file.
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string
+	re      *regexp.Regexp
+}
+
+// ParseCodeowners parses the content of a CODEOWNERS file.
+func ParseCodeowners(content []byte) (Codeowners, error) {
+	var rules Codeowners
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		re, err := regexp.Compile(codeownersPatternToRegexp(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %s", fields[0], err)
+		}
+		rules = append(rules, CodeownersRule{Pattern: fields[0], Owners: fields[1:], re: re})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Owners returns the owners of a file. The last matching rule takes precedence.
+func (c Codeowners) Owners(file string) []string {
+	for i := len(c) - 1; i >= 0; i-- {
+		if c[i].re.MatchString(file) {
+			return c[i].Owners
+		}
+	}
+	return []string{}
+}
+
+// codeownersPatternToRegexp translates a gitignore style pattern to a regular expression.
+func codeownersPatternToRegexp(pattern string) string {
+	// Patterns containing a slash (other than a trailing one) are relative to the root.
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// A pattern matches the file itself or everything inside a matching directory,
+	// except for patterns like 'docs/*' which only match files directly in the directory.
+	if strings.HasSuffix(pattern, "*") && !strings.HasSuffix(pattern, "**") {
+		b.WriteString("$")
+	} else {
+		b.WriteString("(/.*)?$")
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestCodeowners(t *testing.T) {
+	content := []byte(`# Comment
+*       @global-owner
+
+*.js    @js-owner # inline comment
+/build/ @doctocat
+docs/*  docs@example.com
+apps/   @octocat
+**/logs @logs-owner
+/scripts/**/test @org/test-team
+`)
+
+	tests := []struct {
+		description string
+		file        string
+		want        []string
+	}{
+		{
+			description: "falls back to the global owner",
+			file:        "README.md",
+			want:        []string{"@global-owner"},
+		},
+		{
+			description: "matches extensions at any depth",
+			file:        "src/app/index.js",
+			want:        []string{"@js-owner"},
+		},
+		{
+			description: "matches anchored directories",
+			file:        "build/output/run.txt",
+			want:        []string{"@doctocat"},
+		},
+		{
+			description: "matches files directly in a directory",
+			file:        "docs/getting-started.md",
+			want:        []string{"docs@example.com"},
+		},
+		{
+			description: "does not match nested files for directory wildcards",
+			file:        "docs/build-app/troubleshooting.md",
+			want:        []string{"@global-owner"},
+		},
+		{
+			description: "matches unanchored directories at any depth",
+			file:        "src/apps/main.go",
+			want:        []string{"@octocat"},
+		},
+		{
+			description: "later rules take precedence",
+			file:        "build/logs/run.txt",
+			want:        []string{"@logs-owner"},
+		},
+		{
+			description: "matches leading double asterisks",
+			file:        "deep/nested/logs/output.txt",
+			want:        []string{"@logs-owner"},
+		},
+		{
+			description: "matches double asterisks in the middle",
+			file:        "scripts/a/b/test/run.sh",
+			want:        []string{"@org/test-team"},
+		},
+	}
+
+	codeowners, err := resource.ParseCodeowners(content)
+	require.NoError(t, err)
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.want, codeowners.Owners(tc.file))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// optionalPullRequestFields are the pull request fields which are left out of queries when they
+// are not supported by the server (i.e. an older version of Github Enterprise).
+var optionalPullRequestFields = []string{"isDraft", "reviewDecision", "latestOpinionatedReviews", "files", "timelineItems"}
+
+// schemaVersions maps fields of the GraphQL schema to the first Github Enterprise Server version which
+// supports them, and is used instead of detecting support from the schema when github_api_version is set.
+var schemaVersions = map[string]string{
+	"PullRequest.isDraft":                    "2.17",
+	"PullRequest.reviewDecision":             "2.21",
+	"PullRequest.latestOpinionatedReviews":   "2.22",
+	"PullRequest.files":                      "2.19",
+	"PullRequest.timelineItems":              "2.17",
+	"Mutation.markPullRequestReadyForReview": "2.17",
+	"Mutation.enablePullRequestAutoMerge":    "3.1",
+	"Mutation.convertPullRequestToDraft":     "3.2",
+	"Mutation.updatePullRequestBranch":       "3.5",
+}
+
+// unsupportedError is returned for requests which are not supported by the Github API.
+type unsupportedError struct {
+	field string
+}
+
+func (e *unsupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported by this version of the Github API", e.field)
+}
+
+// isSchemaError returns true if a GraphQL request failed because the schema does not have a field.
+func isSchemaError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "doesn't exist on type")
+}
+
+// supports returns true if the GraphQL schema has the given field (e.g. "PullRequest.isDraft"). Unless the
+// version is set explicitly, the schema of the server is introspected (once).
+func (m *GithubClient) supports(field string) (bool, error) {
+	if m.apiVersion != "" {
+		version, ok := schemaVersions[field]
+		return !ok || compareVersions(m.apiVersion, version) >= 0, nil
+	}
+
+	if m.schema == nil {
+		var query struct {
+			PullRequestType struct {
+				Fields []struct {
+					Name string
+				} `graphql:"fields(includeDeprecated: true)"`
+			} `graphql:"pullRequestType: __type(name: \"PullRequest\")"`
+			MutationType struct {
+				Fields []struct {
+					Name string
+				} `graphql:"fields(includeDeprecated: true)"`
+			} `graphql:"mutationType: __type(name: \"Mutation\")"`
+		}
+		if err := m.V4.Query(context.TODO(), &query, nil); err != nil {
+			return false, fmt.Errorf("failed to introspect schema: %s", err)
+		}
+		m.schema = make(map[string]bool)
+		for _, f := range query.PullRequestType.Fields {
+			m.schema["PullRequest."+f.Name] = true
+		}
+		for _, f := range query.MutationType.Fields {
+			m.schema["Mutation."+f.Name] = true
+		}
+	}
+	return m.schema[field], nil
+}
+
+// query runs a GraphQL query, leaving out the optional pull request fields which are not supported by the server.
+func (m *GithubClient) query(q interface{}, vars map[string]interface{}) error {
+	var err error
+	if m.apiVersion == "" {
+		if err = m.V4.Query(context.TODO(), q, vars); !isSchemaError(err) {
+			return err
+		}
+	}
+
+	unsupported := make(map[string]bool)
+	for _, f := range optionalPullRequestFields {
+		ok, err := m.supports("PullRequest." + f)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			unsupported[f] = true
+		}
+	}
+	if len(unsupported) == 0 {
+		if err != nil {
+			return err
+		}
+		return m.V4.Query(context.TODO(), q, vars)
+	}
+
+	// Run the query without the unsupported fields, and copy the result into q (leaving them as zero values)
+	t, _ := withoutFields(reflect.TypeOf(q).Elem(), unsupported)
+	compat := reflect.New(t).Interface()
+	if err := m.V4.Query(context.TODO(), compat, vars); err != nil {
+		return err
+	}
+	b, err := json.Marshal(compat)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, q)
+}
+
+// mutate runs a GraphQL mutation, and returns an unsupportedError if the mutation is not supported by the server.
+func (m *GithubClient) mutate(name string, mutation interface{}, input githubv4.Input) error {
+	if m.apiVersion != "" {
+		if ok, _ := m.supports("Mutation." + name); !ok {
+			return &unsupportedError{field: name}
+		}
+	}
+	err := m.V4.Mutate(context.TODO(), mutation, input, nil)
+	if isSchemaError(err) {
+		return &unsupportedError{field: name}
+	}
+	return err
+}
+
+// withoutFields returns a copy of the query type t without the struct fields for the given GraphQL fields,
+// and whether any fields were removed.
+func withoutFields(t reflect.Type, fields map[string]bool) (reflect.Type, bool) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		if elem, ok := withoutFields(t.Elem(), fields); ok {
+			return reflect.PtrTo(elem), true
+		}
+	case reflect.Slice:
+		if elem, ok := withoutFields(t.Elem(), fields); ok {
+			return reflect.SliceOf(elem), true
+		}
+	case reflect.Struct:
+		// Types which implement json.Unmarshaler are scalars (e.g. githubv4.DateTime)
+		if reflect.PtrTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+			return t, false
+		}
+		var (
+			changed      bool
+			structFields []reflect.StructField
+		)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if fields[graphqlFieldName(f)] {
+				changed = true
+				continue
+			}
+			if ft, ok := withoutFields(f.Type, fields); ok {
+				f.Type, changed = ft, true
+			}
+			structFields = append(structFields, f)
+		}
+		if changed {
+			return reflect.StructOf(structFields), true
+		}
+	}
+	return t, false
+}
+
+// graphqlFieldName returns the name of the GraphQL field queried by a struct field.
+func graphqlFieldName(f reflect.StructField) string {
+	name, ok := f.Tag.Lookup("graphql")
+	if !ok {
+		return strings.ToLower(f.Name[:1]) + f.Name[1:]
+	}
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.Index(name, "("); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSpace(name)
+}
+
+// compareVersions compares two (major.minor.patch) versions, returning -1, 0 or 1.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
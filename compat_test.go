@@ -0,0 +1,152 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+// oldEnterpriseServer returns a Github Enterprise server whose GraphQL schema does not support
+// draft pull requests, review decisions or the updatePullRequestBranch mutation.
+func oldEnterpriseServer(t *testing.T, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			*requests = append(*requests, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"message":"Updating pull request branch."}`))
+			return
+		}
+
+		var body struct {
+			Query string `json:"query"`
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &body))
+
+		switch {
+		case strings.Contains(body.Query, "__type("):
+			*requests = append(*requests, "introspection")
+			w.Write([]byte(`{"data":{
+				"pullRequestType":{"fields":[{"name":"id"},{"name":"number"},{"name":"title"}]},
+				"mutationType":{"fields":[{"name":"addComment"}]}
+			}}`))
+		case strings.Contains(body.Query, "isDraft"):
+			*requests = append(*requests, "query with isDraft")
+			w.Write([]byte(`{"data":null,"errors":[{"message":"Field 'isDraft' doesn't exist on type 'PullRequest'"}]}`))
+		case strings.Contains(body.Query, "updatePullRequestBranch"):
+			*requests = append(*requests, "updatePullRequestBranch")
+			w.Write([]byte(`{"data":null,"errors":[{"message":"Field 'updatePullRequestBranch' doesn't exist on type 'Mutation'"}]}`))
+		case strings.Contains(body.Query, "commits"):
+			*requests = append(*requests, "query")
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{
+				"id":"PR1","number":1,"title":"title","state":"OPEN",
+				"commits":{"edges":[{"node":{"commit":{"oid":"commit1"}}}]},
+				"labels":{"edges":[]},
+				"reviewRequests":{"edges":[]}
+			}}}}`))
+		default:
+			*requests = append(*requests, "query")
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR1"}}}}`))
+		}
+	}))
+}
+
+func TestGithubClientCompatibility(t *testing.T) {
+	tests := []struct {
+		description string
+		apiVersion  string
+		expected    []string
+	}{
+		{
+			description: "unsupported fields are detected from the schema",
+			expected:    []string{"query with isDraft", "introspection", "query"},
+		},
+		{
+			description: "unsupported fields are left out for older api versions",
+			apiVersion:  "2.16",
+			expected:    []string{"query"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests []string
+			server := oldEnterpriseServer(t, &requests)
+			defer server.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:       "itsdalmo/test-repository",
+				AccessToken:      "oauthtoken",
+				APIEndpoint:      server.URL,
+				GithubAPIVersion: tc.apiVersion,
+			})
+			require.NoError(t, err)
+
+			pr, err := client.GetPullRequest("1", "commit1")
+			require.NoError(t, err)
+			assert.Equal(t, "title", pr.Title)
+			assert.False(t, pr.IsDraft)
+			assert.Equal(t, "commit1", pr.Tip.OID)
+			assert.Equal(t, tc.expected, requests)
+		})
+	}
+}
+
+func TestGithubClientUpdateBranchFallback(t *testing.T) {
+	tests := []struct {
+		description string
+		apiVersion  string
+		method      string
+		expected    []string
+		expectError bool
+	}{
+		{
+			description: "falls back to the v3 api",
+			method:      "MERGE",
+			expected:    []string{"query", "updatePullRequestBranch", "PUT /api/v3/repos/itsdalmo/test-repository/pulls/1/update-branch"},
+		},
+		{
+			description: "falls back to the v3 api for older api versions",
+			apiVersion:  "3.4",
+			expected:    []string{"query", "PUT /api/v3/repos/itsdalmo/test-repository/pulls/1/update-branch"},
+		},
+		{
+			description: "rebase is not supported by the v3 api",
+			method:      "REBASE",
+			expected:    []string{"query", "updatePullRequestBranch"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests []string
+			server := oldEnterpriseServer(t, &requests)
+			defer server.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:       "itsdalmo/test-repository",
+				AccessToken:      "oauthtoken",
+				APIEndpoint:      server.URL,
+				GithubAPIVersion: tc.apiVersion,
+			})
+			require.NoError(t, err)
+
+			err = client.UpdateBranch("1", "commit1", tc.method)
+			if tc.expectError {
+				assert.EqualError(t, err, "updatePullRequestBranch is not supported by this version of the Github API")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, requests)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkHeader matches the header of a hunk in a unified diff, e.g. "@@ -1,3 +1,4 @@".
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseSuggestions parses a unified diff (e.g. the output of gofmt -d) into suggested changes,
+// where each block of changed lines replaces the corresponding lines in the original file.
+// Files that are created or deleted by the diff are skipped.
+func ParseSuggestions(diff []byte) ([]Suggestion, error) {
+	var (
+		suggestions []Suggestion
+		path        string
+		line        int
+		oldLeft     int
+		newLeft     int
+		start       int
+		removed     int
+		added       []string
+		inBlock     bool
+		previous    *string
+		pending     []string
+	)
+
+	add := func(s Suggestion) {
+		if path != "" {
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	// flush the current block of changed lines. Pure insertions are anchored on the previous
+	// (context) line, or the next one if the insertion is at the start of the hunk.
+	flush := func() {
+		if !inBlock {
+			return
+		}
+		switch {
+		case removed > 0:
+			add(Suggestion{Path: path, StartLine: start, Line: start + removed - 1, Replacement: added})
+		case previous != nil:
+			add(Suggestion{Path: path, StartLine: start - 1, Line: start - 1, Replacement: append([]string{*previous}, added...)})
+		default:
+			pending = added
+		}
+		inBlock, removed, added = false, 0, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		// Outside of a hunk we only care about file and hunk headers
+		if oldLeft <= 0 && newLeft <= 0 {
+			switch {
+			case strings.HasPrefix(text, "--- "):
+				path = diffPath(text[4:])
+			case strings.HasPrefix(text, "+++ "):
+				if path != "" {
+					path = diffPath(text[4:])
+				}
+			case strings.HasPrefix(text, "@@"):
+				m := hunkHeader.FindStringSubmatch(text)
+				if m == nil {
+					return nil, fmt.Errorf("malformed hunk header: %s", text)
+				}
+				line, _ = strconv.Atoi(m[1])
+				oldLeft, newLeft = hunkLength(m[2]), hunkLength(m[4])
+				previous, pending = nil, nil
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "-"):
+			if !inBlock {
+				inBlock, start = true, line
+			}
+			removed++
+			line++
+			oldLeft--
+		case strings.HasPrefix(text, "+"):
+			if !inBlock {
+				inBlock, start = true, line
+			}
+			added = append(added, text[1:])
+			newLeft--
+		case strings.HasPrefix(text, "\\"):
+			// "\ No newline at end of file"
+		default:
+			flush()
+			context := strings.TrimPrefix(text, " ")
+			previous = &context
+			if pending != nil {
+				add(Suggestion{Path: path, StartLine: line, Line: line, Replacement: append(pending, context)})
+				pending = nil
+			}
+			line++
+			oldLeft--
+			newLeft--
+		}
+		if oldLeft <= 0 && newLeft <= 0 {
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// hunkLength parses the (optional) length in a hunk header, which defaults to 1.
+func hunkLength(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// diffPath returns the path of a file from the ---/+++ lines of a diff (without any a/ or b/ prefix,
+// and without a timestamp), or an empty string for /dev/null.
+func diffPath(s string) string {
+	if i := strings.Index(s, "\t"); i >= 0 {
+		s = s[:i]
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		return s[2:]
+	}
+	return s
+}
+
+// diffHunkRanges returns the (inclusive) line ranges of the new file covered by the hunks in a patch.
+// Github only allows review comments on these lines.
+func diffHunkRanges(patch string) [][2]int {
+	var ranges [][2]int
+	for _, text := range strings.Split(patch, "\n") {
+		m := hunkHeader.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(m[3])
+		if count := hunkLength(m[4]); count > 0 {
+			ranges = append(ranges, [2]int{start, start + count - 1})
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,104 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestParseSuggestions(t *testing.T) {
+	tests := []struct {
+		description string
+		diff        string
+		want        []resource.Suggestion
+	}{
+		{
+			description: "replaced lines",
+			diff: `diff -u main.go.orig main.go
+--- main.go.orig	2020-01-01 00:00:00.000000000 +0000
++++ main.go	2020-01-01 00:00:00.000000000 +0000
+@@ -1,6 +1,5 @@
+ package main
+
+-func main()  {
+-  println("hello")
++func main() {
++	println("hello")
+ }
+-
+`,
+			want: []resource.Suggestion{
+				{Path: "main.go", StartLine: 3, Line: 4, Replacement: []string{"func main() {", "\tprintln(\"hello\")"}},
+				{Path: "main.go", StartLine: 6, Line: 6, Replacement: nil},
+			},
+		},
+		{
+			description: "inserted lines are anchored on the previous line",
+			diff: `--- a/out.go
++++ b/out.go
+@@ -10,2 +10,3 @@ func Put() {
+ 	a := 1
++
+ 	b := 2
+`,
+			want: []resource.Suggestion{
+				{Path: "out.go", StartLine: 10, Line: 10, Replacement: []string{"\ta := 1", ""}},
+			},
+		},
+		{
+			description: "inserted lines at the start of a file are anchored on the next line",
+			diff: `--- a/in.go
++++ b/in.go
+@@ -1,2 +1,3 @@
++// Package resource ...
+ package resource
+
+`,
+			want: []resource.Suggestion{
+				{Path: "in.go", StartLine: 1, Line: 1, Replacement: []string{"// Package resource ...", "package resource"}},
+			},
+		},
+		{
+			description: "removed lines that look like file headers",
+			diff: `--- a/README.md
++++ b/README.md
+@@ -1,3 +1,2 @@
+ # Title
+--- removed
+ text
+--- a/CHANGELOG.md
++++ b/CHANGELOG.md
+@@ -2 +2 @@
+-old
++new
+`,
+			want: []resource.Suggestion{
+				{Path: "README.md", StartLine: 2, Line: 2, Replacement: nil},
+				{Path: "CHANGELOG.md", StartLine: 2, Line: 2, Replacement: []string{"new"}},
+			},
+		},
+		{
+			description: "created and deleted files are skipped",
+			diff: `--- /dev/null
++++ b/new.go
+@@ -0,0 +1 @@
++package resource
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package resource
+`,
+			want: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := resource.ParseSuggestions([]byte(tc.diff))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
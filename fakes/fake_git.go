@@ -8,6 +8,17 @@ import (
 )
 
 type FakeGit struct {
+	BranchStub        func(string) error
+	branchMutex       sync.RWMutex
+	branchArgsForCall []struct {
+		arg1 string
+	}
+	branchReturns struct {
+		result1 error
+	}
+	branchReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CheckoutStub        func(string, string, bool) error
 	checkoutMutex       sync.RWMutex
 	checkoutArgsForCall []struct {
@@ -21,13 +32,67 @@ type FakeGit struct {
 	checkoutReturnsOnCall map[int]struct {
 		result1 error
 	}
-	FetchStub        func(string, int, int, bool) error
+	CommitMessagesStub        func(string, string) ([]string, error)
+	commitMessagesMutex       sync.RWMutex
+	commitMessagesArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	commitMessagesReturns struct {
+		result1 []string
+		result2 error
+	}
+	commitMessagesReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	ConfigStub        func(string, string) error
+	configMutex       sync.RWMutex
+	configArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	configReturns struct {
+		result1 error
+	}
+	configReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeepenStub        func(string, string, int, int) error
+	deepenMutex       sync.RWMutex
+	deepenArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 int
+	}
+	deepenReturns struct {
+		result1 error
+	}
+	deepenReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DiffStub        func(string, string, string) error
+	diffMutex       sync.RWMutex
+	diffArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	diffReturns struct {
+		result1 error
+	}
+	diffReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FetchStub        func(string, int, int, bool, string) error
 	fetchMutex       sync.RWMutex
 	fetchArgsForCall []struct {
 		arg1 string
 		arg2 int
 		arg3 int
 		arg4 bool
+		arg5 string
 	}
 	fetchReturns struct {
 		result1 error
@@ -35,6 +100,19 @@ type FakeGit struct {
 	fetchReturnsOnCall map[int]struct {
 		result1 error
 	}
+	FetchCommitStub        func(string, string, int) error
+	fetchCommitMutex       sync.RWMutex
+	fetchCommitArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}
+	fetchCommitReturns struct {
+		result1 error
+	}
+	fetchCommitReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GitCryptUnlockStub        func(string) error
 	gitCryptUnlockMutex       sync.RWMutex
 	gitCryptUnlockArgsForCall []struct {
@@ -69,7 +147,35 @@ type FakeGit struct {
 	mergeReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PullStub        func(string, string, int, bool, bool) error
+	MergeBaseStub        func(string, string) (string, error)
+	mergeBaseMutex       sync.RWMutex
+	mergeBaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	mergeBaseReturns struct {
+		result1 string
+		result2 error
+	}
+	mergeBaseReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	MirrorStub        func(string, string, int, int) error
+	mirrorMutex       sync.RWMutex
+	mirrorArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 int
+	}
+	mirrorReturns struct {
+		result1 error
+	}
+	mirrorReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PullStub        func(string, string, int, bool, bool, string) error
 	pullMutex       sync.RWMutex
 	pullArgsForCall []struct {
 		arg1 string
@@ -77,6 +183,7 @@ type FakeGit struct {
 		arg3 int
 		arg4 bool
 		arg5 bool
+		arg6 string
 	}
 	pullReturns struct {
 		result1 error
@@ -110,10 +217,96 @@ type FakeGit struct {
 		result1 string
 		result2 error
 	}
+	ShowStub        func(string, string) ([]byte, error)
+	showMutex       sync.RWMutex
+	showArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	showReturns struct {
+		result1 []byte
+		result2 error
+	}
+	showReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	TrackBaseStub        func(string, string) error
+	trackBaseMutex       sync.RWMutex
+	trackBaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	trackBaseReturns struct {
+		result1 error
+	}
+	trackBaseReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeGit) Branch(arg1 string) error {
+	fake.branchMutex.Lock()
+	ret, specificReturn := fake.branchReturnsOnCall[len(fake.branchArgsForCall)]
+	fake.branchArgsForCall = append(fake.branchArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("Branch", []interface{}{arg1})
+	fake.branchMutex.Unlock()
+	if fake.BranchStub != nil {
+		return fake.BranchStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.branchReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) BranchCallCount() int {
+	fake.branchMutex.RLock()
+	defer fake.branchMutex.RUnlock()
+	return len(fake.branchArgsForCall)
+}
+
+func (fake *FakeGit) BranchCalls(stub func(string) error) {
+	fake.branchMutex.Lock()
+	defer fake.branchMutex.Unlock()
+	fake.BranchStub = stub
+}
+
+func (fake *FakeGit) BranchArgsForCall(i int) string {
+	fake.branchMutex.RLock()
+	defer fake.branchMutex.RUnlock()
+	argsForCall := fake.branchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGit) BranchReturns(result1 error) {
+	fake.branchMutex.Lock()
+	defer fake.branchMutex.Unlock()
+	fake.BranchStub = nil
+	fake.branchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) BranchReturnsOnCall(i int, result1 error) {
+	fake.branchMutex.Lock()
+	defer fake.branchMutex.Unlock()
+	fake.BranchStub = nil
+	if fake.branchReturnsOnCall == nil {
+		fake.branchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.branchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGit) Checkout(arg1 string, arg2 string, arg3 bool) error {
 	fake.checkoutMutex.Lock()
 	ret, specificReturn := fake.checkoutReturnsOnCall[len(fake.checkoutArgsForCall)]
@@ -176,7 +369,257 @@ func (fake *FakeGit) CheckoutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeGit) Fetch(arg1 string, arg2 int, arg3 int, arg4 bool) error {
+func (fake *FakeGit) CommitMessages(arg1 string, arg2 string) ([]string, error) {
+	fake.commitMessagesMutex.Lock()
+	ret, specificReturn := fake.commitMessagesReturnsOnCall[len(fake.commitMessagesArgsForCall)]
+	fake.commitMessagesArgsForCall = append(fake.commitMessagesArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("CommitMessages", []interface{}{arg1, arg2})
+	fake.commitMessagesMutex.Unlock()
+	if fake.CommitMessagesStub != nil {
+		return fake.CommitMessagesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.commitMessagesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGit) CommitMessagesCallCount() int {
+	fake.commitMessagesMutex.RLock()
+	defer fake.commitMessagesMutex.RUnlock()
+	return len(fake.commitMessagesArgsForCall)
+}
+
+func (fake *FakeGit) CommitMessagesCalls(stub func(string, string) ([]string, error)) {
+	fake.commitMessagesMutex.Lock()
+	defer fake.commitMessagesMutex.Unlock()
+	fake.CommitMessagesStub = stub
+}
+
+func (fake *FakeGit) CommitMessagesArgsForCall(i int) (string, string) {
+	fake.commitMessagesMutex.RLock()
+	defer fake.commitMessagesMutex.RUnlock()
+	argsForCall := fake.commitMessagesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGit) CommitMessagesReturns(result1 []string, result2 error) {
+	fake.commitMessagesMutex.Lock()
+	defer fake.commitMessagesMutex.Unlock()
+	fake.CommitMessagesStub = nil
+	fake.commitMessagesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) CommitMessagesReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.commitMessagesMutex.Lock()
+	defer fake.commitMessagesMutex.Unlock()
+	fake.CommitMessagesStub = nil
+	if fake.commitMessagesReturnsOnCall == nil {
+		fake.commitMessagesReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.commitMessagesReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) Config(arg1 string, arg2 string) error {
+	fake.configMutex.Lock()
+	ret, specificReturn := fake.configReturnsOnCall[len(fake.configArgsForCall)]
+	fake.configArgsForCall = append(fake.configArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("Config", []interface{}{arg1, arg2})
+	fake.configMutex.Unlock()
+	if fake.ConfigStub != nil {
+		return fake.ConfigStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.configReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) ConfigCallCount() int {
+	fake.configMutex.RLock()
+	defer fake.configMutex.RUnlock()
+	return len(fake.configArgsForCall)
+}
+
+func (fake *FakeGit) ConfigCalls(stub func(string, string) error) {
+	fake.configMutex.Lock()
+	defer fake.configMutex.Unlock()
+	fake.ConfigStub = stub
+}
+
+func (fake *FakeGit) ConfigArgsForCall(i int) (string, string) {
+	fake.configMutex.RLock()
+	defer fake.configMutex.RUnlock()
+	argsForCall := fake.configArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGit) ConfigReturns(result1 error) {
+	fake.configMutex.Lock()
+	defer fake.configMutex.Unlock()
+	fake.ConfigStub = nil
+	fake.configReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) ConfigReturnsOnCall(i int, result1 error) {
+	fake.configMutex.Lock()
+	defer fake.configMutex.Unlock()
+	fake.ConfigStub = nil
+	if fake.configReturnsOnCall == nil {
+		fake.configReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.configReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) Deepen(arg1 string, arg2 string, arg3 int, arg4 int) error {
+	fake.deepenMutex.Lock()
+	ret, specificReturn := fake.deepenReturnsOnCall[len(fake.deepenArgsForCall)]
+	fake.deepenArgsForCall = append(fake.deepenArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Deepen", []interface{}{arg1, arg2, arg3, arg4})
+	fake.deepenMutex.Unlock()
+	if fake.DeepenStub != nil {
+		return fake.DeepenStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deepenReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) DeepenCallCount() int {
+	fake.deepenMutex.RLock()
+	defer fake.deepenMutex.RUnlock()
+	return len(fake.deepenArgsForCall)
+}
+
+func (fake *FakeGit) DeepenCalls(stub func(string, string, int, int) error) {
+	fake.deepenMutex.Lock()
+	defer fake.deepenMutex.Unlock()
+	fake.DeepenStub = stub
+}
+
+func (fake *FakeGit) DeepenArgsForCall(i int) (string, string, int, int) {
+	fake.deepenMutex.RLock()
+	defer fake.deepenMutex.RUnlock()
+	argsForCall := fake.deepenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGit) DeepenReturns(result1 error) {
+	fake.deepenMutex.Lock()
+	defer fake.deepenMutex.Unlock()
+	fake.DeepenStub = nil
+	fake.deepenReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) DeepenReturnsOnCall(i int, result1 error) {
+	fake.deepenMutex.Lock()
+	defer fake.deepenMutex.Unlock()
+	fake.DeepenStub = nil
+	if fake.deepenReturnsOnCall == nil {
+		fake.deepenReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deepenReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) Diff(arg1 string, arg2 string, arg3 string) error {
+	fake.diffMutex.Lock()
+	ret, specificReturn := fake.diffReturnsOnCall[len(fake.diffArgsForCall)]
+	fake.diffArgsForCall = append(fake.diffArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Diff", []interface{}{arg1, arg2, arg3})
+	fake.diffMutex.Unlock()
+	if fake.DiffStub != nil {
+		return fake.DiffStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.diffReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) DiffCallCount() int {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	return len(fake.diffArgsForCall)
+}
+
+func (fake *FakeGit) DiffCalls(stub func(string, string, string) error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = stub
+}
+
+func (fake *FakeGit) DiffArgsForCall(i int) (string, string, string) {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	argsForCall := fake.diffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGit) DiffReturns(result1 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	fake.diffReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) DiffReturnsOnCall(i int, result1 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	if fake.diffReturnsOnCall == nil {
+		fake.diffReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.diffReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) Fetch(arg1 string, arg2 int, arg3 int, arg4 bool, arg5 string) error {
 	fake.fetchMutex.Lock()
 	ret, specificReturn := fake.fetchReturnsOnCall[len(fake.fetchArgsForCall)]
 	fake.fetchArgsForCall = append(fake.fetchArgsForCall, struct {
@@ -184,11 +627,12 @@ func (fake *FakeGit) Fetch(arg1 string, arg2 int, arg3 int, arg4 bool) error {
 		arg2 int
 		arg3 int
 		arg4 bool
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("Fetch", []interface{}{arg1, arg2, arg3, arg4})
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("Fetch", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.fetchMutex.Unlock()
 	if fake.FetchStub != nil {
-		return fake.FetchStub(arg1, arg2, arg3, arg4)
+		return fake.FetchStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -203,17 +647,17 @@ func (fake *FakeGit) FetchCallCount() int {
 	return len(fake.fetchArgsForCall)
 }
 
-func (fake *FakeGit) FetchCalls(stub func(string, int, int, bool) error) {
+func (fake *FakeGit) FetchCalls(stub func(string, int, int, bool, string) error) {
 	fake.fetchMutex.Lock()
 	defer fake.fetchMutex.Unlock()
 	fake.FetchStub = stub
 }
 
-func (fake *FakeGit) FetchArgsForCall(i int) (string, int, int, bool) {
+func (fake *FakeGit) FetchArgsForCall(i int) (string, int, int, bool, string) {
 	fake.fetchMutex.RLock()
 	defer fake.fetchMutex.RUnlock()
 	argsForCall := fake.fetchArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeGit) FetchReturns(result1 error) {
@@ -239,6 +683,68 @@ func (fake *FakeGit) FetchReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGit) FetchCommit(arg1 string, arg2 string, arg3 int) error {
+	fake.fetchCommitMutex.Lock()
+	ret, specificReturn := fake.fetchCommitReturnsOnCall[len(fake.fetchCommitArgsForCall)]
+	fake.fetchCommitArgsForCall = append(fake.fetchCommitArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("FetchCommit", []interface{}{arg1, arg2, arg3})
+	fake.fetchCommitMutex.Unlock()
+	if fake.FetchCommitStub != nil {
+		return fake.FetchCommitStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.fetchCommitReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) FetchCommitCallCount() int {
+	fake.fetchCommitMutex.RLock()
+	defer fake.fetchCommitMutex.RUnlock()
+	return len(fake.fetchCommitArgsForCall)
+}
+
+func (fake *FakeGit) FetchCommitCalls(stub func(string, string, int) error) {
+	fake.fetchCommitMutex.Lock()
+	defer fake.fetchCommitMutex.Unlock()
+	fake.FetchCommitStub = stub
+}
+
+func (fake *FakeGit) FetchCommitArgsForCall(i int) (string, string, int) {
+	fake.fetchCommitMutex.RLock()
+	defer fake.fetchCommitMutex.RUnlock()
+	argsForCall := fake.fetchCommitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGit) FetchCommitReturns(result1 error) {
+	fake.fetchCommitMutex.Lock()
+	defer fake.fetchCommitMutex.Unlock()
+	fake.FetchCommitStub = nil
+	fake.fetchCommitReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) FetchCommitReturnsOnCall(i int, result1 error) {
+	fake.fetchCommitMutex.Lock()
+	defer fake.fetchCommitMutex.Unlock()
+	fake.FetchCommitStub = nil
+	if fake.fetchCommitReturnsOnCall == nil {
+		fake.fetchCommitReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.fetchCommitReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGit) GitCryptUnlock(arg1 string) error {
 	fake.gitCryptUnlockMutex.Lock()
 	ret, specificReturn := fake.gitCryptUnlockReturnsOnCall[len(fake.gitCryptUnlockArgsForCall)]
@@ -420,7 +926,134 @@ func (fake *FakeGit) MergeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeGit) Pull(arg1 string, arg2 string, arg3 int, arg4 bool, arg5 bool) error {
+func (fake *FakeGit) MergeBase(arg1 string, arg2 string) (string, error) {
+	fake.mergeBaseMutex.Lock()
+	ret, specificReturn := fake.mergeBaseReturnsOnCall[len(fake.mergeBaseArgsForCall)]
+	fake.mergeBaseArgsForCall = append(fake.mergeBaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("MergeBase", []interface{}{arg1, arg2})
+	fake.mergeBaseMutex.Unlock()
+	if fake.MergeBaseStub != nil {
+		return fake.MergeBaseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.mergeBaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGit) MergeBaseCallCount() int {
+	fake.mergeBaseMutex.RLock()
+	defer fake.mergeBaseMutex.RUnlock()
+	return len(fake.mergeBaseArgsForCall)
+}
+
+func (fake *FakeGit) MergeBaseCalls(stub func(string, string) (string, error)) {
+	fake.mergeBaseMutex.Lock()
+	defer fake.mergeBaseMutex.Unlock()
+	fake.MergeBaseStub = stub
+}
+
+func (fake *FakeGit) MergeBaseArgsForCall(i int) (string, string) {
+	fake.mergeBaseMutex.RLock()
+	defer fake.mergeBaseMutex.RUnlock()
+	argsForCall := fake.mergeBaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGit) MergeBaseReturns(result1 string, result2 error) {
+	fake.mergeBaseMutex.Lock()
+	defer fake.mergeBaseMutex.Unlock()
+	fake.MergeBaseStub = nil
+	fake.mergeBaseReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) MergeBaseReturnsOnCall(i int, result1 string, result2 error) {
+	fake.mergeBaseMutex.Lock()
+	defer fake.mergeBaseMutex.Unlock()
+	fake.MergeBaseStub = nil
+	if fake.mergeBaseReturnsOnCall == nil {
+		fake.mergeBaseReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.mergeBaseReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) Mirror(arg1 string, arg2 string, arg3 int, arg4 int) error {
+	fake.mirrorMutex.Lock()
+	ret, specificReturn := fake.mirrorReturnsOnCall[len(fake.mirrorArgsForCall)]
+	fake.mirrorArgsForCall = append(fake.mirrorArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Mirror", []interface{}{arg1, arg2, arg3, arg4})
+	fake.mirrorMutex.Unlock()
+	if fake.MirrorStub != nil {
+		return fake.MirrorStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.mirrorReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) MirrorCallCount() int {
+	fake.mirrorMutex.RLock()
+	defer fake.mirrorMutex.RUnlock()
+	return len(fake.mirrorArgsForCall)
+}
+
+func (fake *FakeGit) MirrorCalls(stub func(string, string, int, int) error) {
+	fake.mirrorMutex.Lock()
+	defer fake.mirrorMutex.Unlock()
+	fake.MirrorStub = stub
+}
+
+func (fake *FakeGit) MirrorArgsForCall(i int) (string, string, int, int) {
+	fake.mirrorMutex.RLock()
+	defer fake.mirrorMutex.RUnlock()
+	argsForCall := fake.mirrorArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGit) MirrorReturns(result1 error) {
+	fake.mirrorMutex.Lock()
+	defer fake.mirrorMutex.Unlock()
+	fake.MirrorStub = nil
+	fake.mirrorReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) MirrorReturnsOnCall(i int, result1 error) {
+	fake.mirrorMutex.Lock()
+	defer fake.mirrorMutex.Unlock()
+	fake.MirrorStub = nil
+	if fake.mirrorReturnsOnCall == nil {
+		fake.mirrorReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.mirrorReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) Pull(arg1 string, arg2 string, arg3 int, arg4 bool, arg5 bool, arg6 string) error {
 	fake.pullMutex.Lock()
 	ret, specificReturn := fake.pullReturnsOnCall[len(fake.pullArgsForCall)]
 	fake.pullArgsForCall = append(fake.pullArgsForCall, struct {
@@ -429,11 +1062,12 @@ func (fake *FakeGit) Pull(arg1 string, arg2 string, arg3 int, arg4 bool, arg5 bo
 		arg3 int
 		arg4 bool
 		arg5 bool
-	}{arg1, arg2, arg3, arg4, arg5})
-	fake.recordInvocation("Pull", []interface{}{arg1, arg2, arg3, arg4, arg5})
+		arg6 string
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("Pull", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.pullMutex.Unlock()
 	if fake.PullStub != nil {
-		return fake.PullStub(arg1, arg2, arg3, arg4, arg5)
+		return fake.PullStub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1
@@ -448,17 +1082,17 @@ func (fake *FakeGit) PullCallCount() int {
 	return len(fake.pullArgsForCall)
 }
 
-func (fake *FakeGit) PullCalls(stub func(string, string, int, bool, bool) error) {
+func (fake *FakeGit) PullCalls(stub func(string, string, int, bool, bool, string) error) {
 	fake.pullMutex.Lock()
 	defer fake.pullMutex.Unlock()
 	fake.PullStub = stub
 }
 
-func (fake *FakeGit) PullArgsForCall(i int) (string, string, int, bool, bool) {
+func (fake *FakeGit) PullArgsForCall(i int) (string, string, int, bool, bool, string) {
 	fake.pullMutex.RLock()
 	defer fake.pullMutex.RUnlock()
 	argsForCall := fake.pullArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeGit) PullReturns(result1 error) {
@@ -609,25 +1243,170 @@ func (fake *FakeGit) RevParseReturnsOnCall(i int, result1 string, result2 error)
 	}{result1, result2}
 }
 
+func (fake *FakeGit) Show(arg1 string, arg2 string) ([]byte, error) {
+	fake.showMutex.Lock()
+	ret, specificReturn := fake.showReturnsOnCall[len(fake.showArgsForCall)]
+	fake.showArgsForCall = append(fake.showArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("Show", []interface{}{arg1, arg2})
+	fake.showMutex.Unlock()
+	if fake.ShowStub != nil {
+		return fake.ShowStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.showReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGit) ShowCallCount() int {
+	fake.showMutex.RLock()
+	defer fake.showMutex.RUnlock()
+	return len(fake.showArgsForCall)
+}
+
+func (fake *FakeGit) ShowCalls(stub func(string, string) ([]byte, error)) {
+	fake.showMutex.Lock()
+	defer fake.showMutex.Unlock()
+	fake.ShowStub = stub
+}
+
+func (fake *FakeGit) ShowArgsForCall(i int) (string, string) {
+	fake.showMutex.RLock()
+	defer fake.showMutex.RUnlock()
+	argsForCall := fake.showArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGit) ShowReturns(result1 []byte, result2 error) {
+	fake.showMutex.Lock()
+	defer fake.showMutex.Unlock()
+	fake.ShowStub = nil
+	fake.showReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) ShowReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.showMutex.Lock()
+	defer fake.showMutex.Unlock()
+	fake.ShowStub = nil
+	if fake.showReturnsOnCall == nil {
+		fake.showReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.showReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) TrackBase(arg1 string, arg2 string) error {
+	fake.trackBaseMutex.Lock()
+	ret, specificReturn := fake.trackBaseReturnsOnCall[len(fake.trackBaseArgsForCall)]
+	fake.trackBaseArgsForCall = append(fake.trackBaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("TrackBase", []interface{}{arg1, arg2})
+	fake.trackBaseMutex.Unlock()
+	if fake.TrackBaseStub != nil {
+		return fake.TrackBaseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.trackBaseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) TrackBaseCallCount() int {
+	fake.trackBaseMutex.RLock()
+	defer fake.trackBaseMutex.RUnlock()
+	return len(fake.trackBaseArgsForCall)
+}
+
+func (fake *FakeGit) TrackBaseCalls(stub func(string, string) error) {
+	fake.trackBaseMutex.Lock()
+	defer fake.trackBaseMutex.Unlock()
+	fake.TrackBaseStub = stub
+}
+
+func (fake *FakeGit) TrackBaseArgsForCall(i int) (string, string) {
+	fake.trackBaseMutex.RLock()
+	defer fake.trackBaseMutex.RUnlock()
+	argsForCall := fake.trackBaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGit) TrackBaseReturns(result1 error) {
+	fake.trackBaseMutex.Lock()
+	defer fake.trackBaseMutex.Unlock()
+	fake.TrackBaseStub = nil
+	fake.trackBaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) TrackBaseReturnsOnCall(i int, result1 error) {
+	fake.trackBaseMutex.Lock()
+	defer fake.trackBaseMutex.Unlock()
+	fake.TrackBaseStub = nil
+	if fake.trackBaseReturnsOnCall == nil {
+		fake.trackBaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.trackBaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGit) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.branchMutex.RLock()
+	defer fake.branchMutex.RUnlock()
 	fake.checkoutMutex.RLock()
 	defer fake.checkoutMutex.RUnlock()
+	fake.commitMessagesMutex.RLock()
+	defer fake.commitMessagesMutex.RUnlock()
+	fake.configMutex.RLock()
+	defer fake.configMutex.RUnlock()
+	fake.deepenMutex.RLock()
+	defer fake.deepenMutex.RUnlock()
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
 	fake.fetchMutex.RLock()
 	defer fake.fetchMutex.RUnlock()
+	fake.fetchCommitMutex.RLock()
+	defer fake.fetchCommitMutex.RUnlock()
 	fake.gitCryptUnlockMutex.RLock()
 	defer fake.gitCryptUnlockMutex.RUnlock()
 	fake.initMutex.RLock()
 	defer fake.initMutex.RUnlock()
 	fake.mergeMutex.RLock()
 	defer fake.mergeMutex.RUnlock()
+	fake.mergeBaseMutex.RLock()
+	defer fake.mergeBaseMutex.RUnlock()
+	fake.mirrorMutex.RLock()
+	defer fake.mirrorMutex.RUnlock()
 	fake.pullMutex.RLock()
 	defer fake.pullMutex.RUnlock()
 	fake.rebaseMutex.RLock()
 	defer fake.rebaseMutex.RUnlock()
 	fake.revParseMutex.RLock()
 	defer fake.revParseMutex.RUnlock()
+	fake.showMutex.RLock()
+	defer fake.showMutex.RUnlock()
+	fake.trackBaseMutex.RLock()
+	defer fake.trackBaseMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,17 +2,240 @@
 package fakes
 
 import (
+	"regexp"
 	"sync"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 	resource "github.com/telia-oss/github-pr-resource"
 )
 
 type FakeGithub struct {
-	DeletePreviousCommentsStub        func(string) error
+	APIUsageStub        func() resource.APIUsage
+	aPIUsageMutex       sync.RWMutex
+	aPIUsageArgsForCall []struct {
+	}
+	aPIUsageReturns struct {
+		result1 resource.APIUsage
+	}
+	aPIUsageReturnsOnCall map[int]struct {
+		result1 resource.APIUsage
+	}
+	AddAssigneesStub        func(string, []string) error
+	addAssigneesMutex       sync.RWMutex
+	addAssigneesArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	addAssigneesReturns struct {
+		result1 error
+	}
+	addAssigneesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AddCommentReactionStub        func(int64, string) error
+	addCommentReactionMutex       sync.RWMutex
+	addCommentReactionArgsForCall []struct {
+		arg1 int64
+		arg2 string
+	}
+	addCommentReactionReturns struct {
+		result1 error
+	}
+	addCommentReactionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AddLabelsStub        func(string, []string) error
+	addLabelsMutex       sync.RWMutex
+	addLabelsArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	addLabelsReturns struct {
+		result1 error
+	}
+	addLabelsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AddToProjectStub        func(string, resource.Project) error
+	addToProjectMutex       sync.RWMutex
+	addToProjectArgsForCall []struct {
+		arg1 string
+		arg2 resource.Project
+	}
+	addToProjectReturns struct {
+		result1 error
+	}
+	addToProjectReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CloseIssueStub        func(string) error
+	closeIssueMutex       sync.RWMutex
+	closeIssueArgsForCall []struct {
+		arg1 string
+	}
+	closeIssueReturns struct {
+		result1 error
+	}
+	closeIssueReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ClosePullRequestStub        func(string) error
+	closePullRequestMutex       sync.RWMutex
+	closePullRequestArgsForCall []struct {
+		arg1 string
+	}
+	closePullRequestReturns struct {
+		result1 error
+	}
+	closePullRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CommentOnIssueStub        func(string, string) error
+	commentOnIssueMutex       sync.RWMutex
+	commentOnIssueArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	commentOnIssueReturns struct {
+		result1 error
+	}
+	commentOnIssueReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CompareCommitsStub        func(string, string) (*resource.CommitComparison, error)
+	compareCommitsMutex       sync.RWMutex
+	compareCommitsArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	compareCommitsReturns struct {
+		result1 *resource.CommitComparison
+		result2 error
+	}
+	compareCommitsReturnsOnCall map[int]struct {
+		result1 *resource.CommitComparison
+		result2 error
+	}
+	ConvertToDraftStub        func(string) error
+	convertToDraftMutex       sync.RWMutex
+	convertToDraftArgsForCall []struct {
+		arg1 string
+	}
+	convertToDraftReturns struct {
+		result1 error
+	}
+	convertToDraftReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateCommitCommentStub        func(string, string) (string, error)
+	createCommitCommentMutex       sync.RWMutex
+	createCommitCommentArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	createCommitCommentReturns struct {
+		result1 string
+		result2 error
+	}
+	createCommitCommentReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	CreateGistStub        func(string, string, string) (string, error)
+	createGistMutex       sync.RWMutex
+	createGistArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	createGistReturns struct {
+		result1 string
+		result2 error
+	}
+	createGistReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	CreateReleaseStub        func(resource.Release) error
+	createReleaseMutex       sync.RWMutex
+	createReleaseArgsForCall []struct {
+		arg1 resource.Release
+	}
+	createReleaseReturns struct {
+		result1 error
+	}
+	createReleaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateReviewStub        func(string, string, string, string) error
+	createReviewMutex       sync.RWMutex
+	createReviewArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	createReviewReturns struct {
+		result1 error
+	}
+	createReviewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateSuggestionsStub        func(string, string, string, []resource.Suggestion) error
+	createSuggestionsMutex       sync.RWMutex
+	createSuggestionsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []resource.Suggestion
+	}
+	createSuggestionsReturns struct {
+		result1 error
+	}
+	createSuggestionsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateTagStub        func(string, string, string) error
+	createTagMutex       sync.RWMutex
+	createTagArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	createTagReturns struct {
+		result1 error
+	}
+	createTagReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteBranchStub        func(string) error
+	deleteBranchMutex       sync.RWMutex
+	deleteBranchArgsForCall []struct {
+		arg1 string
+	}
+	deleteBranchReturns struct {
+		result1 error
+	}
+	deleteBranchReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteCommentStub        func(int64) error
+	deleteCommentMutex       sync.RWMutex
+	deleteCommentArgsForCall []struct {
+		arg1 int64
+	}
+	deleteCommentReturns struct {
+		result1 error
+	}
+	deleteCommentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeletePreviousCommentsStub        func(string, *regexp.Regexp) error
 	deletePreviousCommentsMutex       sync.RWMutex
 	deletePreviousCommentsArgsForCall []struct {
 		arg1 string
+		arg2 *regexp.Regexp
 	}
 	deletePreviousCommentsReturns struct {
 		result1 error
@@ -20,6 +243,71 @@ type FakeGithub struct {
 	deletePreviousCommentsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DismissReviewsStub        func(string, string) error
+	dismissReviewsMutex       sync.RWMutex
+	dismissReviewsArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	dismissReviewsReturns struct {
+		result1 error
+	}
+	dismissReviewsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DispatchStub        func(string, map[string]interface{}) error
+	dispatchMutex       sync.RWMutex
+	dispatchArgsForCall []struct {
+		arg1 string
+		arg2 map[string]interface{}
+	}
+	dispatchReturns struct {
+		result1 error
+	}
+	dispatchReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EnableAutoMergeStub        func(string, string) error
+	enableAutoMergeMutex       sync.RWMutex
+	enableAutoMergeArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	enableAutoMergeReturns struct {
+		result1 error
+	}
+	enableAutoMergeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindCommentStub        func(string, string) (string, error)
+	findCommentMutex       sync.RWMutex
+	findCommentArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	findCommentReturns struct {
+		result1 string
+		result2 error
+	}
+	findCommentReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	FindReviewThreadStub        func(string, string, int) (int64, error)
+	findReviewThreadMutex       sync.RWMutex
+	findReviewThreadArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}
+	findReviewThreadReturns struct {
+		result1 int64
+		result2 error
+	}
+	findReviewThreadReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
 	GetChangedFilesStub        func(string, string) ([]resource.ChangedFileObject, error)
 	getChangedFilesMutex       sync.RWMutex
 	getChangedFilesArgsForCall []struct {
@@ -34,6 +322,61 @@ type FakeGithub struct {
 		result1 []resource.ChangedFileObject
 		result2 error
 	}
+	GetCommitStatusStub        func(string, string, string) (*resource.CommitStatus, error)
+	getCommitStatusMutex       sync.RWMutex
+	getCommitStatusArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getCommitStatusReturns struct {
+		result1 *resource.CommitStatus
+		result2 error
+	}
+	getCommitStatusReturnsOnCall map[int]struct {
+		result1 *resource.CommitStatus
+		result2 error
+	}
+	GetFileContentStub        func(string, string) ([]byte, error)
+	getFileContentMutex       sync.RWMutex
+	getFileContentArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getFileContentReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getFileContentReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetLatestCommentStub        func(string) (string, error)
+	getLatestCommentMutex       sync.RWMutex
+	getLatestCommentArgsForCall []struct {
+		arg1 string
+	}
+	getLatestCommentReturns struct {
+		result1 string
+		result2 error
+	}
+	getLatestCommentReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetLinkedIssuesStub        func(string) ([]resource.IssueObject, error)
+	getLinkedIssuesMutex       sync.RWMutex
+	getLinkedIssuesArgsForCall []struct {
+		arg1 string
+	}
+	getLinkedIssuesReturns struct {
+		result1 []resource.IssueObject
+		result2 error
+	}
+	getLinkedIssuesReturnsOnCall map[int]struct {
+		result1 []resource.IssueObject
+		result2 error
+	}
 	GetPullRequestStub        func(string, string) (*resource.PullRequest, error)
 	getPullRequestMutex       sync.RWMutex
 	getPullRequestArgsForCall []struct {
@@ -48,6 +391,44 @@ type FakeGithub struct {
 		result1 *resource.PullRequest
 		result2 error
 	}
+	GetPullRequestDetailsStub        func(string) (*resource.PullRequestDetailsObject, error)
+	getPullRequestDetailsMutex       sync.RWMutex
+	getPullRequestDetailsArgsForCall []struct {
+		arg1 string
+	}
+	getPullRequestDetailsReturns struct {
+		result1 *resource.PullRequestDetailsObject
+		result2 error
+	}
+	getPullRequestDetailsReturnsOnCall map[int]struct {
+		result1 *resource.PullRequestDetailsObject
+		result2 error
+	}
+	GetTokenInfoStub        func() (*resource.TokenInfo, error)
+	getTokenInfoMutex       sync.RWMutex
+	getTokenInfoArgsForCall []struct {
+	}
+	getTokenInfoReturns struct {
+		result1 *resource.TokenInfo
+		result2 error
+	}
+	getTokenInfoReturnsOnCall map[int]struct {
+		result1 *resource.TokenInfo
+		result2 error
+	}
+	ListCommitsStub        func(int) ([]resource.PullRequestCommit, error)
+	listCommitsMutex       sync.RWMutex
+	listCommitsArgsForCall []struct {
+		arg1 int
+	}
+	listCommitsReturns struct {
+		result1 []resource.PullRequestCommit
+		result2 error
+	}
+	listCommitsReturnsOnCall map[int]struct {
+		result1 []resource.PullRequestCommit
+		result2 error
+	}
 	ListModifiedFilesStub        func(int) ([]string, error)
 	listModifiedFilesMutex       sync.RWMutex
 	listModifiedFilesArgsForCall []struct {
@@ -61,10 +442,12 @@ type FakeGithub struct {
 		result1 []string
 		result2 error
 	}
-	ListPullRequestsStub        func([]githubv4.PullRequestState) ([]*resource.PullRequest, error)
+	ListPullRequestsStub        func([]githubv4.PullRequestState, resource.PullRequestFields, time.Time) ([]*resource.PullRequest, error)
 	listPullRequestsMutex       sync.RWMutex
 	listPullRequestsArgsForCall []struct {
 		arg1 []githubv4.PullRequestState
+		arg2 resource.PullRequestFields
+		arg3 time.Time
 	}
 	listPullRequestsReturns struct {
 		result1 []*resource.PullRequest
@@ -74,84 +457,1403 @@ type FakeGithub struct {
 		result1 []*resource.PullRequest
 		result2 error
 	}
-	PostCommentStub        func(string, string) error
-	postCommentMutex       sync.RWMutex
-	postCommentArgsForCall []struct {
+	LockPullRequestStub        func(string, string) error
+	lockPullRequestMutex       sync.RWMutex
+	lockPullRequestArgsForCall []struct {
 		arg1 string
 		arg2 string
 	}
-	postCommentReturns struct {
+	lockPullRequestReturns struct {
 		result1 error
 	}
-	postCommentReturnsOnCall map[int]struct {
+	lockPullRequestReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UpdateCommitStatusStub        func(string, string, string, string, string, string) error
-	updateCommitStatusMutex       sync.RWMutex
-	updateCommitStatusArgsForCall []struct {
+	MarkReadyForReviewStub        func(string) error
+	markReadyForReviewMutex       sync.RWMutex
+	markReadyForReviewArgsForCall []struct {
+		arg1 string
+	}
+	markReadyForReviewReturns struct {
+		result1 error
+	}
+	markReadyForReviewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	MergePullRequestStub        func(string, string, string, string, string) (string, error)
+	mergePullRequestMutex       sync.RWMutex
+	mergePullRequestArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 string
 		arg4 string
 		arg5 string
-		arg6 string
 	}
-	updateCommitStatusReturns struct {
+	mergePullRequestReturns struct {
+		result1 string
+		result2 error
+	}
+	mergePullRequestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	MinimizeCommentStub        func(int64) error
+	minimizeCommentMutex       sync.RWMutex
+	minimizeCommentArgsForCall []struct {
+		arg1 int64
+	}
+	minimizeCommentReturns struct {
 		result1 error
 	}
-	updateCommitStatusReturnsOnCall map[int]struct {
+	minimizeCommentReturnsOnCall map[int]struct {
 		result1 error
 	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
-}
-
-func (fake *FakeGithub) DeletePreviousComments(arg1 string) error {
-	fake.deletePreviousCommentsMutex.Lock()
-	ret, specificReturn := fake.deletePreviousCommentsReturnsOnCall[len(fake.deletePreviousCommentsArgsForCall)]
-	fake.deletePreviousCommentsArgsForCall = append(fake.deletePreviousCommentsArgsForCall, struct {
+	MinimizePreviousCommentsStub        func(string, *regexp.Regexp) error
+	minimizePreviousCommentsMutex       sync.RWMutex
+	minimizePreviousCommentsArgsForCall []struct {
 		arg1 string
-	}{arg1})
-	fake.recordInvocation("DeletePreviousComments", []interface{}{arg1})
-	fake.deletePreviousCommentsMutex.Unlock()
-	if fake.DeletePreviousCommentsStub != nil {
-		return fake.DeletePreviousCommentsStub(arg1)
+		arg2 *regexp.Regexp
 	}
-	if specificReturn {
-		return ret.result1
+	minimizePreviousCommentsReturns struct {
+		result1 error
 	}
-	fakeReturns := fake.deletePreviousCommentsReturns
-	return fakeReturns.result1
-}
-
-func (fake *FakeGithub) DeletePreviousCommentsCallCount() int {
-	fake.deletePreviousCommentsMutex.RLock()
-	defer fake.deletePreviousCommentsMutex.RUnlock()
-	return len(fake.deletePreviousCommentsArgsForCall)
-}
-
-func (fake *FakeGithub) DeletePreviousCommentsCalls(stub func(string) error) {
-	fake.deletePreviousCommentsMutex.Lock()
-	defer fake.deletePreviousCommentsMutex.Unlock()
-	fake.DeletePreviousCommentsStub = stub
-}
-
-func (fake *FakeGithub) DeletePreviousCommentsArgsForCall(i int) string {
-	fake.deletePreviousCommentsMutex.RLock()
-	defer fake.deletePreviousCommentsMutex.RUnlock()
-	argsForCall := fake.deletePreviousCommentsArgsForCall[i]
-	return argsForCall.arg1
-}
-
-func (fake *FakeGithub) DeletePreviousCommentsReturns(result1 error) {
-	fake.deletePreviousCommentsMutex.Lock()
-	defer fake.deletePreviousCommentsMutex.Unlock()
-	fake.DeletePreviousCommentsStub = nil
-	fake.deletePreviousCommentsReturns = struct {
+	minimizePreviousCommentsReturnsOnCall map[int]struct {
 		result1 error
-	}{result1}
-}
-
+	}
+	PostCommentStub        func(string, string) (string, error)
+	postCommentMutex       sync.RWMutex
+	postCommentArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	postCommentReturns struct {
+		result1 string
+		result2 error
+	}
+	postCommentReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	RemoveLabelsStub        func(string, []string) error
+	removeLabelsMutex       sync.RWMutex
+	removeLabelsArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	removeLabelsReturns struct {
+		result1 error
+	}
+	removeLabelsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReopenPullRequestStub        func(string) error
+	reopenPullRequestMutex       sync.RWMutex
+	reopenPullRequestArgsForCall []struct {
+		arg1 string
+	}
+	reopenPullRequestReturns struct {
+		result1 error
+	}
+	reopenPullRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReplyToReviewCommentStub        func(string, int64, string) error
+	replyToReviewCommentMutex       sync.RWMutex
+	replyToReviewCommentArgsForCall []struct {
+		arg1 string
+		arg2 int64
+		arg3 string
+	}
+	replyToReviewCommentReturns struct {
+		result1 error
+	}
+	replyToReviewCommentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RequestReviewersStub        func(string, []string, []string) error
+	requestReviewersMutex       sync.RWMutex
+	requestReviewersArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}
+	requestReviewersReturns struct {
+		result1 error
+	}
+	requestReviewersReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetMilestoneStub        func(string, string, bool) error
+	setMilestoneMutex       sync.RWMutex
+	setMilestoneArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}
+	setMilestoneReturns struct {
+		result1 error
+	}
+	setMilestoneReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnlockPullRequestStub        func(string) error
+	unlockPullRequestMutex       sync.RWMutex
+	unlockPullRequestArgsForCall []struct {
+		arg1 string
+	}
+	unlockPullRequestReturns struct {
+		result1 error
+	}
+	unlockPullRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateBranchStub        func(string, string, string) error
+	updateBranchMutex       sync.RWMutex
+	updateBranchArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	updateBranchReturns struct {
+		result1 error
+	}
+	updateBranchReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateCheckRunStub        func(string, resource.CheckRun) (int64, error)
+	updateCheckRunMutex       sync.RWMutex
+	updateCheckRunArgsForCall []struct {
+		arg1 string
+		arg2 resource.CheckRun
+	}
+	updateCheckRunReturns struct {
+		result1 int64
+		result2 error
+	}
+	updateCheckRunReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	UpdateCommitStatusStub        func(string, string, string, string, string, string) error
+	updateCommitStatusMutex       sync.RWMutex
+	updateCommitStatusArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 string
+	}
+	updateCommitStatusReturns struct {
+		result1 error
+	}
+	updateCommitStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateDeploymentStub        func(string, resource.Deployment) error
+	updateDeploymentMutex       sync.RWMutex
+	updateDeploymentArgsForCall []struct {
+		arg1 string
+		arg2 resource.Deployment
+	}
+	updateDeploymentReturns struct {
+		result1 error
+	}
+	updateDeploymentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdatePullRequestStub        func(string, string, string) error
+	updatePullRequestMutex       sync.RWMutex
+	updatePullRequestArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	updatePullRequestReturns struct {
+		result1 error
+	}
+	updatePullRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpsertCommentStub        func(string, string, string) (string, error)
+	upsertCommentMutex       sync.RWMutex
+	upsertCommentArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	upsertCommentReturns struct {
+		result1 string
+		result2 error
+	}
+	upsertCommentReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGithub) APIUsage() resource.APIUsage {
+	fake.aPIUsageMutex.Lock()
+	ret, specificReturn := fake.aPIUsageReturnsOnCall[len(fake.aPIUsageArgsForCall)]
+	fake.aPIUsageArgsForCall = append(fake.aPIUsageArgsForCall, struct {
+	}{})
+	fake.recordInvocation("APIUsage", []interface{}{})
+	fake.aPIUsageMutex.Unlock()
+	if fake.APIUsageStub != nil {
+		return fake.APIUsageStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.aPIUsageReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) APIUsageCallCount() int {
+	fake.aPIUsageMutex.RLock()
+	defer fake.aPIUsageMutex.RUnlock()
+	return len(fake.aPIUsageArgsForCall)
+}
+
+func (fake *FakeGithub) APIUsageCalls(stub func() resource.APIUsage) {
+	fake.aPIUsageMutex.Lock()
+	defer fake.aPIUsageMutex.Unlock()
+	fake.APIUsageStub = stub
+}
+
+func (fake *FakeGithub) APIUsageReturns(result1 resource.APIUsage) {
+	fake.aPIUsageMutex.Lock()
+	defer fake.aPIUsageMutex.Unlock()
+	fake.APIUsageStub = nil
+	fake.aPIUsageReturns = struct {
+		result1 resource.APIUsage
+	}{result1}
+}
+
+func (fake *FakeGithub) APIUsageReturnsOnCall(i int, result1 resource.APIUsage) {
+	fake.aPIUsageMutex.Lock()
+	defer fake.aPIUsageMutex.Unlock()
+	fake.APIUsageStub = nil
+	if fake.aPIUsageReturnsOnCall == nil {
+		fake.aPIUsageReturnsOnCall = make(map[int]struct {
+			result1 resource.APIUsage
+		})
+	}
+	fake.aPIUsageReturnsOnCall[i] = struct {
+		result1 resource.APIUsage
+	}{result1}
+}
+
+func (fake *FakeGithub) AddAssignees(arg1 string, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.addAssigneesMutex.Lock()
+	ret, specificReturn := fake.addAssigneesReturnsOnCall[len(fake.addAssigneesArgsForCall)]
+	fake.addAssigneesArgsForCall = append(fake.addAssigneesArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	fake.recordInvocation("AddAssignees", []interface{}{arg1, arg2Copy})
+	fake.addAssigneesMutex.Unlock()
+	if fake.AddAssigneesStub != nil {
+		return fake.AddAssigneesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addAssigneesReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) AddAssigneesCallCount() int {
+	fake.addAssigneesMutex.RLock()
+	defer fake.addAssigneesMutex.RUnlock()
+	return len(fake.addAssigneesArgsForCall)
+}
+
+func (fake *FakeGithub) AddAssigneesCalls(stub func(string, []string) error) {
+	fake.addAssigneesMutex.Lock()
+	defer fake.addAssigneesMutex.Unlock()
+	fake.AddAssigneesStub = stub
+}
+
+func (fake *FakeGithub) AddAssigneesArgsForCall(i int) (string, []string) {
+	fake.addAssigneesMutex.RLock()
+	defer fake.addAssigneesMutex.RUnlock()
+	argsForCall := fake.addAssigneesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) AddAssigneesReturns(result1 error) {
+	fake.addAssigneesMutex.Lock()
+	defer fake.addAssigneesMutex.Unlock()
+	fake.AddAssigneesStub = nil
+	fake.addAssigneesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) AddAssigneesReturnsOnCall(i int, result1 error) {
+	fake.addAssigneesMutex.Lock()
+	defer fake.addAssigneesMutex.Unlock()
+	fake.AddAssigneesStub = nil
+	if fake.addAssigneesReturnsOnCall == nil {
+		fake.addAssigneesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addAssigneesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) AddCommentReaction(arg1 int64, arg2 string) error {
+	fake.addCommentReactionMutex.Lock()
+	ret, specificReturn := fake.addCommentReactionReturnsOnCall[len(fake.addCommentReactionArgsForCall)]
+	fake.addCommentReactionArgsForCall = append(fake.addCommentReactionArgsForCall, struct {
+		arg1 int64
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("AddCommentReaction", []interface{}{arg1, arg2})
+	fake.addCommentReactionMutex.Unlock()
+	if fake.AddCommentReactionStub != nil {
+		return fake.AddCommentReactionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addCommentReactionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) AddCommentReactionCallCount() int {
+	fake.addCommentReactionMutex.RLock()
+	defer fake.addCommentReactionMutex.RUnlock()
+	return len(fake.addCommentReactionArgsForCall)
+}
+
+func (fake *FakeGithub) AddCommentReactionCalls(stub func(int64, string) error) {
+	fake.addCommentReactionMutex.Lock()
+	defer fake.addCommentReactionMutex.Unlock()
+	fake.AddCommentReactionStub = stub
+}
+
+func (fake *FakeGithub) AddCommentReactionArgsForCall(i int) (int64, string) {
+	fake.addCommentReactionMutex.RLock()
+	defer fake.addCommentReactionMutex.RUnlock()
+	argsForCall := fake.addCommentReactionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) AddCommentReactionReturns(result1 error) {
+	fake.addCommentReactionMutex.Lock()
+	defer fake.addCommentReactionMutex.Unlock()
+	fake.AddCommentReactionStub = nil
+	fake.addCommentReactionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) AddCommentReactionReturnsOnCall(i int, result1 error) {
+	fake.addCommentReactionMutex.Lock()
+	defer fake.addCommentReactionMutex.Unlock()
+	fake.AddCommentReactionStub = nil
+	if fake.addCommentReactionReturnsOnCall == nil {
+		fake.addCommentReactionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addCommentReactionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) AddLabels(arg1 string, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.addLabelsMutex.Lock()
+	ret, specificReturn := fake.addLabelsReturnsOnCall[len(fake.addLabelsArgsForCall)]
+	fake.addLabelsArgsForCall = append(fake.addLabelsArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	fake.recordInvocation("AddLabels", []interface{}{arg1, arg2Copy})
+	fake.addLabelsMutex.Unlock()
+	if fake.AddLabelsStub != nil {
+		return fake.AddLabelsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addLabelsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) AddLabelsCallCount() int {
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	return len(fake.addLabelsArgsForCall)
+}
+
+func (fake *FakeGithub) AddLabelsCalls(stub func(string, []string) error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = stub
+}
+
+func (fake *FakeGithub) AddLabelsArgsForCall(i int) (string, []string) {
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	argsForCall := fake.addLabelsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) AddLabelsReturns(result1 error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = nil
+	fake.addLabelsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) AddLabelsReturnsOnCall(i int, result1 error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = nil
+	if fake.addLabelsReturnsOnCall == nil {
+		fake.addLabelsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addLabelsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) AddToProject(arg1 string, arg2 resource.Project) error {
+	fake.addToProjectMutex.Lock()
+	ret, specificReturn := fake.addToProjectReturnsOnCall[len(fake.addToProjectArgsForCall)]
+	fake.addToProjectArgsForCall = append(fake.addToProjectArgsForCall, struct {
+		arg1 string
+		arg2 resource.Project
+	}{arg1, arg2})
+	fake.recordInvocation("AddToProject", []interface{}{arg1, arg2})
+	fake.addToProjectMutex.Unlock()
+	if fake.AddToProjectStub != nil {
+		return fake.AddToProjectStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addToProjectReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) AddToProjectCallCount() int {
+	fake.addToProjectMutex.RLock()
+	defer fake.addToProjectMutex.RUnlock()
+	return len(fake.addToProjectArgsForCall)
+}
+
+func (fake *FakeGithub) AddToProjectCalls(stub func(string, resource.Project) error) {
+	fake.addToProjectMutex.Lock()
+	defer fake.addToProjectMutex.Unlock()
+	fake.AddToProjectStub = stub
+}
+
+func (fake *FakeGithub) AddToProjectArgsForCall(i int) (string, resource.Project) {
+	fake.addToProjectMutex.RLock()
+	defer fake.addToProjectMutex.RUnlock()
+	argsForCall := fake.addToProjectArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) AddToProjectReturns(result1 error) {
+	fake.addToProjectMutex.Lock()
+	defer fake.addToProjectMutex.Unlock()
+	fake.AddToProjectStub = nil
+	fake.addToProjectReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) AddToProjectReturnsOnCall(i int, result1 error) {
+	fake.addToProjectMutex.Lock()
+	defer fake.addToProjectMutex.Unlock()
+	fake.AddToProjectStub = nil
+	if fake.addToProjectReturnsOnCall == nil {
+		fake.addToProjectReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addToProjectReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CloseIssue(arg1 string) error {
+	fake.closeIssueMutex.Lock()
+	ret, specificReturn := fake.closeIssueReturnsOnCall[len(fake.closeIssueArgsForCall)]
+	fake.closeIssueArgsForCall = append(fake.closeIssueArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("CloseIssue", []interface{}{arg1})
+	fake.closeIssueMutex.Unlock()
+	if fake.CloseIssueStub != nil {
+		return fake.CloseIssueStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.closeIssueReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) CloseIssueCallCount() int {
+	fake.closeIssueMutex.RLock()
+	defer fake.closeIssueMutex.RUnlock()
+	return len(fake.closeIssueArgsForCall)
+}
+
+func (fake *FakeGithub) CloseIssueCalls(stub func(string) error) {
+	fake.closeIssueMutex.Lock()
+	defer fake.closeIssueMutex.Unlock()
+	fake.CloseIssueStub = stub
+}
+
+func (fake *FakeGithub) CloseIssueArgsForCall(i int) string {
+	fake.closeIssueMutex.RLock()
+	defer fake.closeIssueMutex.RUnlock()
+	argsForCall := fake.closeIssueArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) CloseIssueReturns(result1 error) {
+	fake.closeIssueMutex.Lock()
+	defer fake.closeIssueMutex.Unlock()
+	fake.CloseIssueStub = nil
+	fake.closeIssueReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CloseIssueReturnsOnCall(i int, result1 error) {
+	fake.closeIssueMutex.Lock()
+	defer fake.closeIssueMutex.Unlock()
+	fake.CloseIssueStub = nil
+	if fake.closeIssueReturnsOnCall == nil {
+		fake.closeIssueReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeIssueReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ClosePullRequest(arg1 string) error {
+	fake.closePullRequestMutex.Lock()
+	ret, specificReturn := fake.closePullRequestReturnsOnCall[len(fake.closePullRequestArgsForCall)]
+	fake.closePullRequestArgsForCall = append(fake.closePullRequestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ClosePullRequest", []interface{}{arg1})
+	fake.closePullRequestMutex.Unlock()
+	if fake.ClosePullRequestStub != nil {
+		return fake.ClosePullRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.closePullRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) ClosePullRequestCallCount() int {
+	fake.closePullRequestMutex.RLock()
+	defer fake.closePullRequestMutex.RUnlock()
+	return len(fake.closePullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) ClosePullRequestCalls(stub func(string) error) {
+	fake.closePullRequestMutex.Lock()
+	defer fake.closePullRequestMutex.Unlock()
+	fake.ClosePullRequestStub = stub
+}
+
+func (fake *FakeGithub) ClosePullRequestArgsForCall(i int) string {
+	fake.closePullRequestMutex.RLock()
+	defer fake.closePullRequestMutex.RUnlock()
+	argsForCall := fake.closePullRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ClosePullRequestReturns(result1 error) {
+	fake.closePullRequestMutex.Lock()
+	defer fake.closePullRequestMutex.Unlock()
+	fake.ClosePullRequestStub = nil
+	fake.closePullRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ClosePullRequestReturnsOnCall(i int, result1 error) {
+	fake.closePullRequestMutex.Lock()
+	defer fake.closePullRequestMutex.Unlock()
+	fake.ClosePullRequestStub = nil
+	if fake.closePullRequestReturnsOnCall == nil {
+		fake.closePullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closePullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CommentOnIssue(arg1 string, arg2 string) error {
+	fake.commentOnIssueMutex.Lock()
+	ret, specificReturn := fake.commentOnIssueReturnsOnCall[len(fake.commentOnIssueArgsForCall)]
+	fake.commentOnIssueArgsForCall = append(fake.commentOnIssueArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("CommentOnIssue", []interface{}{arg1, arg2})
+	fake.commentOnIssueMutex.Unlock()
+	if fake.CommentOnIssueStub != nil {
+		return fake.CommentOnIssueStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.commentOnIssueReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) CommentOnIssueCallCount() int {
+	fake.commentOnIssueMutex.RLock()
+	defer fake.commentOnIssueMutex.RUnlock()
+	return len(fake.commentOnIssueArgsForCall)
+}
+
+func (fake *FakeGithub) CommentOnIssueCalls(stub func(string, string) error) {
+	fake.commentOnIssueMutex.Lock()
+	defer fake.commentOnIssueMutex.Unlock()
+	fake.CommentOnIssueStub = stub
+}
+
+func (fake *FakeGithub) CommentOnIssueArgsForCall(i int) (string, string) {
+	fake.commentOnIssueMutex.RLock()
+	defer fake.commentOnIssueMutex.RUnlock()
+	argsForCall := fake.commentOnIssueArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) CommentOnIssueReturns(result1 error) {
+	fake.commentOnIssueMutex.Lock()
+	defer fake.commentOnIssueMutex.Unlock()
+	fake.CommentOnIssueStub = nil
+	fake.commentOnIssueReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CommentOnIssueReturnsOnCall(i int, result1 error) {
+	fake.commentOnIssueMutex.Lock()
+	defer fake.commentOnIssueMutex.Unlock()
+	fake.CommentOnIssueStub = nil
+	if fake.commentOnIssueReturnsOnCall == nil {
+		fake.commentOnIssueReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.commentOnIssueReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CompareCommits(arg1 string, arg2 string) (*resource.CommitComparison, error) {
+	fake.compareCommitsMutex.Lock()
+	ret, specificReturn := fake.compareCommitsReturnsOnCall[len(fake.compareCommitsArgsForCall)]
+	fake.compareCommitsArgsForCall = append(fake.compareCommitsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("CompareCommits", []interface{}{arg1, arg2})
+	fake.compareCommitsMutex.Unlock()
+	if fake.CompareCommitsStub != nil {
+		return fake.CompareCommitsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.compareCommitsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) CompareCommitsCallCount() int {
+	fake.compareCommitsMutex.RLock()
+	defer fake.compareCommitsMutex.RUnlock()
+	return len(fake.compareCommitsArgsForCall)
+}
+
+func (fake *FakeGithub) CompareCommitsCalls(stub func(string, string) (*resource.CommitComparison, error)) {
+	fake.compareCommitsMutex.Lock()
+	defer fake.compareCommitsMutex.Unlock()
+	fake.CompareCommitsStub = stub
+}
+
+func (fake *FakeGithub) CompareCommitsArgsForCall(i int) (string, string) {
+	fake.compareCommitsMutex.RLock()
+	defer fake.compareCommitsMutex.RUnlock()
+	argsForCall := fake.compareCommitsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) CompareCommitsReturns(result1 *resource.CommitComparison, result2 error) {
+	fake.compareCommitsMutex.Lock()
+	defer fake.compareCommitsMutex.Unlock()
+	fake.CompareCommitsStub = nil
+	fake.compareCommitsReturns = struct {
+		result1 *resource.CommitComparison
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CompareCommitsReturnsOnCall(i int, result1 *resource.CommitComparison, result2 error) {
+	fake.compareCommitsMutex.Lock()
+	defer fake.compareCommitsMutex.Unlock()
+	fake.CompareCommitsStub = nil
+	if fake.compareCommitsReturnsOnCall == nil {
+		fake.compareCommitsReturnsOnCall = make(map[int]struct {
+			result1 *resource.CommitComparison
+			result2 error
+		})
+	}
+	fake.compareCommitsReturnsOnCall[i] = struct {
+		result1 *resource.CommitComparison
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ConvertToDraft(arg1 string) error {
+	fake.convertToDraftMutex.Lock()
+	ret, specificReturn := fake.convertToDraftReturnsOnCall[len(fake.convertToDraftArgsForCall)]
+	fake.convertToDraftArgsForCall = append(fake.convertToDraftArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ConvertToDraft", []interface{}{arg1})
+	fake.convertToDraftMutex.Unlock()
+	if fake.ConvertToDraftStub != nil {
+		return fake.ConvertToDraftStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.convertToDraftReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) ConvertToDraftCallCount() int {
+	fake.convertToDraftMutex.RLock()
+	defer fake.convertToDraftMutex.RUnlock()
+	return len(fake.convertToDraftArgsForCall)
+}
+
+func (fake *FakeGithub) ConvertToDraftCalls(stub func(string) error) {
+	fake.convertToDraftMutex.Lock()
+	defer fake.convertToDraftMutex.Unlock()
+	fake.ConvertToDraftStub = stub
+}
+
+func (fake *FakeGithub) ConvertToDraftArgsForCall(i int) string {
+	fake.convertToDraftMutex.RLock()
+	defer fake.convertToDraftMutex.RUnlock()
+	argsForCall := fake.convertToDraftArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ConvertToDraftReturns(result1 error) {
+	fake.convertToDraftMutex.Lock()
+	defer fake.convertToDraftMutex.Unlock()
+	fake.ConvertToDraftStub = nil
+	fake.convertToDraftReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ConvertToDraftReturnsOnCall(i int, result1 error) {
+	fake.convertToDraftMutex.Lock()
+	defer fake.convertToDraftMutex.Unlock()
+	fake.ConvertToDraftStub = nil
+	if fake.convertToDraftReturnsOnCall == nil {
+		fake.convertToDraftReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.convertToDraftReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateCommitComment(arg1 string, arg2 string) (string, error) {
+	fake.createCommitCommentMutex.Lock()
+	ret, specificReturn := fake.createCommitCommentReturnsOnCall[len(fake.createCommitCommentArgsForCall)]
+	fake.createCommitCommentArgsForCall = append(fake.createCommitCommentArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("CreateCommitComment", []interface{}{arg1, arg2})
+	fake.createCommitCommentMutex.Unlock()
+	if fake.CreateCommitCommentStub != nil {
+		return fake.CreateCommitCommentStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createCommitCommentReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) CreateCommitCommentCallCount() int {
+	fake.createCommitCommentMutex.RLock()
+	defer fake.createCommitCommentMutex.RUnlock()
+	return len(fake.createCommitCommentArgsForCall)
+}
+
+func (fake *FakeGithub) CreateCommitCommentCalls(stub func(string, string) (string, error)) {
+	fake.createCommitCommentMutex.Lock()
+	defer fake.createCommitCommentMutex.Unlock()
+	fake.CreateCommitCommentStub = stub
+}
+
+func (fake *FakeGithub) CreateCommitCommentArgsForCall(i int) (string, string) {
+	fake.createCommitCommentMutex.RLock()
+	defer fake.createCommitCommentMutex.RUnlock()
+	argsForCall := fake.createCommitCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) CreateCommitCommentReturns(result1 string, result2 error) {
+	fake.createCommitCommentMutex.Lock()
+	defer fake.createCommitCommentMutex.Unlock()
+	fake.CreateCommitCommentStub = nil
+	fake.createCommitCommentReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreateCommitCommentReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createCommitCommentMutex.Lock()
+	defer fake.createCommitCommentMutex.Unlock()
+	fake.CreateCommitCommentStub = nil
+	if fake.createCommitCommentReturnsOnCall == nil {
+		fake.createCommitCommentReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createCommitCommentReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreateGist(arg1 string, arg2 string, arg3 string) (string, error) {
+	fake.createGistMutex.Lock()
+	ret, specificReturn := fake.createGistReturnsOnCall[len(fake.createGistArgsForCall)]
+	fake.createGistArgsForCall = append(fake.createGistArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("CreateGist", []interface{}{arg1, arg2, arg3})
+	fake.createGistMutex.Unlock()
+	if fake.CreateGistStub != nil {
+		return fake.CreateGistStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createGistReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) CreateGistCallCount() int {
+	fake.createGistMutex.RLock()
+	defer fake.createGistMutex.RUnlock()
+	return len(fake.createGistArgsForCall)
+}
+
+func (fake *FakeGithub) CreateGistCalls(stub func(string, string, string) (string, error)) {
+	fake.createGistMutex.Lock()
+	defer fake.createGistMutex.Unlock()
+	fake.CreateGistStub = stub
+}
+
+func (fake *FakeGithub) CreateGistArgsForCall(i int) (string, string, string) {
+	fake.createGistMutex.RLock()
+	defer fake.createGistMutex.RUnlock()
+	argsForCall := fake.createGistArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) CreateGistReturns(result1 string, result2 error) {
+	fake.createGistMutex.Lock()
+	defer fake.createGistMutex.Unlock()
+	fake.CreateGistStub = nil
+	fake.createGistReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreateGistReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createGistMutex.Lock()
+	defer fake.createGistMutex.Unlock()
+	fake.CreateGistStub = nil
+	if fake.createGistReturnsOnCall == nil {
+		fake.createGistReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createGistReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreateRelease(arg1 resource.Release) error {
+	fake.createReleaseMutex.Lock()
+	ret, specificReturn := fake.createReleaseReturnsOnCall[len(fake.createReleaseArgsForCall)]
+	fake.createReleaseArgsForCall = append(fake.createReleaseArgsForCall, struct {
+		arg1 resource.Release
+	}{arg1})
+	fake.recordInvocation("CreateRelease", []interface{}{arg1})
+	fake.createReleaseMutex.Unlock()
+	if fake.CreateReleaseStub != nil {
+		return fake.CreateReleaseStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createReleaseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) CreateReleaseCallCount() int {
+	fake.createReleaseMutex.RLock()
+	defer fake.createReleaseMutex.RUnlock()
+	return len(fake.createReleaseArgsForCall)
+}
+
+func (fake *FakeGithub) CreateReleaseCalls(stub func(resource.Release) error) {
+	fake.createReleaseMutex.Lock()
+	defer fake.createReleaseMutex.Unlock()
+	fake.CreateReleaseStub = stub
+}
+
+func (fake *FakeGithub) CreateReleaseArgsForCall(i int) resource.Release {
+	fake.createReleaseMutex.RLock()
+	defer fake.createReleaseMutex.RUnlock()
+	argsForCall := fake.createReleaseArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) CreateReleaseReturns(result1 error) {
+	fake.createReleaseMutex.Lock()
+	defer fake.createReleaseMutex.Unlock()
+	fake.CreateReleaseStub = nil
+	fake.createReleaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateReleaseReturnsOnCall(i int, result1 error) {
+	fake.createReleaseMutex.Lock()
+	defer fake.createReleaseMutex.Unlock()
+	fake.CreateReleaseStub = nil
+	if fake.createReleaseReturnsOnCall == nil {
+		fake.createReleaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createReleaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateReview(arg1 string, arg2 string, arg3 string, arg4 string) error {
+	fake.createReviewMutex.Lock()
+	ret, specificReturn := fake.createReviewReturnsOnCall[len(fake.createReviewArgsForCall)]
+	fake.createReviewArgsForCall = append(fake.createReviewArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CreateReview", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createReviewMutex.Unlock()
+	if fake.CreateReviewStub != nil {
+		return fake.CreateReviewStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createReviewReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) CreateReviewCallCount() int {
+	fake.createReviewMutex.RLock()
+	defer fake.createReviewMutex.RUnlock()
+	return len(fake.createReviewArgsForCall)
+}
+
+func (fake *FakeGithub) CreateReviewCalls(stub func(string, string, string, string) error) {
+	fake.createReviewMutex.Lock()
+	defer fake.createReviewMutex.Unlock()
+	fake.CreateReviewStub = stub
+}
+
+func (fake *FakeGithub) CreateReviewArgsForCall(i int) (string, string, string, string) {
+	fake.createReviewMutex.RLock()
+	defer fake.createReviewMutex.RUnlock()
+	argsForCall := fake.createReviewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGithub) CreateReviewReturns(result1 error) {
+	fake.createReviewMutex.Lock()
+	defer fake.createReviewMutex.Unlock()
+	fake.CreateReviewStub = nil
+	fake.createReviewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateReviewReturnsOnCall(i int, result1 error) {
+	fake.createReviewMutex.Lock()
+	defer fake.createReviewMutex.Unlock()
+	fake.CreateReviewStub = nil
+	if fake.createReviewReturnsOnCall == nil {
+		fake.createReviewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createReviewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateSuggestions(arg1 string, arg2 string, arg3 string, arg4 []resource.Suggestion) error {
+	var arg4Copy []resource.Suggestion
+	if arg4 != nil {
+		arg4Copy = make([]resource.Suggestion, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.createSuggestionsMutex.Lock()
+	ret, specificReturn := fake.createSuggestionsReturnsOnCall[len(fake.createSuggestionsArgsForCall)]
+	fake.createSuggestionsArgsForCall = append(fake.createSuggestionsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []resource.Suggestion
+	}{arg1, arg2, arg3, arg4Copy})
+	fake.recordInvocation("CreateSuggestions", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.createSuggestionsMutex.Unlock()
+	if fake.CreateSuggestionsStub != nil {
+		return fake.CreateSuggestionsStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createSuggestionsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) CreateSuggestionsCallCount() int {
+	fake.createSuggestionsMutex.RLock()
+	defer fake.createSuggestionsMutex.RUnlock()
+	return len(fake.createSuggestionsArgsForCall)
+}
+
+func (fake *FakeGithub) CreateSuggestionsCalls(stub func(string, string, string, []resource.Suggestion) error) {
+	fake.createSuggestionsMutex.Lock()
+	defer fake.createSuggestionsMutex.Unlock()
+	fake.CreateSuggestionsStub = stub
+}
+
+func (fake *FakeGithub) CreateSuggestionsArgsForCall(i int) (string, string, string, []resource.Suggestion) {
+	fake.createSuggestionsMutex.RLock()
+	defer fake.createSuggestionsMutex.RUnlock()
+	argsForCall := fake.createSuggestionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGithub) CreateSuggestionsReturns(result1 error) {
+	fake.createSuggestionsMutex.Lock()
+	defer fake.createSuggestionsMutex.Unlock()
+	fake.CreateSuggestionsStub = nil
+	fake.createSuggestionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateSuggestionsReturnsOnCall(i int, result1 error) {
+	fake.createSuggestionsMutex.Lock()
+	defer fake.createSuggestionsMutex.Unlock()
+	fake.CreateSuggestionsStub = nil
+	if fake.createSuggestionsReturnsOnCall == nil {
+		fake.createSuggestionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createSuggestionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateTag(arg1 string, arg2 string, arg3 string) error {
+	fake.createTagMutex.Lock()
+	ret, specificReturn := fake.createTagReturnsOnCall[len(fake.createTagArgsForCall)]
+	fake.createTagArgsForCall = append(fake.createTagArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("CreateTag", []interface{}{arg1, arg2, arg3})
+	fake.createTagMutex.Unlock()
+	if fake.CreateTagStub != nil {
+		return fake.CreateTagStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createTagReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) CreateTagCallCount() int {
+	fake.createTagMutex.RLock()
+	defer fake.createTagMutex.RUnlock()
+	return len(fake.createTagArgsForCall)
+}
+
+func (fake *FakeGithub) CreateTagCalls(stub func(string, string, string) error) {
+	fake.createTagMutex.Lock()
+	defer fake.createTagMutex.Unlock()
+	fake.CreateTagStub = stub
+}
+
+func (fake *FakeGithub) CreateTagArgsForCall(i int) (string, string, string) {
+	fake.createTagMutex.RLock()
+	defer fake.createTagMutex.RUnlock()
+	argsForCall := fake.createTagArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) CreateTagReturns(result1 error) {
+	fake.createTagMutex.Lock()
+	defer fake.createTagMutex.Unlock()
+	fake.CreateTagStub = nil
+	fake.createTagReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateTagReturnsOnCall(i int, result1 error) {
+	fake.createTagMutex.Lock()
+	defer fake.createTagMutex.Unlock()
+	fake.CreateTagStub = nil
+	if fake.createTagReturnsOnCall == nil {
+		fake.createTagReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createTagReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeleteBranch(arg1 string) error {
+	fake.deleteBranchMutex.Lock()
+	ret, specificReturn := fake.deleteBranchReturnsOnCall[len(fake.deleteBranchArgsForCall)]
+	fake.deleteBranchArgsForCall = append(fake.deleteBranchArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteBranch", []interface{}{arg1})
+	fake.deleteBranchMutex.Unlock()
+	if fake.DeleteBranchStub != nil {
+		return fake.DeleteBranchStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteBranchReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DeleteBranchCallCount() int {
+	fake.deleteBranchMutex.RLock()
+	defer fake.deleteBranchMutex.RUnlock()
+	return len(fake.deleteBranchArgsForCall)
+}
+
+func (fake *FakeGithub) DeleteBranchCalls(stub func(string) error) {
+	fake.deleteBranchMutex.Lock()
+	defer fake.deleteBranchMutex.Unlock()
+	fake.DeleteBranchStub = stub
+}
+
+func (fake *FakeGithub) DeleteBranchArgsForCall(i int) string {
+	fake.deleteBranchMutex.RLock()
+	defer fake.deleteBranchMutex.RUnlock()
+	argsForCall := fake.deleteBranchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) DeleteBranchReturns(result1 error) {
+	fake.deleteBranchMutex.Lock()
+	defer fake.deleteBranchMutex.Unlock()
+	fake.DeleteBranchStub = nil
+	fake.deleteBranchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeleteBranchReturnsOnCall(i int, result1 error) {
+	fake.deleteBranchMutex.Lock()
+	defer fake.deleteBranchMutex.Unlock()
+	fake.DeleteBranchStub = nil
+	if fake.deleteBranchReturnsOnCall == nil {
+		fake.deleteBranchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteBranchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeleteComment(arg1 int64) error {
+	fake.deleteCommentMutex.Lock()
+	ret, specificReturn := fake.deleteCommentReturnsOnCall[len(fake.deleteCommentArgsForCall)]
+	fake.deleteCommentArgsForCall = append(fake.deleteCommentArgsForCall, struct {
+		arg1 int64
+	}{arg1})
+	fake.recordInvocation("DeleteComment", []interface{}{arg1})
+	fake.deleteCommentMutex.Unlock()
+	if fake.DeleteCommentStub != nil {
+		return fake.DeleteCommentStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteCommentReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DeleteCommentCallCount() int {
+	fake.deleteCommentMutex.RLock()
+	defer fake.deleteCommentMutex.RUnlock()
+	return len(fake.deleteCommentArgsForCall)
+}
+
+func (fake *FakeGithub) DeleteCommentCalls(stub func(int64) error) {
+	fake.deleteCommentMutex.Lock()
+	defer fake.deleteCommentMutex.Unlock()
+	fake.DeleteCommentStub = stub
+}
+
+func (fake *FakeGithub) DeleteCommentArgsForCall(i int) int64 {
+	fake.deleteCommentMutex.RLock()
+	defer fake.deleteCommentMutex.RUnlock()
+	argsForCall := fake.deleteCommentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) DeleteCommentReturns(result1 error) {
+	fake.deleteCommentMutex.Lock()
+	defer fake.deleteCommentMutex.Unlock()
+	fake.DeleteCommentStub = nil
+	fake.deleteCommentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeleteCommentReturnsOnCall(i int, result1 error) {
+	fake.deleteCommentMutex.Lock()
+	defer fake.deleteCommentMutex.Unlock()
+	fake.DeleteCommentStub = nil
+	if fake.deleteCommentReturnsOnCall == nil {
+		fake.deleteCommentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteCommentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeletePreviousComments(arg1 string, arg2 *regexp.Regexp) error {
+	fake.deletePreviousCommentsMutex.Lock()
+	ret, specificReturn := fake.deletePreviousCommentsReturnsOnCall[len(fake.deletePreviousCommentsArgsForCall)]
+	fake.deletePreviousCommentsArgsForCall = append(fake.deletePreviousCommentsArgsForCall, struct {
+		arg1 string
+		arg2 *regexp.Regexp
+	}{arg1, arg2})
+	fake.recordInvocation("DeletePreviousComments", []interface{}{arg1, arg2})
+	fake.deletePreviousCommentsMutex.Unlock()
+	if fake.DeletePreviousCommentsStub != nil {
+		return fake.DeletePreviousCommentsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deletePreviousCommentsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsCallCount() int {
+	fake.deletePreviousCommentsMutex.RLock()
+	defer fake.deletePreviousCommentsMutex.RUnlock()
+	return len(fake.deletePreviousCommentsArgsForCall)
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsCalls(stub func(string, *regexp.Regexp) error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = stub
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsArgsForCall(i int) (string, *regexp.Regexp) {
+	fake.deletePreviousCommentsMutex.RLock()
+	defer fake.deletePreviousCommentsMutex.RUnlock()
+	argsForCall := fake.deletePreviousCommentsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsReturns(result1 error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = nil
+	fake.deletePreviousCommentsReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGithub) DeletePreviousCommentsReturnsOnCall(i int, result1 error) {
 	fake.deletePreviousCommentsMutex.Lock()
 	defer fake.deletePreviousCommentsMutex.Unlock()
@@ -161,331 +1863,1901 @@ func (fake *FakeGithub) DeletePreviousCommentsReturnsOnCall(i int, result1 error
 			result1 error
 		})
 	}
-	fake.deletePreviousCommentsReturnsOnCall[i] = struct {
+	fake.deletePreviousCommentsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DismissReviews(arg1 string, arg2 string) error {
+	fake.dismissReviewsMutex.Lock()
+	ret, specificReturn := fake.dismissReviewsReturnsOnCall[len(fake.dismissReviewsArgsForCall)]
+	fake.dismissReviewsArgsForCall = append(fake.dismissReviewsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("DismissReviews", []interface{}{arg1, arg2})
+	fake.dismissReviewsMutex.Unlock()
+	if fake.DismissReviewsStub != nil {
+		return fake.DismissReviewsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.dismissReviewsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DismissReviewsCallCount() int {
+	fake.dismissReviewsMutex.RLock()
+	defer fake.dismissReviewsMutex.RUnlock()
+	return len(fake.dismissReviewsArgsForCall)
+}
+
+func (fake *FakeGithub) DismissReviewsCalls(stub func(string, string) error) {
+	fake.dismissReviewsMutex.Lock()
+	defer fake.dismissReviewsMutex.Unlock()
+	fake.DismissReviewsStub = stub
+}
+
+func (fake *FakeGithub) DismissReviewsArgsForCall(i int) (string, string) {
+	fake.dismissReviewsMutex.RLock()
+	defer fake.dismissReviewsMutex.RUnlock()
+	argsForCall := fake.dismissReviewsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) DismissReviewsReturns(result1 error) {
+	fake.dismissReviewsMutex.Lock()
+	defer fake.dismissReviewsMutex.Unlock()
+	fake.DismissReviewsStub = nil
+	fake.dismissReviewsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DismissReviewsReturnsOnCall(i int, result1 error) {
+	fake.dismissReviewsMutex.Lock()
+	defer fake.dismissReviewsMutex.Unlock()
+	fake.DismissReviewsStub = nil
+	if fake.dismissReviewsReturnsOnCall == nil {
+		fake.dismissReviewsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.dismissReviewsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) Dispatch(arg1 string, arg2 map[string]interface{}) error {
+	fake.dispatchMutex.Lock()
+	ret, specificReturn := fake.dispatchReturnsOnCall[len(fake.dispatchArgsForCall)]
+	fake.dispatchArgsForCall = append(fake.dispatchArgsForCall, struct {
+		arg1 string
+		arg2 map[string]interface{}
+	}{arg1, arg2})
+	fake.recordInvocation("Dispatch", []interface{}{arg1, arg2})
+	fake.dispatchMutex.Unlock()
+	if fake.DispatchStub != nil {
+		return fake.DispatchStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.dispatchReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DispatchCallCount() int {
+	fake.dispatchMutex.RLock()
+	defer fake.dispatchMutex.RUnlock()
+	return len(fake.dispatchArgsForCall)
+}
+
+func (fake *FakeGithub) DispatchCalls(stub func(string, map[string]interface{}) error) {
+	fake.dispatchMutex.Lock()
+	defer fake.dispatchMutex.Unlock()
+	fake.DispatchStub = stub
+}
+
+func (fake *FakeGithub) DispatchArgsForCall(i int) (string, map[string]interface{}) {
+	fake.dispatchMutex.RLock()
+	defer fake.dispatchMutex.RUnlock()
+	argsForCall := fake.dispatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) DispatchReturns(result1 error) {
+	fake.dispatchMutex.Lock()
+	defer fake.dispatchMutex.Unlock()
+	fake.DispatchStub = nil
+	fake.dispatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DispatchReturnsOnCall(i int, result1 error) {
+	fake.dispatchMutex.Lock()
+	defer fake.dispatchMutex.Unlock()
+	fake.DispatchStub = nil
+	if fake.dispatchReturnsOnCall == nil {
+		fake.dispatchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.dispatchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) EnableAutoMerge(arg1 string, arg2 string) error {
+	fake.enableAutoMergeMutex.Lock()
+	ret, specificReturn := fake.enableAutoMergeReturnsOnCall[len(fake.enableAutoMergeArgsForCall)]
+	fake.enableAutoMergeArgsForCall = append(fake.enableAutoMergeArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("EnableAutoMerge", []interface{}{arg1, arg2})
+	fake.enableAutoMergeMutex.Unlock()
+	if fake.EnableAutoMergeStub != nil {
+		return fake.EnableAutoMergeStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.enableAutoMergeReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) EnableAutoMergeCallCount() int {
+	fake.enableAutoMergeMutex.RLock()
+	defer fake.enableAutoMergeMutex.RUnlock()
+	return len(fake.enableAutoMergeArgsForCall)
+}
+
+func (fake *FakeGithub) EnableAutoMergeCalls(stub func(string, string) error) {
+	fake.enableAutoMergeMutex.Lock()
+	defer fake.enableAutoMergeMutex.Unlock()
+	fake.EnableAutoMergeStub = stub
+}
+
+func (fake *FakeGithub) EnableAutoMergeArgsForCall(i int) (string, string) {
+	fake.enableAutoMergeMutex.RLock()
+	defer fake.enableAutoMergeMutex.RUnlock()
+	argsForCall := fake.enableAutoMergeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) EnableAutoMergeReturns(result1 error) {
+	fake.enableAutoMergeMutex.Lock()
+	defer fake.enableAutoMergeMutex.Unlock()
+	fake.EnableAutoMergeStub = nil
+	fake.enableAutoMergeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) EnableAutoMergeReturnsOnCall(i int, result1 error) {
+	fake.enableAutoMergeMutex.Lock()
+	defer fake.enableAutoMergeMutex.Unlock()
+	fake.EnableAutoMergeStub = nil
+	if fake.enableAutoMergeReturnsOnCall == nil {
+		fake.enableAutoMergeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.enableAutoMergeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) FindComment(arg1 string, arg2 string) (string, error) {
+	fake.findCommentMutex.Lock()
+	ret, specificReturn := fake.findCommentReturnsOnCall[len(fake.findCommentArgsForCall)]
+	fake.findCommentArgsForCall = append(fake.findCommentArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("FindComment", []interface{}{arg1, arg2})
+	fake.findCommentMutex.Unlock()
+	if fake.FindCommentStub != nil {
+		return fake.FindCommentStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.findCommentReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) FindCommentCallCount() int {
+	fake.findCommentMutex.RLock()
+	defer fake.findCommentMutex.RUnlock()
+	return len(fake.findCommentArgsForCall)
+}
+
+func (fake *FakeGithub) FindCommentCalls(stub func(string, string) (string, error)) {
+	fake.findCommentMutex.Lock()
+	defer fake.findCommentMutex.Unlock()
+	fake.FindCommentStub = stub
+}
+
+func (fake *FakeGithub) FindCommentArgsForCall(i int) (string, string) {
+	fake.findCommentMutex.RLock()
+	defer fake.findCommentMutex.RUnlock()
+	argsForCall := fake.findCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) FindCommentReturns(result1 string, result2 error) {
+	fake.findCommentMutex.Lock()
+	defer fake.findCommentMutex.Unlock()
+	fake.FindCommentStub = nil
+	fake.findCommentReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) FindCommentReturnsOnCall(i int, result1 string, result2 error) {
+	fake.findCommentMutex.Lock()
+	defer fake.findCommentMutex.Unlock()
+	fake.FindCommentStub = nil
+	if fake.findCommentReturnsOnCall == nil {
+		fake.findCommentReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.findCommentReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) FindReviewThread(arg1 string, arg2 string, arg3 int) (int64, error) {
+	fake.findReviewThreadMutex.Lock()
+	ret, specificReturn := fake.findReviewThreadReturnsOnCall[len(fake.findReviewThreadArgsForCall)]
+	fake.findReviewThreadArgsForCall = append(fake.findReviewThreadArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("FindReviewThread", []interface{}{arg1, arg2, arg3})
+	fake.findReviewThreadMutex.Unlock()
+	if fake.FindReviewThreadStub != nil {
+		return fake.FindReviewThreadStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.findReviewThreadReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) FindReviewThreadCallCount() int {
+	fake.findReviewThreadMutex.RLock()
+	defer fake.findReviewThreadMutex.RUnlock()
+	return len(fake.findReviewThreadArgsForCall)
+}
+
+func (fake *FakeGithub) FindReviewThreadCalls(stub func(string, string, int) (int64, error)) {
+	fake.findReviewThreadMutex.Lock()
+	defer fake.findReviewThreadMutex.Unlock()
+	fake.FindReviewThreadStub = stub
+}
+
+func (fake *FakeGithub) FindReviewThreadArgsForCall(i int) (string, string, int) {
+	fake.findReviewThreadMutex.RLock()
+	defer fake.findReviewThreadMutex.RUnlock()
+	argsForCall := fake.findReviewThreadArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) FindReviewThreadReturns(result1 int64, result2 error) {
+	fake.findReviewThreadMutex.Lock()
+	defer fake.findReviewThreadMutex.Unlock()
+	fake.FindReviewThreadStub = nil
+	fake.findReviewThreadReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) FindReviewThreadReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.findReviewThreadMutex.Lock()
+	defer fake.findReviewThreadMutex.Unlock()
+	fake.FindReviewThreadStub = nil
+	if fake.findReviewThreadReturnsOnCall == nil {
+		fake.findReviewThreadReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.findReviewThreadReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetChangedFiles(arg1 string, arg2 string) ([]resource.ChangedFileObject, error) {
+	fake.getChangedFilesMutex.Lock()
+	ret, specificReturn := fake.getChangedFilesReturnsOnCall[len(fake.getChangedFilesArgsForCall)]
+	fake.getChangedFilesArgsForCall = append(fake.getChangedFilesArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetChangedFiles", []interface{}{arg1, arg2})
+	fake.getChangedFilesMutex.Unlock()
+	if fake.GetChangedFilesStub != nil {
+		return fake.GetChangedFilesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getChangedFilesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetChangedFilesCallCount() int {
+	fake.getChangedFilesMutex.RLock()
+	defer fake.getChangedFilesMutex.RUnlock()
+	return len(fake.getChangedFilesArgsForCall)
+}
+
+func (fake *FakeGithub) GetChangedFilesCalls(stub func(string, string) ([]resource.ChangedFileObject, error)) {
+	fake.getChangedFilesMutex.Lock()
+	defer fake.getChangedFilesMutex.Unlock()
+	fake.GetChangedFilesStub = stub
+}
+
+func (fake *FakeGithub) GetChangedFilesArgsForCall(i int) (string, string) {
+	fake.getChangedFilesMutex.RLock()
+	defer fake.getChangedFilesMutex.RUnlock()
+	argsForCall := fake.getChangedFilesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) GetChangedFilesReturns(result1 []resource.ChangedFileObject, result2 error) {
+	fake.getChangedFilesMutex.Lock()
+	defer fake.getChangedFilesMutex.Unlock()
+	fake.GetChangedFilesStub = nil
+	fake.getChangedFilesReturns = struct {
+		result1 []resource.ChangedFileObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetChangedFilesReturnsOnCall(i int, result1 []resource.ChangedFileObject, result2 error) {
+	fake.getChangedFilesMutex.Lock()
+	defer fake.getChangedFilesMutex.Unlock()
+	fake.GetChangedFilesStub = nil
+	if fake.getChangedFilesReturnsOnCall == nil {
+		fake.getChangedFilesReturnsOnCall = make(map[int]struct {
+			result1 []resource.ChangedFileObject
+			result2 error
+		})
+	}
+	fake.getChangedFilesReturnsOnCall[i] = struct {
+		result1 []resource.ChangedFileObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetCommitStatus(arg1 string, arg2 string, arg3 string) (*resource.CommitStatus, error) {
+	fake.getCommitStatusMutex.Lock()
+	ret, specificReturn := fake.getCommitStatusReturnsOnCall[len(fake.getCommitStatusArgsForCall)]
+	fake.getCommitStatusArgsForCall = append(fake.getCommitStatusArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetCommitStatus", []interface{}{arg1, arg2, arg3})
+	fake.getCommitStatusMutex.Unlock()
+	if fake.GetCommitStatusStub != nil {
+		return fake.GetCommitStatusStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCommitStatusReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetCommitStatusCallCount() int {
+	fake.getCommitStatusMutex.RLock()
+	defer fake.getCommitStatusMutex.RUnlock()
+	return len(fake.getCommitStatusArgsForCall)
+}
+
+func (fake *FakeGithub) GetCommitStatusCalls(stub func(string, string, string) (*resource.CommitStatus, error)) {
+	fake.getCommitStatusMutex.Lock()
+	defer fake.getCommitStatusMutex.Unlock()
+	fake.GetCommitStatusStub = stub
+}
+
+func (fake *FakeGithub) GetCommitStatusArgsForCall(i int) (string, string, string) {
+	fake.getCommitStatusMutex.RLock()
+	defer fake.getCommitStatusMutex.RUnlock()
+	argsForCall := fake.getCommitStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) GetCommitStatusReturns(result1 *resource.CommitStatus, result2 error) {
+	fake.getCommitStatusMutex.Lock()
+	defer fake.getCommitStatusMutex.Unlock()
+	fake.GetCommitStatusStub = nil
+	fake.getCommitStatusReturns = struct {
+		result1 *resource.CommitStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetCommitStatusReturnsOnCall(i int, result1 *resource.CommitStatus, result2 error) {
+	fake.getCommitStatusMutex.Lock()
+	defer fake.getCommitStatusMutex.Unlock()
+	fake.GetCommitStatusStub = nil
+	if fake.getCommitStatusReturnsOnCall == nil {
+		fake.getCommitStatusReturnsOnCall = make(map[int]struct {
+			result1 *resource.CommitStatus
+			result2 error
+		})
+	}
+	fake.getCommitStatusReturnsOnCall[i] = struct {
+		result1 *resource.CommitStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetFileContent(arg1 string, arg2 string) ([]byte, error) {
+	fake.getFileContentMutex.Lock()
+	ret, specificReturn := fake.getFileContentReturnsOnCall[len(fake.getFileContentArgsForCall)]
+	fake.getFileContentArgsForCall = append(fake.getFileContentArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetFileContent", []interface{}{arg1, arg2})
+	fake.getFileContentMutex.Unlock()
+	if fake.GetFileContentStub != nil {
+		return fake.GetFileContentStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getFileContentReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetFileContentCallCount() int {
+	fake.getFileContentMutex.RLock()
+	defer fake.getFileContentMutex.RUnlock()
+	return len(fake.getFileContentArgsForCall)
+}
+
+func (fake *FakeGithub) GetFileContentCalls(stub func(string, string) ([]byte, error)) {
+	fake.getFileContentMutex.Lock()
+	defer fake.getFileContentMutex.Unlock()
+	fake.GetFileContentStub = stub
+}
+
+func (fake *FakeGithub) GetFileContentArgsForCall(i int) (string, string) {
+	fake.getFileContentMutex.RLock()
+	defer fake.getFileContentMutex.RUnlock()
+	argsForCall := fake.getFileContentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) GetFileContentReturns(result1 []byte, result2 error) {
+	fake.getFileContentMutex.Lock()
+	defer fake.getFileContentMutex.Unlock()
+	fake.GetFileContentStub = nil
+	fake.getFileContentReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetFileContentReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getFileContentMutex.Lock()
+	defer fake.getFileContentMutex.Unlock()
+	fake.GetFileContentStub = nil
+	if fake.getFileContentReturnsOnCall == nil {
+		fake.getFileContentReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getFileContentReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetLatestComment(arg1 string) (string, error) {
+	fake.getLatestCommentMutex.Lock()
+	ret, specificReturn := fake.getLatestCommentReturnsOnCall[len(fake.getLatestCommentArgsForCall)]
+	fake.getLatestCommentArgsForCall = append(fake.getLatestCommentArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetLatestComment", []interface{}{arg1})
+	fake.getLatestCommentMutex.Unlock()
+	if fake.GetLatestCommentStub != nil {
+		return fake.GetLatestCommentStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getLatestCommentReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetLatestCommentCallCount() int {
+	fake.getLatestCommentMutex.RLock()
+	defer fake.getLatestCommentMutex.RUnlock()
+	return len(fake.getLatestCommentArgsForCall)
+}
+
+func (fake *FakeGithub) GetLatestCommentCalls(stub func(string) (string, error)) {
+	fake.getLatestCommentMutex.Lock()
+	defer fake.getLatestCommentMutex.Unlock()
+	fake.GetLatestCommentStub = stub
+}
+
+func (fake *FakeGithub) GetLatestCommentArgsForCall(i int) string {
+	fake.getLatestCommentMutex.RLock()
+	defer fake.getLatestCommentMutex.RUnlock()
+	argsForCall := fake.getLatestCommentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) GetLatestCommentReturns(result1 string, result2 error) {
+	fake.getLatestCommentMutex.Lock()
+	defer fake.getLatestCommentMutex.Unlock()
+	fake.GetLatestCommentStub = nil
+	fake.getLatestCommentReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetLatestCommentReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getLatestCommentMutex.Lock()
+	defer fake.getLatestCommentMutex.Unlock()
+	fake.GetLatestCommentStub = nil
+	if fake.getLatestCommentReturnsOnCall == nil {
+		fake.getLatestCommentReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getLatestCommentReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetLinkedIssues(arg1 string) ([]resource.IssueObject, error) {
+	fake.getLinkedIssuesMutex.Lock()
+	ret, specificReturn := fake.getLinkedIssuesReturnsOnCall[len(fake.getLinkedIssuesArgsForCall)]
+	fake.getLinkedIssuesArgsForCall = append(fake.getLinkedIssuesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetLinkedIssues", []interface{}{arg1})
+	fake.getLinkedIssuesMutex.Unlock()
+	if fake.GetLinkedIssuesStub != nil {
+		return fake.GetLinkedIssuesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getLinkedIssuesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetLinkedIssuesCallCount() int {
+	fake.getLinkedIssuesMutex.RLock()
+	defer fake.getLinkedIssuesMutex.RUnlock()
+	return len(fake.getLinkedIssuesArgsForCall)
+}
+
+func (fake *FakeGithub) GetLinkedIssuesCalls(stub func(string) ([]resource.IssueObject, error)) {
+	fake.getLinkedIssuesMutex.Lock()
+	defer fake.getLinkedIssuesMutex.Unlock()
+	fake.GetLinkedIssuesStub = stub
+}
+
+func (fake *FakeGithub) GetLinkedIssuesArgsForCall(i int) string {
+	fake.getLinkedIssuesMutex.RLock()
+	defer fake.getLinkedIssuesMutex.RUnlock()
+	argsForCall := fake.getLinkedIssuesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) GetLinkedIssuesReturns(result1 []resource.IssueObject, result2 error) {
+	fake.getLinkedIssuesMutex.Lock()
+	defer fake.getLinkedIssuesMutex.Unlock()
+	fake.GetLinkedIssuesStub = nil
+	fake.getLinkedIssuesReturns = struct {
+		result1 []resource.IssueObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetLinkedIssuesReturnsOnCall(i int, result1 []resource.IssueObject, result2 error) {
+	fake.getLinkedIssuesMutex.Lock()
+	defer fake.getLinkedIssuesMutex.Unlock()
+	fake.GetLinkedIssuesStub = nil
+	if fake.getLinkedIssuesReturnsOnCall == nil {
+		fake.getLinkedIssuesReturnsOnCall = make(map[int]struct {
+			result1 []resource.IssueObject
+			result2 error
+		})
+	}
+	fake.getLinkedIssuesReturnsOnCall[i] = struct {
+		result1 []resource.IssueObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequest(arg1 string, arg2 string) (*resource.PullRequest, error) {
+	fake.getPullRequestMutex.Lock()
+	ret, specificReturn := fake.getPullRequestReturnsOnCall[len(fake.getPullRequestArgsForCall)]
+	fake.getPullRequestArgsForCall = append(fake.getPullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetPullRequest", []interface{}{arg1, arg2})
+	fake.getPullRequestMutex.Unlock()
+	if fake.GetPullRequestStub != nil {
+		return fake.GetPullRequestStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPullRequestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetPullRequestCallCount() int {
+	fake.getPullRequestMutex.RLock()
+	defer fake.getPullRequestMutex.RUnlock()
+	return len(fake.getPullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) GetPullRequestCalls(stub func(string, string) (*resource.PullRequest, error)) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = stub
+}
+
+func (fake *FakeGithub) GetPullRequestArgsForCall(i int) (string, string) {
+	fake.getPullRequestMutex.RLock()
+	defer fake.getPullRequestMutex.RUnlock()
+	argsForCall := fake.getPullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) GetPullRequestReturns(result1 *resource.PullRequest, result2 error) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = nil
+	fake.getPullRequestReturns = struct {
+		result1 *resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequestReturnsOnCall(i int, result1 *resource.PullRequest, result2 error) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = nil
+	if fake.getPullRequestReturnsOnCall == nil {
+		fake.getPullRequestReturnsOnCall = make(map[int]struct {
+			result1 *resource.PullRequest
+			result2 error
+		})
+	}
+	fake.getPullRequestReturnsOnCall[i] = struct {
+		result1 *resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequestDetails(arg1 string) (*resource.PullRequestDetailsObject, error) {
+	fake.getPullRequestDetailsMutex.Lock()
+	ret, specificReturn := fake.getPullRequestDetailsReturnsOnCall[len(fake.getPullRequestDetailsArgsForCall)]
+	fake.getPullRequestDetailsArgsForCall = append(fake.getPullRequestDetailsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetPullRequestDetails", []interface{}{arg1})
+	fake.getPullRequestDetailsMutex.Unlock()
+	if fake.GetPullRequestDetailsStub != nil {
+		return fake.GetPullRequestDetailsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPullRequestDetailsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetPullRequestDetailsCallCount() int {
+	fake.getPullRequestDetailsMutex.RLock()
+	defer fake.getPullRequestDetailsMutex.RUnlock()
+	return len(fake.getPullRequestDetailsArgsForCall)
+}
+
+func (fake *FakeGithub) GetPullRequestDetailsCalls(stub func(string) (*resource.PullRequestDetailsObject, error)) {
+	fake.getPullRequestDetailsMutex.Lock()
+	defer fake.getPullRequestDetailsMutex.Unlock()
+	fake.GetPullRequestDetailsStub = stub
+}
+
+func (fake *FakeGithub) GetPullRequestDetailsArgsForCall(i int) string {
+	fake.getPullRequestDetailsMutex.RLock()
+	defer fake.getPullRequestDetailsMutex.RUnlock()
+	argsForCall := fake.getPullRequestDetailsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) GetPullRequestDetailsReturns(result1 *resource.PullRequestDetailsObject, result2 error) {
+	fake.getPullRequestDetailsMutex.Lock()
+	defer fake.getPullRequestDetailsMutex.Unlock()
+	fake.GetPullRequestDetailsStub = nil
+	fake.getPullRequestDetailsReturns = struct {
+		result1 *resource.PullRequestDetailsObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequestDetailsReturnsOnCall(i int, result1 *resource.PullRequestDetailsObject, result2 error) {
+	fake.getPullRequestDetailsMutex.Lock()
+	defer fake.getPullRequestDetailsMutex.Unlock()
+	fake.GetPullRequestDetailsStub = nil
+	if fake.getPullRequestDetailsReturnsOnCall == nil {
+		fake.getPullRequestDetailsReturnsOnCall = make(map[int]struct {
+			result1 *resource.PullRequestDetailsObject
+			result2 error
+		})
+	}
+	fake.getPullRequestDetailsReturnsOnCall[i] = struct {
+		result1 *resource.PullRequestDetailsObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetTokenInfo() (*resource.TokenInfo, error) {
+	fake.getTokenInfoMutex.Lock()
+	ret, specificReturn := fake.getTokenInfoReturnsOnCall[len(fake.getTokenInfoArgsForCall)]
+	fake.getTokenInfoArgsForCall = append(fake.getTokenInfoArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetTokenInfo", []interface{}{})
+	fake.getTokenInfoMutex.Unlock()
+	if fake.GetTokenInfoStub != nil {
+		return fake.GetTokenInfoStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getTokenInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetTokenInfoCallCount() int {
+	fake.getTokenInfoMutex.RLock()
+	defer fake.getTokenInfoMutex.RUnlock()
+	return len(fake.getTokenInfoArgsForCall)
+}
+
+func (fake *FakeGithub) GetTokenInfoCalls(stub func() (*resource.TokenInfo, error)) {
+	fake.getTokenInfoMutex.Lock()
+	defer fake.getTokenInfoMutex.Unlock()
+	fake.GetTokenInfoStub = stub
+}
+
+func (fake *FakeGithub) GetTokenInfoReturns(result1 *resource.TokenInfo, result2 error) {
+	fake.getTokenInfoMutex.Lock()
+	defer fake.getTokenInfoMutex.Unlock()
+	fake.GetTokenInfoStub = nil
+	fake.getTokenInfoReturns = struct {
+		result1 *resource.TokenInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetTokenInfoReturnsOnCall(i int, result1 *resource.TokenInfo, result2 error) {
+	fake.getTokenInfoMutex.Lock()
+	defer fake.getTokenInfoMutex.Unlock()
+	fake.GetTokenInfoStub = nil
+	if fake.getTokenInfoReturnsOnCall == nil {
+		fake.getTokenInfoReturnsOnCall = make(map[int]struct {
+			result1 *resource.TokenInfo
+			result2 error
+		})
+	}
+	fake.getTokenInfoReturnsOnCall[i] = struct {
+		result1 *resource.TokenInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListCommits(arg1 int) ([]resource.PullRequestCommit, error) {
+	fake.listCommitsMutex.Lock()
+	ret, specificReturn := fake.listCommitsReturnsOnCall[len(fake.listCommitsArgsForCall)]
+	fake.listCommitsArgsForCall = append(fake.listCommitsArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("ListCommits", []interface{}{arg1})
+	fake.listCommitsMutex.Unlock()
+	if fake.ListCommitsStub != nil {
+		return fake.ListCommitsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listCommitsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) ListCommitsCallCount() int {
+	fake.listCommitsMutex.RLock()
+	defer fake.listCommitsMutex.RUnlock()
+	return len(fake.listCommitsArgsForCall)
+}
+
+func (fake *FakeGithub) ListCommitsCalls(stub func(int) ([]resource.PullRequestCommit, error)) {
+	fake.listCommitsMutex.Lock()
+	defer fake.listCommitsMutex.Unlock()
+	fake.ListCommitsStub = stub
+}
+
+func (fake *FakeGithub) ListCommitsArgsForCall(i int) int {
+	fake.listCommitsMutex.RLock()
+	defer fake.listCommitsMutex.RUnlock()
+	argsForCall := fake.listCommitsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ListCommitsReturns(result1 []resource.PullRequestCommit, result2 error) {
+	fake.listCommitsMutex.Lock()
+	defer fake.listCommitsMutex.Unlock()
+	fake.ListCommitsStub = nil
+	fake.listCommitsReturns = struct {
+		result1 []resource.PullRequestCommit
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListCommitsReturnsOnCall(i int, result1 []resource.PullRequestCommit, result2 error) {
+	fake.listCommitsMutex.Lock()
+	defer fake.listCommitsMutex.Unlock()
+	fake.ListCommitsStub = nil
+	if fake.listCommitsReturnsOnCall == nil {
+		fake.listCommitsReturnsOnCall = make(map[int]struct {
+			result1 []resource.PullRequestCommit
+			result2 error
+		})
+	}
+	fake.listCommitsReturnsOnCall[i] = struct {
+		result1 []resource.PullRequestCommit
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListModifiedFiles(arg1 int) ([]string, error) {
+	fake.listModifiedFilesMutex.Lock()
+	ret, specificReturn := fake.listModifiedFilesReturnsOnCall[len(fake.listModifiedFilesArgsForCall)]
+	fake.listModifiedFilesArgsForCall = append(fake.listModifiedFilesArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("ListModifiedFiles", []interface{}{arg1})
+	fake.listModifiedFilesMutex.Unlock()
+	if fake.ListModifiedFilesStub != nil {
+		return fake.ListModifiedFilesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listModifiedFilesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) ListModifiedFilesCallCount() int {
+	fake.listModifiedFilesMutex.RLock()
+	defer fake.listModifiedFilesMutex.RUnlock()
+	return len(fake.listModifiedFilesArgsForCall)
+}
+
+func (fake *FakeGithub) ListModifiedFilesCalls(stub func(int) ([]string, error)) {
+	fake.listModifiedFilesMutex.Lock()
+	defer fake.listModifiedFilesMutex.Unlock()
+	fake.ListModifiedFilesStub = stub
+}
+
+func (fake *FakeGithub) ListModifiedFilesArgsForCall(i int) int {
+	fake.listModifiedFilesMutex.RLock()
+	defer fake.listModifiedFilesMutex.RUnlock()
+	argsForCall := fake.listModifiedFilesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ListModifiedFilesReturns(result1 []string, result2 error) {
+	fake.listModifiedFilesMutex.Lock()
+	defer fake.listModifiedFilesMutex.Unlock()
+	fake.ListModifiedFilesStub = nil
+	fake.listModifiedFilesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListModifiedFilesReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listModifiedFilesMutex.Lock()
+	defer fake.listModifiedFilesMutex.Unlock()
+	fake.ListModifiedFilesStub = nil
+	if fake.listModifiedFilesReturnsOnCall == nil {
+		fake.listModifiedFilesReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listModifiedFilesReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListPullRequests(arg1 []githubv4.PullRequestState, arg2 resource.PullRequestFields, arg3 time.Time) ([]*resource.PullRequest, error) {
+	var arg1Copy []githubv4.PullRequestState
+	if arg1 != nil {
+		arg1Copy = make([]githubv4.PullRequestState, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.listPullRequestsMutex.Lock()
+	ret, specificReturn := fake.listPullRequestsReturnsOnCall[len(fake.listPullRequestsArgsForCall)]
+	fake.listPullRequestsArgsForCall = append(fake.listPullRequestsArgsForCall, struct {
+		arg1 []githubv4.PullRequestState
+		arg2 resource.PullRequestFields
+		arg3 time.Time
+	}{arg1Copy, arg2, arg3})
+	fake.recordInvocation("ListPullRequests", []interface{}{arg1Copy, arg2, arg3})
+	fake.listPullRequestsMutex.Unlock()
+	if fake.ListPullRequestsStub != nil {
+		return fake.ListPullRequestsStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listPullRequestsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) ListPullRequestsCallCount() int {
+	fake.listPullRequestsMutex.RLock()
+	defer fake.listPullRequestsMutex.RUnlock()
+	return len(fake.listPullRequestsArgsForCall)
+}
+
+func (fake *FakeGithub) ListPullRequestsCalls(stub func([]githubv4.PullRequestState, resource.PullRequestFields, time.Time) ([]*resource.PullRequest, error)) {
+	fake.listPullRequestsMutex.Lock()
+	defer fake.listPullRequestsMutex.Unlock()
+	fake.ListPullRequestsStub = stub
+}
+
+func (fake *FakeGithub) ListPullRequestsArgsForCall(i int) ([]githubv4.PullRequestState, resource.PullRequestFields, time.Time) {
+	fake.listPullRequestsMutex.RLock()
+	defer fake.listPullRequestsMutex.RUnlock()
+	argsForCall := fake.listPullRequestsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) ListPullRequestsReturns(result1 []*resource.PullRequest, result2 error) {
+	fake.listPullRequestsMutex.Lock()
+	defer fake.listPullRequestsMutex.Unlock()
+	fake.ListPullRequestsStub = nil
+	fake.listPullRequestsReturns = struct {
+		result1 []*resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListPullRequestsReturnsOnCall(i int, result1 []*resource.PullRequest, result2 error) {
+	fake.listPullRequestsMutex.Lock()
+	defer fake.listPullRequestsMutex.Unlock()
+	fake.ListPullRequestsStub = nil
+	if fake.listPullRequestsReturnsOnCall == nil {
+		fake.listPullRequestsReturnsOnCall = make(map[int]struct {
+			result1 []*resource.PullRequest
+			result2 error
+		})
+	}
+	fake.listPullRequestsReturnsOnCall[i] = struct {
+		result1 []*resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) LockPullRequest(arg1 string, arg2 string) error {
+	fake.lockPullRequestMutex.Lock()
+	ret, specificReturn := fake.lockPullRequestReturnsOnCall[len(fake.lockPullRequestArgsForCall)]
+	fake.lockPullRequestArgsForCall = append(fake.lockPullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("LockPullRequest", []interface{}{arg1, arg2})
+	fake.lockPullRequestMutex.Unlock()
+	if fake.LockPullRequestStub != nil {
+		return fake.LockPullRequestStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.lockPullRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) LockPullRequestCallCount() int {
+	fake.lockPullRequestMutex.RLock()
+	defer fake.lockPullRequestMutex.RUnlock()
+	return len(fake.lockPullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) LockPullRequestCalls(stub func(string, string) error) {
+	fake.lockPullRequestMutex.Lock()
+	defer fake.lockPullRequestMutex.Unlock()
+	fake.LockPullRequestStub = stub
+}
+
+func (fake *FakeGithub) LockPullRequestArgsForCall(i int) (string, string) {
+	fake.lockPullRequestMutex.RLock()
+	defer fake.lockPullRequestMutex.RUnlock()
+	argsForCall := fake.lockPullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) LockPullRequestReturns(result1 error) {
+	fake.lockPullRequestMutex.Lock()
+	defer fake.lockPullRequestMutex.Unlock()
+	fake.LockPullRequestStub = nil
+	fake.lockPullRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) LockPullRequestReturnsOnCall(i int, result1 error) {
+	fake.lockPullRequestMutex.Lock()
+	defer fake.lockPullRequestMutex.Unlock()
+	fake.LockPullRequestStub = nil
+	if fake.lockPullRequestReturnsOnCall == nil {
+		fake.lockPullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.lockPullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) MarkReadyForReview(arg1 string) error {
+	fake.markReadyForReviewMutex.Lock()
+	ret, specificReturn := fake.markReadyForReviewReturnsOnCall[len(fake.markReadyForReviewArgsForCall)]
+	fake.markReadyForReviewArgsForCall = append(fake.markReadyForReviewArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("MarkReadyForReview", []interface{}{arg1})
+	fake.markReadyForReviewMutex.Unlock()
+	if fake.MarkReadyForReviewStub != nil {
+		return fake.MarkReadyForReviewStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.markReadyForReviewReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) MarkReadyForReviewCallCount() int {
+	fake.markReadyForReviewMutex.RLock()
+	defer fake.markReadyForReviewMutex.RUnlock()
+	return len(fake.markReadyForReviewArgsForCall)
+}
+
+func (fake *FakeGithub) MarkReadyForReviewCalls(stub func(string) error) {
+	fake.markReadyForReviewMutex.Lock()
+	defer fake.markReadyForReviewMutex.Unlock()
+	fake.MarkReadyForReviewStub = stub
+}
+
+func (fake *FakeGithub) MarkReadyForReviewArgsForCall(i int) string {
+	fake.markReadyForReviewMutex.RLock()
+	defer fake.markReadyForReviewMutex.RUnlock()
+	argsForCall := fake.markReadyForReviewArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) MarkReadyForReviewReturns(result1 error) {
+	fake.markReadyForReviewMutex.Lock()
+	defer fake.markReadyForReviewMutex.Unlock()
+	fake.MarkReadyForReviewStub = nil
+	fake.markReadyForReviewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) MarkReadyForReviewReturnsOnCall(i int, result1 error) {
+	fake.markReadyForReviewMutex.Lock()
+	defer fake.markReadyForReviewMutex.Unlock()
+	fake.MarkReadyForReviewStub = nil
+	if fake.markReadyForReviewReturnsOnCall == nil {
+		fake.markReadyForReviewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markReadyForReviewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) MergePullRequest(arg1 string, arg2 string, arg3 string, arg4 string, arg5 string) (string, error) {
+	fake.mergePullRequestMutex.Lock()
+	ret, specificReturn := fake.mergePullRequestReturnsOnCall[len(fake.mergePullRequestArgsForCall)]
+	fake.mergePullRequestArgsForCall = append(fake.mergePullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("MergePullRequest", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.mergePullRequestMutex.Unlock()
+	if fake.MergePullRequestStub != nil {
+		return fake.MergePullRequestStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.mergePullRequestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) MergePullRequestCallCount() int {
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
+	return len(fake.mergePullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) MergePullRequestCalls(stub func(string, string, string, string, string) (string, error)) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = stub
+}
+
+func (fake *FakeGithub) MergePullRequestArgsForCall(i int) (string, string, string, string, string) {
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
+	argsForCall := fake.mergePullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeGithub) MergePullRequestReturns(result1 string, result2 error) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = nil
+	fake.mergePullRequestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) MergePullRequestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = nil
+	if fake.mergePullRequestReturnsOnCall == nil {
+		fake.mergePullRequestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.mergePullRequestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) MinimizeComment(arg1 int64) error {
+	fake.minimizeCommentMutex.Lock()
+	ret, specificReturn := fake.minimizeCommentReturnsOnCall[len(fake.minimizeCommentArgsForCall)]
+	fake.minimizeCommentArgsForCall = append(fake.minimizeCommentArgsForCall, struct {
+		arg1 int64
+	}{arg1})
+	fake.recordInvocation("MinimizeComment", []interface{}{arg1})
+	fake.minimizeCommentMutex.Unlock()
+	if fake.MinimizeCommentStub != nil {
+		return fake.MinimizeCommentStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.minimizeCommentReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) MinimizeCommentCallCount() int {
+	fake.minimizeCommentMutex.RLock()
+	defer fake.minimizeCommentMutex.RUnlock()
+	return len(fake.minimizeCommentArgsForCall)
+}
+
+func (fake *FakeGithub) MinimizeCommentCalls(stub func(int64) error) {
+	fake.minimizeCommentMutex.Lock()
+	defer fake.minimizeCommentMutex.Unlock()
+	fake.MinimizeCommentStub = stub
+}
+
+func (fake *FakeGithub) MinimizeCommentArgsForCall(i int) int64 {
+	fake.minimizeCommentMutex.RLock()
+	defer fake.minimizeCommentMutex.RUnlock()
+	argsForCall := fake.minimizeCommentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) MinimizeCommentReturns(result1 error) {
+	fake.minimizeCommentMutex.Lock()
+	defer fake.minimizeCommentMutex.Unlock()
+	fake.MinimizeCommentStub = nil
+	fake.minimizeCommentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) MinimizeCommentReturnsOnCall(i int, result1 error) {
+	fake.minimizeCommentMutex.Lock()
+	defer fake.minimizeCommentMutex.Unlock()
+	fake.MinimizeCommentStub = nil
+	if fake.minimizeCommentReturnsOnCall == nil {
+		fake.minimizeCommentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.minimizeCommentReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeGithub) GetChangedFiles(arg1 string, arg2 string) ([]resource.ChangedFileObject, error) {
-	fake.getChangedFilesMutex.Lock()
-	ret, specificReturn := fake.getChangedFilesReturnsOnCall[len(fake.getChangedFilesArgsForCall)]
-	fake.getChangedFilesArgsForCall = append(fake.getChangedFilesArgsForCall, struct {
+func (fake *FakeGithub) MinimizePreviousComments(arg1 string, arg2 *regexp.Regexp) error {
+	fake.minimizePreviousCommentsMutex.Lock()
+	ret, specificReturn := fake.minimizePreviousCommentsReturnsOnCall[len(fake.minimizePreviousCommentsArgsForCall)]
+	fake.minimizePreviousCommentsArgsForCall = append(fake.minimizePreviousCommentsArgsForCall, struct {
+		arg1 string
+		arg2 *regexp.Regexp
+	}{arg1, arg2})
+	fake.recordInvocation("MinimizePreviousComments", []interface{}{arg1, arg2})
+	fake.minimizePreviousCommentsMutex.Unlock()
+	if fake.MinimizePreviousCommentsStub != nil {
+		return fake.MinimizePreviousCommentsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.minimizePreviousCommentsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) MinimizePreviousCommentsCallCount() int {
+	fake.minimizePreviousCommentsMutex.RLock()
+	defer fake.minimizePreviousCommentsMutex.RUnlock()
+	return len(fake.minimizePreviousCommentsArgsForCall)
+}
+
+func (fake *FakeGithub) MinimizePreviousCommentsCalls(stub func(string, *regexp.Regexp) error) {
+	fake.minimizePreviousCommentsMutex.Lock()
+	defer fake.minimizePreviousCommentsMutex.Unlock()
+	fake.MinimizePreviousCommentsStub = stub
+}
+
+func (fake *FakeGithub) MinimizePreviousCommentsArgsForCall(i int) (string, *regexp.Regexp) {
+	fake.minimizePreviousCommentsMutex.RLock()
+	defer fake.minimizePreviousCommentsMutex.RUnlock()
+	argsForCall := fake.minimizePreviousCommentsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) MinimizePreviousCommentsReturns(result1 error) {
+	fake.minimizePreviousCommentsMutex.Lock()
+	defer fake.minimizePreviousCommentsMutex.Unlock()
+	fake.MinimizePreviousCommentsStub = nil
+	fake.minimizePreviousCommentsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) MinimizePreviousCommentsReturnsOnCall(i int, result1 error) {
+	fake.minimizePreviousCommentsMutex.Lock()
+	defer fake.minimizePreviousCommentsMutex.Unlock()
+	fake.MinimizePreviousCommentsStub = nil
+	if fake.minimizePreviousCommentsReturnsOnCall == nil {
+		fake.minimizePreviousCommentsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.minimizePreviousCommentsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) PostComment(arg1 string, arg2 string) (string, error) {
+	fake.postCommentMutex.Lock()
+	ret, specificReturn := fake.postCommentReturnsOnCall[len(fake.postCommentArgsForCall)]
+	fake.postCommentArgsForCall = append(fake.postCommentArgsForCall, struct {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
-	fake.recordInvocation("GetChangedFiles", []interface{}{arg1, arg2})
-	fake.getChangedFilesMutex.Unlock()
-	if fake.GetChangedFilesStub != nil {
-		return fake.GetChangedFilesStub(arg1, arg2)
+	fake.recordInvocation("PostComment", []interface{}{arg1, arg2})
+	fake.postCommentMutex.Unlock()
+	if fake.PostCommentStub != nil {
+		return fake.PostCommentStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getChangedFilesReturns
+	fakeReturns := fake.postCommentReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeGithub) GetChangedFilesCallCount() int {
-	fake.getChangedFilesMutex.RLock()
-	defer fake.getChangedFilesMutex.RUnlock()
-	return len(fake.getChangedFilesArgsForCall)
+func (fake *FakeGithub) PostCommentCallCount() int {
+	fake.postCommentMutex.RLock()
+	defer fake.postCommentMutex.RUnlock()
+	return len(fake.postCommentArgsForCall)
 }
 
-func (fake *FakeGithub) GetChangedFilesCalls(stub func(string, string) ([]resource.ChangedFileObject, error)) {
-	fake.getChangedFilesMutex.Lock()
-	defer fake.getChangedFilesMutex.Unlock()
-	fake.GetChangedFilesStub = stub
+func (fake *FakeGithub) PostCommentCalls(stub func(string, string) (string, error)) {
+	fake.postCommentMutex.Lock()
+	defer fake.postCommentMutex.Unlock()
+	fake.PostCommentStub = stub
 }
 
-func (fake *FakeGithub) GetChangedFilesArgsForCall(i int) (string, string) {
-	fake.getChangedFilesMutex.RLock()
-	defer fake.getChangedFilesMutex.RUnlock()
-	argsForCall := fake.getChangedFilesArgsForCall[i]
+func (fake *FakeGithub) PostCommentArgsForCall(i int) (string, string) {
+	fake.postCommentMutex.RLock()
+	defer fake.postCommentMutex.RUnlock()
+	argsForCall := fake.postCommentArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeGithub) GetChangedFilesReturns(result1 []resource.ChangedFileObject, result2 error) {
-	fake.getChangedFilesMutex.Lock()
-	defer fake.getChangedFilesMutex.Unlock()
-	fake.GetChangedFilesStub = nil
-	fake.getChangedFilesReturns = struct {
-		result1 []resource.ChangedFileObject
+func (fake *FakeGithub) PostCommentReturns(result1 string, result2 error) {
+	fake.postCommentMutex.Lock()
+	defer fake.postCommentMutex.Unlock()
+	fake.PostCommentStub = nil
+	fake.postCommentReturns = struct {
+		result1 string
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeGithub) GetChangedFilesReturnsOnCall(i int, result1 []resource.ChangedFileObject, result2 error) {
-	fake.getChangedFilesMutex.Lock()
-	defer fake.getChangedFilesMutex.Unlock()
-	fake.GetChangedFilesStub = nil
-	if fake.getChangedFilesReturnsOnCall == nil {
-		fake.getChangedFilesReturnsOnCall = make(map[int]struct {
-			result1 []resource.ChangedFileObject
+func (fake *FakeGithub) PostCommentReturnsOnCall(i int, result1 string, result2 error) {
+	fake.postCommentMutex.Lock()
+	defer fake.postCommentMutex.Unlock()
+	fake.PostCommentStub = nil
+	if fake.postCommentReturnsOnCall == nil {
+		fake.postCommentReturnsOnCall = make(map[int]struct {
+			result1 string
 			result2 error
 		})
 	}
-	fake.getChangedFilesReturnsOnCall[i] = struct {
-		result1 []resource.ChangedFileObject
+	fake.postCommentReturnsOnCall[i] = struct {
+		result1 string
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeGithub) GetPullRequest(arg1 string, arg2 string) (*resource.PullRequest, error) {
-	fake.getPullRequestMutex.Lock()
-	ret, specificReturn := fake.getPullRequestReturnsOnCall[len(fake.getPullRequestArgsForCall)]
-	fake.getPullRequestArgsForCall = append(fake.getPullRequestArgsForCall, struct {
+func (fake *FakeGithub) RemoveLabels(arg1 string, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.removeLabelsMutex.Lock()
+	ret, specificReturn := fake.removeLabelsReturnsOnCall[len(fake.removeLabelsArgsForCall)]
+	fake.removeLabelsArgsForCall = append(fake.removeLabelsArgsForCall, struct {
 		arg1 string
-		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("GetPullRequest", []interface{}{arg1, arg2})
-	fake.getPullRequestMutex.Unlock()
-	if fake.GetPullRequestStub != nil {
-		return fake.GetPullRequestStub(arg1, arg2)
+		arg2 []string
+	}{arg1, arg2Copy})
+	fake.recordInvocation("RemoveLabels", []interface{}{arg1, arg2Copy})
+	fake.removeLabelsMutex.Unlock()
+	if fake.RemoveLabelsStub != nil {
+		return fake.RemoveLabelsStub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.getPullRequestReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.removeLabelsReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeGithub) GetPullRequestCallCount() int {
-	fake.getPullRequestMutex.RLock()
-	defer fake.getPullRequestMutex.RUnlock()
-	return len(fake.getPullRequestArgsForCall)
+func (fake *FakeGithub) RemoveLabelsCallCount() int {
+	fake.removeLabelsMutex.RLock()
+	defer fake.removeLabelsMutex.RUnlock()
+	return len(fake.removeLabelsArgsForCall)
 }
 
-func (fake *FakeGithub) GetPullRequestCalls(stub func(string, string) (*resource.PullRequest, error)) {
-	fake.getPullRequestMutex.Lock()
-	defer fake.getPullRequestMutex.Unlock()
-	fake.GetPullRequestStub = stub
+func (fake *FakeGithub) RemoveLabelsCalls(stub func(string, []string) error) {
+	fake.removeLabelsMutex.Lock()
+	defer fake.removeLabelsMutex.Unlock()
+	fake.RemoveLabelsStub = stub
 }
 
-func (fake *FakeGithub) GetPullRequestArgsForCall(i int) (string, string) {
-	fake.getPullRequestMutex.RLock()
-	defer fake.getPullRequestMutex.RUnlock()
-	argsForCall := fake.getPullRequestArgsForCall[i]
+func (fake *FakeGithub) RemoveLabelsArgsForCall(i int) (string, []string) {
+	fake.removeLabelsMutex.RLock()
+	defer fake.removeLabelsMutex.RUnlock()
+	argsForCall := fake.removeLabelsArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeGithub) GetPullRequestReturns(result1 *resource.PullRequest, result2 error) {
-	fake.getPullRequestMutex.Lock()
-	defer fake.getPullRequestMutex.Unlock()
-	fake.GetPullRequestStub = nil
-	fake.getPullRequestReturns = struct {
-		result1 *resource.PullRequest
-		result2 error
-	}{result1, result2}
+func (fake *FakeGithub) RemoveLabelsReturns(result1 error) {
+	fake.removeLabelsMutex.Lock()
+	defer fake.removeLabelsMutex.Unlock()
+	fake.RemoveLabelsStub = nil
+	fake.removeLabelsReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeGithub) GetPullRequestReturnsOnCall(i int, result1 *resource.PullRequest, result2 error) {
-	fake.getPullRequestMutex.Lock()
-	defer fake.getPullRequestMutex.Unlock()
-	fake.GetPullRequestStub = nil
-	if fake.getPullRequestReturnsOnCall == nil {
-		fake.getPullRequestReturnsOnCall = make(map[int]struct {
-			result1 *resource.PullRequest
-			result2 error
+func (fake *FakeGithub) RemoveLabelsReturnsOnCall(i int, result1 error) {
+	fake.removeLabelsMutex.Lock()
+	defer fake.removeLabelsMutex.Unlock()
+	fake.RemoveLabelsStub = nil
+	if fake.removeLabelsReturnsOnCall == nil {
+		fake.removeLabelsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeLabelsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ReopenPullRequest(arg1 string) error {
+	fake.reopenPullRequestMutex.Lock()
+	ret, specificReturn := fake.reopenPullRequestReturnsOnCall[len(fake.reopenPullRequestArgsForCall)]
+	fake.reopenPullRequestArgsForCall = append(fake.reopenPullRequestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ReopenPullRequest", []interface{}{arg1})
+	fake.reopenPullRequestMutex.Unlock()
+	if fake.ReopenPullRequestStub != nil {
+		return fake.ReopenPullRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.reopenPullRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) ReopenPullRequestCallCount() int {
+	fake.reopenPullRequestMutex.RLock()
+	defer fake.reopenPullRequestMutex.RUnlock()
+	return len(fake.reopenPullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) ReopenPullRequestCalls(stub func(string) error) {
+	fake.reopenPullRequestMutex.Lock()
+	defer fake.reopenPullRequestMutex.Unlock()
+	fake.ReopenPullRequestStub = stub
+}
+
+func (fake *FakeGithub) ReopenPullRequestArgsForCall(i int) string {
+	fake.reopenPullRequestMutex.RLock()
+	defer fake.reopenPullRequestMutex.RUnlock()
+	argsForCall := fake.reopenPullRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ReopenPullRequestReturns(result1 error) {
+	fake.reopenPullRequestMutex.Lock()
+	defer fake.reopenPullRequestMutex.Unlock()
+	fake.ReopenPullRequestStub = nil
+	fake.reopenPullRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ReopenPullRequestReturnsOnCall(i int, result1 error) {
+	fake.reopenPullRequestMutex.Lock()
+	defer fake.reopenPullRequestMutex.Unlock()
+	fake.ReopenPullRequestStub = nil
+	if fake.reopenPullRequestReturnsOnCall == nil {
+		fake.reopenPullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reopenPullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ReplyToReviewComment(arg1 string, arg2 int64, arg3 string) error {
+	fake.replyToReviewCommentMutex.Lock()
+	ret, specificReturn := fake.replyToReviewCommentReturnsOnCall[len(fake.replyToReviewCommentArgsForCall)]
+	fake.replyToReviewCommentArgsForCall = append(fake.replyToReviewCommentArgsForCall, struct {
+		arg1 string
+		arg2 int64
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ReplyToReviewComment", []interface{}{arg1, arg2, arg3})
+	fake.replyToReviewCommentMutex.Unlock()
+	if fake.ReplyToReviewCommentStub != nil {
+		return fake.ReplyToReviewCommentStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.replyToReviewCommentReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) ReplyToReviewCommentCallCount() int {
+	fake.replyToReviewCommentMutex.RLock()
+	defer fake.replyToReviewCommentMutex.RUnlock()
+	return len(fake.replyToReviewCommentArgsForCall)
+}
+
+func (fake *FakeGithub) ReplyToReviewCommentCalls(stub func(string, int64, string) error) {
+	fake.replyToReviewCommentMutex.Lock()
+	defer fake.replyToReviewCommentMutex.Unlock()
+	fake.ReplyToReviewCommentStub = stub
+}
+
+func (fake *FakeGithub) ReplyToReviewCommentArgsForCall(i int) (string, int64, string) {
+	fake.replyToReviewCommentMutex.RLock()
+	defer fake.replyToReviewCommentMutex.RUnlock()
+	argsForCall := fake.replyToReviewCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) ReplyToReviewCommentReturns(result1 error) {
+	fake.replyToReviewCommentMutex.Lock()
+	defer fake.replyToReviewCommentMutex.Unlock()
+	fake.ReplyToReviewCommentStub = nil
+	fake.replyToReviewCommentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ReplyToReviewCommentReturnsOnCall(i int, result1 error) {
+	fake.replyToReviewCommentMutex.Lock()
+	defer fake.replyToReviewCommentMutex.Unlock()
+	fake.ReplyToReviewCommentStub = nil
+	if fake.replyToReviewCommentReturnsOnCall == nil {
+		fake.replyToReviewCommentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.replyToReviewCommentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) RequestReviewers(arg1 string, arg2 []string, arg3 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.requestReviewersMutex.Lock()
+	ret, specificReturn := fake.requestReviewersReturnsOnCall[len(fake.requestReviewersArgsForCall)]
+	fake.requestReviewersArgsForCall = append(fake.requestReviewersArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}{arg1, arg2Copy, arg3Copy})
+	fake.recordInvocation("RequestReviewers", []interface{}{arg1, arg2Copy, arg3Copy})
+	fake.requestReviewersMutex.Unlock()
+	if fake.RequestReviewersStub != nil {
+		return fake.RequestReviewersStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.requestReviewersReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) RequestReviewersCallCount() int {
+	fake.requestReviewersMutex.RLock()
+	defer fake.requestReviewersMutex.RUnlock()
+	return len(fake.requestReviewersArgsForCall)
+}
+
+func (fake *FakeGithub) RequestReviewersCalls(stub func(string, []string, []string) error) {
+	fake.requestReviewersMutex.Lock()
+	defer fake.requestReviewersMutex.Unlock()
+	fake.RequestReviewersStub = stub
+}
+
+func (fake *FakeGithub) RequestReviewersArgsForCall(i int) (string, []string, []string) {
+	fake.requestReviewersMutex.RLock()
+	defer fake.requestReviewersMutex.RUnlock()
+	argsForCall := fake.requestReviewersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) RequestReviewersReturns(result1 error) {
+	fake.requestReviewersMutex.Lock()
+	defer fake.requestReviewersMutex.Unlock()
+	fake.RequestReviewersStub = nil
+	fake.requestReviewersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) RequestReviewersReturnsOnCall(i int, result1 error) {
+	fake.requestReviewersMutex.Lock()
+	defer fake.requestReviewersMutex.Unlock()
+	fake.RequestReviewersStub = nil
+	if fake.requestReviewersReturnsOnCall == nil {
+		fake.requestReviewersReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.getPullRequestReturnsOnCall[i] = struct {
-		result1 *resource.PullRequest
-		result2 error
-	}{result1, result2}
+	fake.requestReviewersReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeGithub) ListModifiedFiles(arg1 int) ([]string, error) {
-	fake.listModifiedFilesMutex.Lock()
-	ret, specificReturn := fake.listModifiedFilesReturnsOnCall[len(fake.listModifiedFilesArgsForCall)]
-	fake.listModifiedFilesArgsForCall = append(fake.listModifiedFilesArgsForCall, struct {
-		arg1 int
-	}{arg1})
-	fake.recordInvocation("ListModifiedFiles", []interface{}{arg1})
-	fake.listModifiedFilesMutex.Unlock()
-	if fake.ListModifiedFilesStub != nil {
-		return fake.ListModifiedFilesStub(arg1)
+func (fake *FakeGithub) SetMilestone(arg1 string, arg2 string, arg3 bool) error {
+	fake.setMilestoneMutex.Lock()
+	ret, specificReturn := fake.setMilestoneReturnsOnCall[len(fake.setMilestoneArgsForCall)]
+	fake.setMilestoneArgsForCall = append(fake.setMilestoneArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("SetMilestone", []interface{}{arg1, arg2, arg3})
+	fake.setMilestoneMutex.Unlock()
+	if fake.SetMilestoneStub != nil {
+		return fake.SetMilestoneStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listModifiedFilesReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.setMilestoneReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeGithub) ListModifiedFilesCallCount() int {
-	fake.listModifiedFilesMutex.RLock()
-	defer fake.listModifiedFilesMutex.RUnlock()
-	return len(fake.listModifiedFilesArgsForCall)
+func (fake *FakeGithub) SetMilestoneCallCount() int {
+	fake.setMilestoneMutex.RLock()
+	defer fake.setMilestoneMutex.RUnlock()
+	return len(fake.setMilestoneArgsForCall)
 }
 
-func (fake *FakeGithub) ListModifiedFilesCalls(stub func(int) ([]string, error)) {
-	fake.listModifiedFilesMutex.Lock()
-	defer fake.listModifiedFilesMutex.Unlock()
-	fake.ListModifiedFilesStub = stub
+func (fake *FakeGithub) SetMilestoneCalls(stub func(string, string, bool) error) {
+	fake.setMilestoneMutex.Lock()
+	defer fake.setMilestoneMutex.Unlock()
+	fake.SetMilestoneStub = stub
 }
 
-func (fake *FakeGithub) ListModifiedFilesArgsForCall(i int) int {
-	fake.listModifiedFilesMutex.RLock()
-	defer fake.listModifiedFilesMutex.RUnlock()
-	argsForCall := fake.listModifiedFilesArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeGithub) SetMilestoneArgsForCall(i int) (string, string, bool) {
+	fake.setMilestoneMutex.RLock()
+	defer fake.setMilestoneMutex.RUnlock()
+	argsForCall := fake.setMilestoneArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeGithub) ListModifiedFilesReturns(result1 []string, result2 error) {
-	fake.listModifiedFilesMutex.Lock()
-	defer fake.listModifiedFilesMutex.Unlock()
-	fake.ListModifiedFilesStub = nil
-	fake.listModifiedFilesReturns = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+func (fake *FakeGithub) SetMilestoneReturns(result1 error) {
+	fake.setMilestoneMutex.Lock()
+	defer fake.setMilestoneMutex.Unlock()
+	fake.SetMilestoneStub = nil
+	fake.setMilestoneReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeGithub) ListModifiedFilesReturnsOnCall(i int, result1 []string, result2 error) {
-	fake.listModifiedFilesMutex.Lock()
-	defer fake.listModifiedFilesMutex.Unlock()
-	fake.ListModifiedFilesStub = nil
-	if fake.listModifiedFilesReturnsOnCall == nil {
-		fake.listModifiedFilesReturnsOnCall = make(map[int]struct {
-			result1 []string
-			result2 error
+func (fake *FakeGithub) SetMilestoneReturnsOnCall(i int, result1 error) {
+	fake.setMilestoneMutex.Lock()
+	defer fake.setMilestoneMutex.Unlock()
+	fake.SetMilestoneStub = nil
+	if fake.setMilestoneReturnsOnCall == nil {
+		fake.setMilestoneReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listModifiedFilesReturnsOnCall[i] = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+	fake.setMilestoneReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeGithub) ListPullRequests(arg1 []githubv4.PullRequestState) ([]*resource.PullRequest, error) {
-	var arg1Copy []githubv4.PullRequestState
-	if arg1 != nil {
-		arg1Copy = make([]githubv4.PullRequestState, len(arg1))
-		copy(arg1Copy, arg1)
-	}
-	fake.listPullRequestsMutex.Lock()
-	ret, specificReturn := fake.listPullRequestsReturnsOnCall[len(fake.listPullRequestsArgsForCall)]
-	fake.listPullRequestsArgsForCall = append(fake.listPullRequestsArgsForCall, struct {
-		arg1 []githubv4.PullRequestState
-	}{arg1Copy})
-	fake.recordInvocation("ListPullRequests", []interface{}{arg1Copy})
-	fake.listPullRequestsMutex.Unlock()
-	if fake.ListPullRequestsStub != nil {
-		return fake.ListPullRequestsStub(arg1)
+func (fake *FakeGithub) UnlockPullRequest(arg1 string) error {
+	fake.unlockPullRequestMutex.Lock()
+	ret, specificReturn := fake.unlockPullRequestReturnsOnCall[len(fake.unlockPullRequestArgsForCall)]
+	fake.unlockPullRequestArgsForCall = append(fake.unlockPullRequestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("UnlockPullRequest", []interface{}{arg1})
+	fake.unlockPullRequestMutex.Unlock()
+	if fake.UnlockPullRequestStub != nil {
+		return fake.UnlockPullRequestStub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listPullRequestsReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.unlockPullRequestReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeGithub) ListPullRequestsCallCount() int {
-	fake.listPullRequestsMutex.RLock()
-	defer fake.listPullRequestsMutex.RUnlock()
-	return len(fake.listPullRequestsArgsForCall)
+func (fake *FakeGithub) UnlockPullRequestCallCount() int {
+	fake.unlockPullRequestMutex.RLock()
+	defer fake.unlockPullRequestMutex.RUnlock()
+	return len(fake.unlockPullRequestArgsForCall)
 }
 
-func (fake *FakeGithub) ListPullRequestsCalls(stub func([]githubv4.PullRequestState) ([]*resource.PullRequest, error)) {
-	fake.listPullRequestsMutex.Lock()
-	defer fake.listPullRequestsMutex.Unlock()
-	fake.ListPullRequestsStub = stub
+func (fake *FakeGithub) UnlockPullRequestCalls(stub func(string) error) {
+	fake.unlockPullRequestMutex.Lock()
+	defer fake.unlockPullRequestMutex.Unlock()
+	fake.UnlockPullRequestStub = stub
 }
 
-func (fake *FakeGithub) ListPullRequestsArgsForCall(i int) []githubv4.PullRequestState {
-	fake.listPullRequestsMutex.RLock()
-	defer fake.listPullRequestsMutex.RUnlock()
-	argsForCall := fake.listPullRequestsArgsForCall[i]
+func (fake *FakeGithub) UnlockPullRequestArgsForCall(i int) string {
+	fake.unlockPullRequestMutex.RLock()
+	defer fake.unlockPullRequestMutex.RUnlock()
+	argsForCall := fake.unlockPullRequestArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeGithub) ListPullRequestsReturns(result1 []*resource.PullRequest, result2 error) {
-	fake.listPullRequestsMutex.Lock()
-	defer fake.listPullRequestsMutex.Unlock()
-	fake.ListPullRequestsStub = nil
-	fake.listPullRequestsReturns = struct {
-		result1 []*resource.PullRequest
-		result2 error
-	}{result1, result2}
+func (fake *FakeGithub) UnlockPullRequestReturns(result1 error) {
+	fake.unlockPullRequestMutex.Lock()
+	defer fake.unlockPullRequestMutex.Unlock()
+	fake.UnlockPullRequestStub = nil
+	fake.unlockPullRequestReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeGithub) ListPullRequestsReturnsOnCall(i int, result1 []*resource.PullRequest, result2 error) {
-	fake.listPullRequestsMutex.Lock()
-	defer fake.listPullRequestsMutex.Unlock()
-	fake.ListPullRequestsStub = nil
-	if fake.listPullRequestsReturnsOnCall == nil {
-		fake.listPullRequestsReturnsOnCall = make(map[int]struct {
-			result1 []*resource.PullRequest
-			result2 error
+func (fake *FakeGithub) UnlockPullRequestReturnsOnCall(i int, result1 error) {
+	fake.unlockPullRequestMutex.Lock()
+	defer fake.unlockPullRequestMutex.Unlock()
+	fake.UnlockPullRequestStub = nil
+	if fake.unlockPullRequestReturnsOnCall == nil {
+		fake.unlockPullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listPullRequestsReturnsOnCall[i] = struct {
-		result1 []*resource.PullRequest
-		result2 error
-	}{result1, result2}
+	fake.unlockPullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeGithub) PostComment(arg1 string, arg2 string) error {
-	fake.postCommentMutex.Lock()
-	ret, specificReturn := fake.postCommentReturnsOnCall[len(fake.postCommentArgsForCall)]
-	fake.postCommentArgsForCall = append(fake.postCommentArgsForCall, struct {
+func (fake *FakeGithub) UpdateBranch(arg1 string, arg2 string, arg3 string) error {
+	fake.updateBranchMutex.Lock()
+	ret, specificReturn := fake.updateBranchReturnsOnCall[len(fake.updateBranchArgsForCall)]
+	fake.updateBranchArgsForCall = append(fake.updateBranchArgsForCall, struct {
 		arg1 string
 		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("PostComment", []interface{}{arg1, arg2})
-	fake.postCommentMutex.Unlock()
-	if fake.PostCommentStub != nil {
-		return fake.PostCommentStub(arg1, arg2)
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UpdateBranch", []interface{}{arg1, arg2, arg3})
+	fake.updateBranchMutex.Unlock()
+	if fake.UpdateBranchStub != nil {
+		return fake.UpdateBranchStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.postCommentReturns
+	fakeReturns := fake.updateBranchReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeGithub) PostCommentCallCount() int {
-	fake.postCommentMutex.RLock()
-	defer fake.postCommentMutex.RUnlock()
-	return len(fake.postCommentArgsForCall)
+func (fake *FakeGithub) UpdateBranchCallCount() int {
+	fake.updateBranchMutex.RLock()
+	defer fake.updateBranchMutex.RUnlock()
+	return len(fake.updateBranchArgsForCall)
 }
 
-func (fake *FakeGithub) PostCommentCalls(stub func(string, string) error) {
-	fake.postCommentMutex.Lock()
-	defer fake.postCommentMutex.Unlock()
-	fake.PostCommentStub = stub
+func (fake *FakeGithub) UpdateBranchCalls(stub func(string, string, string) error) {
+	fake.updateBranchMutex.Lock()
+	defer fake.updateBranchMutex.Unlock()
+	fake.UpdateBranchStub = stub
 }
 
-func (fake *FakeGithub) PostCommentArgsForCall(i int) (string, string) {
-	fake.postCommentMutex.RLock()
-	defer fake.postCommentMutex.RUnlock()
-	argsForCall := fake.postCommentArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeGithub) UpdateBranchArgsForCall(i int) (string, string, string) {
+	fake.updateBranchMutex.RLock()
+	defer fake.updateBranchMutex.RUnlock()
+	argsForCall := fake.updateBranchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeGithub) PostCommentReturns(result1 error) {
-	fake.postCommentMutex.Lock()
-	defer fake.postCommentMutex.Unlock()
-	fake.PostCommentStub = nil
-	fake.postCommentReturns = struct {
+func (fake *FakeGithub) UpdateBranchReturns(result1 error) {
+	fake.updateBranchMutex.Lock()
+	defer fake.updateBranchMutex.Unlock()
+	fake.UpdateBranchStub = nil
+	fake.updateBranchReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeGithub) PostCommentReturnsOnCall(i int, result1 error) {
-	fake.postCommentMutex.Lock()
-	defer fake.postCommentMutex.Unlock()
-	fake.PostCommentStub = nil
-	if fake.postCommentReturnsOnCall == nil {
-		fake.postCommentReturnsOnCall = make(map[int]struct {
+func (fake *FakeGithub) UpdateBranchReturnsOnCall(i int, result1 error) {
+	fake.updateBranchMutex.Lock()
+	defer fake.updateBranchMutex.Unlock()
+	fake.UpdateBranchStub = nil
+	if fake.updateBranchReturnsOnCall == nil {
+		fake.updateBranchReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.postCommentReturnsOnCall[i] = struct {
+	fake.updateBranchReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
+func (fake *FakeGithub) UpdateCheckRun(arg1 string, arg2 resource.CheckRun) (int64, error) {
+	fake.updateCheckRunMutex.Lock()
+	ret, specificReturn := fake.updateCheckRunReturnsOnCall[len(fake.updateCheckRunArgsForCall)]
+	fake.updateCheckRunArgsForCall = append(fake.updateCheckRunArgsForCall, struct {
+		arg1 string
+		arg2 resource.CheckRun
+	}{arg1, arg2})
+	fake.recordInvocation("UpdateCheckRun", []interface{}{arg1, arg2})
+	fake.updateCheckRunMutex.Unlock()
+	if fake.UpdateCheckRunStub != nil {
+		return fake.UpdateCheckRunStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.updateCheckRunReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) UpdateCheckRunCallCount() int {
+	fake.updateCheckRunMutex.RLock()
+	defer fake.updateCheckRunMutex.RUnlock()
+	return len(fake.updateCheckRunArgsForCall)
+}
+
+func (fake *FakeGithub) UpdateCheckRunCalls(stub func(string, resource.CheckRun) (int64, error)) {
+	fake.updateCheckRunMutex.Lock()
+	defer fake.updateCheckRunMutex.Unlock()
+	fake.UpdateCheckRunStub = stub
+}
+
+func (fake *FakeGithub) UpdateCheckRunArgsForCall(i int) (string, resource.CheckRun) {
+	fake.updateCheckRunMutex.RLock()
+	defer fake.updateCheckRunMutex.RUnlock()
+	argsForCall := fake.updateCheckRunArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) UpdateCheckRunReturns(result1 int64, result2 error) {
+	fake.updateCheckRunMutex.Lock()
+	defer fake.updateCheckRunMutex.Unlock()
+	fake.UpdateCheckRunStub = nil
+	fake.updateCheckRunReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) UpdateCheckRunReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.updateCheckRunMutex.Lock()
+	defer fake.updateCheckRunMutex.Unlock()
+	fake.UpdateCheckRunStub = nil
+	if fake.updateCheckRunReturnsOnCall == nil {
+		fake.updateCheckRunReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.updateCheckRunReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGithub) UpdateCommitStatus(arg1 string, arg2 string, arg3 string, arg4 string, arg5 string, arg6 string) error {
 	fake.updateCommitStatusMutex.Lock()
 	ret, specificReturn := fake.updateCommitStatusReturnsOnCall[len(fake.updateCommitStatusArgsForCall)]
@@ -551,23 +3823,303 @@ func (fake *FakeGithub) UpdateCommitStatusReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGithub) UpdateDeployment(arg1 string, arg2 resource.Deployment) error {
+	fake.updateDeploymentMutex.Lock()
+	ret, specificReturn := fake.updateDeploymentReturnsOnCall[len(fake.updateDeploymentArgsForCall)]
+	fake.updateDeploymentArgsForCall = append(fake.updateDeploymentArgsForCall, struct {
+		arg1 string
+		arg2 resource.Deployment
+	}{arg1, arg2})
+	fake.recordInvocation("UpdateDeployment", []interface{}{arg1, arg2})
+	fake.updateDeploymentMutex.Unlock()
+	if fake.UpdateDeploymentStub != nil {
+		return fake.UpdateDeploymentStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updateDeploymentReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) UpdateDeploymentCallCount() int {
+	fake.updateDeploymentMutex.RLock()
+	defer fake.updateDeploymentMutex.RUnlock()
+	return len(fake.updateDeploymentArgsForCall)
+}
+
+func (fake *FakeGithub) UpdateDeploymentCalls(stub func(string, resource.Deployment) error) {
+	fake.updateDeploymentMutex.Lock()
+	defer fake.updateDeploymentMutex.Unlock()
+	fake.UpdateDeploymentStub = stub
+}
+
+func (fake *FakeGithub) UpdateDeploymentArgsForCall(i int) (string, resource.Deployment) {
+	fake.updateDeploymentMutex.RLock()
+	defer fake.updateDeploymentMutex.RUnlock()
+	argsForCall := fake.updateDeploymentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) UpdateDeploymentReturns(result1 error) {
+	fake.updateDeploymentMutex.Lock()
+	defer fake.updateDeploymentMutex.Unlock()
+	fake.UpdateDeploymentStub = nil
+	fake.updateDeploymentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) UpdateDeploymentReturnsOnCall(i int, result1 error) {
+	fake.updateDeploymentMutex.Lock()
+	defer fake.updateDeploymentMutex.Unlock()
+	fake.UpdateDeploymentStub = nil
+	if fake.updateDeploymentReturnsOnCall == nil {
+		fake.updateDeploymentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateDeploymentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) UpdatePullRequest(arg1 string, arg2 string, arg3 string) error {
+	fake.updatePullRequestMutex.Lock()
+	ret, specificReturn := fake.updatePullRequestReturnsOnCall[len(fake.updatePullRequestArgsForCall)]
+	fake.updatePullRequestArgsForCall = append(fake.updatePullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UpdatePullRequest", []interface{}{arg1, arg2, arg3})
+	fake.updatePullRequestMutex.Unlock()
+	if fake.UpdatePullRequestStub != nil {
+		return fake.UpdatePullRequestStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updatePullRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) UpdatePullRequestCallCount() int {
+	fake.updatePullRequestMutex.RLock()
+	defer fake.updatePullRequestMutex.RUnlock()
+	return len(fake.updatePullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) UpdatePullRequestCalls(stub func(string, string, string) error) {
+	fake.updatePullRequestMutex.Lock()
+	defer fake.updatePullRequestMutex.Unlock()
+	fake.UpdatePullRequestStub = stub
+}
+
+func (fake *FakeGithub) UpdatePullRequestArgsForCall(i int) (string, string, string) {
+	fake.updatePullRequestMutex.RLock()
+	defer fake.updatePullRequestMutex.RUnlock()
+	argsForCall := fake.updatePullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) UpdatePullRequestReturns(result1 error) {
+	fake.updatePullRequestMutex.Lock()
+	defer fake.updatePullRequestMutex.Unlock()
+	fake.UpdatePullRequestStub = nil
+	fake.updatePullRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) UpdatePullRequestReturnsOnCall(i int, result1 error) {
+	fake.updatePullRequestMutex.Lock()
+	defer fake.updatePullRequestMutex.Unlock()
+	fake.UpdatePullRequestStub = nil
+	if fake.updatePullRequestReturnsOnCall == nil {
+		fake.updatePullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updatePullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) UpsertComment(arg1 string, arg2 string, arg3 string) (string, error) {
+	fake.upsertCommentMutex.Lock()
+	ret, specificReturn := fake.upsertCommentReturnsOnCall[len(fake.upsertCommentArgsForCall)]
+	fake.upsertCommentArgsForCall = append(fake.upsertCommentArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UpsertComment", []interface{}{arg1, arg2, arg3})
+	fake.upsertCommentMutex.Unlock()
+	if fake.UpsertCommentStub != nil {
+		return fake.UpsertCommentStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.upsertCommentReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) UpsertCommentCallCount() int {
+	fake.upsertCommentMutex.RLock()
+	defer fake.upsertCommentMutex.RUnlock()
+	return len(fake.upsertCommentArgsForCall)
+}
+
+func (fake *FakeGithub) UpsertCommentCalls(stub func(string, string, string) (string, error)) {
+	fake.upsertCommentMutex.Lock()
+	defer fake.upsertCommentMutex.Unlock()
+	fake.UpsertCommentStub = stub
+}
+
+func (fake *FakeGithub) UpsertCommentArgsForCall(i int) (string, string, string) {
+	fake.upsertCommentMutex.RLock()
+	defer fake.upsertCommentMutex.RUnlock()
+	argsForCall := fake.upsertCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) UpsertCommentReturns(result1 string, result2 error) {
+	fake.upsertCommentMutex.Lock()
+	defer fake.upsertCommentMutex.Unlock()
+	fake.UpsertCommentStub = nil
+	fake.upsertCommentReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) UpsertCommentReturnsOnCall(i int, result1 string, result2 error) {
+	fake.upsertCommentMutex.Lock()
+	defer fake.upsertCommentMutex.Unlock()
+	fake.UpsertCommentStub = nil
+	if fake.upsertCommentReturnsOnCall == nil {
+		fake.upsertCommentReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.upsertCommentReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGithub) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.aPIUsageMutex.RLock()
+	defer fake.aPIUsageMutex.RUnlock()
+	fake.addAssigneesMutex.RLock()
+	defer fake.addAssigneesMutex.RUnlock()
+	fake.addCommentReactionMutex.RLock()
+	defer fake.addCommentReactionMutex.RUnlock()
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	fake.addToProjectMutex.RLock()
+	defer fake.addToProjectMutex.RUnlock()
+	fake.closeIssueMutex.RLock()
+	defer fake.closeIssueMutex.RUnlock()
+	fake.closePullRequestMutex.RLock()
+	defer fake.closePullRequestMutex.RUnlock()
+	fake.commentOnIssueMutex.RLock()
+	defer fake.commentOnIssueMutex.RUnlock()
+	fake.compareCommitsMutex.RLock()
+	defer fake.compareCommitsMutex.RUnlock()
+	fake.convertToDraftMutex.RLock()
+	defer fake.convertToDraftMutex.RUnlock()
+	fake.createCommitCommentMutex.RLock()
+	defer fake.createCommitCommentMutex.RUnlock()
+	fake.createGistMutex.RLock()
+	defer fake.createGistMutex.RUnlock()
+	fake.createReleaseMutex.RLock()
+	defer fake.createReleaseMutex.RUnlock()
+	fake.createReviewMutex.RLock()
+	defer fake.createReviewMutex.RUnlock()
+	fake.createSuggestionsMutex.RLock()
+	defer fake.createSuggestionsMutex.RUnlock()
+	fake.createTagMutex.RLock()
+	defer fake.createTagMutex.RUnlock()
+	fake.deleteBranchMutex.RLock()
+	defer fake.deleteBranchMutex.RUnlock()
+	fake.deleteCommentMutex.RLock()
+	defer fake.deleteCommentMutex.RUnlock()
 	fake.deletePreviousCommentsMutex.RLock()
 	defer fake.deletePreviousCommentsMutex.RUnlock()
+	fake.dismissReviewsMutex.RLock()
+	defer fake.dismissReviewsMutex.RUnlock()
+	fake.dispatchMutex.RLock()
+	defer fake.dispatchMutex.RUnlock()
+	fake.enableAutoMergeMutex.RLock()
+	defer fake.enableAutoMergeMutex.RUnlock()
+	fake.findCommentMutex.RLock()
+	defer fake.findCommentMutex.RUnlock()
+	fake.findReviewThreadMutex.RLock()
+	defer fake.findReviewThreadMutex.RUnlock()
 	fake.getChangedFilesMutex.RLock()
 	defer fake.getChangedFilesMutex.RUnlock()
+	fake.getCommitStatusMutex.RLock()
+	defer fake.getCommitStatusMutex.RUnlock()
+	fake.getFileContentMutex.RLock()
+	defer fake.getFileContentMutex.RUnlock()
+	fake.getLatestCommentMutex.RLock()
+	defer fake.getLatestCommentMutex.RUnlock()
+	fake.getLinkedIssuesMutex.RLock()
+	defer fake.getLinkedIssuesMutex.RUnlock()
 	fake.getPullRequestMutex.RLock()
 	defer fake.getPullRequestMutex.RUnlock()
+	fake.getPullRequestDetailsMutex.RLock()
+	defer fake.getPullRequestDetailsMutex.RUnlock()
+	fake.getTokenInfoMutex.RLock()
+	defer fake.getTokenInfoMutex.RUnlock()
+	fake.listCommitsMutex.RLock()
+	defer fake.listCommitsMutex.RUnlock()
 	fake.listModifiedFilesMutex.RLock()
 	defer fake.listModifiedFilesMutex.RUnlock()
 	fake.listPullRequestsMutex.RLock()
 	defer fake.listPullRequestsMutex.RUnlock()
+	fake.lockPullRequestMutex.RLock()
+	defer fake.lockPullRequestMutex.RUnlock()
+	fake.markReadyForReviewMutex.RLock()
+	defer fake.markReadyForReviewMutex.RUnlock()
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
+	fake.minimizeCommentMutex.RLock()
+	defer fake.minimizeCommentMutex.RUnlock()
+	fake.minimizePreviousCommentsMutex.RLock()
+	defer fake.minimizePreviousCommentsMutex.RUnlock()
 	fake.postCommentMutex.RLock()
 	defer fake.postCommentMutex.RUnlock()
+	fake.removeLabelsMutex.RLock()
+	defer fake.removeLabelsMutex.RUnlock()
+	fake.reopenPullRequestMutex.RLock()
+	defer fake.reopenPullRequestMutex.RUnlock()
+	fake.replyToReviewCommentMutex.RLock()
+	defer fake.replyToReviewCommentMutex.RUnlock()
+	fake.requestReviewersMutex.RLock()
+	defer fake.requestReviewersMutex.RUnlock()
+	fake.setMilestoneMutex.RLock()
+	defer fake.setMilestoneMutex.RUnlock()
+	fake.unlockPullRequestMutex.RLock()
+	defer fake.unlockPullRequestMutex.RUnlock()
+	fake.updateBranchMutex.RLock()
+	defer fake.updateBranchMutex.RUnlock()
+	fake.updateCheckRunMutex.RLock()
+	defer fake.updateCheckRunMutex.RUnlock()
 	fake.updateCommitStatusMutex.RLock()
 	defer fake.updateCommitStatusMutex.RUnlock()
+	fake.updateDeploymentMutex.RLock()
+	defer fake.updateDeploymentMutex.RUnlock()
+	fake.updatePullRequestMutex.RLock()
+	defer fake.updatePullRequestMutex.RUnlock()
+	fake.upsertCommentMutex.RLock()
+	defer fake.upsertCommentMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,1158 @@
+package fakes
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+// MemoryGithub is an in-memory implementation of resource.Github, which simulates the state of a
+// repository (pull requests, comments, statuses etc.) instead of recording calls like FakeGithub.
+// The state is exported, so that it can be set up and inspected directly, but must not be modified
+// while the fake is in use by another goroutine.
+type MemoryGithub struct {
+	Owner      string
+	Repository string
+	// Viewer is the login of the authenticated user, which is the author of comments and reviews.
+	Viewer    string
+	TokenInfo resource.TokenInfo
+	Usage     resource.APIUsage
+
+	PullRequests map[int]*MemoryPullRequest
+	Issues       map[int]*MemoryIssue
+	// Branches and Tags map names to commits.
+	Branches map[string]string
+	Tags     map[string]string
+	// Files maps refs (commits or branches) to the content of files at the ref.
+	Files     map[string]map[string][]byte
+	Statuses  map[string][]MemoryStatus
+	CheckRuns map[string][]MemoryCheckRun
+	// CommitComments maps commits to the comments on them.
+	CommitComments map[string][]MemoryComment
+	// Comparisons maps "base...head" to the comparison of the commits (which are even by default).
+	Comparisons map[string]resource.CommitComparison
+	// CommitDetails maps the OID of a commit to its signature verification and parents (which are an
+	// unsigned commit with a single parent by default).
+	CommitDetails map[string]resource.PullRequestCommit
+	Deployments   map[string][]resource.Deployment
+	Releases      []resource.Release
+	Gists         []MemoryGist
+	Dispatches    []MemoryDispatch
+
+	mu     sync.Mutex
+	nextID int64
+}
+
+// MemoryPullRequest is a pull request in a MemoryGithub.
+type MemoryPullRequest struct {
+	resource.PullRequestObject
+	Body      string
+	Author    string
+	UpdatedAt time.Time
+	// Commits of the pull request (oldest first), where the last one is the head.
+	Commits []resource.CommitObject
+	// ForcePushes are the times commits were last force pushed to the pull request, by OID.
+	ForcePushes        map[string]time.Time
+	Files              []string
+	Labels             []string
+	Assignees          []string
+	RequestedReviewers []string
+	Reviews            []MemoryReview
+	Comments           []MemoryComment
+	ReviewComments     []MemoryComment
+	Milestone          string
+	AutoMergeMethod    string
+	Locked             bool
+	LockReason         string
+	Projects           []resource.Project
+	LinkedIssues       []int
+}
+
+// Head returns the head commit of the pull request.
+func (p *MemoryPullRequest) Head() resource.CommitObject {
+	if len(p.Commits) == 0 {
+		return resource.CommitObject{}
+	}
+	return p.Commits[len(p.Commits)-1]
+}
+
+// MemoryReview is a review of a pull request.
+type MemoryReview struct {
+	Author      string
+	CommitRef   string
+	Event       string
+	Body        string
+	Suggestions []resource.Suggestion
+	Dismissed   bool
+}
+
+// MemoryComment is a comment (or review comment) on a pull request or issue.
+type MemoryComment struct {
+	ID        int64
+	Author    string
+	Body      string
+	Minimized string
+	Reactions []string
+	// Path and Line are set for review comments.
+	Path      string
+	Line      int
+	Resolved  bool
+	InReplyTo int64
+}
+
+// MemoryIssue is an issue in a MemoryGithub.
+type MemoryIssue struct {
+	resource.IssueObject
+	Comments []MemoryComment
+}
+
+// MemoryStatus is a commit status.
+type MemoryStatus struct {
+	Context     string
+	State       string
+	TargetURL   string
+	Description string
+}
+
+// MemoryCheckRun is a check run of a commit.
+type MemoryCheckRun struct {
+	ID int64
+	resource.CheckRun
+}
+
+// MemoryDispatch is a repository_dispatch event sent to a MemoryGithub.
+type MemoryDispatch struct {
+	EventType     string
+	ClientPayload map[string]interface{}
+}
+
+// MemoryGist is a gist.
+type MemoryGist struct {
+	URL         string
+	Description string
+	Filename    string
+	Content     string
+}
+
+// NewMemoryGithub returns an empty repository.
+func NewMemoryGithub(owner, repository string) *MemoryGithub {
+	return &MemoryGithub{
+		Owner:        owner,
+		Repository:   repository,
+		Viewer:       "concourse",
+		Usage:        resource.APIUsage{RESTRemaining: -1, GraphQLRemaining: -1},
+		PullRequests: make(map[int]*MemoryPullRequest),
+		Issues:       make(map[int]*MemoryIssue),
+		Branches:     make(map[string]string),
+		Tags:         make(map[string]string),
+		Files:        make(map[string]map[string][]byte),
+		Statuses:     make(map[string][]MemoryStatus),
+		CheckRuns:    make(map[string][]MemoryCheckRun),
+		Deployments:  make(map[string][]resource.Deployment),
+
+		CommitComments: make(map[string][]MemoryComment),
+		Comparisons:    make(map[string]resource.CommitComparison),
+		CommitDetails:  make(map[string]resource.PullRequestCommit),
+	}
+}
+
+// AddPullRequest opens a pull request from the head branch to the base branch, with a single commit.
+func (m *MemoryGithub) AddPullRequest(number int, base, head string, commit resource.CommitObject) *MemoryPullRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := &MemoryPullRequest{UpdatedAt: time.Now()}
+	p.ID = fmt.Sprintf("PR_%d", number)
+	p.Number = number
+	p.Title = fmt.Sprintf("Pull request %d", number)
+	p.URL = m.url("pull", number)
+	p.BaseRefName = base
+	p.HeadRefName = head
+	p.Repository.URL = m.url("", 0)
+	p.HeadRepository = &struct{ NameWithOwner string }{NameWithOwner: m.Owner + "/" + m.Repository}
+	p.State = githubv4.PullRequestStateOpen
+	p.Commits = []resource.CommitObject{commit}
+	m.PullRequests[number] = p
+	m.Branches[head] = commit.OID
+	return p
+}
+
+// Push adds a commit to a pull request.
+func (m *MemoryGithub) Push(number int, commit resource.CommitObject) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequest(number)
+	if err != nil {
+		return err
+	}
+	p.Commits = append(p.Commits, commit)
+	p.UpdatedAt = time.Now()
+	m.Branches[p.HeadRefName] = commit.OID
+	return nil
+}
+
+// ForcePush replaces the commits of a pull request, e.g. after a rebase.
+func (m *MemoryGithub) ForcePush(number int, commits ...resource.CommitObject) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequest(number)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("force push to pull request %d without commits", number)
+	}
+	if p.ForcePushes == nil {
+		p.ForcePushes = make(map[string]time.Time)
+	}
+	head := commits[len(commits)-1]
+	p.Commits = commits
+	p.UpdatedAt = time.Now()
+	p.ForcePushes[head.OID] = p.UpdatedAt
+	m.Branches[p.HeadRefName] = head.OID
+	return nil
+}
+
+// APIUsage implements resource.Github.
+func (m *MemoryGithub) APIUsage() resource.APIUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Usage
+}
+
+// GetTokenInfo implements resource.Github.
+func (m *MemoryGithub) GetTokenInfo() (*resource.TokenInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	info := m.TokenInfo
+	return &info, nil
+}
+
+// ListPullRequests implements resource.Github.
+func (m *MemoryGithub) ListPullRequests(states []githubv4.PullRequestState, fields resource.PullRequestFields, since time.Time) ([]*resource.PullRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Most recently updated first, like the Github API
+	var pulls []*MemoryPullRequest
+	for _, p := range m.PullRequests {
+		if len(p.Commits) == 0 || !containsState(states, p.State) {
+			continue
+		}
+		if !since.IsZero() && !p.UpdatedAt.After(since) {
+			continue
+		}
+		pulls = append(pulls, p)
+	}
+	sort.Slice(pulls, func(i, j int) bool { return pulls[i].UpdatedAt.After(pulls[j].UpdatedAt) })
+
+	response := make([]*resource.PullRequest, 0, len(pulls))
+	for _, p := range pulls {
+		pr := m.convert(p, p.Head())
+		if !fields.Reviews {
+			pr.ApprovedReviewCount = 0
+		}
+		if !fields.Labels {
+			pr.Labels = nil
+		}
+		if fields.Files && len(p.Files) > 0 {
+			pr.Files = append([]string(nil), p.Files...)
+		}
+		if fields.Readiness {
+			m.setReadiness(pr, p)
+		}
+		response = append(response, pr)
+	}
+	return response, nil
+}
+
+// CompareCommits implements resource.Github.
+func (m *MemoryGithub) CompareCommits(base, head string) (*resource.CommitComparison, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	comparison := m.Comparisons[base+"..."+head]
+	return &comparison, nil
+}
+
+// ListCommits implements resource.Github.
+func (m *MemoryGithub) ListCommits(number int) ([]resource.PullRequestCommit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequest(number)
+	if err != nil {
+		return nil, err
+	}
+	var commits []resource.PullRequestCommit
+	for _, c := range p.Commits {
+		details, ok := m.CommitDetails[c.OID]
+		if !ok {
+			details = resource.PullRequestCommit{VerificationReason: "unsigned", Parents: 1}
+		}
+		details.OID = c.OID
+		commits = append(commits, details)
+	}
+	return commits, nil
+}
+
+// ListModifiedFiles implements resource.Github.
+func (m *MemoryGithub) ListModifiedFiles(number int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequest(number)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), p.Files...), nil
+}
+
+// PostComment implements resource.Github.
+func (m *MemoryGithub) PostComment(prNumber, comment string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	number, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+	c := MemoryComment{ID: m.id(), Author: m.Viewer, Body: comment}
+	if issue, ok := m.Issues[number]; ok {
+		issue.Comments = append(issue.Comments, c)
+		return m.commentURL("issues", number, c.ID), nil
+	}
+	p, err := m.pullRequest(number)
+	if err != nil {
+		return "", err
+	}
+	p.Comments = append(p.Comments, c)
+	return m.commentURL("pull", number, c.ID), nil
+}
+
+// CreateCommitComment implements resource.Github.
+func (m *MemoryGithub) CreateCommitComment(commitRef, comment string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := MemoryComment{ID: m.id(), Author: m.Viewer, Body: comment}
+	m.CommitComments[commitRef] = append(m.CommitComments[commitRef], c)
+	return fmt.Sprintf("%s/commit/%s#commitcomment-%d", m.url("", 0), commitRef, c.ID), nil
+}
+
+// UpsertComment implements resource.Github.
+func (m *MemoryGithub) UpsertComment(prNumber, marker, comment string) (string, error) {
+	m.mu.Lock()
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		m.mu.Unlock()
+		return "", err
+	}
+	for i := len(p.Comments) - 1; i >= 0; i-- {
+		if p.Comments[i].Author == m.Viewer && p.Comments[i].Minimized == "" && strings.Contains(p.Comments[i].Body, marker) {
+			p.Comments[i].Body = comment
+			m.mu.Unlock()
+			return m.commentURL("pull", p.Number, p.Comments[i].ID), nil
+		}
+	}
+	m.mu.Unlock()
+	return m.PostComment(prNumber, comment)
+}
+
+// GetLatestComment implements resource.Github.
+func (m *MemoryGithub) GetLatestComment(prNumber string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return "", err
+	}
+	for i := len(p.Comments) - 1; i >= 0; i-- {
+		if p.Comments[i].Author == m.Viewer {
+			return p.Comments[i].Body, nil
+		}
+	}
+	return "", nil
+}
+
+// FindComment implements resource.Github.
+func (m *MemoryGithub) FindComment(prNumber, text string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return "", err
+	}
+	for i := len(p.Comments) - 1; i >= 0; i-- {
+		if c := p.Comments[i]; c.Author == m.Viewer && strings.Contains(c.Body, text) {
+			return c.Body, nil
+		}
+	}
+	return "", nil
+}
+
+// AddCommentReaction implements resource.Github.
+func (m *MemoryGithub) AddCommentReaction(commentID int64, reaction string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.comment(commentID)
+	if err != nil {
+		return err
+	}
+	c.Reactions = append(c.Reactions, reaction)
+	return nil
+}
+
+// CreateGist implements resource.Github.
+func (m *MemoryGithub) CreateGist(description, filename, content string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sum := sha1.Sum([]byte(content))
+	gist := MemoryGist{
+		URL:         "https://gist.github.com/" + m.Viewer + "/" + hex.EncodeToString(sum[:]),
+		Description: description,
+		Filename:    filename,
+		Content:     content,
+	}
+	m.Gists = append(m.Gists, gist)
+	return gist.URL, nil
+}
+
+// RequestReviewers implements resource.Github.
+func (m *MemoryGithub) RequestReviewers(prNumber string, reviewers, teamReviewers []string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.RequestedReviewers = appendMissing(p.RequestedReviewers, append(reviewers, teamReviewers...)...)
+		return nil
+	})
+}
+
+// CreateReview implements resource.Github.
+func (m *MemoryGithub) CreateReview(prNumber, commitRef, event, body string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Reviews = append(p.Reviews, MemoryReview{Author: m.Viewer, CommitRef: commitRef, Event: event, Body: body})
+		return nil
+	})
+}
+
+// CreateSuggestions implements resource.Github.
+func (m *MemoryGithub) CreateSuggestions(prNumber, commitRef, body string, suggestions []resource.Suggestion) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Reviews = append(p.Reviews, MemoryReview{Author: m.Viewer, CommitRef: commitRef, Event: "COMMENT", Body: body, Suggestions: suggestions})
+		for _, s := range suggestions {
+			p.ReviewComments = append(p.ReviewComments, MemoryComment{
+				ID:     m.id(),
+				Author: m.Viewer,
+				Body:   "```suggestion\n" + strings.Join(s.Replacement, "\n") + "\n```",
+				Path:   s.Path,
+				Line:   s.Line,
+			})
+		}
+		return nil
+	})
+}
+
+// FindReviewThread implements resource.Github.
+func (m *MemoryGithub) FindReviewThread(prNumber, path string, line int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return 0, err
+	}
+	var resolved int64
+	for _, c := range p.ReviewComments {
+		if c.InReplyTo != 0 || c.Path != path || c.Line != line {
+			continue
+		}
+		if !c.Resolved {
+			return c.ID, nil
+		}
+		if resolved == 0 {
+			resolved = c.ID
+		}
+	}
+	if resolved == 0 {
+		return 0, fmt.Errorf("no review thread found on %s:%d", path, line)
+	}
+	return resolved, nil
+}
+
+// ReplyToReviewComment implements resource.Github.
+func (m *MemoryGithub) ReplyToReviewComment(prNumber string, commentID int64, body string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		for _, c := range p.ReviewComments {
+			if c.ID == commentID {
+				p.ReviewComments = append(p.ReviewComments, MemoryComment{
+					ID: m.id(), Author: m.Viewer, Body: body, Path: c.Path, Line: c.Line, InReplyTo: commentID,
+				})
+				return nil
+			}
+		}
+		return fmt.Errorf("review comment %d not found", commentID)
+	})
+}
+
+// DismissReviews implements resource.Github.
+func (m *MemoryGithub) DismissReviews(prNumber, message string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		for i := range p.Reviews {
+			if p.Reviews[i].Event == "APPROVE" {
+				p.Reviews[i].Dismissed = true
+			}
+		}
+		return nil
+	})
+}
+
+// MergePullRequest implements resource.Github.
+func (m *MemoryGithub) MergePullRequest(prNumber, commitRef, method, title, message string) (string, error) {
+	var sha string
+	err := m.update(prNumber, func(p *MemoryPullRequest) error {
+		if p.State != githubv4.PullRequestStateOpen {
+			return fmt.Errorf("pull request was not merged: pull request is %s", strings.ToLower(string(p.State)))
+		}
+		if commitRef != "" && p.Head().OID != commitRef {
+			return fmt.Errorf("head branch was modified. review and try the merge again")
+		}
+		sum := sha1.Sum([]byte(p.Head().OID + method))
+		sha = hex.EncodeToString(sum[:])
+		p.State = githubv4.PullRequestStateMerged
+		p.MergedAt = githubv4.DateTime{Time: time.Now()}
+		m.Branches[p.BaseRefName] = sha
+		return nil
+	})
+	return sha, err
+}
+
+// EnableAutoMerge implements resource.Github.
+func (m *MemoryGithub) EnableAutoMerge(prNumber, method string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.AutoMergeMethod = method
+		return nil
+	})
+}
+
+// UpdateBranch implements resource.Github.
+func (m *MemoryGithub) UpdateBranch(prNumber, commitRef, method string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		if commitRef != "" && p.Head().OID != commitRef {
+			return fmt.Errorf("expected head sha didn't match current head ref")
+		}
+		var commit resource.CommitObject
+		sum := sha1.Sum([]byte(p.Head().OID + m.Branches[p.BaseRefName]))
+		commit.OID = hex.EncodeToString(sum[:])
+		commit.CommittedDate = githubv4.DateTime{Time: time.Now()}
+		commit.Message = fmt.Sprintf("Merge branch '%s' into %s", p.BaseRefName, p.HeadRefName)
+		p.Commits = append(p.Commits, commit)
+		m.Branches[p.HeadRefName] = commit.OID
+		return nil
+	})
+}
+
+// MarkReadyForReview implements resource.Github.
+func (m *MemoryGithub) MarkReadyForReview(prNumber string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.IsDraft = false
+		return nil
+	})
+}
+
+// ConvertToDraft implements resource.Github.
+func (m *MemoryGithub) ConvertToDraft(prNumber string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.IsDraft = true
+		return nil
+	})
+}
+
+// UpdatePullRequest implements resource.Github.
+func (m *MemoryGithub) UpdatePullRequest(prNumber, title, body string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		if title != "" {
+			p.Title = title
+		}
+		if body != "" {
+			p.Body = body
+		}
+		return nil
+	})
+}
+
+// SetMilestone implements resource.Github.
+func (m *MemoryGithub) SetMilestone(prNumber, title string, create bool) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Milestone = title
+		return nil
+	})
+}
+
+// AddAssignees implements resource.Github.
+func (m *MemoryGithub) AddAssignees(prNumber string, assignees []string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Assignees = appendMissing(p.Assignees, assignees...)
+		return nil
+	})
+}
+
+// AddLabels implements resource.Github.
+func (m *MemoryGithub) AddLabels(prNumber string, labels []string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Labels = appendMissing(p.Labels, labels...)
+		return nil
+	})
+}
+
+// RemoveLabels implements resource.Github.
+func (m *MemoryGithub) RemoveLabels(prNumber string, labels []string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		var kept []string
+		for _, l := range p.Labels {
+			if !containsString(labels, l) {
+				kept = append(kept, l)
+			}
+		}
+		p.Labels = kept
+		return nil
+	})
+}
+
+// AddToProject implements resource.Github.
+func (m *MemoryGithub) AddToProject(prNumber string, project resource.Project) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Projects = append(p.Projects, project)
+		return nil
+	})
+}
+
+// ClosePullRequest implements resource.Github.
+func (m *MemoryGithub) ClosePullRequest(prNumber string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.State = githubv4.PullRequestStateClosed
+		p.ClosedAt = githubv4.DateTime{Time: time.Now()}
+		return nil
+	})
+}
+
+// ReopenPullRequest implements resource.Github.
+func (m *MemoryGithub) ReopenPullRequest(prNumber string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		if p.State == githubv4.PullRequestStateMerged {
+			return fmt.Errorf("pull request %d is merged", p.Number)
+		}
+		p.State = githubv4.PullRequestStateOpen
+		p.ClosedAt = githubv4.DateTime{}
+		return nil
+	})
+}
+
+// LockPullRequest implements resource.Github.
+func (m *MemoryGithub) LockPullRequest(prNumber, reason string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Locked, p.LockReason = true, reason
+		return nil
+	})
+}
+
+// UnlockPullRequest implements resource.Github.
+func (m *MemoryGithub) UnlockPullRequest(prNumber string) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		p.Locked, p.LockReason = false, ""
+		return nil
+	})
+}
+
+// DeleteBranch implements resource.Github.
+func (m *MemoryGithub) DeleteBranch(branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.Branches[branch]; !ok {
+		return fmt.Errorf("branch %s does not exist", branch)
+	}
+	delete(m.Branches, branch)
+	return nil
+}
+
+// CreateTag implements resource.Github.
+func (m *MemoryGithub) CreateTag(name, commitRef, message string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.Tags[name]; ok {
+		return fmt.Errorf("tag %s already exists", name)
+	}
+	m.Tags[name] = commitRef
+	return nil
+}
+
+// CreateRelease implements resource.Github.
+func (m *MemoryGithub) CreateRelease(release resource.Release) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Releases = append(m.Releases, release)
+	return nil
+}
+
+// Dispatch implements resource.Github.
+func (m *MemoryGithub) Dispatch(eventType string, payload map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Dispatches = append(m.Dispatches, MemoryDispatch{EventType: eventType, ClientPayload: payload})
+	return nil
+}
+
+// GetPullRequest implements resource.Github.
+func (m *MemoryGithub) GetPullRequest(prNumber, commitRef string) (*resource.PullRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range p.Commits {
+		if c.OID == commitRef {
+			pr := m.convert(p, c)
+			pr.ApprovedReviewCount = 0
+			return pr, nil
+		}
+	}
+	return nil, &resource.CommitNotFoundError{Ref: commitRef}
+}
+
+// GetPullRequestDetails implements resource.Github.
+func (m *MemoryGithub) GetPullRequestDetails(prNumber string) (*resource.PullRequestDetailsObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return nil, err
+	}
+	d := &resource.PullRequestDetailsObject{
+		ID:           p.ID,
+		Number:       p.Number,
+		Title:        p.Title,
+		Body:         p.Body,
+		URL:          p.URL,
+		State:        p.State,
+		IsDraft:      p.IsDraft,
+		Locked:       p.Locked,
+		ChangedFiles: len(p.Files),
+		UpdatedAt:    githubv4.DateTime{Time: p.UpdatedAt},
+		BaseRefName:  p.BaseRefName,
+		BaseRefOid:   m.Branches[p.BaseRefName],
+		HeadRefName:  p.HeadRefName,
+		HeadRefOid:   p.Head().OID,
+	}
+	d.Author.Login = p.Author
+	for _, a := range p.Assignees {
+		d.Assignees.Nodes = append(d.Assignees.Nodes, struct {
+			Login string `json:"login"`
+		}{a})
+	}
+	for _, l := range p.Labels {
+		d.Labels.Nodes = append(d.Labels.Nodes, struct {
+			Name string `json:"name"`
+		}{l})
+	}
+	return d, nil
+}
+
+// GetChangedFiles implements resource.Github.
+func (m *MemoryGithub) GetChangedFiles(prNumber, commitRef string) ([]resource.ChangedFileObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]resource.ChangedFileObject, 0, len(p.Files))
+	for _, f := range p.Files {
+		files = append(files, resource.ChangedFileObject{Path: f})
+	}
+	return files, nil
+}
+
+// GetLinkedIssues implements resource.Github.
+func (m *MemoryGithub) GetLinkedIssues(prNumber string) ([]resource.IssueObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return nil, err
+	}
+	var issues []resource.IssueObject
+	for _, n := range p.LinkedIssues {
+		if issue, ok := m.Issues[n]; ok {
+			issues = append(issues, issue.IssueObject)
+		}
+	}
+	return issues, nil
+}
+
+// CommentOnIssue implements resource.Github.
+func (m *MemoryGithub) CommentOnIssue(issueID, comment string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, err := m.issue(issueID)
+	if err != nil {
+		return err
+	}
+	issue.Comments = append(issue.Comments, MemoryComment{ID: m.id(), Author: m.Viewer, Body: comment})
+	return nil
+}
+
+// CloseIssue implements resource.Github.
+func (m *MemoryGithub) CloseIssue(issueID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, err := m.issue(issueID)
+	if err != nil {
+		return err
+	}
+	issue.State = githubv4.IssueStateClosed
+	return nil
+}
+
+// GetFileContent implements resource.Github.
+func (m *MemoryGithub) GetFileContent(path, ref string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	content, ok := m.Files[ref][path]
+	if !ok {
+		return nil, fmt.Errorf("file %s does not exist at %s", path, ref)
+	}
+	return content, nil
+}
+
+// UpdateCommitStatus implements resource.Github.
+func (m *MemoryGithub) UpdateCommitStatus(commitRef, baseContext, statusContext, status, targetURL, description string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if baseContext == "" {
+		baseContext = "concourse-ci"
+	}
+	if statusContext == "" {
+		statusContext = "status"
+	}
+	if description == "" {
+		description = fmt.Sprintf("Concourse CI build %s", status)
+	}
+	m.Statuses[commitRef] = append(m.Statuses[commitRef], MemoryStatus{
+		Context:     path.Join(baseContext, statusContext),
+		State:       strings.ToLower(status),
+		TargetURL:   targetURL,
+		Description: description,
+	})
+	return nil
+}
+
+// GetCommitStatus implements resource.Github.
+func (m *MemoryGithub) GetCommitStatus(commitRef, baseContext, statusContext string) (*resource.CommitStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if baseContext == "" {
+		baseContext = "concourse-ci"
+	}
+	if statusContext == "" {
+		statusContext = "status"
+	}
+	statuses := m.Statuses[commitRef]
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if s := statuses[i]; s.Context == path.Join(baseContext, statusContext) {
+			return &resource.CommitStatus{Context: s.Context, State: s.State, TargetURL: s.TargetURL, Description: s.Description}, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateCheckRun implements resource.Github.
+func (m *MemoryGithub) UpdateCheckRun(commitRef string, run resource.CheckRun) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	runs := m.CheckRuns[commitRef]
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].Name == run.Name {
+			runs[i].CheckRun = run
+			return runs[i].ID, nil
+		}
+	}
+	id := m.id()
+	m.CheckRuns[commitRef] = append(runs, MemoryCheckRun{ID: id, CheckRun: run})
+	return id, nil
+}
+
+// UpdateDeployment implements resource.Github.
+func (m *MemoryGithub) UpdateDeployment(commitRef string, d resource.Deployment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Deployments[commitRef] = append(m.Deployments[commitRef], d)
+	return nil
+}
+
+// DeletePreviousComments implements resource.Github.
+func (m *MemoryGithub) DeletePreviousComments(prNumber string, filter *regexp.Regexp) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		keep := func(c MemoryComment) bool {
+			return c.Author != m.Viewer || (filter != nil && !filter.MatchString(c.Body))
+		}
+		p.Comments = filterComments(p.Comments, keep)
+		p.ReviewComments = filterComments(p.ReviewComments, keep)
+		return nil
+	})
+}
+
+// DeleteComment implements resource.Github.
+func (m *MemoryGithub) DeleteComment(commentID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.comment(commentID); err != nil {
+		return err
+	}
+	keep := func(c MemoryComment) bool { return c.ID != commentID }
+	for _, p := range m.PullRequests {
+		p.Comments = filterComments(p.Comments, keep)
+	}
+	for _, issue := range m.Issues {
+		issue.Comments = filterComments(issue.Comments, keep)
+	}
+	return nil
+}
+
+// MinimizeComment implements resource.Github.
+func (m *MemoryGithub) MinimizeComment(commentID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.comment(commentID)
+	if err != nil {
+		return err
+	}
+	c.Minimized = string(githubv4.ReportedContentClassifiersResolved)
+	return nil
+}
+
+// MinimizePreviousComments implements resource.Github.
+func (m *MemoryGithub) MinimizePreviousComments(prNumber string, filter *regexp.Regexp) error {
+	return m.update(prNumber, func(p *MemoryPullRequest) error {
+		for i, c := range p.Comments {
+			if c.Author != m.Viewer || c.Minimized != "" || (filter != nil && !filter.MatchString(c.Body)) {
+				continue
+			}
+			p.Comments[i].Minimized = string(githubv4.ReportedContentClassifiersOutdated)
+		}
+		return nil
+	})
+}
+
+// update calls fn with the pull request (while holding the lock), and marks it as updated if fn succeeds.
+func (m *MemoryGithub) update(prNumber string, fn func(*MemoryPullRequest) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pullRequestString(prNumber)
+	if err != nil {
+		return err
+	}
+	if err := fn(p); err != nil {
+		return err
+	}
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *MemoryGithub) pullRequestString(prNumber string) (*MemoryPullRequest, error) {
+	number, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+	return m.pullRequest(number)
+}
+
+func (m *MemoryGithub) pullRequest(number int) (*MemoryPullRequest, error) {
+	p, ok := m.PullRequests[number]
+	if !ok {
+		return nil, fmt.Errorf("pull request %d does not exist", number)
+	}
+	return p, nil
+}
+
+func (m *MemoryGithub) issue(issueID string) (*MemoryIssue, error) {
+	number, err := strconv.Atoi(issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert issue number to int: %s", err)
+	}
+	issue, ok := m.Issues[number]
+	if !ok {
+		return nil, fmt.Errorf("issue %d does not exist", number)
+	}
+	return issue, nil
+}
+
+// comment returns a pointer to the comment (or review comment) with the ID.
+func (m *MemoryGithub) comment(id int64) (*MemoryComment, error) {
+	for _, p := range m.PullRequests {
+		for _, comments := range [][]MemoryComment{p.Comments, p.ReviewComments} {
+			for i := range comments {
+				if comments[i].ID == id {
+					return &comments[i], nil
+				}
+			}
+		}
+	}
+	for _, issue := range m.Issues {
+		for i := range issue.Comments {
+			if issue.Comments[i].ID == id {
+				return &issue.Comments[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("comment %d does not exist", id)
+}
+
+// convert returns the pull request at the commit.
+func (m *MemoryGithub) convert(p *MemoryPullRequest, commit resource.CommitObject) *resource.PullRequest {
+	pr := &resource.PullRequest{
+		PullRequestObject: p.PullRequestObject,
+		Tip:               commit,
+	}
+	pr.UpdatedAt = githubv4.DateTime{Time: p.UpdatedAt}
+	if date, ok := p.ForcePushes[commit.OID]; ok {
+		pr.ForcePushedDate = githubv4.DateTime{Time: date}
+	}
+	for _, r := range p.Reviews {
+		if r.Event == "APPROVE" && !r.Dismissed {
+			pr.ApprovedReviewCount++
+		}
+	}
+	for _, l := range p.Labels {
+		pr.Labels = append(pr.Labels, resource.LabelObject{Name: l})
+	}
+	for _, r := range p.RequestedReviewers {
+		reviewer := resource.RequestedReviewerObject{Typename: "User"}
+		reviewer.User.Login = r
+		pr.RequestedReviewers = append(pr.RequestedReviewers, reviewer)
+	}
+	return pr
+}
+
+// setReadiness sets the latest reviews (which have no dates) and the statuses and check runs of the tip.
+func (m *MemoryGithub) setReadiness(pr *resource.PullRequest, p *MemoryPullRequest) {
+	latest := make(map[string]string)
+	for _, r := range p.Reviews {
+		if (r.Event == "APPROVE" || r.Event == "REQUEST_CHANGES") && !r.Dismissed {
+			latest[r.Author] = r.Event
+		}
+	}
+	for _, event := range latest {
+		if event == "REQUEST_CHANGES" {
+			pr.ChangesRequestedCount++
+		}
+	}
+
+	seen := make(map[string]bool)
+	statuses := m.Statuses[pr.Tip.OID]
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if s := statuses[i]; !seen[s.Context] {
+			seen[s.Context] = true
+			pr.Contexts = append(pr.Contexts, resource.CommitContext{Name: s.Context, State: s.State})
+		}
+	}
+	for _, r := range m.CheckRuns[pr.Tip.OID] {
+		state := "pending"
+		switch {
+		case r.Status != "completed":
+		case r.Conclusion == "success" || r.Conclusion == "neutral" || r.Conclusion == "skipped":
+			state = "success"
+		default:
+			state = "failure"
+		}
+		pr.Contexts = append(pr.Contexts, resource.CommitContext{Name: r.Name, State: state})
+	}
+}
+
+func (m *MemoryGithub) id() int64 {
+	m.nextID++
+	return m.nextID
+}
+
+func (m *MemoryGithub) url(kind string, number int) string {
+	u := fmt.Sprintf("https://github.com/%s/%s", m.Owner, m.Repository)
+	if kind != "" {
+		u += fmt.Sprintf("/%s/%d", kind, number)
+	}
+	return u
+}
+
+func (m *MemoryGithub) commentURL(kind string, number int, id int64) string {
+	return fmt.Sprintf("%s#issuecomment-%d", m.url(kind, number), id)
+}
+
+func filterComments(comments []MemoryComment, keep func(MemoryComment) bool) []MemoryComment {
+	var kept []MemoryComment
+	for _, c := range comments {
+		if keep(c) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+func containsState(states []githubv4.PullRequestState, state githubv4.PullRequestState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func appendMissing(values []string, more ...string) []string {
+	for _, v := range more {
+		if !containsString(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+var _ resource.Github = new(MemoryGithub)
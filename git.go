@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -17,12 +18,22 @@ import (
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_git.go . Git
 type Git interface {
 	Init(string) error
-	Pull(string, string, int, bool, bool) error
+	Config(string, string) error
+	Pull(string, string, int, bool, bool, string) error
+	Mirror(string, string, int, int) error
 	RevParse(string) (string, error)
-	Fetch(string, int, int, bool) error
+	Show(string, string) ([]byte, error)
+	MergeBase(string, string) (string, error)
+	CommitMessages(string, string) ([]string, error)
+	Diff(string, string, string) error
+	Fetch(string, int, int, bool, string) error
+	FetchCommit(string, string, int) error
+	Deepen(string, string, int, int) error
 	Checkout(string, string, bool) error
 	Merge(string, bool) error
 	Rebase(string, string, bool) error
+	Branch(string) error
+	TrackBase(string, string) error
 	GitCryptUnlock(string) error
 }
 
@@ -31,24 +42,104 @@ func NewGitClient(source *Source, dir string, output io.Writer) (*GitClient, err
 	if source.SkipSSLVerification {
 		os.Setenv("GIT_SSL_NO_VERIFY", "true")
 	}
+	if source.CACerts != "" {
+		path, err := writeCABundle(source.CACerts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write ca_certs: %s", err)
+		}
+		os.Setenv("GIT_SSL_CAINFO", path)
+	}
+	// Proxies are configured through the environment (which takes care of no_proxy)
+	for name, proxy := range map[string]string{"http_proxy": source.HTTPProxy, "https_proxy": source.HTTPSProxy, "no_proxy": source.NoProxy} {
+		if proxy != "" {
+			os.Setenv(name, proxy)
+		}
+	}
 	if source.DisableGitLFS {
 		os.Setenv("GIT_LFS_SKIP_SMUDGE", "true")
 	}
+	for _, secret := range source.secrets() {
+		addSecret(secret)
+	}
+	sshConfig, err := writeSSHConfig(source.SubmoduleCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write ssh config for submodule_credentials: %s", err)
+	}
 	return &GitClient{
-		AccessToken: source.AccessToken,
-		Directory:   dir,
-		Output:      output,
+		AccessToken:          source.AccessToken,
+		SubmoduleCredentials: source.SubmoduleCredentials,
+		SSHConfig:            sshConfig,
+		Directory:            dir,
+		Output:               NewRedactingWriter(output),
 	}, nil
 }
 
+// writeSSHConfig writes the private keys (and known hosts) of the submodule credentials to a temporary
+// directory, along with an ssh config which uses them for the hosts. Returns the path of the config, or an
+// empty string if no private keys are used. The directory is removed by GitClient.Close.
+func writeSSHConfig(credentials []SubmoduleCredential) (path string, err error) {
+	var config strings.Builder
+	var dir string
+	defer func() {
+		if err != nil && dir != "" {
+			os.RemoveAll(dir)
+		}
+	}()
+	for i, c := range credentials {
+		if c.PrivateKey == "" {
+			continue
+		}
+		if dir == "" {
+			if dir, err = ioutil.TempDir("", "ssh-"); err != nil {
+				return "", err
+			}
+		}
+		key := filepath.Join(dir, fmt.Sprintf("key-%d", i))
+		if err := ioutil.WriteFile(key, []byte(strings.TrimSpace(c.PrivateKey)+"\n"), 0600); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&config, "Host %s\n  IdentityFile %s\n  IdentitiesOnly yes\n", c.Host, key)
+		if c.SkipHostKeyVerification {
+			fmt.Fprintf(&config, "  StrictHostKeyChecking no\n  UserKnownHostsFile /dev/null\n")
+			continue
+		}
+		knownHosts := filepath.Join(dir, fmt.Sprintf("known_hosts-%d", i))
+		if err := ioutil.WriteFile(knownHosts, []byte(strings.TrimSpace(c.KnownHosts)+"\n"), 0600); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&config, "  StrictHostKeyChecking yes\n  UserKnownHostsFile %s\n", knownHosts)
+	}
+	if dir == "" {
+		return "", nil
+	}
+	path = filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(config.String()), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 // GitClient ...
 type GitClient struct {
-	AccessToken string
-	Directory   string
-	Output      io.Writer
+	AccessToken          string
+	SubmoduleCredentials []SubmoduleCredential
+	SSHConfig            string
+	Directory            string
+	Output               io.Writer
+}
+
+// Close removes the private keys of the submodule credentials (if any) from disk.
+func (g *GitClient) Close() error {
+	if g.SSHConfig == "" {
+		return nil
+	}
+	return os.RemoveAll(filepath.Dir(g.SSHConfig))
 }
 
 func (g *GitClient) command(name string, arg ...string) *exec.Cmd {
+	if name == "git" {
+		arg = append(g.submoduleConfig(), arg...)
+	}
 	cmd := exec.Command(name, arg...)
 	cmd.Dir = g.Directory
 	cmd.Stdout = g.Output
@@ -57,9 +148,36 @@ func (g *GitClient) command(name string, arg ...string) *exec.Cmd {
 	cmd.Env = append(cmd.Env,
 		"X_OAUTH_BASIC_TOKEN="+g.AccessToken,
 		"GIT_ASKPASS=/usr/local/bin/askpass.sh")
+	// Credentials for submodules are passed through the environment (read by the
+	// credential helpers from submoduleConfig) to avoid writing them to disk.
+	for i, c := range g.SubmoduleCredentials {
+		cmd.Env = append(cmd.Env,
+			fmt.Sprintf("GIT_SUBMODULE_USERNAME_%d=%s", i, c.Username),
+			fmt.Sprintf("GIT_SUBMODULE_PASSWORD_%d=%s", i, c.Password))
+	}
+	if g.SSHConfig != "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -F "+g.SSHConfig)
+	}
 	return cmd
 }
 
+// submoduleConfig returns the git options (-c) which configure the credentials for submodules over HTTPS,
+// and rewrite SSH URLs for those hosts to HTTPS. They are passed on the command line since the clones of
+// submodules do not read the configuration of the repository, but inherit the options.
+func (g *GitClient) submoduleConfig() []string {
+	var args []string
+	for i, c := range g.SubmoduleCredentials {
+		if c.Password == "" {
+			continue
+		}
+		helper := fmt.Sprintf(`!f() { echo "username=${GIT_SUBMODULE_USERNAME_%d}"; echo "password=${GIT_SUBMODULE_PASSWORD_%d}"; }; f`, i, i)
+		args = append(args,
+			"-c", fmt.Sprintf("credential.https://%s.helper=%s", c.Host, helper),
+			"-c", fmt.Sprintf("url.https://%s/.insteadOf=git@%s:", c.Host, c.Host))
+	}
+	return args
+}
+
 // Init ...
 func (g *GitClient) Init(branch string) error {
 	if err := g.command("git", "init").Run(); err != nil {
@@ -74,6 +192,11 @@ func (g *GitClient) Init(branch string) error {
 	if err := g.command("git", "config", "user.email", "concourse@local").Run(); err != nil {
 		return fmt.Errorf("failed to configure git email: %s", err)
 	}
+	// Protocol v2 only advertises the refs which are fetched, which is much faster for large repositories
+	// (and is not the default before git 2.26).
+	if err := g.command("git", "config", "protocol.version", "2").Run(); err != nil {
+		return fmt.Errorf("failed to configure git protocol version: %s", err)
+	}
 	if err := g.command("git", "config", "url.https://x-oauth-basic@github.com/.insteadOf", "git@github.com:").Run(); err != nil {
 		return fmt.Errorf("failed to configure github url: %s", err)
 	}
@@ -83,21 +206,31 @@ func (g *GitClient) Init(branch string) error {
 	return nil
 }
 
-// Pull ...
-func (g *GitClient) Pull(uri, branch string, depth int, submodules bool, fetchTags bool) error {
-	endpoint, err := g.Endpoint(uri)
-	if err != nil {
-		return err
+// Config sets a git configuration option in the local repository.
+func (g *GitClient) Config(key, value string) error {
+	if err := g.command("git", "config", key, value).Run(); err != nil {
+		return fmt.Errorf("failed to configure %s: %s", key, err)
 	}
+	return nil
+}
 
-	if err := g.command("git", "remote", "add", "origin", endpoint).Run(); err != nil {
-		return fmt.Errorf("setting 'origin' remote to '%s' failed: %s", endpoint, err)
+// Pull ...
+func (g *GitClient) Pull(uri, branch string, depth int, submodules bool, fetchTags bool, filter string) (err error) {
+	span := startSpan("git pull", spanKindInternal, "branch", branch, "depth", depth)
+	defer func() { span.End(err) }()
+
+	if err := g.remote(uri, filter); err != nil {
+		return err
 	}
 
-	args := []string{"pull", "origin", branch}
+	// git pull does not support --filter, so the branch is fetched and checked out instead.
+	args := []string{"fetch", "origin", branch}
 	if depth > 0 {
 		args = append(args, "--depth", strconv.Itoa(depth))
 	}
+	if filter != "" {
+		args = append(args, "--filter", filter)
+	}
 	if fetchTags {
 		args = append(args, "--tags")
 	}
@@ -106,12 +239,11 @@ func (g *GitClient) Pull(uri, branch string, depth int, submodules bool, fetchTa
 	}
 	cmd := g.command("git", args...)
 
-	// Discard output to have zero chance of logging the access token.
-	cmd.Stdout = ioutil.Discard
-	cmd.Stderr = ioutil.Discard
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("pull failed: %s", cmd)
+	if err := runRedacted(cmd); err != nil {
+		return fmt.Errorf("pull failed: %s", err)
+	}
+	if err := g.command("git", "reset", "--hard", "FETCH_HEAD").Run(); err != nil {
+		return fmt.Errorf("checkout of %s failed: %s", branch, err)
 	}
 	if submodules {
 		submodulesGet := g.command("git", "submodule", "update", "--init", "--recursive")
@@ -122,6 +254,70 @@ func (g *GitClient) Pull(uri, branch string, depth int, submodules bool, fetchTa
 	return nil
 }
 
+// remote adds (or updates) the origin remote, which all fetches go through. When a partial clone
+// filter is used, origin is registered as the promisor remote so that omitted objects can be
+// fetched lazily later on.
+func (g *GitClient) remote(uri, filter string) error {
+	endpoint, err := g.Endpoint(uri)
+	if err != nil {
+		return err
+	}
+
+	if err := g.command("git", "remote", "add", "origin", endpoint).Run(); err != nil {
+		// The remote already exists when retrying a failed pull.
+		if err := g.command("git", "remote", "set-url", "origin", endpoint).Run(); err != nil {
+			return fmt.Errorf("setting 'origin' remote to '%s' failed: %s", uri, err)
+		}
+	}
+
+	if filter != "" {
+		for _, c := range [][2]string{
+			{"core.repositoryformatversion", "1"},
+			{"extensions.partialClone", "origin"},
+			{"remote.origin.promisor", "true"},
+			{"remote.origin.partialclonefilter", filter},
+		} {
+			if err := g.command("git", "config", c[0], c[1]).Run(); err != nil {
+				return fmt.Errorf("failed to configure partial clone (%s): %s", c[0], err)
+			}
+		}
+	}
+	return nil
+}
+
+// Mirror fetches the base branch and the pull request head into a bare repository
+// (without a working tree) at refs/heads/<branch> and refs/pull/<number>/head.
+func (g *GitClient) Mirror(uri, branch string, prNumber int, depth int) (err error) {
+	span := startSpan("git mirror", spanKindInternal, "branch", branch, "pr", prNumber, "depth", depth)
+	defer func() { span.End(err) }()
+
+	endpoint, err := g.Endpoint(uri)
+	if err != nil {
+		return err
+	}
+
+	if err := g.command("git", "init", "--bare", ".git").Run(); err != nil {
+		return fmt.Errorf("init failed: %s", err)
+	}
+	if err := g.command("git", "config", "remote.origin.url", endpoint).Run(); err != nil {
+		return fmt.Errorf("setting 'origin' remote to '%s' failed: %s", uri, err)
+	}
+
+	args := []string{"fetch", "origin",
+		fmt.Sprintf("+refs/heads/%s:refs/heads/%s", branch, branch),
+		fmt.Sprintf("+refs/pull/%d/head:refs/pull/%d/head", prNumber, prNumber),
+	}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	cmd := g.command("git", args...)
+
+	if err := runRedacted(cmd); err != nil {
+		return fmt.Errorf("fetch failed: %s", err)
+	}
+	return nil
+}
+
 // RevParse retrieves the SHA of the given branch.
 func (g *GitClient) RevParse(branch string) (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--verify", branch)
@@ -133,34 +329,134 @@ func (g *GitClient) RevParse(branch string) (string, error) {
 	return strings.TrimSpace(string(sha)), nil
 }
 
-// Fetch ...
-func (g *GitClient) Fetch(uri string, prNumber int, depth int, submodules bool) error {
-	endpoint, err := g.Endpoint(uri)
+// Show returns the content of a file at the given revision.
+func (g *GitClient) Show(rev, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", rev, path))
+	cmd.Dir = g.Directory
+	cmd.Stderr = ioutil.Discard
+	content, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("show '%s:%s' failed: %s", rev, path, err)
+	}
+	return content, nil
+}
+
+// MergeBase retrieves the SHA of the best common ancestor of two commits.
+func (g *GitClient) MergeBase(base, head string) (string, error) {
+	cmd := exec.Command("git", "merge-base", base, head)
+	cmd.Dir = g.Directory
+	sha, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("merge-base '%s' '%s' failed: %s: %s", base, head, err, string(sha))
+	}
+	return strings.TrimSpace(string(sha)), nil
+}
+
+// CommitMessages returns the messages of the commits reachable from head but not from base.
+func (g *GitClient) CommitMessages(base, head string) ([]string, error) {
+	cmd := exec.Command("git", "log", "--format=%B%x00", fmt.Sprintf("%s..%s", base, head))
+	cmd.Dir = g.Directory
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("log '%s..%s' failed: %s: %s", base, head, err, string(out))
+	}
+	var messages []string
+	for _, m := range strings.Split(string(out), "\x00") {
+		if m = strings.TrimSpace(m); m != "" {
+			messages = append(messages, m)
+		}
+	}
+	return messages, nil
+}
+
+// Diff writes the unified diff between the merge base of two commits and the latter to a file.
+func (g *GitClient) Diff(base, head, path string) error {
+	f, err := os.Create(path)
 	if err != nil {
+		return fmt.Errorf("failed to create diff file: %s", err)
+	}
+	defer f.Close()
+
+	cmd := exec.Command("git", "diff", "--binary", fmt.Sprintf("%s...%s", base, head))
+	cmd.Dir = g.Directory
+	cmd.Stdout = f
+	cmd.Stderr = g.Output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("diff '%s...%s' failed: %s", base, head, err)
+	}
+	return nil
+}
+
+// Fetch ...
+func (g *GitClient) Fetch(uri string, prNumber int, depth int, submodules bool, filter string) (err error) {
+	span := startSpan("git fetch", spanKindInternal, "pr", prNumber, "depth", depth)
+	defer func() { span.End(err) }()
+
+	if err := g.remote(uri, filter); err != nil {
 		return err
 	}
 
-	args := []string{"fetch", endpoint, fmt.Sprintf("pull/%s/head", strconv.Itoa(prNumber))}
+	args := []string{"fetch", "origin", fmt.Sprintf("pull/%s/head", strconv.Itoa(prNumber))}
 	if depth > 0 {
 		args = append(args, "--depth", strconv.Itoa(depth))
 	}
+	if filter != "" {
+		args = append(args, "--filter", filter)
+	}
 	if submodules {
 		args = append(args, "--recurse-submodules")
 	}
 	cmd := g.command("git", args...)
 
-	// Discard output to have zero chance of logging the access token.
-	cmd.Stdout = ioutil.Discard
-	cmd.Stderr = ioutil.Discard
-
-	if err := cmd.Run(); err != nil {
+	if err := runRedacted(cmd); err != nil {
 		return fmt.Errorf("fetch failed: %s", err)
 	}
 	return nil
 }
 
+// FetchCommit fetches a single commit by its SHA, e.g. when it is no longer part of the pull request.
+func (g *GitClient) FetchCommit(uri, sha string, depth int) (err error) {
+	span := startSpan("git fetch", spanKindInternal, "sha", sha, "depth", depth)
+	defer func() { span.End(err) }()
+
+	if err := g.remote(uri, ""); err != nil {
+		return err
+	}
+
+	args := []string{"fetch", "origin", sha}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	cmd := g.command("git", args...)
+
+	if err := runRedacted(cmd); err != nil {
+		return fmt.Errorf("fetch of commit %s failed: %s", sha, err)
+	}
+	return nil
+}
+
+// Deepen the shallow history of both the base branch and the pull request by the given number of commits.
+func (g *GitClient) Deepen(uri, branch string, prNumber int, depth int) (err error) {
+	span := startSpan("git deepen", spanKindInternal, "branch", branch, "pr", prNumber, "depth", depth)
+	defer func() { span.End(err) }()
+
+	if err := g.remote(uri, ""); err != nil {
+		return err
+	}
+
+	cmd := g.command("git", "fetch", "--deepen", strconv.Itoa(depth), "origin", branch, fmt.Sprintf("pull/%s/head", strconv.Itoa(prNumber)))
+
+	if err := runRedacted(cmd); err != nil {
+		return fmt.Errorf("deepen failed: %s", err)
+	}
+	return nil
+}
+
 // CheckOut
-func (g *GitClient) Checkout(branch, sha string, submodules bool) error {
+func (g *GitClient) Checkout(branch, sha string, submodules bool) (err error) {
+	span := startSpan("git checkout", spanKindInternal, "branch", branch, "sha", sha)
+	defer func() { span.End(err) }()
+
 	if err := g.command("git", "checkout", "-b", branch, sha).Run(); err != nil {
 		return fmt.Errorf("checkout failed: %s", err)
 	}
@@ -175,7 +471,10 @@ func (g *GitClient) Checkout(branch, sha string, submodules bool) error {
 }
 
 // Merge ...
-func (g *GitClient) Merge(sha string, submodules bool) error {
+func (g *GitClient) Merge(sha string, submodules bool) (err error) {
+	span := startSpan("git merge", spanKindInternal, "sha", sha)
+	defer func() { span.End(err) }()
+
 	if err := g.command("git", "merge", sha, "--no-stat").Run(); err != nil {
 		return fmt.Errorf("merge failed: %s", err)
 	}
@@ -190,7 +489,10 @@ func (g *GitClient) Merge(sha string, submodules bool) error {
 }
 
 // Rebase ...
-func (g *GitClient) Rebase(baseRef string, headSha string, submodules bool) error {
+func (g *GitClient) Rebase(baseRef string, headSha string, submodules bool) (err error) {
+	span := startSpan("git rebase", spanKindInternal, "base", baseRef, "sha", headSha)
+	defer func() { span.End(err) }()
+
 	if err := g.command("git", "rebase", baseRef, headSha).Run(); err != nil {
 		return fmt.Errorf("rebase failed: %s", err)
 	}
@@ -204,6 +506,30 @@ func (g *GitClient) Rebase(baseRef string, headSha string, submodules bool) erro
 	return nil
 }
 
+// Branch creates (or resets) a local branch at the current HEAD, and checks it out.
+func (g *GitClient) Branch(name string) (err error) {
+	span := startSpan("git branch", spanKindInternal, "branch", name)
+	defer func() { span.End(err) }()
+
+	if err := g.command("git", "checkout", "-B", name).Run(); err != nil {
+		return fmt.Errorf("branch failed: %s", err)
+	}
+	return nil
+}
+
+// TrackBase points the remote tracking ref of the base branch (refs/remotes/origin/<branch>) at the
+// given commit, and creates a symbolic ref named base which points to it.
+func (g *GitClient) TrackBase(branch, sha string) error {
+	ref := "refs/remotes/origin/" + branch
+	if err := g.command("git", "update-ref", ref, sha).Run(); err != nil {
+		return fmt.Errorf("failed to update %s: %s", ref, err)
+	}
+	if err := g.command("git", "symbolic-ref", "base", ref).Run(); err != nil {
+		return fmt.Errorf("failed to create symbolic ref base: %s", err)
+	}
+	return nil
+}
+
 // GitCryptUnlock unlocks the repository using git-crypt
 func (g *GitClient) GitCryptUnlock(base64key string) error {
 	keyDir, err := ioutil.TempDir("", "")
@@ -225,12 +551,30 @@ func (g *GitClient) GitCryptUnlock(base64key string) error {
 	return nil
 }
 
+// runRedacted runs a command which talks to the remote, and whose output might contain the access token
+// (e.g. in the URL of the remote). Its output is only included in the error (with secrets redacted).
+func runRedacted(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			return fmt.Errorf("%s: %s", err, Redact(output))
+		}
+		return err
+	}
+	return nil
+}
+
 // Endpoint takes an uri and produces an endpoint with the login information baked in.
 func (g *GitClient) Endpoint(uri string) (string, error) {
 	endpoint, err := url.Parse(uri)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse commit url: %s", err)
 	}
-	endpoint.User = url.UserPassword("x-oauth-basic", g.AccessToken)
+	// Public repositories are cloned anonymously when no token is set
+	if g.AccessToken != "" {
+		endpoint.User = url.UserPassword("x-oauth-basic", g.AccessToken)
+	}
 	return endpoint.String(), nil
 }
@@ -2,15 +2,15 @@ package resource
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v28/github"
 	"github.com/shurcooL/githubv4"
@@ -20,13 +20,59 @@ import (
 // Github for testing purposes.
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_github.go . Github
 type Github interface {
-	ListPullRequests([]githubv4.PullRequestState) ([]*PullRequest, error)
+	APIUsage() APIUsage
+	GetTokenInfo() (*TokenInfo, error)
+	ListPullRequests([]githubv4.PullRequestState, PullRequestFields, time.Time) ([]*PullRequest, error)
 	ListModifiedFiles(int) ([]string, error)
-	PostComment(string, string) error
+	CompareCommits(string, string) (*CommitComparison, error)
+	ListCommits(int) ([]PullRequestCommit, error)
+	PostComment(string, string) (string, error)
+	UpsertComment(string, string, string) (string, error)
+	GetLatestComment(string) (string, error)
+	FindComment(string, string) (string, error)
+	CreateCommitComment(string, string) (string, error)
+	AddCommentReaction(int64, string) error
+	CreateGist(string, string, string) (string, error)
+	RequestReviewers(string, []string, []string) error
+	CreateReview(string, string, string, string) error
+	CreateSuggestions(string, string, string, []Suggestion) error
+	FindReviewThread(string, string, int) (int64, error)
+	ReplyToReviewComment(string, int64, string) error
+	DismissReviews(string, string) error
+	MergePullRequest(string, string, string, string, string) (string, error)
+	EnableAutoMerge(string, string) error
+	UpdateBranch(string, string, string) error
+	MarkReadyForReview(string) error
+	ConvertToDraft(string) error
+	UpdatePullRequest(string, string, string) error
+	SetMilestone(string, string, bool) error
+	AddAssignees(string, []string) error
+	AddLabels(string, []string) error
+	RemoveLabels(string, []string) error
+	AddToProject(string, Project) error
+	ClosePullRequest(string) error
+	ReopenPullRequest(string) error
+	LockPullRequest(string, string) error
+	UnlockPullRequest(string) error
+	DeleteBranch(string) error
+	CreateTag(string, string, string) error
+	CreateRelease(Release) error
+	Dispatch(string, map[string]interface{}) error
 	GetPullRequest(string, string) (*PullRequest, error)
+	GetPullRequestDetails(string) (*PullRequestDetailsObject, error)
 	GetChangedFiles(string, string) ([]ChangedFileObject, error)
+	GetLinkedIssues(string) ([]IssueObject, error)
+	CommentOnIssue(string, string) error
+	CloseIssue(string) error
+	GetFileContent(string, string) ([]byte, error)
 	UpdateCommitStatus(string, string, string, string, string, string) error
-	DeletePreviousComments(string) error
+	GetCommitStatus(string, string, string) (*CommitStatus, error)
+	UpdateCheckRun(string, CheckRun) (int64, error)
+	UpdateDeployment(string, Deployment) error
+	DeletePreviousComments(string, *regexp.Regexp) error
+	DeleteComment(int64) error
+	MinimizeComment(int64) error
+	MinimizePreviousComments(string, *regexp.Regexp) error
 }
 
 // GithubClient for handling requests to the Github V3 and V4 APIs.
@@ -35,6 +81,16 @@ type GithubClient struct {
 	V4         *githubv4.Client
 	Repository string
 	Owner      string
+
+	tokenSource oauth2.TokenSource
+	app         *appTokenSource
+	apiVersion  string
+	schema      map[string]bool
+	viewer      string
+	transport   *retryTransport
+	apiMode     string
+	// rest is true if the REST API is used instead of GraphQL (see rest.go).
+	rest bool
 }
 
 // NewGithubClient ...
@@ -44,26 +100,88 @@ func NewGithubClient(s *Source) (*GithubClient, error) {
 		return nil, err
 	}
 
-	// Skip SSL verification for self-signed certificates
+	// Redact credentials from logs and errors
+	for _, secret := range s.secrets() {
+		addSecret(secret)
+	}
+
+	// Trust custom CAs or skip SSL verification for self-signed certificates
 	// source: https://github.com/google/go-github/pull/598#issuecomment-333039238
-	var ctx context.Context
-	if s.SkipSSLVerification {
-		insecureClient := &http.Client{Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	config, err := tlsConfig(s)
+	if err != nil {
+		return nil, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = config
+	t.Proxy = proxyFunc(s)
+	var transport http.RoundTripper = t
+
+	// Record or replay responses when a cassette is selected (see cassette.go)
+	if transport, err = newCassetteTransport(transport); err != nil {
+		return nil, err
+	}
+
+	// Limit the number of concurrent requests (below the retries, so that waiting to retry does not hold a slot).
+	// Anonymous requests are made one at a time by default, so that the (much lower) rate limit is tracked
+	// accurately and waited for before it is exhausted.
+	concurrency := s.APIConcurrency
+	if concurrency == 0 && s.unauthenticated() {
+		concurrency = 1
+	}
+	if concurrency > 0 {
+		transport = newConcurrencyTransport(transport, concurrency)
+	}
+
+	// Log requests (including each retry) at debug level
+	transport = &debugTransport{base: transport}
+
+	// Trace requests (including each retry) when tracing is configured
+	transport = &tracingTransport{base: transport}
+
+	// Retry requests that fail due to transient errors or rate limiting, and explain
+	// requests that fail due to missing permissions
+	retry := &retryTransport{base: transport}
+	retry.attempts, retry.delay, retry.timeout = s.retryPolicy()
+	transport = &permissionsTransport{base: retry}
+
+	var (
+		tokenSource oauth2.TokenSource
+		app         *appTokenSource
+	)
+	switch {
+	case s.AppID != 0:
+		app, err = newAppTokenSource(s, owner, repository, &http.Client{Transport: transport})
+		if err != nil {
+			return nil, err
 		}
-		ctx = context.WithValue(context.TODO(), oauth2.HTTPClient, insecureClient)
-	} else {
-		ctx = context.TODO()
+		tokenSource = oauth2.ReuseTokenSource(nil, app)
+	case s.AccessTokenFile != "":
+		tokenSource = &fileTokenSource{path: s.AccessTokenFile}
+	case s.AccessToken != "":
+		tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: s.AccessToken})
+	}
+
+	// The token source is used as is (instead of oauth2.NewClient which caches the token),
+	// so that tokens which are rotated on disk are picked up.
+	client := &http.Client{Transport: transport}
+	if tokenSource != nil {
+		client.Transport = &oauth2.Transport{Source: tokenSource, Base: transport}
+	}
+	if s.AccessTokenFile != "" {
+		client.Transport = &unauthorizedRetryTransport{base: client.Transport}
 	}
 
-	client := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: s.AccessToken},
-	))
+	// Record the changes made through the API when put keeps an audit log
+	client.Transport = &auditTransport{base: client.Transport}
+
+	// Include the details of errors returned by the API (e.g. GraphQL errors and the request ID)
+	client.Transport = &apiErrorTransport{base: client.Transport}
+
+	v3Endpoint, v4Endpoint := s.Endpoints()
 
 	var v3 *github.Client
-	if s.V3Endpoint != "" {
-		endpoint, err := url.Parse(s.V3Endpoint)
+	if v3Endpoint != "" {
+		endpoint, err := url.Parse(v3Endpoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse v3 endpoint: %s", err)
 		}
@@ -76,15 +194,12 @@ func NewGithubClient(s *Source) (*GithubClient, error) {
 	}
 
 	var v4 *githubv4.Client
-	if s.V4Endpoint != "" {
-		endpoint, err := url.Parse(s.V4Endpoint)
+	if v4Endpoint != "" {
+		endpoint, err := url.Parse(v4Endpoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse v4 endpoint: %s", err)
 		}
 		v4 = githubv4.NewEnterpriseClient(endpoint.String(), client)
-		if err != nil {
-			return nil, err
-		}
 	} else {
 		v4 = githubv4.NewClient(client)
 	}
@@ -94,11 +209,108 @@ func NewGithubClient(s *Source) (*GithubClient, error) {
 		V4:         v4,
 		Owner:      owner,
 		Repository: repository,
+
+		tokenSource: tokenSource,
+		app:         app,
+		apiVersion:  s.GithubAPIVersion,
+		transport:   retry,
+		apiMode:     s.APIMode,
+		rest:        s.APIMode == apiModeREST || s.unauthenticated(),
 	}, nil
 }
 
-// ListPullRequests gets the last commit on all pull requests with the matching state.
-func (m *GithubClient) ListPullRequests(prStates []githubv4.PullRequestState) ([]*PullRequest, error) {
+// Token returns the token used to authenticate with Github, e.g. the current installation token
+// when authenticating as a Github App, or an empty string when no credentials are set.
+func (m *GithubClient) Token() (string, error) {
+	if m.tokenSource == nil {
+		return "", nil
+	}
+	token, err := m.tokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// APIUsage returns the requests made to the Github API by the client so far.
+func (m *GithubClient) APIUsage() APIUsage {
+	return m.transport.usage()
+}
+
+// GetTokenInfo returns the scopes (or app permissions) of the token, and whether the repository is private.
+func (m *GithubClient) GetTokenInfo() (*TokenInfo, error) {
+	repository, res, err := m.V3.Repositories.Get(context.TODO(), m.Owner, m.Repository)
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			if token, err := m.Token(); err == nil && strings.HasPrefix(token, fineGrainedTokenPrefix) {
+				return nil, fmt.Errorf("repository %s/%s does not exist or has not been selected for the fine-grained token", m.Owner, m.Repository)
+			}
+			if m.tokenSource == nil {
+				return nil, fmt.Errorf("repository %s/%s does not exist or is not public (no credentials are set)", m.Owner, m.Repository)
+			}
+			return nil, fmt.Errorf("repository %s/%s does not exist or the token does not have access to it", m.Owner, m.Repository)
+		}
+		return nil, err
+	}
+
+	info := &TokenInfo{Private: repository.GetPrivate()}
+	if token, err := m.Token(); err == nil && strings.HasPrefix(token, fineGrainedTokenPrefix) {
+		info.FineGrained = true
+	} else if m.app != nil {
+		info.Permissions = m.app.permissions
+	} else if _, ok := res.Header["X-Oauth-Scopes"]; ok {
+		info.Scopes = []string{}
+		for _, scope := range strings.Split(res.Header.Get("X-Oauth-Scopes"), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+	}
+	return info, nil
+}
+
+// PullRequestFields selects the optional data which is fetched when listing pull requests.
+type PullRequestFields struct {
+	Labels  bool
+	Reviews bool
+	Files   bool
+	// Readiness is the review decision, the latest reviews and the statuses and check runs of the tip,
+	// which are needed to tell whether a pull request is ready to merge.
+	Readiness bool
+}
+
+// pullRequestsPageSize is the number of pull requests fetched per page, and filesPageSize is the
+// number of changed files fetched for each of them (pull requests with more files have nil Files).
+const (
+	pullRequestsPageSize = 100
+	filesPageSize        = 100
+)
+
+// isQueryLimitError returns true if a GraphQL query failed because it exceeded the node or resource
+// limits of the API (or timed out), in which case a smaller query might succeed.
+func isQueryLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, s := range []string{"exceeds the maximum limit", "limits for this query exceeded", "result of a timeout"} {
+		if strings.Contains(err.Error(), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListPullRequests gets the last commit on all pull requests with the matching state, along with the
+// changed files if requested. If the query exceeds the limits of the API, the number of pull requests
+// per page is reduced, and as a last resort the files are left out.
+//
+// Pull requests are listed from the most recently updated, and if since is set, only pull requests
+// which have been updated after it are listed (i.e. pagination stops at the first older pull request).
+func (m *GithubClient) ListPullRequests(prStates []githubv4.PullRequestState, fields PullRequestFields, since time.Time) ([]*PullRequest, error) {
+	if m.rest {
+		return m.listPullRequestsREST(prStates, fields, since)
+	}
+
 	var query struct {
 		Repository struct {
 			PullRequests struct {
@@ -107,59 +319,178 @@ func (m *GithubClient) ListPullRequests(prStates []githubv4.PullRequestState) ([
 						PullRequestObject
 						Reviews struct {
 							TotalCount int
-						} `graphql:"reviews(states: $prReviewStates)"`
+						} `graphql:"reviews(states: $prReviewStates) @include(if: $withReviews)"`
 						Commits struct {
 							Edges []struct {
 								Node struct {
-									Commit CommitObject
+									Commit struct {
+										CommitObject
+										Status *struct {
+											Contexts []struct {
+												Context   string
+												State     githubv4.StatusState
+												CreatedAt githubv4.DateTime
+											}
+										} `graphql:"status @include(if: $withReadiness)"`
+										CheckSuites struct {
+											Nodes []struct {
+												CheckRuns struct {
+													Nodes []struct {
+														Name        string
+														Status      string
+														Conclusion  string
+														CompletedAt githubv4.DateTime
+													}
+												} `graphql:"checkRuns(first:$checkRunsFirst)"`
+											}
+										} `graphql:"checkSuites(first:$checkSuitesFirst) @include(if: $withReadiness)"`
+									}
 								}
 							}
 						} `graphql:"commits(last:$commitsLast)"`
+						ReviewDecision           githubv4.PullRequestReviewDecision `graphql:"reviewDecision @include(if: $withReadiness)"`
+						LatestOpinionatedReviews struct {
+							Nodes []struct {
+								State       githubv4.PullRequestReviewState
+								SubmittedAt githubv4.DateTime
+							}
+						} `graphql:"latestOpinionatedReviews(first:$reviewsFirst,writersOnly:true) @include(if: $withReadiness)"`
 						Labels struct {
 							Edges []struct {
 								Node struct {
 									LabelObject
 								}
 							}
-						} `graphql:"labels(first:$labelsFirst)"`
+						} `graphql:"labels(first:$labelsFirst) @include(if: $withLabels)"`
+						Files struct {
+							Nodes []struct {
+								Path string
+							}
+							PageInfo struct {
+								HasNextPage bool
+							}
+						} `graphql:"files(first:$filesFirst) @include(if: $withFiles)"`
+						ForcePushes ForcePushEvents `graphql:"forcePushes: timelineItems(last:$forcePushesLast,itemTypes:[HEAD_REF_FORCE_PUSHED_EVENT])"`
 					}
 				}
 				PageInfo struct {
 					EndCursor   githubv4.String
 					HasNextPage bool
 				}
-			} `graphql:"pullRequests(first:$prFirst,states:$prStates,after:$prCursor)"`
+			} `graphql:"pullRequests(first:$prFirst,states:$prStates,after:$prCursor,orderBy:{field:UPDATED_AT,direction:DESC})"`
 		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
 	}
 
 	vars := map[string]interface{}{
-		"repositoryOwner": githubv4.String(m.Owner),
-		"repositoryName":  githubv4.String(m.Repository),
-		"prFirst":         githubv4.Int(100),
-		"prStates":        prStates,
-		"prCursor":        (*githubv4.String)(nil),
-		"commitsLast":     githubv4.Int(1),
-		"prReviewStates":  []githubv4.PullRequestReviewState{githubv4.PullRequestReviewStateApproved},
-		"labelsFirst":     githubv4.Int(100),
+		"repositoryOwner":  githubv4.String(m.Owner),
+		"repositoryName":   githubv4.String(m.Repository),
+		"prFirst":          githubv4.Int(pullRequestsPageSize),
+		"prStates":         prStates,
+		"prCursor":         (*githubv4.String)(nil),
+		"commitsLast":      githubv4.Int(1),
+		"prReviewStates":   []githubv4.PullRequestReviewState{githubv4.PullRequestReviewStateApproved},
+		"labelsFirst":      githubv4.Int(100),
+		"withReviews":      githubv4.Boolean(fields.Reviews),
+		"withLabels":       githubv4.Boolean(fields.Labels),
+		"filesFirst":       githubv4.Int(filesPageSize),
+		"withFiles":        githubv4.Boolean(fields.Files),
+		"forcePushesLast":  githubv4.Int(1),
+		"withReadiness":    githubv4.Boolean(fields.Readiness),
+		"reviewsFirst":     githubv4.Int(100),
+		"checkSuitesFirst": githubv4.Int(50),
+		"checkRunsFirst":   githubv4.Int(100),
 	}
 
 	var response []*PullRequest
+Pages:
 	for {
-		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
-			return nil, err
+		if err := m.query(&query, vars); err != nil {
+			if response == nil && !fields.Readiness && m.fallbackToREST(err) {
+				return m.listPullRequestsREST(prStates, fields, since)
+			}
+			if !isQueryLimitError(err) || !fields.Files {
+				return nil, err
+			}
+			// Retry with fewer pull requests per page, and then without files
+			if vars["prFirst"] == githubv4.Int(pullRequestsPageSize) {
+				vars["prFirst"] = githubv4.Int(pullRequestsPageSize / 4)
+			} else {
+				vars["prFirst"] = githubv4.Int(pullRequestsPageSize)
+				vars["withFiles"] = githubv4.Boolean(false)
+				fields.Files = false
+			}
+			continue
 		}
 		for _, p := range query.Repository.PullRequests.Edges {
+			// Pull requests which have not been updated since cannot have new commits (nor be closed or merged)
+			if !since.IsZero() && !p.Node.UpdatedAt.After(since) {
+				break Pages
+			}
+
 			labels := make([]LabelObject, len(p.Node.Labels.Edges))
 			for _, l := range p.Node.Labels.Edges {
 				labels = append(labels, l.Node.LabelObject)
 			}
 
+			// Files are only set if all of them were fetched (an empty list is indistinguishable
+			// from files not being supported by the server, so it is left out as well)
+			var files []string
+			if len(p.Node.Files.Nodes) > 0 && !p.Node.Files.PageInfo.HasNextPage {
+				for _, f := range p.Node.Files.Nodes {
+					files = append(files, f.Path)
+				}
+			}
+
+			var changesRequested int
+			var approved githubv4.DateTime
+			for _, r := range p.Node.LatestOpinionatedReviews.Nodes {
+				switch r.State {
+				case githubv4.PullRequestReviewStateChangesRequested:
+					changesRequested++
+				case githubv4.PullRequestReviewStateApproved:
+					if r.SubmittedAt.After(approved.Time) {
+						approved = r.SubmittedAt
+					}
+				}
+			}
+
 			for _, c := range p.Node.Commits.Edges {
+				// Statuses and check runs are normalized to the states of statuses
+				var contexts []CommitContext
+				if c.Node.Commit.Status != nil {
+					for _, s := range c.Node.Commit.Status.Contexts {
+						state := strings.ToLower(string(s.State))
+						if s.State == githubv4.StatusStateExpected {
+							state = "pending"
+						}
+						contexts = append(contexts, CommitContext{Name: s.Context, State: state, Date: s.CreatedAt})
+					}
+				}
+				for _, suite := range c.Node.Commit.CheckSuites.Nodes {
+					for _, r := range suite.CheckRuns.Nodes {
+						state := "pending"
+						switch {
+						case r.Status != "COMPLETED":
+						case r.Conclusion == "SUCCESS", r.Conclusion == "NEUTRAL", r.Conclusion == "SKIPPED":
+							state = "success"
+						default:
+							state = "failure"
+						}
+						contexts = append(contexts, CommitContext{Name: r.Name, State: state, Date: r.CompletedAt})
+					}
+				}
+
 				response = append(response, &PullRequest{
-					PullRequestObject:   p.Node.PullRequestObject,
-					Tip:                 c.Node.Commit,
-					ApprovedReviewCount: p.Node.Reviews.TotalCount,
-					Labels:              labels,
+					PullRequestObject:     p.Node.PullRequestObject,
+					Tip:                   c.Node.Commit.CommitObject,
+					ApprovedReviewCount:   p.Node.Reviews.TotalCount,
+					Labels:                labels,
+					Files:                 files,
+					ForcePushedDate:       p.Node.ForcePushes.PushedDate(c.Node.Commit.OID),
+					ReviewDecision:        p.Node.ReviewDecision,
+					ChangesRequestedCount: changesRequested,
+					ApprovedDate:          approved,
+					Contexts:              contexts,
 				})
 			}
 		}
@@ -200,14 +531,55 @@ func (m *GithubClient) ListModifiedFiles(prNumber int) ([]string, error) {
 	return files, nil
 }
 
-// PostComment to a pull request or issue.
-func (m *GithubClient) PostComment(prNumber, comment string) error {
+// CompareCommits returns the number of commits the head is ahead of and behind the base (not supported by V4 API).
+func (m *GithubClient) CompareCommits(base, head string) (*CommitComparison, error) {
+	comparison, _, err := m.V3.Repositories.CompareCommits(context.TODO(), m.Owner, m.Repository, base, head)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitComparison{AheadBy: comparison.GetAheadBy(), BehindBy: comparison.GetBehindBy()}, nil
+}
+
+// ListCommits returns the commits of a pull request, with their signature verification and parents.
+func (m *GithubClient) ListCommits(prNumber int) ([]PullRequestCommit, error) {
+	var commits []PullRequestCommit
+
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		result, response, err := m.V3.PullRequests.ListCommits(context.TODO(), m.Owner, m.Repository, prNumber, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range result {
+			var verification *github.SignatureVerification
+			if c.Commit != nil {
+				verification = c.Commit.Verification
+			}
+			commits = append(commits, PullRequestCommit{
+				OID:                c.GetSHA(),
+				Verified:           verification.GetVerified(),
+				VerificationReason: verification.GetReason(),
+				Parents:            len(c.Parents),
+			})
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+	return commits, nil
+}
+
+// PostComment to a pull request or issue, and return the URL of the comment.
+func (m *GithubClient) PostComment(prNumber, comment string) (string, error) {
 	pr, err := strconv.Atoi(prNumber)
 	if err != nil {
-		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+		return "", fmt.Errorf("failed to convert pull request number to int: %s", err)
 	}
 
-	_, _, err = m.V3.Issues.CreateComment(
+	created, _, err := m.V3.Issues.CreateComment(
 		context.TODO(),
 		m.Owner,
 		m.Repository,
@@ -216,196 +588,1753 @@ func (m *GithubClient) PostComment(prNumber, comment string) error {
 			Body: github.String(comment),
 		},
 	)
-	return err
-}
-
-// GetChangedFiles ...
-func (m *GithubClient) GetChangedFiles(prNumber string, commitRef string) ([]ChangedFileObject, error) {
-	pr, err := strconv.Atoi(prNumber)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+		return "", err
 	}
+	return created.GetHTMLURL(), nil
+}
 
-	var cfo []ChangedFileObject
-
-	var filequery struct {
-		Repository struct {
-			PullRequest struct {
-				Files struct {
-					Edges []struct {
-						Node struct {
-							ChangedFileObject
-						}
-					} `graphql:"edges"`
-					PageInfo struct {
-						EndCursor   githubv4.String
-						HasNextPage bool
-					} `graphql:"pageInfo"`
-				} `graphql:"files(first:$changedFilesFirst, after: $changedFilesEndCursor)"`
-			} `graphql:"pullRequest(number:$prNumber)"`
-		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+// UpsertComment updates the last comment (made by the authenticated user) which contains the marker,
+// or posts a new comment if no such comment exists. Minimized comments are not updated (since the update
+// would be hidden). The URL of the comment is returned.
+func (m *GithubClient) UpsertComment(prNumber, marker, comment string) (string, error) {
+	comments, err := m.viewerComments(prNumber)
+	if err != nil {
+		return "", err
 	}
 
-	offset := ""
-
-	for {
-		vars := map[string]interface{}{
-			"repositoryOwner":       githubv4.String(m.Owner),
-			"repositoryName":        githubv4.String(m.Repository),
-			"prNumber":              githubv4.Int(pr),
-			"changedFilesFirst":     githubv4.Int(100),
-			"changedFilesEndCursor": githubv4.String(offset),
-		}
-
-		if err := m.V4.Query(context.TODO(), &filequery, vars); err != nil {
-			return nil, err
-		}
-
-		for _, f := range filequery.Repository.PullRequest.Files.Edges {
-			cfo = append(cfo, ChangedFileObject{Path: f.Node.Path})
-		}
-
-		if !filequery.Repository.PullRequest.Files.PageInfo.HasNextPage {
-			break
+	for i := len(comments) - 1; i >= 0; i-- {
+		if !comments[i].IsMinimized && strings.Contains(comments[i].Body, marker) {
+			updated, _, err := m.V3.Issues.EditComment(
+				context.TODO(),
+				m.Owner,
+				m.Repository,
+				comments[i].DatabaseId,
+				&github.IssueComment{
+					Body: github.String(comment),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return updated.GetHTMLURL(), nil
 		}
-
-		offset = string(filequery.Repository.PullRequest.Files.PageInfo.EndCursor)
 	}
-
-	return cfo, nil
+	return m.PostComment(prNumber, comment)
 }
 
-// GetPullRequest ...
-func (m *GithubClient) GetPullRequest(prNumber, commitRef string) (*PullRequest, error) {
-	pr, err := strconv.Atoi(prNumber)
+// GetLatestComment returns the body of the last comment made by the authenticated user,
+// or an empty string if there is none.
+func (m *GithubClient) GetLatestComment(prNumber string) (string, error) {
+	comments, err := m.viewerComments(prNumber)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
-	}
-
-	var query struct {
-		Repository struct {
-			PullRequest struct {
-				PullRequestObject
-				Commits struct {
-					Edges []struct {
-						Node struct {
-							Commit CommitObject
-						}
-					}
-				} `graphql:"commits(last:$commitsLast)"`
-			} `graphql:"pullRequest(number:$prNumber)"`
-		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+		return "", err
 	}
-
-	vars := map[string]interface{}{
-		"repositoryOwner": githubv4.String(m.Owner),
-		"repositoryName":  githubv4.String(m.Repository),
-		"prNumber":        githubv4.Int(pr),
-		"commitsLast":     githubv4.Int(100),
+	if len(comments) == 0 {
+		return "", nil
 	}
+	return comments[len(comments)-1].Body, nil
+}
 
-	// TODO: Pagination - in case someone pushes > 100 commits before the build has time to start :p
-	if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
-		return nil, err
+// FindComment returns the body of the last comment made by the authenticated user which contains
+// the given text, or an empty string if there is none.
+func (m *GithubClient) FindComment(prNumber, text string) (string, error) {
+	comments, err := m.viewerComments(prNumber)
+	if err != nil {
+		return "", err
 	}
-
-	for _, c := range query.Repository.PullRequest.Commits.Edges {
-		if c.Node.Commit.OID == commitRef {
-			// Return as soon as we find the correct ref.
-			return &PullRequest{
-				PullRequestObject: query.Repository.PullRequest.PullRequestObject,
-				Tip:               c.Node.Commit,
-			}, nil
+	for i := len(comments) - 1; i >= 0; i-- {
+		if strings.Contains(comments[i].Body, text) {
+			return comments[i].Body, nil
 		}
 	}
-
-	// Return an error if the commit was not found
-	return nil, fmt.Errorf("commit with ref '%s' does not exist", commitRef)
+	return "", nil
 }
 
-// UpdateCommitStatus for a given commit (not supported by V4 API).
-func (m *GithubClient) UpdateCommitStatus(commitRef, baseContext, statusContext, status, targetURL, description string) error {
-	if baseContext == "" {
-		baseContext = "concourse-ci"
+// CreateCommitComment posts a comment on a commit, and returns the URL of the comment.
+func (m *GithubClient) CreateCommitComment(commitRef, comment string) (string, error) {
+	created, _, err := m.V3.Repositories.CreateComment(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		commitRef,
+		&github.RepositoryComment{
+			Body: github.String(comment),
+		},
+	)
+	if err != nil {
+		return "", err
 	}
+	return created.GetHTMLURL(), nil
+}
 
-	if statusContext == "" {
-		statusContext = "status"
-	}
+// AddCommentReaction adds a reaction (e.g. +1 or rocket) to a comment.
+func (m *GithubClient) AddCommentReaction(commentID int64, reaction string) error {
+	_, _, err := m.V3.Reactions.CreateIssueCommentReaction(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		commentID,
+		reaction,
+	)
+	return err
+}
 
-	if targetURL == "" {
-		targetURL = strings.Join([]string{os.Getenv("ATC_EXTERNAL_URL"), "builds", os.Getenv("BUILD_ID")}, "/")
+// CreateGist creates a secret gist with a single file, and returns the URL of the gist.
+func (m *GithubClient) CreateGist(description, filename, content string) (string, error) {
+	gist, _, err := m.V3.Gists.Create(context.TODO(), &github.Gist{
+		Description: github.String(description),
+		Public:      github.Bool(false),
+		Files: map[github.GistFilename]github.GistFile{
+			github.GistFilename(filename): {Content: github.String(content)},
+		},
+	})
+	if err != nil {
+		return "", err
 	}
+	return gist.GetHTMLURL(), nil
+}
 
-	if description == "" {
-		description = fmt.Sprintf("Concourse CI build %s", status)
+// RequestReviewers requests a review from users and teams on a pull request.
+func (m *GithubClient) RequestReviewers(prNumber string, reviewers, teamReviewers []string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
 	}
 
-	_, _, err := m.V3.Repositories.CreateStatus(
+	_, _, err = m.V3.PullRequests.RequestReviewers(
 		context.TODO(),
 		m.Owner,
 		m.Repository,
-		commitRef,
-		&github.RepoStatus{
-			State:       github.String(strings.ToLower(status)),
-			TargetURL:   github.String(targetURL),
-			Description: github.String(description),
-			Context:     github.String(path.Join(baseContext, statusContext)),
+		pr,
+		github.ReviewersRequest{
+			Reviewers:     reviewers,
+			TeamReviewers: teamReviewers,
 		},
 	)
 	return err
 }
 
-func (m *GithubClient) DeletePreviousComments(prNumber string) error {
+// CreateReview submits a review (APPROVE, REQUEST_CHANGES or COMMENT) for a commit on a pull request.
+func (m *GithubClient) CreateReview(prNumber, commitRef, event, body string) error {
 	pr, err := strconv.Atoi(prNumber)
 	if err != nil {
 		return fmt.Errorf("failed to convert pull request number to int: %s", err)
 	}
 
-	var getComments struct {
-		Viewer struct {
-			Login string
-		}
-		Repository struct {
-			PullRequest struct {
-				Id       string
-				Comments struct {
-					Edges []struct {
-						Node struct {
-							DatabaseId int64
-							Author     struct {
-								Login string
-							}
-						}
-					}
-				} `graphql:"comments(last:$commentsLast)"`
-			} `graphql:"pullRequest(number:$prNumber)"`
-		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	review := &github.PullRequestReviewRequest{
+		CommitID: github.String(commitRef),
+		Event:    github.String(event),
+	}
+	if body != "" {
+		review.Body = github.String(body)
 	}
 
-	vars := map[string]interface{}{
+	_, _, err = m.V3.PullRequests.CreateReview(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		review,
+	)
+	return err
+}
+
+// reviewRequest is a request to create a review, with comments on lines rather than diff positions
+// (which are not supported by the version of go-github we use).
+type reviewRequest struct {
+	CommitID string          `json:"commit_id"`
+	Body     string          `json:"body,omitempty"`
+	Event    string          `json:"event"`
+	Comments []reviewComment `json:"comments"`
+}
+
+// reviewComment is a (multi-line) comment in a reviewRequest.
+type reviewComment struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line,omitempty"`
+	StartSide string `json:"start_side,omitempty"`
+	Line      int    `json:"line"`
+	Side      string `json:"side"`
+	Body      string `json:"body"`
+}
+
+// CreateSuggestions posts a review with a suggested change comment for each suggestion. Suggestions on lines
+// that are not part of the diff of the pull request are skipped, since Github does not allow comments on them.
+func (m *GithubClient) CreateSuggestions(prNumber, commitRef, body string, suggestions []Suggestion) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	ranges := make(map[string][][2]int)
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		result, response, err := m.V3.PullRequests.ListFiles(
+			context.TODO(),
+			m.Owner,
+			m.Repository,
+			pr,
+			opt,
+		)
+		if err != nil {
+			return err
+		}
+		for _, f := range result {
+			ranges[f.GetFilename()] = diffHunkRanges(f.GetPatch())
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+
+	review := reviewRequest{
+		CommitID: commitRef,
+		Body:     body,
+		Event:    "COMMENT",
+	}
+	for _, s := range suggestions {
+		var commentable bool
+		for _, r := range ranges[s.Path] {
+			if r[0] <= s.StartLine && s.Line <= r[1] {
+				commentable = true
+				break
+			}
+		}
+		if !commentable {
+			continue
+		}
+
+		c := reviewComment{
+			Path: s.Path,
+			Line: s.Line,
+			Side: "RIGHT",
+			Body: "```suggestion\n",
+		}
+		if s.StartLine != s.Line {
+			c.StartLine = s.StartLine
+			c.StartSide = "RIGHT"
+		}
+		if len(s.Replacement) > 0 {
+			c.Body += strings.Join(s.Replacement, "\n") + "\n"
+		}
+		c.Body += "```"
+		review.Comments = append(review.Comments, c)
+	}
+	if len(review.Comments) == 0 {
+		return nil
+	}
+
+	u := fmt.Sprintf("repos/%s/%s/pulls/%d/reviews", m.Owner, m.Repository, pr)
+	req, err := m.V3.NewRequest("POST", u, review)
+	if err != nil {
+		return err
+	}
+	_, err = m.V3.Do(context.TODO(), req, nil)
+	return err
+}
+
+// FindReviewThread returns the ID of the first comment in the review thread on the given path and line.
+// Unresolved threads are preferred, and outdated threads are ignored.
+func (m *GithubClient) FindReviewThread(prNumber, path string, line int) (int64, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						Path       string
+						Line       int
+						IsResolved bool
+						Comments   struct {
+							Nodes []struct {
+								DatabaseId int64
+							}
+						} `graphql:"comments(first:1)"`
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"reviewThreads(first:$threadsFirst,after:$threadsCursor)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(pr),
+		"threadsFirst":    githubv4.Int(100),
+		"threadsCursor":   (*githubv4.String)(nil),
+	}
+
+	var resolved int64
+	for {
+		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+			return 0, err
+		}
+		for _, t := range query.Repository.PullRequest.ReviewThreads.Nodes {
+			if t.Path != path || t.Line != line || len(t.Comments.Nodes) == 0 {
+				continue
+			}
+			if !t.IsResolved {
+				return t.Comments.Nodes[0].DatabaseId, nil
+			}
+			if resolved == 0 {
+				resolved = t.Comments.Nodes[0].DatabaseId
+			}
+		}
+		if !query.Repository.PullRequest.ReviewThreads.PageInfo.HasNextPage {
+			break
+		}
+		vars["threadsCursor"] = query.Repository.PullRequest.ReviewThreads.PageInfo.EndCursor
+	}
+	if resolved == 0 {
+		return 0, fmt.Errorf("no review thread found on %s:%d", path, line)
+	}
+	return resolved, nil
+}
+
+// ReplyToReviewComment posts a reply in the review thread of the given review comment.
+func (m *GithubClient) ReplyToReviewComment(prNumber string, commentID int64, body string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	_, _, err = m.V3.PullRequests.CreateCommentInReplyTo(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		body,
+		commentID,
+	)
+	return err
+}
+
+// DismissReviews dismisses all approving reviews of a pull request.
+func (m *GithubClient) DismissReviews(prNumber, message string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var reviews []*github.PullRequestReview
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		result, response, err := m.V3.PullRequests.ListReviews(context.TODO(), m.Owner, m.Repository, pr, opt)
+		if err != nil {
+			return err
+		}
+		reviews = append(reviews, result...)
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+
+	for _, r := range reviews {
+		if r.GetState() != "APPROVED" {
+			continue
+		}
+		_, _, err := m.V3.PullRequests.DismissReview(context.TODO(), m.Owner, m.Repository, pr, r.GetID(), &github.PullRequestReviewDismissalRequest{
+			Message: github.String(message),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergePullRequest merges a pull request, provided that the head of the pull request still matches the commit.
+func (m *GithubClient) MergePullRequest(prNumber, commitRef, method, title, message string) (string, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	result, _, err := m.V3.PullRequests.Merge(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		message,
+		&github.PullRequestOptions{
+			CommitTitle: title,
+			SHA:         commitRef,
+			MergeMethod: method,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	if !result.GetMerged() {
+		return "", fmt.Errorf("pull request was not merged: %s", result.GetMessage())
+	}
+	return result.GetSHA(), nil
+}
+
+// EnablePullRequestAutoMergeInput is the input type of the enablePullRequestAutoMerge mutation
+// (which is not available in the version of githubv4 we use).
+type EnablePullRequestAutoMergeInput struct {
+	PullRequestID githubv4.ID                      `json:"pullRequestId"`
+	MergeMethod   *githubv4.PullRequestMergeMethod `json:"mergeMethod,omitempty"`
+}
+
+// EnableAutoMerge on a pull request, so that Github merges it once all requirements are met.
+func (m *GithubClient) EnableAutoMerge(prNumber, method string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	id, err := m.pullRequestID(pr)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		EnablePullRequestAutoMerge struct {
+			ClientMutationID string
+		} `graphql:"enablePullRequestAutoMerge(input:$input)"`
+	}
+
+	input := EnablePullRequestAutoMergeInput{
+		PullRequestID: id,
+	}
+	if method != "" {
+		mergeMethod := githubv4.PullRequestMergeMethod(method)
+		input.MergeMethod = &mergeMethod
+	}
+
+	return m.mutate("enablePullRequestAutoMerge", &mutation, input)
+}
+
+// UpdatePullRequestBranchInput is the input type of the updatePullRequestBranch mutation
+// (which is not available in the version of githubv4 we use).
+type UpdatePullRequestBranchInput struct {
+	PullRequestID   githubv4.ID           `json:"pullRequestId"`
+	ExpectedHeadOid *githubv4.GitObjectID `json:"expectedHeadOid,omitempty"`
+	UpdateMethod    *githubv4.String      `json:"updateMethod,omitempty"`
+}
+
+// UpdateBranch of a pull request with the latest changes from the base branch, using either
+// the MERGE or REBASE method. The head of the pull request must still be at the expected commit.
+func (m *GithubClient) UpdateBranch(prNumber, commitRef, method string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	id, err := m.pullRequestID(pr)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		UpdatePullRequestBranch struct {
+			ClientMutationID string
+		} `graphql:"updatePullRequestBranch(input:$input)"`
+	}
+
+	input := UpdatePullRequestBranchInput{
+		PullRequestID: id,
+	}
+	if commitRef != "" {
+		oid := githubv4.GitObjectID(commitRef)
+		input.ExpectedHeadOid = &oid
+	}
+	if method != "" {
+		input.UpdateMethod = githubv4.NewString(githubv4.String(method))
+	}
+
+	err = m.mutate("updatePullRequestBranch", &mutation, input)
+	if _, ok := err.(*unsupportedError); !ok || method == "REBASE" {
+		return err
+	}
+
+	// Fall back to the V3 API, which only supports merging
+	opts := &github.PullReqestBranchUpdateOptions{}
+	if commitRef != "" {
+		opts.ExpectedHeadSHA = github.String(commitRef)
+	}
+	_, _, err = m.V3.PullRequests.UpdateBranch(context.TODO(), m.Owner, m.Repository, pr, opts)
+	if _, ok := err.(*github.AcceptedError); ok {
+		return nil
+	}
+	return err
+}
+
+// MarkReadyForReview converts a draft pull request into one that is ready for review.
+func (m *GithubClient) MarkReadyForReview(prNumber string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	id, err := m.pullRequestID(pr)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		MarkPullRequestReadyForReview struct {
+			ClientMutationID string
+		} `graphql:"markPullRequestReadyForReview(input:$input)"`
+	}
+
+	input := githubv4.MarkPullRequestReadyForReviewInput{
+		PullRequestID: id,
+	}
+
+	return m.mutate("markPullRequestReadyForReview", &mutation, input)
+}
+
+// ConvertPullRequestToDraftInput is the input type of the convertPullRequestToDraft mutation
+// (which is not available in the version of githubv4 we use).
+type ConvertPullRequestToDraftInput struct {
+	PullRequestID githubv4.ID `json:"pullRequestId"`
+}
+
+// ConvertToDraft converts a pull request back into a draft.
+func (m *GithubClient) ConvertToDraft(prNumber string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	id, err := m.pullRequestID(pr)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		ConvertPullRequestToDraft struct {
+			ClientMutationID string
+		} `graphql:"convertPullRequestToDraft(input:$input)"`
+	}
+
+	input := ConvertPullRequestToDraftInput{
+		PullRequestID: id,
+	}
+
+	return m.mutate("convertPullRequestToDraft", &mutation, input)
+}
+
+// pullRequestID returns the GraphQL node ID of a pull request, which is required by mutations.
+func (m *GithubClient) pullRequestID(pr int) (githubv4.ID, error) {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				ID string
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(pr),
+	}
+
+	if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+		return nil, err
+	}
+	return githubv4.ID(query.Repository.PullRequest.ID), nil
+}
+
+// UpdatePullRequest sets the title and/or body of a pull request (empty values are left unchanged).
+func (m *GithubClient) UpdatePullRequest(prNumber, title, body string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	update := &github.PullRequest{}
+	if title != "" {
+		update.Title = github.String(title)
+	}
+	if body != "" {
+		update.Body = github.String(body)
+	}
+
+	_, _, err = m.V3.PullRequests.Edit(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		update,
+	)
+	return err
+}
+
+// SetMilestone of a pull request by title. If the milestone does not exist, it is created when create is true.
+func (m *GithubClient) SetMilestone(prNumber, title string, create bool) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var number int
+	opt := &github.MilestoneListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for number == 0 {
+		milestones, response, err := m.V3.Issues.ListMilestones(context.TODO(), m.Owner, m.Repository, opt)
+		if err != nil {
+			return err
+		}
+		for _, milestone := range milestones {
+			if milestone.GetTitle() == title {
+				number = milestone.GetNumber()
+				break
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+
+	if number == 0 {
+		if !create {
+			return fmt.Errorf("milestone not found: %s", title)
+		}
+		milestone, _, err := m.V3.Issues.CreateMilestone(context.TODO(), m.Owner, m.Repository, &github.Milestone{
+			Title: github.String(title),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create milestone: %s", err)
+		}
+		number = milestone.GetNumber()
+	}
+
+	_, _, err = m.V3.Issues.Edit(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		&github.IssueRequest{
+			Milestone: github.Int(number),
+		},
+	)
+	return err
+}
+
+// AddAssignees to a pull request.
+func (m *GithubClient) AddAssignees(prNumber string, assignees []string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	_, _, err = m.V3.Issues.AddAssignees(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		assignees,
+	)
+	return err
+}
+
+// AddLabels to a pull request.
+func (m *GithubClient) AddLabels(prNumber string, labels []string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	_, _, err = m.V3.Issues.AddLabelsToIssue(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		labels,
+	)
+	return err
+}
+
+// RemoveLabels from a pull request. Labels that are not set on the pull request are ignored.
+func (m *GithubClient) RemoveLabels(prNumber string, labels []string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	for _, l := range labels {
+		response, err := m.V3.Issues.RemoveLabelForIssue(
+			context.TODO(),
+			m.Owner,
+			m.Repository,
+			pr,
+			l,
+		)
+		if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddProjectV2ItemByIdInput is the input type of the addProjectV2ItemById mutation
+// (which is not available in the version of githubv4 we use).
+type AddProjectV2ItemByIdInput struct {
+	ProjectID githubv4.ID `json:"projectId"`
+	ContentID githubv4.ID `json:"contentId"`
+}
+
+// UpdateProjectV2ItemFieldValueInput is the input type of the updateProjectV2ItemFieldValue mutation
+// (which is not available in the version of githubv4 we use).
+type UpdateProjectV2ItemFieldValueInput struct {
+	ProjectID githubv4.ID         `json:"projectId"`
+	ItemID    githubv4.ID         `json:"itemId"`
+	FieldID   githubv4.ID         `json:"fieldId"`
+	Value     ProjectV2FieldValue `json:"value"`
+}
+
+// ProjectV2FieldValue is the value of a project field, only one of which should be set.
+type ProjectV2FieldValue struct {
+	Text                 *githubv4.String `json:"text,omitempty"`
+	Number               *githubv4.Float  `json:"number,omitempty"`
+	Date                 *githubv4.Date   `json:"date,omitempty"`
+	SingleSelectOptionID *githubv4.String `json:"singleSelectOptionId,omitempty"`
+}
+
+// AddToProject adds a pull request to a project (v2) owned by a user or organization,
+// and sets the given field values (by field name) on the project item.
+func (m *GithubClient) AddToProject(prNumber string, project Project) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	contentID, err := m.pullRequestID(pr)
+	if err != nil {
+		return err
+	}
+
+	p, err := m.projectV2(project.Owner, project.Number)
+	if err != nil {
+		return err
+	}
+
+	var add struct {
+		AddProjectV2ItemById struct {
+			Item struct {
+				ID string
+			}
+		} `graphql:"addProjectV2ItemById(input:$input)"`
+	}
+
+	input := AddProjectV2ItemByIdInput{
+		ProjectID: githubv4.ID(p.ID),
+		ContentID: contentID,
+	}
+	if err := m.V4.Mutate(context.TODO(), &add, input, nil); err != nil {
+		return fmt.Errorf("failed to add item: %s", err)
+	}
+
+	for name, value := range project.Fields {
+		var field *projectV2Field
+		for i := range p.Fields {
+			if strings.EqualFold(p.Fields[i].Name, name) {
+				field = &p.Fields[i]
+				break
+			}
+		}
+		if field == nil {
+			return fmt.Errorf("project field not found: %s", name)
+		}
+
+		var v ProjectV2FieldValue
+		switch field.DataType {
+		case "SINGLE_SELECT":
+			for _, o := range field.Options {
+				if strings.EqualFold(o.Name, value) {
+					v.SingleSelectOptionID = githubv4.NewString(githubv4.String(o.ID))
+					break
+				}
+			}
+			if v.SingleSelectOptionID == nil {
+				return fmt.Errorf("unknown option for project field %s: %s", name, value)
+			}
+		case "NUMBER":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("invalid number for project field %s: %s", name, err)
+			}
+			v.Number = githubv4.NewFloat(githubv4.Float(n))
+		case "DATE":
+			d, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return fmt.Errorf("invalid date for project field %s: %s", name, err)
+			}
+			v.Date = githubv4.NewDate(githubv4.Date{Time: d})
+		case "TEXT":
+			v.Text = githubv4.NewString(githubv4.String(value))
+		default:
+			return fmt.Errorf("unsupported type for project field %s: %s", name, field.DataType)
+		}
+
+		var update struct {
+			UpdateProjectV2ItemFieldValue struct {
+				ClientMutationID string
+			} `graphql:"updateProjectV2ItemFieldValue(input:$input)"`
+		}
+
+		input := UpdateProjectV2ItemFieldValueInput{
+			ProjectID: githubv4.ID(p.ID),
+			ItemID:    githubv4.ID(add.AddProjectV2ItemById.Item.ID),
+			FieldID:   githubv4.ID(field.ID),
+			Value:     v,
+		}
+		if err := m.V4.Mutate(context.TODO(), &update, input, nil); err != nil {
+			return fmt.Errorf("failed to update project field %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// projectV2Field is a field of a project (v2), with the options of single select fields.
+type projectV2Field struct {
+	ID       string
+	Name     string
+	DataType string
+	Options  []struct {
+		ID   string
+		Name string
+	}
+}
+
+// projectV2Object is a project (v2) and its fields.
+type projectV2Object struct {
+	ID     string
+	Fields []projectV2Field
+}
+
+// projectV2 looks up a project (v2) by number. The owner defaults to the owner of the repository,
+// and can be either an organization or a user.
+func (m *GithubClient) projectV2(owner string, number int) (*projectV2Object, error) {
+	if owner == "" {
+		owner = m.Owner
+	}
+
+	type project struct {
+		ID     string
+		Fields struct {
+			Nodes []struct {
+				Field struct {
+					ID       string
+					Name     string
+					DataType string
+				} `graphql:"... on ProjectV2Field"`
+				SingleSelectField struct {
+					ID       string
+					Name     string
+					DataType string
+					Options  []struct {
+						ID   string
+						Name string
+					}
+				} `graphql:"... on ProjectV2SingleSelectField"`
+			}
+		} `graphql:"fields(first:100)"`
+	}
+
+	var orgQuery struct {
+		Organization struct {
+			ProjectV2 project `graphql:"projectV2(number:$projectNumber)"`
+		} `graphql:"organization(login:$owner)"`
+	}
+	var userQuery struct {
+		User struct {
+			ProjectV2 project `graphql:"projectV2(number:$projectNumber)"`
+		} `graphql:"user(login:$owner)"`
+	}
+
+	vars := map[string]interface{}{
+		"owner":         githubv4.String(owner),
+		"projectNumber": githubv4.Int(number),
+	}
+
+	var p project
+	if err := m.V4.Query(context.TODO(), &orgQuery, vars); err == nil {
+		p = orgQuery.Organization.ProjectV2
+	} else if err := m.V4.Query(context.TODO(), &userQuery, vars); err == nil {
+		p = userQuery.User.ProjectV2
+	} else {
+		return nil, fmt.Errorf("failed to find project %d for %s: %s", number, owner, err)
+	}
+
+	result := &projectV2Object{ID: p.ID}
+	for _, n := range p.Fields.Nodes {
+		if n.SingleSelectField.ID != "" {
+			result.Fields = append(result.Fields, projectV2Field{
+				ID:       n.SingleSelectField.ID,
+				Name:     n.SingleSelectField.Name,
+				DataType: n.SingleSelectField.DataType,
+				Options:  n.SingleSelectField.Options,
+			})
+			continue
+		}
+		result.Fields = append(result.Fields, projectV2Field{
+			ID:       n.Field.ID,
+			Name:     n.Field.Name,
+			DataType: n.Field.DataType,
+		})
+	}
+	return result, nil
+}
+
+// ClosePullRequest without merging it.
+func (m *GithubClient) ClosePullRequest(prNumber string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	_, _, err = m.V3.PullRequests.Edit(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		&github.PullRequest{
+			State: github.String("closed"),
+		},
+	)
+	return err
+}
+
+// ReopenPullRequest that has been closed.
+func (m *GithubClient) ReopenPullRequest(prNumber string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	_, _, err = m.V3.PullRequests.Edit(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		pr,
+		&github.PullRequest{
+			State: github.String("open"),
+		},
+	)
+	return err
+}
+
+// LockPullRequest conversation, optionally with a reason (off-topic, too heated, resolved or spam).
+func (m *GithubClient) LockPullRequest(prNumber, reason string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var opt *github.LockIssueOptions
+	if reason != "" {
+		opt = &github.LockIssueOptions{LockReason: reason}
+	}
+	_, err = m.V3.Issues.Lock(context.TODO(), m.Owner, m.Repository, pr, opt)
+	return err
+}
+
+// UnlockPullRequest conversation.
+func (m *GithubClient) UnlockPullRequest(prNumber string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	_, err = m.V3.Issues.Unlock(context.TODO(), m.Owner, m.Repository, pr)
+	return err
+}
+
+// DeleteBranch from the repository.
+func (m *GithubClient) DeleteBranch(branch string) error {
+	_, err := m.V3.Git.DeleteRef(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		"heads/"+branch,
+	)
+	return err
+}
+
+// CreateTag pointing to a commit. The tag is annotated if a message is given, and lightweight otherwise.
+func (m *GithubClient) CreateTag(name, commitRef, message string) error {
+	sha := commitRef
+	if message != "" {
+		tag, _, err := m.V3.Git.CreateTag(context.TODO(), m.Owner, m.Repository, &github.Tag{
+			Tag:     github.String(name),
+			Message: github.String(message),
+			Object: &github.GitObject{
+				Type: github.String("commit"),
+				SHA:  github.String(commitRef),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		sha = tag.GetSHA()
+	}
+
+	_, _, err := m.V3.Git.CreateRef(context.TODO(), m.Owner, m.Repository, &github.Reference{
+		Ref:    github.String("refs/tags/" + name),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	})
+	return err
+}
+
+// CreateRelease for an existing tag.
+func (m *GithubClient) CreateRelease(release Release) error {
+	_, _, err := m.V3.Repositories.CreateRelease(context.TODO(), m.Owner, m.Repository, &github.RepositoryRelease{
+		TagName:    github.String(release.TagName),
+		Name:       github.String(release.Name),
+		Body:       github.String(release.Body),
+		Draft:      github.Bool(release.Draft),
+		Prerelease: github.Bool(release.Prerelease),
+	})
+	return err
+}
+
+// Dispatch sends a repository_dispatch event to the repository (not supported by V4 API).
+func (m *GithubClient) Dispatch(eventType string, payload map[string]interface{}) error {
+	body := struct {
+		EventType     string                 `json:"event_type"`
+		ClientPayload map[string]interface{} `json:"client_payload,omitempty"`
+	}{
+		EventType:     eventType,
+		ClientPayload: payload,
+	}
+
+	u := fmt.Sprintf("repos/%s/%s/dispatches", m.Owner, m.Repository)
+	req, err := m.V3.NewRequest("POST", u, body)
+	if err != nil {
+		return err
+	}
+	_, err = m.V3.Do(context.TODO(), req, nil)
+	return err
+}
+
+// GetChangedFiles ...
+func (m *GithubClient) GetChangedFiles(prNumber string, commitRef string) ([]ChangedFileObject, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+	if m.rest {
+		return m.changedFilesREST(pr)
+	}
+
+	var cfo []ChangedFileObject
+
+	var filequery struct {
+		Repository struct {
+			PullRequest struct {
+				Files struct {
+					Edges []struct {
+						Node struct {
+							ChangedFileObject
+						}
+					} `graphql:"edges"`
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					} `graphql:"pageInfo"`
+				} `graphql:"files(first:$changedFilesFirst, after: $changedFilesEndCursor)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	offset := ""
+
+	for {
+		vars := map[string]interface{}{
+			"repositoryOwner":       githubv4.String(m.Owner),
+			"repositoryName":        githubv4.String(m.Repository),
+			"prNumber":              githubv4.Int(pr),
+			"changedFilesFirst":     githubv4.Int(100),
+			"changedFilesEndCursor": githubv4.String(offset),
+		}
+
+		if err := m.V4.Query(context.TODO(), &filequery, vars); err != nil {
+			if cfo == nil && m.fallbackToREST(err) {
+				return m.changedFilesREST(pr)
+			}
+			return nil, err
+		}
+
+		for _, f := range filequery.Repository.PullRequest.Files.Edges {
+			cfo = append(cfo, ChangedFileObject{Path: f.Node.Path})
+		}
+
+		if !filequery.Repository.PullRequest.Files.PageInfo.HasNextPage {
+			break
+		}
+
+		offset = string(filequery.Repository.PullRequest.Files.PageInfo.EndCursor)
+	}
+
+	return cfo, nil
+}
+
+// CommentOnIssue posts a comment on an issue, identified by its node ID (since linked issues can be in other repositories).
+func (m *GithubClient) CommentOnIssue(issueID, comment string) error {
+	var mutation struct {
+		AddComment struct {
+			ClientMutationID string
+		} `graphql:"addComment(input:$input)"`
+	}
+
+	input := githubv4.AddCommentInput{
+		SubjectID: githubv4.ID(issueID),
+		Body:      githubv4.String(comment),
+	}
+
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// CloseIssue identified by its node ID.
+func (m *GithubClient) CloseIssue(issueID string) error {
+	var mutation struct {
+		CloseIssue struct {
+			ClientMutationID string
+		} `graphql:"closeIssue(input:$input)"`
+	}
+
+	input := githubv4.CloseIssueInput{
+		IssueID: githubv4.ID(issueID),
+	}
+
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// GetFileContent returns the content of a file in the repository at the given ref.
+func (m *GithubClient) GetFileContent(path, ref string) ([]byte, error) {
+	file, _, _, err := m.V3.Repositories.GetContents(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		path,
+		&github.RepositoryContentGetOptions{Ref: ref},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("not a file: %s", path)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+// GetLinkedIssues returns the issues that will be closed by the pull request, either
+// through closing keywords or by being linked manually.
+func (m *GithubClient) GetLinkedIssues(prNumber string) ([]IssueObject, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				ClosingIssuesReferences struct {
+					Edges []struct {
+						Node struct {
+							IssueObject
+						}
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"closingIssuesReferences(first:$issuesFirst,after:$issuesCursor)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(pr),
+		"issuesFirst":     githubv4.Int(100),
+		"issuesCursor":    (*githubv4.String)(nil),
+	}
+
+	issues := []IssueObject{}
+	for {
+		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+			return nil, err
+		}
+		for _, e := range query.Repository.PullRequest.ClosingIssuesReferences.Edges {
+			issues = append(issues, e.Node.IssueObject)
+		}
+		if !query.Repository.PullRequest.ClosingIssuesReferences.PageInfo.HasNextPage {
+			break
+		}
+		vars["issuesCursor"] = query.Repository.PullRequest.ClosingIssuesReferences.PageInfo.EndCursor
+	}
+	return issues, nil
+}
+
+// CommitNotFoundError is returned by GetPullRequest when the commit is not part of the pull request
+// (e.g. because the branch was force pushed).
+type CommitNotFoundError struct {
+	Ref string
+}
+
+func (e *CommitNotFoundError) Error() string {
+	return fmt.Sprintf("commit with ref '%s' does not exist", e.Ref)
+}
+
+// GetPullRequest ...
+func (m *GithubClient) GetPullRequest(prNumber, commitRef string) (*PullRequest, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+	if m.rest {
+		return m.getPullRequestREST(pr, commitRef)
+	}
+
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				PullRequestObject
+				Commits struct {
+					Edges []struct {
+						Node struct {
+							Commit CommitObject
+						}
+					}
+				} `graphql:"commits(last:$commitsLast)"`
+				Labels struct {
+					Edges []struct {
+						Node struct {
+							LabelObject
+						}
+					}
+				} `graphql:"labels(first:$labelsFirst)"`
+				ReviewRequests struct {
+					Edges []struct {
+						Node struct {
+							RequestedReviewer RequestedReviewerObject
+						}
+					}
+				} `graphql:"reviewRequests(first:$reviewRequestsFirst)"`
+				ForcePushes ForcePushEvents `graphql:"forcePushes: timelineItems(last:$forcePushesLast,itemTypes:[HEAD_REF_FORCE_PUSHED_EVENT])"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner":     githubv4.String(m.Owner),
+		"repositoryName":      githubv4.String(m.Repository),
+		"prNumber":            githubv4.Int(pr),
+		"commitsLast":         githubv4.Int(100),
+		"labelsFirst":         githubv4.Int(100),
+		"reviewRequestsFirst": githubv4.Int(100),
+		"forcePushesLast":     githubv4.Int(100),
+	}
+
+	// TODO: Pagination - in case someone pushes > 100 commits before the build has time to start :p
+	if err := m.query(&query, vars); err != nil {
+		if m.fallbackToREST(err) {
+			return m.getPullRequestREST(pr, commitRef)
+		}
+		return nil, err
+	}
+
+	labels := make([]LabelObject, 0, len(query.Repository.PullRequest.Labels.Edges))
+	for _, l := range query.Repository.PullRequest.Labels.Edges {
+		labels = append(labels, l.Node.LabelObject)
+	}
+
+	reviewers := make([]RequestedReviewerObject, 0, len(query.Repository.PullRequest.ReviewRequests.Edges))
+	for _, r := range query.Repository.PullRequest.ReviewRequests.Edges {
+		reviewers = append(reviewers, r.Node.RequestedReviewer)
+	}
+
+	for _, c := range query.Repository.PullRequest.Commits.Edges {
+		if c.Node.Commit.OID == commitRef {
+			// Return as soon as we find the correct ref.
+			return &PullRequest{
+				PullRequestObject:  query.Repository.PullRequest.PullRequestObject,
+				Tip:                c.Node.Commit,
+				Labels:             labels,
+				RequestedReviewers: reviewers,
+				ForcePushedDate:    query.Repository.PullRequest.ForcePushes.PushedDate(commitRef),
+			}, nil
+		}
+	}
+
+	// Return an error if the commit was not found
+	return nil, &CommitNotFoundError{Ref: commitRef}
+}
+
+// GetPullRequestDetails returns the extended pull request object.
+func (m *GithubClient) GetPullRequestDetails(prNumber string) (*PullRequestDetailsObject, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var query struct {
+		Repository struct {
+			PullRequest PullRequestDetailsObject `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
 		"repositoryOwner": githubv4.String(m.Owner),
 		"repositoryName":  githubv4.String(m.Repository),
 		"prNumber":        githubv4.Int(pr),
-		"commentsLast":    githubv4.Int(100),
 	}
 
-	if err := m.V4.Query(context.TODO(), &getComments, vars); err != nil {
+	if err := m.query(&query, vars); err != nil {
+		return nil, err
+	}
+	return &query.Repository.PullRequest, nil
+}
+
+// UpdateCommitStatus for a given commit (not supported by V4 API).
+func (m *GithubClient) UpdateCommitStatus(commitRef, baseContext, statusContext, status, targetURL, description string) error {
+	if baseContext == "" {
+		baseContext = "concourse-ci"
+	}
+
+	if statusContext == "" {
+		statusContext = "status"
+	}
+
+	if targetURL == "" {
+		targetURL = buildURL()
+	}
+
+	if description == "" {
+		description = defaultStatusDescription(status)
+	}
+
+	_, _, err := m.V3.Repositories.CreateStatus(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		commitRef,
+		&github.RepoStatus{
+			State:       github.String(strings.ToLower(status)),
+			TargetURL:   github.String(targetURL),
+			Description: github.String(description),
+			Context:     github.String(path.Join(baseContext, statusContext)),
+		},
+	)
+	return err
+}
+
+// defaultStatusDescription is the description of statuses which are set without one.
+func defaultStatusDescription(status string) string {
+	return fmt.Sprintf("Concourse CI build %s", status)
+}
+
+// GetCommitStatus returns the latest status of a commit with the given context (using the same defaults
+// as UpdateCommitStatus), or nil if there is none.
+func (m *GithubClient) GetCommitStatus(commitRef, baseContext, statusContext string) (*CommitStatus, error) {
+	if baseContext == "" {
+		baseContext = "concourse-ci"
+	}
+	if statusContext == "" {
+		statusContext = "status"
+	}
+	fullContext := path.Join(baseContext, statusContext)
+
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		combined, response, err := m.V3.Repositories.GetCombinedStatus(context.TODO(), m.Owner, m.Repository, commitRef, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range combined.Statuses {
+			if s.GetContext() == fullContext {
+				return &CommitStatus{
+					Context:     s.GetContext(),
+					State:       s.GetState(),
+					TargetURL:   s.GetTargetURL(),
+					Description: s.GetDescription(),
+				}, nil
+			}
+		}
+		if response.NextPage == 0 {
+			return nil, nil
+		}
+		opt.Page = response.NextPage
+	}
+}
+
+// maxAnnotationsPerRequest is the number of annotations the Checks API accepts per request.
+const maxAnnotationsPerRequest = 50
+
+// UpdateCheckRun creates a check run for the commit, or updates the latest check run with the same name,
+// and returns the ID of the check run.
+func (m *GithubClient) UpdateCheckRun(commitRef string, run CheckRun) (int64, error) {
+	existing, _, err := m.V3.Checks.ListCheckRunsForRef(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		commitRef,
+		&github.ListCheckRunsOptions{CheckName: github.String(run.Name)},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var annotations []*github.CheckRunAnnotation
+	for _, a := range run.Annotations {
+		annotation := &github.CheckRunAnnotation{
+			Path:            github.String(a.Path),
+			StartLine:       github.Int(a.StartLine),
+			EndLine:         github.Int(a.EndLine),
+			AnnotationLevel: github.String(a.AnnotationLevel),
+			Message:         github.String(a.Message),
+		}
+		if a.StartColumn > 0 {
+			annotation.StartColumn = github.Int(a.StartColumn)
+		}
+		if a.EndColumn > 0 {
+			annotation.EndColumn = github.Int(a.EndColumn)
+		}
+		if a.Title != "" {
+			annotation.Title = github.String(a.Title)
+		}
+		if a.RawDetails != "" {
+			annotation.RawDetails = github.String(a.RawDetails)
+		}
+		annotations = append(annotations, annotation)
+	}
+
+	// Annotations are sent in batches, the first batch together with the check run itself.
+	output := func(batch []*github.CheckRunAnnotation) *github.CheckRunOutput {
+		return &github.CheckRunOutput{
+			Title:       github.String(run.Title),
+			Summary:     github.String(run.Summary),
+			Annotations: batch,
+		}
+	}
+	next := func() []*github.CheckRunAnnotation {
+		n := len(annotations)
+		if n > maxAnnotationsPerRequest {
+			n = maxAnnotationsPerRequest
+		}
+		batch := annotations[:n]
+		annotations = annotations[n:]
+		return batch
+	}
+
+	var status, conclusion, detailsURL *string
+	var completedAt *github.Timestamp
+	if run.Status != "" {
+		status = github.String(run.Status)
+	}
+	if run.Conclusion != "" {
+		conclusion = github.String(run.Conclusion)
+		completedAt = &github.Timestamp{Time: time.Now()}
+	}
+	if run.DetailsURL != "" {
+		detailsURL = github.String(run.DetailsURL)
+	}
+
+	var id int64
+	if existing.GetTotal() > 0 {
+		id = existing.CheckRuns[0].GetID()
+		_, _, err = m.V3.Checks.UpdateCheckRun(context.TODO(), m.Owner, m.Repository, id, github.UpdateCheckRunOptions{
+			Name:        run.Name,
+			DetailsURL:  detailsURL,
+			Status:      status,
+			Conclusion:  conclusion,
+			CompletedAt: completedAt,
+			Output:      output(next()),
+		})
+	} else {
+		var created *github.CheckRun
+		created, _, err = m.V3.Checks.CreateCheckRun(context.TODO(), m.Owner, m.Repository, github.CreateCheckRunOptions{
+			Name:        run.Name,
+			HeadBranch:  run.HeadBranch,
+			HeadSHA:     commitRef,
+			DetailsURL:  detailsURL,
+			Status:      status,
+			Conclusion:  conclusion,
+			CompletedAt: completedAt,
+			Output:      output(next()),
+		})
+		id = created.GetID()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for len(annotations) > 0 {
+		if _, _, err := m.V3.Checks.UpdateCheckRun(context.TODO(), m.Owner, m.Repository, id, github.UpdateCheckRunOptions{
+			Name:   run.Name,
+			Output: output(next()),
+		}); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// UpdateDeployment creates a deployment status for the latest deployment of the commit to the environment,
+// creating the deployment first if it does not exist.
+func (m *GithubClient) UpdateDeployment(commitRef string, d Deployment) error {
+	existing, _, err := m.V3.Repositories.ListDeployments(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		&github.DeploymentsListOptions{SHA: commitRef, Environment: d.Environment},
+	)
+	if err != nil {
 		return err
 	}
 
-	for _, e := range getComments.Repository.PullRequest.Comments.Edges {
-		if e.Node.Author.Login == getComments.Viewer.Login {
-			_, err := m.V3.Issues.DeleteComment(context.TODO(), m.Owner, m.Repository, e.Node.DatabaseId)
-			if err != nil {
+	var id int64
+	if len(existing) > 0 {
+		id = existing[0].GetID()
+	} else {
+		deployment, _, err := m.V3.Repositories.CreateDeployment(context.TODO(), m.Owner, m.Repository, &github.DeploymentRequest{
+			Ref:                   github.String(commitRef),
+			Environment:           github.String(d.Environment),
+			Description:           github.String(d.Description),
+			TransientEnvironment:  github.Bool(d.Transient),
+			ProductionEnvironment: github.Bool(d.Production),
+			// Do not merge the base branch or wait for statuses, since we are deploying a specific commit.
+			AutoMerge:        github.Bool(false),
+			RequiredContexts: &[]string{},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create deployment: %s", err)
+		}
+		id = deployment.GetID()
+	}
+
+	status := &github.DeploymentStatusRequest{
+		State:       github.String(d.State),
+		Description: github.String(d.Description),
+	}
+	if d.EnvironmentURL != "" {
+		status.EnvironmentURL = github.String(d.EnvironmentURL)
+	}
+	if d.LogURL != "" {
+		status.LogURL = github.String(d.LogURL)
+	}
+
+	_, _, err = m.V3.Repositories.CreateDeploymentStatus(context.TODO(), m.Owner, m.Repository, id, status)
+	return err
+}
+
+// DeletePreviousComments made by the authenticated user, including review comments (i.e. inline comments
+// posted with suggestions_file and review_reply). If filter is not nil, only comments with
+// a body matching the filter are deleted.
+func (m *GithubClient) DeletePreviousComments(prNumber string, filter *regexp.Regexp) error {
+	comments, err := m.viewerComments(prNumber)
+	if err != nil {
+		return err
+	}
+	reviewComments, err := m.viewerReviewComments(prNumber)
+	if err != nil {
+		return err
+	}
+
+	// Comments are deleted one at a time (as recommended by Github to avoid secondary rate limits),
+	// and comments which have already been deleted (e.g. by a concurrent build) are ignored.
+	for _, c := range comments {
+		if filter == nil || filter.MatchString(c.Body) {
+			res, err := m.V3.Issues.DeleteComment(context.TODO(), m.Owner, m.Repository, c.DatabaseId)
+			if err != nil && (res == nil || res.StatusCode != http.StatusNotFound) {
 				return err
 			}
 		}
 	}
+	for _, c := range reviewComments {
+		if filter == nil || filter.MatchString(c.Body) {
+			res, err := m.V3.PullRequests.DeleteComment(context.TODO(), m.Owner, m.Repository, c.DatabaseId)
+			if err != nil && (res == nil || res.StatusCode != http.StatusNotFound) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// MinimizePreviousComments made by the authenticated user (as outdated). If filter is not nil,
+// only comments with a body matching the filter are minimized.
+func (m *GithubClient) MinimizePreviousComments(prNumber string, filter *regexp.Regexp) error {
+	comments, err := m.viewerComments(prNumber)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range comments {
+		if c.IsMinimized || (filter != nil && !filter.MatchString(c.Body)) {
+			continue
+		}
+
+		var mutation struct {
+			MinimizeComment struct {
+				ClientMutationID string
+			} `graphql:"minimizeComment(input:$input)"`
+		}
+
+		input := githubv4.MinimizeCommentInput{
+			SubjectID:  githubv4.ID(c.ID),
+			Classifier: githubv4.ReportedContentClassifiersOutdated,
+		}
+
+		if err := m.V4.Mutate(context.TODO(), &mutation, input, nil); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// DeleteComment by ID.
+func (m *GithubClient) DeleteComment(commentID int64) error {
+	_, err := m.V3.Issues.DeleteComment(context.TODO(), m.Owner, m.Repository, commentID)
+	return err
+}
+
+// MinimizeComment by ID, marking it as resolved.
+func (m *GithubClient) MinimizeComment(commentID int64) error {
+	comment, _, err := m.V3.Issues.GetComment(context.TODO(), m.Owner, m.Repository, commentID)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		MinimizeComment struct {
+			ClientMutationID string
+		} `graphql:"minimizeComment(input:$input)"`
+	}
+
+	input := githubv4.MinimizeCommentInput{
+		SubjectID:  githubv4.ID(comment.GetNodeID()),
+		Classifier: githubv4.ReportedContentClassifiersResolved,
+	}
+
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// viewerComments lists all comments on a pull request (oldest first) that were made by the authenticated user.
+func (m *GithubClient) viewerComments(prNumber string) ([]CommentObject, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var getComments struct {
+		Viewer struct {
+			Login string
+		}
+		Repository struct {
+			PullRequest struct {
+				Id       string
+				Comments struct {
+					Edges []struct {
+						Node struct {
+							CommentObject
+							Author struct {
+								Login string
+							}
+						}
+					}
+					PageInfo struct {
+						StartCursor     githubv4.String
+						HasPreviousPage bool
+					}
+				} `graphql:"comments(last:$commentsLast,before:$commentsCursor)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(pr),
+		"commentsLast":    githubv4.Int(100),
+		"commentsCursor":  (*githubv4.String)(nil),
+	}
+
+	// Pages are fetched from the newest to the oldest comments
+	var comments []CommentObject
+	for {
+		if err := m.V4.Query(context.TODO(), &getComments, vars); err != nil {
+			return nil, err
+		}
+		m.viewer = getComments.Viewer.Login
+
+		var page []CommentObject
+		for _, e := range getComments.Repository.PullRequest.Comments.Edges {
+			if e.Node.Author.Login == getComments.Viewer.Login {
+				page = append(page, e.Node.CommentObject)
+			}
+		}
+		comments = append(page, comments...)
+
+		if !getComments.Repository.PullRequest.Comments.PageInfo.HasPreviousPage {
+			break
+		}
+		vars["commentsCursor"] = githubv4.NewString(getComments.Repository.PullRequest.Comments.PageInfo.StartCursor)
+	}
+	return comments, nil
+}
+
+// viewerReviewComments lists all review comments on a pull request that were made by the authenticated
+// user, which must have been looked up by viewerComments. The V3 API suffixes the login of Github Apps
+// with [bot] (unlike the V4 API), which is ignored.
+func (m *GithubClient) viewerReviewComments(prNumber string) ([]CommentObject, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var comments []CommentObject
+	opt := &github.PullRequestListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		result, response, err := m.V3.PullRequests.ListComments(context.TODO(), m.Owner, m.Repository, pr, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range result {
+			if strings.TrimSuffix(c.GetUser().GetLogin(), "[bot]") == strings.TrimSuffix(m.viewer, "[bot]") {
+				comments = append(comments, CommentObject{ID: c.GetNodeID(), DatabaseId: c.GetID(), Body: c.GetBody()})
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+	return comments, nil
+}
+
 func parseRepository(s string) (string, string, error) {
 	parts := strings.Split(s, "/")
 	if len(parts) != 2 {
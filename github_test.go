@@ -0,0 +1,304 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestListPullRequestsFields(t *testing.T) {
+	tests := []struct {
+		description string
+		fields      resource.PullRequestFields
+	}{
+		{
+			description: "all fields are fetched",
+			fields:      resource.PullRequestFields{Labels: true, Reviews: true, Files: true},
+		},
+		{
+			description: "labels, reviews and files can be skipped",
+			fields:      resource.PullRequestFields{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query     string                 `json:"query"`
+					Variables map[string]interface{} `json:"variables"`
+				}
+				b, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(b, &body))
+
+				assert.Contains(t, body.Query, "reviews(states: $prReviewStates) @include(if: $withReviews)")
+				assert.Contains(t, body.Query, "labels(first:$labelsFirst) @include(if: $withLabels)")
+				assert.Equal(t, tc.fields.Reviews, body.Variables["withReviews"])
+				assert.Equal(t, tc.fields.Labels, body.Variables["withLabels"])
+				assert.Contains(t, body.Query, "files(first:$filesFirst) @include(if: $withFiles)")
+				assert.Equal(t, tc.fields.Files, body.Variables["withFiles"])
+
+				w.Write([]byte(`{"data":{"repository":{"pullRequests":{"edges":[],"pageInfo":{"hasNextPage":false}}}}}`))
+			}))
+			defer server.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+			})
+			require.NoError(t, err)
+
+			_, err = client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, tc.fields, time.Time{})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestListPullRequestsQueryLimits(t *testing.T) {
+	limitError := `{"errors":[{"type":"MAX_NODE_LIMIT_EXCEEDED","message":"By the time this query traverses to the files connection, it is requesting up to 1,000,000 possible nodes which exceeds the maximum limit of 500,000."}]}`
+	pullRequests := `{"data":{"repository":{"pullRequests":{"edges":[
+		{"node":{"number":1,"commits":{"edges":[{"node":{"commit":{"oid":"sha1"}}}]},"files":{"nodes":[{"path":"a.txt"},{"path":"b.txt"}],"pageInfo":{"hasNextPage":false}}}},
+		{"node":{"number":2,"commits":{"edges":[{"node":{"commit":{"oid":"sha2"}}}]},"files":{"nodes":[{"path":"c.txt"}],"pageInfo":{"hasNextPage":true}}}}
+	],"pageInfo":{"hasNextPage":false}}}}}`
+	pullRequestsWithoutFiles := `{"data":{"repository":{"pullRequests":{"edges":[
+		{"node":{"number":1,"commits":{"edges":[{"node":{"commit":{"oid":"sha1"}}}]}}},
+		{"node":{"number":2,"commits":{"edges":[{"node":{"commit":{"oid":"sha2"}}}]}}}
+	],"pageInfo":{"hasNextPage":false}}}}}`
+
+	tests := []struct {
+		description string
+		limit       func(prFirst float64, withFiles bool) bool
+		expected    [][]string
+		queries     int
+	}{
+		{
+			description: "files are fetched along with pull requests",
+			limit:       func(float64, bool) bool { return false },
+			expected:    [][]string{{"a.txt", "b.txt"}, nil},
+			queries:     1,
+		},
+		{
+			description: "fewer pull requests are fetched per page when limits are exceeded",
+			limit:       func(prFirst float64, withFiles bool) bool { return prFirst > 25 },
+			expected:    [][]string{{"a.txt", "b.txt"}, nil},
+			queries:     2,
+		},
+		{
+			description: "files are left out when limits are still exceeded",
+			limit:       func(prFirst float64, withFiles bool) bool { return withFiles },
+			expected:    [][]string{nil, nil},
+			queries:     3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var queries int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Variables map[string]interface{} `json:"variables"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				queries++
+
+				withFiles := body.Variables["withFiles"].(bool)
+				if tc.limit(body.Variables["prFirst"].(float64), withFiles) {
+					w.Write([]byte(limitError))
+					return
+				}
+				if !withFiles {
+					w.Write([]byte(pullRequestsWithoutFiles))
+					return
+				}
+				w.Write([]byte(pullRequests))
+			}))
+			defer server.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+				// Skip introspection of the schema
+				GithubAPIVersion: "3.9",
+			})
+			require.NoError(t, err)
+
+			pulls, err := client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{Files: true}, time.Time{})
+			require.NoError(t, err)
+
+			var files [][]string
+			for _, p := range pulls {
+				files = append(files, p.Files)
+			}
+			assert.Equal(t, tc.expected, files)
+			assert.Equal(t, tc.queries, queries)
+		})
+	}
+}
+
+func TestDeletePreviousComments(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/graphql":
+			var body struct {
+				Variables map[string]interface{} `json:"variables"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			// The newest page is fetched first
+			if body.Variables["commentsCursor"] == nil {
+				w.Write([]byte(`{"data":{"viewer":{"login":"bot"},"repository":{"pullRequest":{"comments":{
+					"edges":[
+						{"node":{"databaseId":3,"body":"build failed","author":{"login":"bot"}}},
+						{"node":{"databaseId":4,"body":"build failed","author":{"login":"someone"}}}
+					],
+					"pageInfo":{"startCursor":"cursor3","hasPreviousPage":true}}}}}}`))
+				return
+			}
+			assert.Equal(t, "cursor3", body.Variables["commentsCursor"])
+			w.Write([]byte(`{"data":{"viewer":{"login":"bot"},"repository":{"pullRequest":{"comments":{
+				"edges":[
+					{"node":{"databaseId":1,"body":"build failed","author":{"login":"bot"}}},
+					{"node":{"databaseId":2,"body":"lgtm","author":{"login":"bot"}}}
+				],
+				"pageInfo":{"hasPreviousPage":false}}}}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/itsdalmo/test-repository/pulls/1/comments":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+				w.Write([]byte(`[{"id":10,"body":"build failed","user":{"login":"bot"}}]`))
+				return
+			}
+			w.Write([]byte(`[{"id":11,"body":"build failed","user":{"login":"someone"}},{"id":12,"body":"build failed","user":{"login":"bot"}},
+				{"id":13,"body":"build failed","user":{"login":"bot[bot]","type":"Bot"}},{"id":14,"body":"build failed","user":{"login":"someone[bot]","type":"Bot"}}]`))
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			if strings.HasSuffix(r.URL.Path, "/1") {
+				// Deleted by a concurrent build
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeletePreviousComments("1", regexp.MustCompile("failed")))
+	assert.Equal(t, []string{
+		"/repos/itsdalmo/test-repository/issues/comments/1",
+		"/repos/itsdalmo/test-repository/issues/comments/3",
+		"/repos/itsdalmo/test-repository/pulls/comments/10",
+		"/repos/itsdalmo/test-repository/pulls/comments/12",
+		// The V3 API reports the login of Github Apps with a [bot] suffix
+		"/repos/itsdalmo/test-repository/pulls/comments/13",
+	}, deleted)
+}
+
+func TestListPullRequestsSince(t *testing.T) {
+	since := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	node := func(number int, updated time.Duration) string {
+		return fmt.Sprintf(`{"node":{"number":%d,"updatedAt":"%s","commits":{"edges":[{"node":{"commit":{"oid":"sha%d"}}}]}}}`,
+			number, since.Add(updated).Format(time.RFC3339), number)
+	}
+	pages := map[string]string{
+		"":        `{"data":{"repository":{"pullRequests":{"edges":[` + node(1, 3*time.Hour) + `,` + node(2, 2*time.Hour) + `],"pageInfo":{"endCursor":"cursor1","hasNextPage":true}}}}}`,
+		"cursor1": `{"data":{"repository":{"pullRequests":{"edges":[` + node(3, time.Hour) + `,` + node(4, 0) + `],"pageInfo":{"endCursor":"cursor2","hasNextPage":true}}}}}`,
+	}
+
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries++
+
+		assert.Contains(t, body.Query, "orderBy:{field:UPDATED_AT,direction:DESC}")
+		cursor, _ := body.Variables["prCursor"].(string)
+		page, ok := pages[cursor]
+		if !assert.True(t, ok, "unexpected cursor: %s", cursor) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+
+	pulls, err := client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{}, since)
+	require.NoError(t, err)
+
+	var numbers []int
+	for _, p := range pulls {
+		numbers = append(numbers, p.Number)
+	}
+	assert.Equal(t, []int{1, 2, 3}, numbers)
+	assert.Equal(t, 2, queries)
+}
+
+func TestGetCommitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/itsdalmo/test-repository/commits/sha/status", r.URL.Path)
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`{"statuses":[{"context":"concourse-ci/status","state":"success","target_url":"https://ci.example.com","description":"Concourse CI build success"}]}`))
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, "http://"+r.Host, r.URL.Path))
+		w.Write([]byte(`{"statuses":[{"context":"concourse-ci/unit","state":"failure"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+
+	status, err := client.GetCommitStatus("sha", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, &resource.CommitStatus{
+		Context:     "concourse-ci/status",
+		State:       "success",
+		TargetURL:   "https://ci.example.com",
+		Description: "Concourse CI build success",
+	}, status)
+
+	status, err = client.GetCommitStatus("sha", "", "e2e")
+	require.NoError(t, err)
+	assert.Nil(t, status)
+}
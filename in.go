@@ -2,30 +2,81 @@ package resource
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
 )
 
 // Get (business logic)
 func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResponse, error) {
+	if err := request.Params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %s", err)
+	}
 	if request.Params.SkipDownload {
 		return &GetResponse{Version: request.Version}, nil
 	}
 
 	pull, err := github.GetPullRequest(request.Version.PR, request.Version.Commit)
+	_, missingCommit := err.(*CommitNotFoundError)
+	if missingCommit {
+		// The branch was force pushed after the version was checked, so the commit has to be fetched by itself
+		logger.Warn("commit is no longer part of the pull request (the branch was probably force pushed), fetching it directly",
+			"pr", request.Version.PR, "commit", request.Version.Commit)
+		pull, err = pullRequestWithCommit(github, request.Version)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve pull request: %s", err)
 	}
-
-	// Initialize and pull the base for the PR
-	if err := git.Init(pull.BaseRefName); err != nil {
-		return nil, err
+	if pull.IsCrossRepository && pull.HeadRepository == nil {
+		logger.Warn("the fork of the pull request has been deleted, it is fetched from the base repository", "pr", pull.Number)
 	}
-	if err := git.Pull(pull.Repository.URL, pull.BaseRefName, request.Params.GitDepth, request.Params.Submodules, request.Params.FetchTags); err != nil {
-		return nil, err
+	if request.Version.State != "" && pull.State != request.Version.State {
+		logger.Warn("pull request state has changed since the version was checked",
+			"pr", pull.Number, "version_state", request.Version.State, "state", pull.State)
+	}
+
+	// Network operations are retried on failure.
+	attempts, delay := request.Params.GetRetries+1, request.Params.retryDelay()
+
+	if request.Params.Bare {
+		// Fetch the base and the PR without checking out a working tree
+		if err := retry(attempts, delay, func() error {
+			return git.Mirror(pull.Repository.URL, pull.BaseRefName, pull.Number, request.Params.GitDepth)
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		// Initialize and pull the base for the PR
+		if err := git.Init(pull.BaseRefName); err != nil {
+			return nil, err
+		}
+		if request.Params.GitUserName != "" {
+			if err := git.Config("user.name", request.Params.GitUserName); err != nil {
+				return nil, err
+			}
+		}
+		if request.Params.GitUserEmail != "" {
+			if err := git.Config("user.email", request.Params.GitUserEmail); err != nil {
+				return nil, err
+			}
+		}
+		if request.Params.Submodules && request.Params.SubmoduleJobs > 0 {
+			if err := git.Config("submodule.fetchJobs", strconv.Itoa(request.Params.SubmoduleJobs)); err != nil {
+				return nil, err
+			}
+		}
+		if err := retry(attempts, delay, func() error {
+			return git.Pull(pull.Repository.URL, pull.BaseRefName, request.Params.GitDepth, request.Params.Submodules, request.Params.FetchTags, request.Params.Filter)
+		}); err != nil {
+			return nil, err
+		}
 	}
 
 	// Get the last commit SHA in base for the metadata
@@ -35,23 +86,65 @@ func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResp
 	}
 
 	// Fetch the PR and merge the specified commit into the base
-	if err := git.Fetch(pull.Repository.URL, pull.Number, request.Params.GitDepth, request.Params.Submodules); err != nil {
-		return nil, err
+	if !request.Params.Bare {
+		if err := retry(attempts, delay, func() error {
+			return git.Fetch(pull.Repository.URL, pull.Number, request.Params.GitDepth, request.Params.Submodules, request.Params.Filter)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if missingCommit {
+		if err := retry(attempts, delay, func() error {
+			return git.FetchCommit(pull.Repository.URL, pull.Tip.OID, request.Params.GitDepth)
+		}); err != nil {
+			return nil, fmt.Errorf("commit %s is no longer part of pull request #%d and could not be fetched: %s", pull.Tip.OID, pull.Number, err)
+		}
+	}
+
+	// Deepen shallow clones until the merge base is part of the history
+	needsMergeBase := request.Params.Patch || len(request.Params.CommitTrailers) > 0 || request.Params.FetchBase ||
+		(!request.Params.Bare && request.Params.IntegrationTool != "checkout")
+	if request.Params.GitDepth > 0 && needsMergeBase {
+		if err := deepenUntilMergeBase(git, pull, request.Params.GitDepth, attempts, delay); err != nil {
+			return nil, err
+		}
+	}
+
+	// Track the base so that tasks can e.g. diff against it (git diff origin/<base>...HEAD), since the
+	// local base branch is integrated with the pull request.
+	if request.Params.FetchBase {
+		if err := git.TrackBase(pull.BaseRefName, baseSHA); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create the metadata
-	var metadata Metadata
-	metadata.Add("pr", strconv.Itoa(pull.Number))
-	metadata.Add("title", pull.Title)
-	metadata.Add("url", pull.URL)
-	metadata.Add("head_name", pull.HeadRefName)
-	metadata.Add("head_sha", pull.Tip.OID)
-	metadata.Add("base_name", pull.BaseRefName)
-	metadata.Add("base_sha", baseSHA)
-	metadata.Add("message", pull.Tip.Message)
-	metadata.Add("author", pull.Tip.Author.User.Login)
-	metadata.Add("author_email", pull.Tip.Author.Email)
-	metadata.Add("state", string(pull.State))
+	metadata := newMetadata(pull, baseSHA)
+	upstream := request.Source.UpstreamCompatible
+	if upstream {
+		metadata = metadata.upstream()
+	}
+
+	// Add how far the pull request is behind (and ahead of) its base. This is only informational, so get does
+	// not fail if the commits cannot be compared.
+	if !upstream {
+		if comparison, err := github.CompareCommits(pull.BaseRefName, pull.Tip.OID); err != nil {
+			logger.Warn("failed to compare the pull request with its base", "pr", pull.Number, "error", err)
+		} else if comparison != nil {
+			metadata.Add("behind_by", strconv.Itoa(comparison.BehindBy))
+			metadata.Add("ahead_by", strconv.Itoa(comparison.AheadBy))
+		}
+	}
+
+	if len(request.Params.CommitTrailers) > 0 {
+		messages, err := git.CommitMessages(baseSHA, pull.Tip.OID)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range request.Params.CommitTrailers {
+			metadata.Add(trailerMetadataName(key), strings.Join(ParseTrailers(messages, key), "\n"))
+		}
+	}
 
 	// Write version and metadata for reuse in PUT
 	path := filepath.Join(outputDir, ".git", "resource")
@@ -72,6 +165,11 @@ func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResp
 	if err := ioutil.WriteFile(filepath.Join(path, "metadata.json"), b, 0644); err != nil {
 		return nil, fmt.Errorf("failed to write metadata: %s", err)
 	}
+	if !upstream {
+		if err := ioutil.WriteFile(filepath.Join(path, "metadata.env"), metadata.Env(), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write metadata env file: %s", err)
+		}
+	}
 
 	for _, d := range metadata {
 		filename := d.Name
@@ -81,38 +179,133 @@ func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResp
 		}
 	}
 
-	switch tool := request.Params.IntegrationTool; tool {
-	case "rebase":
-		if err := git.Rebase(pull.BaseRefName, pull.Tip.OID, request.Params.Submodules); err != nil {
-			return nil, err
+	// Write labels and requested reviewers so tasks can gate on them without credentials
+	// (unless the files have to match the upstream resource)
+	if !upstream {
+		labels := make([]string, 0, len(pull.Labels))
+		for _, l := range pull.Labels {
+			labels = append(labels, l.Name)
 		}
-	case "merge", "":
-		if err := git.Merge(pull.Tip.OID, request.Params.Submodules); err != nil {
-			return nil, err
+		b, err = json.Marshal(labels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal labels: %s", err)
 		}
-	case "checkout":
-		if err := git.Checkout(pull.HeadRefName, pull.Tip.OID, request.Params.Submodules); err != nil {
-			return nil, err
+		if err := ioutil.WriteFile(filepath.Join(path, "labels.json"), b, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write labels: %s", err)
+		}
+
+		reviewers := make([]RequestedReviewer, 0, len(pull.RequestedReviewers))
+		for _, r := range pull.RequestedReviewers {
+			reviewers = append(reviewers, RequestedReviewer{Type: r.Typename, Name: r.Name()})
+		}
+		b, err = json.Marshal(reviewers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal requested reviewers: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(path, "requested_reviewers.json"), b, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write requested reviewers: %s", err)
 		}
-	default:
-		return nil, fmt.Errorf("invalid integration tool specified: %s", tool)
 	}
 
-	if request.Source.GitCryptKey != "" {
-		if err := git.GitCryptUnlock(request.Source.GitCryptKey); err != nil {
+	if request.Params.Patch {
+		if err := git.Diff(baseSHA, pull.Tip.OID, filepath.Join(path, "pr.patch")); err != nil {
 			return nil, err
 		}
 	}
 
-	if request.Params.ListChangedFiles {
-		cfol, err := github.GetChangedFiles(request.Version.PR, request.Version.Commit)
+	// Integrate the PR with the base unless only the refs were fetched
+	if !request.Params.Bare {
+		switch tool := request.Params.IntegrationTool; tool {
+		case "rebase":
+			if err := git.Rebase(pull.BaseRefName, pull.Tip.OID, request.Params.Submodules); err != nil {
+				return nil, err
+			}
+		case "merge", "":
+			if err := git.Merge(pull.Tip.OID, request.Params.Submodules); err != nil {
+				return nil, err
+			}
+		case "checkout":
+			if err := git.Checkout(pull.HeadRefName, pull.Tip.OID, request.Params.Submodules); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid integration tool specified: %s", tool)
+		}
+
+		// Check out a branch named after the head of the pull request (which checkout already did), since build
+		// tools derive e.g. versions from the branch name. The base branch is left as is if the names are the same.
+		if request.Params.IntegrationTool != "checkout" && pull.HeadRefName != pull.BaseRefName {
+			if err := git.Branch(pull.HeadRefName); err != nil {
+				return nil, err
+			}
+		}
+
+		if key := request.EffectiveSource().GitCryptKey; key != "" {
+			if err := git.GitCryptUnlock(key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Verify that the commit of the version is what was checked out, and that it is still part of the pull request
+	if request.Params.Verify {
+		verification, err := verifyCheckout(github, git, request, missingCommit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify checkout: %s", err)
+		}
+		if !verification.Verified {
+			logger.Warn("checkout could not be verified", "pr", request.Version.PR, "commit", request.Version.Commit,
+				"problems", strings.Join(verification.Problems, "; "))
+		}
+		b, err := json.Marshal(verification)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal verification: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(path, "verified"), b, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write verification: %s", err)
+		}
+	}
+
+	if request.Params.FullMetadata {
+		details, err := github.GetPullRequestDetails(request.Version.PR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve pull request details: %s", err)
+		}
+		b, err := json.Marshal(details)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal pull request details: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(path, "pr.json"), b, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write pull request details: %s", err)
+		}
+	}
+
+	if request.Params.ListLinkedIssues {
+		issues, err := github.GetLinkedIssues(request.Version.PR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch linked issues: %s", err)
+		}
+		b, err := json.Marshal(issues)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal linked issues: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(path, "linked_issues.json"), b, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write linked issues: %s", err)
+		}
+	}
+
+	var changedFiles []ChangedFileObject
+	if request.Params.ListChangedFiles || request.Params.ResolveCodeowners {
+		changedFiles, err = github.GetChangedFiles(request.Version.PR, request.Version.Commit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch list of changed files: %s", err)
 		}
+	}
 
+	if request.Params.ListChangedFiles {
 		var fl []byte
 
-		for _, v := range cfol {
+		for _, v := range changedFiles {
 			fl = append(fl, []byte(v.Path+"\n")...)
 		}
 
@@ -122,20 +315,222 @@ func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResp
 		}
 	}
 
+	if request.Params.ResolveCodeowners {
+		codeowners, err := readCodeowners(git, baseSHA)
+		if err != nil {
+			return nil, err
+		}
+		owners := make(map[string][]string, len(changedFiles))
+		for _, f := range changedFiles {
+			owners[f.Path] = codeowners.Owners(f.Path)
+		}
+		b, err := json.Marshal(owners)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal codeowners: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(path, "codeowners.json"), b, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write codeowners: %s", err)
+		}
+	}
+
+	if !upstream {
+		github.APIUsage().AddTo(&metadata)
+	}
 	return &GetResponse{
 		Version:  request.Version,
 		Metadata: metadata,
 	}, nil
 }
 
+// trailerMetadataName returns the metadata name for a trailer key, e.g. trailer_signed_off_by for Signed-off-by.
+func trailerMetadataName(key string) string {
+	return "trailer_" + strings.Replace(strings.ToLower(key), "-", "_", -1)
+}
+
+// readCodeowners parses the CODEOWNERS file at the given revision. An empty set
+// of rules is returned if the repository does not have a CODEOWNERS file.
+func readCodeowners(git Git, rev string) (Codeowners, error) {
+	for _, p := range CodeownersPaths {
+		content, err := git.Show(rev, p)
+		if err != nil {
+			continue
+		}
+		codeowners, err := ParseCodeowners(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", p, err)
+		}
+		return codeowners, nil
+	}
+	return Codeowners{}, nil
+}
+
+// maxDeepenAttempts caps the number of times a shallow clone is deepened
+// while looking for the merge base.
+const maxDeepenAttempts = 10
+
+// deepenUntilMergeBase deepens the history of a shallow clone (doubling the depth
+// on each attempt) until a merge base between the base branch and the pull request
+// tip can be found.
+func deepenUntilMergeBase(git Git, pull *PullRequest, depth, attempts int, delay time.Duration) error {
+	for i := 0; ; i++ {
+		if _, err := git.MergeBase(pull.BaseRefName, pull.Tip.OID); err == nil {
+			return nil
+		}
+		if i == maxDeepenAttempts {
+			return fmt.Errorf("merge base not found after deepening the clone %d times", maxDeepenAttempts)
+		}
+		if err := retry(attempts, delay, func() error {
+			return git.Deepen(pull.Repository.URL, pull.BaseRefName, pull.Number, depth)
+		}); err != nil {
+			return err
+		}
+		depth *= 2
+	}
+}
+
+// newMetadata for a pull request.
+func newMetadata(pull *PullRequest, baseSHA string) Metadata {
+	var metadata Metadata
+	metadata.Add("pr", strconv.Itoa(pull.Number))
+	metadata.Add("title", pull.Title)
+	metadata.Add("url", pull.URL)
+	metadata.Add("head_name", pull.HeadRefName)
+	metadata.Add("head_sha", pull.Tip.OID)
+	metadata.Add("base_name", pull.BaseRefName)
+	metadata.Add("base_sha", baseSHA)
+	metadata.Add("message", pull.Tip.Message)
+	metadata.Add("author", pull.Tip.Author.User.Login)
+	metadata.Add("author_email", pull.Tip.Author.Email)
+	metadata.Add("author_name", pull.Tip.Author.Name)
+	metadata.Add("state", string(pull.State))
+	return metadata
+}
+
+// pullRequestWithCommit returns the pull request of a version whose commit is no longer part of it, with the
+// commit as the tip. Since only the SHA of the commit is known, the message and author are left empty.
+func pullRequestWithCommit(github Github, version Version) (*PullRequest, error) {
+	details, err := github.GetPullRequestDetails(version.PR)
+	if err != nil {
+		return nil, err
+	}
+	pull, err := github.GetPullRequest(version.PR, details.HeadRefOid)
+	if err != nil {
+		return nil, err
+	}
+	pull.Tip = CommitObject{OID: version.Commit, CommittedDate: githubv4.DateTime{Time: version.CommittedDate}}
+	pull.ForcePushedDate = githubv4.DateTime{}
+	return pull, nil
+}
+
 // GetParameters ...
 type GetParameters struct {
-	SkipDownload     bool   `json:"skip_download"`
-	IntegrationTool  string `json:"integration_tool"`
-	GitDepth         int    `json:"git_depth"`
-	Submodules       bool   `json:"submodules"`
-	ListChangedFiles bool   `json:"list_changed_files"`
-	FetchTags        bool   `json:"fetch_tags"`
+	SkipDownload      bool     `json:"skip_download"`
+	IntegrationTool   string   `json:"integration_tool"`
+	GitDepth          int      `json:"git_depth"`
+	Submodules        bool     `json:"submodules"`
+	SubmoduleJobs     int      `json:"submodule_jobs"`
+	ListChangedFiles  bool     `json:"list_changed_files"`
+	ListLinkedIssues  bool     `json:"list_linked_issues"`
+	ResolveCodeowners bool     `json:"resolve_codeowners"`
+	FetchTags         bool     `json:"fetch_tags"`
+	FullMetadata      bool     `json:"full_metadata"`
+	Filter            string   `json:"filter"`
+	GetRetries        int      `json:"get_retries"`
+	RetryDelay        string   `json:"retry_delay"`
+	Bare              bool     `json:"bare"`
+	Patch             bool     `json:"patch"`
+	CommitTrailers    []string `json:"commit_trailers"`
+	GitUserName       string   `json:"git_user_name"`
+	GitUserEmail      string   `json:"git_user_email"`
+	FetchBase         bool     `json:"fetch_base"`
+	Verify            bool     `json:"verify"`
+
+	// Overrides for the source configuration.
+	DisableGitLFS *bool   `json:"disable_git_lfs"`
+	GitCryptKey   *string `json:"git_crypt_key"`
+}
+
+// Validate the get parameters.
+func (p *GetParameters) Validate() error {
+	if p.GetRetries < 0 {
+		return errors.New("get_retries must not be negative")
+	}
+	if p.RetryDelay != "" {
+		if _, err := time.ParseDuration(p.RetryDelay); err != nil {
+			return fmt.Errorf("failed to parse retry_delay: %s", err)
+		}
+	}
+	return nil
+}
+
+// retryDelay returns the initial delay between retries of network operations.
+func (p *GetParameters) retryDelay() time.Duration {
+	if p.RetryDelay == "" {
+		return 5 * time.Second
+	}
+	d, _ := time.ParseDuration(p.RetryDelay)
+	return d
+}
+
+// Verification written to verified, which is the result of verifying the checkout.
+type Verification struct {
+	Verified bool   `json:"verified"`
+	Commit   string `json:"commit"`
+	// Head is the commit which is checked out (e.g. the merge commit), and is empty for bare repositories.
+	Head     string   `json:"head,omitempty"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// verifyCheckout verifies that the commit of the version was fetched and is what was checked out (or merged),
+// and that it is still part of the pull request on Github (i.e. the branch was not force pushed).
+func verifyCheckout(github Github, git Git, request GetRequest, missingCommit bool) (*Verification, error) {
+	commit := request.Version.Commit
+	v := &Verification{Commit: commit}
+
+	if sha, err := git.RevParse(commit + "^{commit}"); err != nil || sha != commit {
+		v.Problems = append(v.Problems, fmt.Sprintf("commit %s was not fetched", commit))
+	}
+
+	if !request.Params.Bare {
+		head, err := git.RevParse("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		v.Head = head
+		switch request.Params.IntegrationTool {
+		case "checkout":
+			if head != commit {
+				v.Problems = append(v.Problems, fmt.Sprintf("HEAD is %s instead of commit %s", head, commit))
+			}
+		case "merge", "":
+			if base, err := git.MergeBase(commit, head); err != nil || base != commit {
+				v.Problems = append(v.Problems, fmt.Sprintf("commit %s is not part of HEAD", commit))
+			}
+		}
+		// Rebased commits are rewritten, so only the presence of the commit is verified
+	}
+
+	// The pull request is fetched again, since the branch can be force pushed while the repository is cloned
+	if !missingCommit {
+		_, err := github.GetPullRequest(request.Version.PR, commit)
+		if _, ok := err.(*CommitNotFoundError); ok {
+			missingCommit = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if missingCommit {
+		v.Problems = append(v.Problems, fmt.Sprintf("commit %s is no longer part of the pull request (the branch was force pushed)", commit))
+	}
+
+	v.Verified = len(v.Problems) == 0
+	return v, nil
+}
+
+// RequestedReviewer written to requested_reviewers.json.
+type RequestedReviewer struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
 }
 
 // GetRequest ...
@@ -145,6 +540,19 @@ type GetRequest struct {
 	Params  GetParameters `json:"params"`
 }
 
+// EffectiveSource returns the source configuration with the overrides
+// from the get parameters applied.
+func (r *GetRequest) EffectiveSource() Source {
+	s := r.Source
+	if r.Params.DisableGitLFS != nil {
+		s.DisableGitLFS = *r.Params.DisableGitLFS
+	}
+	if r.Params.GitCryptKey != nil {
+		s.GitCryptKey = *r.Params.GitCryptKey
+	}
+	return s
+}
+
 // GetResponse ...
 type GetResponse struct {
 	Version  Version  `json:"version"`
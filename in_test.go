@@ -1,6 +1,8 @@
 package resource_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/shurcooL/githubv4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	resource "github.com/telia-oss/github-pr-resource"
 	"github.com/telia-oss/github-pr-resource/fakes"
 )
@@ -18,15 +21,24 @@ import (
 func TestGet(t *testing.T) {
 
 	tests := []struct {
-		description    string
-		source         resource.Source
-		version        resource.Version
-		parameters     resource.GetParameters
-		pullRequest    *resource.PullRequest
-		versionString  string
-		metadataString string
-		files          []resource.ChangedFileObject
-		filesString    string
+		description     string
+		source          resource.Source
+		version         resource.Version
+		parameters      resource.GetParameters
+		pullRequest     *resource.PullRequest
+		versionString   string
+		metadataString  string
+		files           []resource.ChangedFileObject
+		filesString     string
+		labelsString    string
+		reviewers       []resource.RequestedReviewerObject
+		reviewersString string
+		details         *resource.PullRequestDetailsObject
+		detailsString   string
+		issues          []resource.IssueObject
+		issuesString    string
+		codeowners      string
+		ownersString    string
 	}{
 		{
 			description: "get works",
@@ -44,7 +56,7 @@ func TestGet(t *testing.T) {
 			parameters:     resource.GetParameters{},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"state","value":"OPEN"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
 		},
 		{
 			description: "get supports unlocking with git crypt",
@@ -63,7 +75,7 @@ func TestGet(t *testing.T) {
 			parameters:     resource.GetParameters{},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"state","value":"OPEN"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
 		},
 		{
 			description: "get supports rebasing",
@@ -83,7 +95,7 @@ func TestGet(t *testing.T) {
 			},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"state","value":"OPEN"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
 		},
 		{
 			description: "get supports checkout",
@@ -103,7 +115,7 @@ func TestGet(t *testing.T) {
 			},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"state","value":"OPEN"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
 		},
 		{
 			description: "get supports git_depth",
@@ -123,7 +135,89 @@ func TestGet(t *testing.T) {
 			},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"state","value":"OPEN"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+		},
+		{
+			description: "get supports partial clone filters",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters: resource.GetParameters{
+				Filter: "blob:none",
+			},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+		},
+		{
+			description: "get supports fetching submodules in parallel",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters: resource.GetParameters{
+				Submodules:    true,
+				SubmoduleJobs: 8,
+			},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+		},
+		{
+			description: "get supports writing a patch",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters: resource.GetParameters{
+				Patch: true,
+			},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+		},
+		{
+			description: "get supports configuring the git identity",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters: resource.GetParameters{
+				GitUserName:  "ci-bot",
+				GitUserEmail: "ci-bot@example.com",
+			},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
 		},
 		{
 			description: "get supports list_changed_files",
@@ -151,22 +245,134 @@ func TestGet(t *testing.T) {
 				},
 			},
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"state","value":"OPEN"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
 			filesString:    "README.md\nOther.md\n",
 		},
+		{
+			description: "get supports resolve_codeowners",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters: resource.GetParameters{
+				ResolveCodeowners: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			files: []resource.ChangedFileObject{
+				{
+					Path: "README.md",
+				},
+				{
+					Path: "terraform/main.tf",
+				},
+			},
+			codeowners:     "*.md @docs\n/terraform/ @org/platform\n",
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+			ownersString:   `{"README.md":["@docs"],"terraform/main.tf":["@org/platform"]}`,
+		},
+		{
+			description: "get writes labels and requested reviewers",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters:  resource.GetParameters{},
+			pullRequest: createTestPR(1, "master", false, false, 0, []string{"bug", "enhancement"}, false, githubv4.PullRequestStateOpen),
+			reviewers: []resource.RequestedReviewerObject{
+				createTestReviewer("User", "itsdalmo"),
+				createTestReviewer("Team", "platform"),
+			},
+			versionString:   `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString:  `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+			labelsString:    `["bug","enhancement"]`,
+			reviewersString: `[{"type":"User","name":"itsdalmo"},{"type":"Team","name":"platform"}]`,
+		},
+		{
+			description: "get supports list_linked_issues",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters: resource.GetParameters{
+				ListLinkedIssues: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			issues: []resource.IssueObject{
+				{ID: "issue1", Number: 1, Title: "issue1 title", URL: "issue1 url", State: githubv4.IssueStateOpen},
+			},
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+			issuesString:   `[{"id":"issue1","number":1,"title":"issue1 title","url":"issue1 url","state":"OPEN"}]`,
+		},
+		{
+			description: "get supports full_metadata",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:                  "pr1",
+				Commit:              "commit1",
+				CommittedDate:       time.Time{},
+				ApprovedReviewCount: "0",
+				State:               githubv4.PullRequestStateOpen,
+			},
+			parameters: resource.GetParameters{
+				FullMetadata: true,
+			},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			details:        &resource.PullRequestDetailsObject{Number: 1, Title: "pr1 title"},
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"title","value":"pr1 title"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"author_email","value":"user@example.com"},{"name":"author_name","value":"name1"},{"name":"state","value":"OPEN"}]`,
+			detailsString:  `"number":1,"title":"pr1 title"`,
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
 			github := new(fakes.FakeGithub)
+			tc.pullRequest.RequestedReviewers = tc.reviewers
 			github.GetPullRequestReturns(tc.pullRequest, nil)
 
 			if tc.files != nil {
 				github.GetChangedFilesReturns(tc.files, nil)
 			}
 
+			if tc.details != nil {
+				github.GetPullRequestDetailsReturns(tc.details, nil)
+			}
+
+			if tc.issues != nil {
+				github.GetLinkedIssuesReturns(tc.issues, nil)
+			}
+
 			git := new(fakes.FakeGit)
 			git.RevParseReturns("sha", nil)
+			if tc.codeowners != "" {
+				git.ShowReturns([]byte(tc.codeowners), nil)
+			}
 
 			dir := createTestDirectory(t)
 			defer os.RemoveAll(dir)
@@ -196,6 +402,7 @@ func TestGet(t *testing.T) {
 					"message":      "commit message1",
 					"author":       "login1",
 					"author_email": "user@example.com",
+					"author_name":  "name1",
 					"title":        "pr1 title",
 				}
 
@@ -204,10 +411,40 @@ func TestGet(t *testing.T) {
 					assert.Equal(t, expected, actual)
 				}
 
-				if tc.files != nil {
+				env := readTestFile(t, filepath.Join(dir, ".git", "resource", "metadata.env"))
+				assert.Contains(t, env, "PR_NUMBER='1'\n")
+				assert.Contains(t, env, "PR_AUTHOR='login1'\n")
+				assert.Contains(t, env, "HEAD_SHA='oid1'\n")
+
+				if tc.ownersString != "" {
+					owners := readTestFile(t, filepath.Join(dir, ".git", "resource", "codeowners.json"))
+					assert.Equal(t, tc.ownersString, owners)
+				}
+
+				if tc.parameters.ListChangedFiles {
 					changedFiles := readTestFile(t, filepath.Join(dir, ".git", "resource", "changed_files"))
 					assert.Equal(t, tc.filesString, changedFiles)
 				}
+
+				if tc.labelsString != "" {
+					labels := readTestFile(t, filepath.Join(dir, ".git", "resource", "labels.json"))
+					assert.Equal(t, tc.labelsString, labels)
+				}
+
+				if tc.reviewersString != "" {
+					reviewers := readTestFile(t, filepath.Join(dir, ".git", "resource", "requested_reviewers.json"))
+					assert.Equal(t, tc.reviewersString, reviewers)
+				}
+
+				if tc.detailsString != "" {
+					details := readTestFile(t, filepath.Join(dir, ".git", "resource", "pr.json"))
+					assert.Contains(t, details, tc.detailsString)
+				}
+
+				if tc.issuesString != "" {
+					issues := readTestFile(t, filepath.Join(dir, ".git", "resource", "linked_issues.json"))
+					assert.Equal(t, tc.issuesString, issues)
+				}
 			}
 
 			// Validate Github calls
@@ -223,13 +460,34 @@ func TestGet(t *testing.T) {
 				assert.Equal(t, tc.pullRequest.BaseRefName, base)
 			}
 
+			if tc.parameters.GitUserName != "" {
+				if assert.Equal(t, 2, git.ConfigCallCount()) {
+					key, value := git.ConfigArgsForCall(0)
+					assert.Equal(t, "user.name", key)
+					assert.Equal(t, tc.parameters.GitUserName, value)
+
+					key, value = git.ConfigArgsForCall(1)
+					assert.Equal(t, "user.email", key)
+					assert.Equal(t, tc.parameters.GitUserEmail, value)
+				}
+			}
+
+			if tc.parameters.SubmoduleJobs > 0 {
+				if assert.Equal(t, 1, git.ConfigCallCount()) {
+					key, value := git.ConfigArgsForCall(0)
+					assert.Equal(t, "submodule.fetchJobs", key)
+					assert.Equal(t, strconv.Itoa(tc.parameters.SubmoduleJobs), value)
+				}
+			}
+
 			if assert.Equal(t, 1, git.PullCallCount()) {
-				url, base, depth, submodules, fetchTags := git.PullArgsForCall(0)
+				url, base, depth, submodules, fetchTags, filter := git.PullArgsForCall(0)
 				assert.Equal(t, tc.pullRequest.Repository.URL, url)
 				assert.Equal(t, tc.pullRequest.BaseRefName, base)
 				assert.Equal(t, tc.parameters.GitDepth, depth)
 				assert.Equal(t, tc.parameters.Submodules, submodules)
 				assert.Equal(t, tc.parameters.FetchTags, fetchTags)
+				assert.Equal(t, tc.parameters.Filter, filter)
 			}
 
 			if assert.Equal(t, 1, git.RevParseCallCount()) {
@@ -238,11 +496,21 @@ func TestGet(t *testing.T) {
 			}
 
 			if assert.Equal(t, 1, git.FetchCallCount()) {
-				url, pr, depth, submodules := git.FetchArgsForCall(0)
+				url, pr, depth, submodules, filter := git.FetchArgsForCall(0)
 				assert.Equal(t, tc.pullRequest.Repository.URL, url)
 				assert.Equal(t, tc.pullRequest.Number, pr)
 				assert.Equal(t, tc.parameters.GitDepth, depth)
 				assert.Equal(t, tc.parameters.Submodules, submodules)
+				assert.Equal(t, tc.parameters.Filter, filter)
+			}
+
+			if tc.parameters.Patch {
+				if assert.Equal(t, 1, git.DiffCallCount()) {
+					base, head, path := git.DiffArgsForCall(0)
+					assert.Equal(t, "sha", base)
+					assert.Equal(t, tc.pullRequest.Tip.OID, head)
+					assert.Equal(t, filepath.Join(dir, ".git", "resource", "pr.patch"), path)
+				}
 			}
 
 			switch tc.parameters.IntegrationTool {
@@ -318,6 +586,564 @@ func TestGetSkipDownload(t *testing.T) {
 	}
 }
 
+func TestGetDeepensShallowClone(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	pullRequest := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	github.GetPullRequestReturns(pullRequest, nil)
+
+	git := new(fakes.FakeGit)
+	git.RevParseReturns("sha", nil)
+	git.MergeBaseReturnsOnCall(0, "", errors.New("no merge base"))
+	git.MergeBaseReturnsOnCall(1, "", errors.New("no merge base"))
+	git.MergeBaseReturnsOnCall(2, "base", nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	input := resource.GetRequest{
+		Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+		Version: resource.Version{PR: "pr1", Commit: "commit1"},
+		Params:  resource.GetParameters{GitDepth: 2},
+	}
+	_, err := resource.Get(input, github, git, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 2, git.DeepenCallCount()) {
+		url, base, pr, depth := git.DeepenArgsForCall(0)
+		assert.Equal(t, pullRequest.Repository.URL, url)
+		assert.Equal(t, pullRequest.BaseRefName, base)
+		assert.Equal(t, pullRequest.Number, pr)
+		assert.Equal(t, 2, depth)
+
+		_, _, _, depth = git.DeepenArgsForCall(1)
+		assert.Equal(t, 4, depth)
+	}
+	assert.Equal(t, 1, git.MergeCallCount())
+}
+
+func TestGetBare(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	pullRequest := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	github.GetPullRequestReturns(pullRequest, nil)
+
+	git := new(fakes.FakeGit)
+	git.RevParseReturns("sha", nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	input := resource.GetRequest{
+		Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+		Version: resource.Version{PR: "pr1", Commit: "commit1"},
+		Params:  resource.GetParameters{Bare: true, GitDepth: 1},
+	}
+	_, err := resource.Get(input, github, git, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, git.MirrorCallCount()) {
+		url, base, pr, depth := git.MirrorArgsForCall(0)
+		assert.Equal(t, pullRequest.Repository.URL, url)
+		assert.Equal(t, pullRequest.BaseRefName, base)
+		assert.Equal(t, pullRequest.Number, pr)
+		assert.Equal(t, 1, depth)
+	}
+	assert.Equal(t, 0, git.InitCallCount())
+	assert.Equal(t, 0, git.PullCallCount())
+	assert.Equal(t, 0, git.FetchCallCount())
+	assert.Equal(t, 0, git.MergeCallCount())
+	assert.Equal(t, "sha", readTestFile(t, filepath.Join(dir, ".git", "resource", "base_sha")))
+}
+
+func TestGetHeadBranch(t *testing.T) {
+	tests := []struct {
+		description     string
+		integrationTool string
+		headName        string
+		expected        []string
+	}{
+		{
+			description: "merged pull requests are checked out as the head branch",
+			expected:    []string{"pr1"},
+		},
+		{
+			description:     "rebased pull requests are checked out as the head branch",
+			integrationTool: "rebase",
+			expected:        []string{"pr1"},
+		},
+		{
+			description:     "checkout creates the head branch itself",
+			integrationTool: "checkout",
+		},
+		{
+			description: "the base branch is left as is if the head has the same name",
+			headName:    "master",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			pullRequest := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+			if tc.headName != "" {
+				pullRequest.HeadRefName = tc.headName
+			}
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(pullRequest, nil)
+
+			git := new(fakes.FakeGit)
+			git.RevParseReturns("sha", nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			input := resource.GetRequest{
+				Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+				Version: resource.Version{PR: "pr1", Commit: "commit1"},
+				Params:  resource.GetParameters{IntegrationTool: tc.integrationTool},
+			}
+			_, err := resource.Get(input, github, git, dir)
+			require.NoError(t, err)
+
+			var branches []string
+			for i := 0; i < git.BranchCallCount(); i++ {
+				branches = append(branches, git.BranchArgsForCall(i))
+			}
+			assert.Equal(t, tc.expected, branches)
+			assert.Equal(t, pullRequest.HeadRefName, readTestFile(t, filepath.Join(dir, ".git", "resource", "head_name")))
+		})
+	}
+}
+
+func TestGetFetchBase(t *testing.T) {
+	tests := []struct {
+		description string
+		fetchBase   bool
+		depth       int
+		integration string
+	}{
+		{
+			description: "the base is not tracked by default",
+		},
+		{
+			description: "the base is tracked at the commit in the metadata",
+			fetchBase:   true,
+		},
+		{
+			description: "shallow checkouts are deepened until the merge base is part of the history",
+			fetchBase:   true,
+			depth:       1,
+			integration: "checkout",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			pullRequest := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(pullRequest, nil)
+
+			git := new(fakes.FakeGit)
+			git.RevParseReturns("sha", nil)
+			git.MergeBaseReturns("base", nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			input := resource.GetRequest{
+				Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+				Version: resource.Version{PR: "pr1", Commit: "commit1"},
+				Params:  resource.GetParameters{FetchBase: tc.fetchBase, GitDepth: tc.depth, IntegrationTool: tc.integration},
+			}
+			_, err := resource.Get(input, github, git, dir)
+			require.NoError(t, err)
+
+			if !tc.fetchBase {
+				assert.Equal(t, 0, git.TrackBaseCallCount())
+				return
+			}
+			require.Equal(t, 1, git.TrackBaseCallCount())
+			branch, sha := git.TrackBaseArgsForCall(0)
+			assert.Equal(t, "master", branch)
+			assert.Equal(t, "sha", sha)
+			if tc.depth > 0 {
+				assert.Equal(t, 1, git.MergeBaseCallCount())
+			}
+		})
+	}
+}
+
+func TestGetVerify(t *testing.T) {
+	tests := []struct {
+		description     string
+		integrationTool string
+		head            string
+		mergeBase       string
+		forcePushed     bool
+		expected        resource.Verification
+	}{
+		{
+			description: "merged commits are verified",
+			head:        "merge-sha",
+			mergeBase:   "oid1",
+			expected:    resource.Verification{Verified: true, Commit: "oid1", Head: "merge-sha"},
+		},
+		{
+			description:     "checked out commits are verified",
+			integrationTool: "checkout",
+			head:            "oid1",
+			expected:        resource.Verification{Verified: true, Commit: "oid1", Head: "oid1"},
+		},
+		{
+			description:     "checking out another commit is not verified",
+			integrationTool: "checkout",
+			head:            "other",
+			expected: resource.Verification{Commit: "oid1", Head: "other", Problems: []string{
+				"HEAD is other instead of commit oid1",
+			}},
+		},
+		{
+			description: "merges which do not contain the commit are not verified",
+			head:        "merge-sha",
+			mergeBase:   "other",
+			expected: resource.Verification{Commit: "oid1", Head: "merge-sha", Problems: []string{
+				"commit oid1 is not part of HEAD",
+			}},
+		},
+		{
+			description: "commits which are force pushed away during get are not verified",
+			head:        "merge-sha",
+			mergeBase:   "oid1",
+			forcePushed: true,
+			expected: resource.Verification{Commit: "oid1", Head: "merge-sha", Problems: []string{
+				"commit oid1 is no longer part of the pull request (the branch was force pushed)",
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			pullRequest := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturnsOnCall(0, pullRequest, nil)
+			if tc.forcePushed {
+				github.GetPullRequestReturnsOnCall(1, nil, &resource.CommitNotFoundError{Ref: "oid1"})
+			} else {
+				github.GetPullRequestReturnsOnCall(1, pullRequest, nil)
+			}
+
+			git := new(fakes.FakeGit)
+			git.RevParseStub = func(rev string) (string, error) {
+				switch rev {
+				case "HEAD":
+					return tc.head, nil
+				case "oid1^{commit}":
+					return "oid1", nil
+				}
+				return "sha", nil
+			}
+			git.MergeBaseReturns(tc.mergeBase, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			input := resource.GetRequest{
+				Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+				Version: resource.Version{PR: "pr1", Commit: "oid1"},
+				Params:  resource.GetParameters{IntegrationTool: tc.integrationTool, Verify: true},
+			}
+			_, err := resource.Get(input, github, git, dir)
+			require.NoError(t, err)
+
+			var verification resource.Verification
+			require.NoError(t, json.Unmarshal([]byte(readTestFile(t, filepath.Join(dir, ".git", "resource", "verified"))), &verification))
+			assert.Equal(t, tc.expected, verification)
+		})
+	}
+}
+
+func TestGetBehindBy(t *testing.T) {
+	tests := []struct {
+		description string
+		comparison  *resource.CommitComparison
+		compareErr  error
+		expected    map[string]string
+	}{
+		{
+			description: "get adds how far the pull request is behind and ahead of the base",
+			comparison:  &resource.CommitComparison{AheadBy: 2, BehindBy: 40},
+			expected:    map[string]string{"behind_by": "40", "ahead_by": "2"},
+		},
+		{
+			description: "get does not fail if the commits cannot be compared",
+			compareErr:  errors.New("no common ancestor"),
+			expected:    map[string]string{"behind_by": "", "ahead_by": ""},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.CompareCommitsReturns(tc.comparison, tc.compareErr)
+
+			git := new(fakes.FakeGit)
+			git.RevParseReturns("sha", nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			input := resource.GetRequest{
+				Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+				Version: resource.Version{PR: "pr1", Commit: "commit1"},
+			}
+			output, err := resource.Get(input, github, git, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.CompareCommitsCallCount()) {
+				base, head := github.CompareCommitsArgsForCall(0)
+				assert.Equal(t, "master", base)
+				assert.Equal(t, "oid1", head)
+			}
+			for name, value := range tc.expected {
+				assert.Equal(t, value, output.Metadata.Get(name))
+			}
+		})
+	}
+}
+
+func TestGetSourceOverrides(t *testing.T) {
+	disabled, empty := true, ""
+	request := resource.GetRequest{
+		Source: resource.Source{
+			Repository:  "itsdalmo/test-repository",
+			AccessToken: "oauthtoken",
+			GitCryptKey: "gitcryptkey",
+		},
+		Params: resource.GetParameters{
+			DisableGitLFS: &disabled,
+			GitCryptKey:   &empty,
+		},
+	}
+
+	source := request.EffectiveSource()
+	assert.True(t, source.DisableGitLFS)
+	assert.Equal(t, "", source.GitCryptKey)
+	assert.Equal(t, "gitcryptkey", request.Source.GitCryptKey)
+
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+	git := new(fakes.FakeGit)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	request.Version = resource.Version{PR: "pr1", Commit: "commit1"}
+	_, err := resource.Get(request, github, git, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, git.GitCryptUnlockCallCount())
+}
+
+func TestGetRetries(t *testing.T) {
+	tests := []struct {
+		description string
+		retries     int
+		failures    int
+		wantErr     bool
+	}{
+		{
+			description: "get retries failed fetches",
+			retries:     2,
+			failures:    2,
+		},
+		{
+			description: "get fails when retries are exhausted",
+			retries:     1,
+			failures:    2,
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			git := new(fakes.FakeGit)
+			git.RevParseReturns("sha", nil)
+			for i := 0; i < tc.failures; i++ {
+				git.FetchReturnsOnCall(i, errors.New("fetch failed"))
+			}
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			input := resource.GetRequest{
+				Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+				Version: resource.Version{PR: "pr1", Commit: "commit1"},
+				Params:  resource.GetParameters{GetRetries: tc.retries, RetryDelay: "1ms"},
+			}
+			_, err := resource.Get(input, github, git, dir)
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.retries+1, git.FetchCallCount())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.failures+1, git.FetchCallCount())
+			}
+		})
+	}
+}
+
+func TestGetMissingCommit(t *testing.T) {
+	tests := []struct {
+		description string
+		fetchErr    error
+		wantErr     string
+	}{
+		{
+			description: "get fetches commits which are no longer part of the pull request",
+		},
+		{
+			description: "get fails with a clear error if the commit cannot be fetched",
+			fetchErr:    errors.New("not our ref"),
+			wantErr:     "commit commit1 is no longer part of pull request #1 and could not be fetched: not our ref",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestStub = func(pr, commit string) (*resource.PullRequest, error) {
+				if commit != "head" {
+					return nil, &resource.CommitNotFoundError{Ref: commit}
+				}
+				return createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil
+			}
+			github.GetPullRequestDetailsReturns(&resource.PullRequestDetailsObject{HeadRefOid: "head"}, nil)
+
+			git := new(fakes.FakeGit)
+			git.RevParseReturns("sha", nil)
+			git.FetchCommitReturns(tc.fetchErr)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			input := resource.GetRequest{
+				Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+				Version: resource.Version{PR: "1", Commit: "commit1"},
+			}
+			_, err := resource.Get(input, github, git, dir)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, git.FetchCommitCallCount()) {
+				url, sha, depth := git.FetchCommitArgsForCall(0)
+				assert.Equal(t, "repo1 url", url)
+				assert.Equal(t, "commit1", sha)
+				assert.Equal(t, 0, depth)
+			}
+			if assert.Equal(t, 1, git.MergeCallCount()) {
+				sha, _ := git.MergeArgsForCall(0)
+				assert.Equal(t, "commit1", sha)
+			}
+			assert.Equal(t, "commit1", readTestFile(t, filepath.Join(dir, ".git", "resource", "head_sha")))
+		})
+	}
+}
+
+func TestGetUpstreamCompatible(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+	git := new(fakes.FakeGit)
+	git.RevParseReturns("sha", nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", UpstreamCompatible: true}
+	require.NoError(t, source.Validate())
+	version := resource.Version{
+		PR:                  "1",
+		Commit:              "oid1",
+		CommittedDate:       time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+		ApprovedReviewCount: "0",
+		State:               githubv4.PullRequestStateOpen,
+	}
+	output, err := resource.Get(resource.GetRequest{Source: source, Version: version}, github, git, dir)
+	require.NoError(t, err)
+
+	expected := []string{"pr", "title", "url", "head_name", "head_sha", "base_name", "base_sha", "message", "author", "author_email", "state"}
+	var names []string
+	for _, m := range output.Metadata {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, expected, names)
+
+	infos, err := ioutil.ReadDir(filepath.Join(dir, ".git", "resource"))
+	require.NoError(t, err)
+	var files []string
+	for _, info := range infos {
+		files = append(files, info.Name())
+	}
+	assert.ElementsMatch(t, append(expected, "version.json", "metadata.json"), files)
+	assert.Equal(t, `{"pr":"1","commit":"oid1","committed":"2020-01-01T12:00:00Z","approved_review_count":"0","state":"OPEN"}`,
+		readTestFile(t, filepath.Join(dir, ".git", "resource", "version.json")))
+
+	source.IncludeUpdatedAt = true
+	assert.EqualError(t, source.Validate(), "include_updated_at cannot be set when upstream_compatible is set (the version would not be compatible)")
+}
+
+func TestMetadataEnv(t *testing.T) {
+	var metadata resource.Metadata
+	metadata.Add("pr", "1")
+	metadata.Add("message", "it's\nmultiline")
+
+	assert.Equal(t, "PR_NUMBER='1'\nMESSAGE='it'\\''s\nmultiline'\n", string(metadata.Env()))
+}
+
+func TestParseTrailers(t *testing.T) {
+	messages := []string{
+		"Add feature\n\nSigned-off-by: Jane <jane@example.com>\nTicket: ABC-1",
+		"Fix typo\n\nThis mentions Ticket: ABC-9 in the body.\n\nsigned-off-by: John <john@example.com>",
+		"Rework\n\nSigned-off-by: Jane <jane@example.com>",
+	}
+
+	assert.Equal(t, []string{"Jane <jane@example.com>", "John <john@example.com>"}, resource.ParseTrailers(messages, "Signed-off-by"))
+	assert.Equal(t, []string{"ABC-1"}, resource.ParseTrailers(messages, "Ticket"))
+	assert.Equal(t, []string{}, resource.ParseTrailers(messages, "Change-Id"))
+}
+
+func TestGetCommitTrailers(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	pullRequest := createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	github.GetPullRequestReturns(pullRequest, nil)
+
+	git := new(fakes.FakeGit)
+	git.RevParseReturns("sha", nil)
+	git.CommitMessagesReturns([]string{"Add feature\n\nSigned-off-by: Jane <jane@example.com>\nChange-Id: I123"}, nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	input := resource.GetRequest{
+		Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+		Version: resource.Version{PR: "pr1", Commit: "commit1"},
+		Params:  resource.GetParameters{CommitTrailers: []string{"Signed-off-by", "Change-Id"}},
+	}
+	output, err := resource.Get(input, github, git, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, git.CommitMessagesCallCount()) {
+		base, head := git.CommitMessagesArgsForCall(0)
+		assert.Equal(t, "sha", base)
+		assert.Equal(t, pullRequest.Tip.OID, head)
+	}
+	assert.Contains(t, output.Metadata, &resource.MetadataField{Name: "trailer_signed_off_by", Value: "Jane <jane@example.com>"})
+	assert.Equal(t, "I123", readTestFile(t, filepath.Join(dir, ".git", "resource", "trailer_change_id")))
+}
+
 func createTestPR(
 	count int,
 	baseName string,
@@ -369,11 +1195,13 @@ func createTestPR(
 			Message:       m,
 			Author: struct {
 				User  struct{ Login string }
+				Name  string
 				Email string
 			}{
 				User: struct{ Login string }{
 					Login: fmt.Sprintf("login%s", n),
 				},
+				Name:  fmt.Sprintf("name%s", n),
 				Email: "user@example.com",
 			},
 		},
@@ -382,6 +1210,16 @@ func createTestPR(
 	}
 }
 
+func createTestReviewer(typename, name string) resource.RequestedReviewerObject {
+	r := resource.RequestedReviewerObject{Typename: typename}
+	if typename == "Team" {
+		r.Team.Slug = name
+	} else {
+		r.User.Login = name
+	}
+	return r
+}
+
 func createTestDirectory(t *testing.T) string {
 	dir, err := ioutil.TempDir("", "github-pr-resource")
 	if err != nil {
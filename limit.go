@@ -0,0 +1,50 @@
+package resource
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// concurrencyTransport limits the number of requests to the Github API which are in flight at the same
+// time (across all code paths using the client), to avoid secondary rate limits. A request is in flight
+// until its response body has been closed.
+type concurrencyTransport struct {
+	base http.RoundTripper
+	sem  chan struct{}
+}
+
+func newConcurrencyTransport(base http.RoundTripper, limit int) *concurrencyTransport {
+	return &concurrencyTransport{base: base, sem: make(chan struct{}, limit)}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := func() { <-t.sem }
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseBody releases the slot of a request once the response body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,84 @@
+package resource_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientConcurrency(t *testing.T) {
+	tests := []struct {
+		description string
+		concurrency int
+		expectedMax int
+	}{
+		{
+			description: "requests are limited to the configured concurrency",
+			concurrency: 2,
+			expectedMax: 2,
+		},
+		{
+			description: "requests are not limited by default",
+			concurrency: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				mu                sync.Mutex
+				inFlight, maxSeen int
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				inFlight++
+				if inFlight > maxSeen {
+					maxSeen = inFlight
+				}
+				mu.Unlock()
+
+				time.Sleep(50 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			source := resource.Source{
+				Repository:     "itsdalmo/test-repository",
+				AccessToken:    "oauthtoken",
+				V3Endpoint:     server.URL + "/",
+				V4Endpoint:     server.URL + "/graphql",
+				APIConcurrency: tc.concurrency,
+			}
+			require.NoError(t, source.Validate())
+			client, err := resource.NewGithubClient(&source)
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					_, err := client.ListModifiedFiles(i)
+					assert.NoError(t, err)
+				}(i)
+			}
+			wg.Wait()
+
+			if tc.concurrency > 0 {
+				assert.Equal(t, tc.expectedMax, maxSeen)
+			} else {
+				assert.True(t, maxSeen > 2, "requests should be concurrent")
+			}
+		})
+	}
+}
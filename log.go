@@ -0,0 +1,183 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel of a log line.
+type LogLevel int
+
+// Log levels, from the most to the least verbose.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+var logLevelNames = []string{"debug", "info", "warn", "error"}
+
+func (l LogLevel) String() string {
+	if l < LogLevelDebug || l > LogLevelError {
+		return strconv.Itoa(int(l))
+	}
+	return logLevelNames[l]
+}
+
+// ParseLogLevel parses the name of a log level (e.g. debug).
+func ParseLogLevel(s string) (LogLevel, error) {
+	for i, name := range logLevelNames {
+		if strings.EqualFold(s, name) {
+			return LogLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level \"%s\" (must be one of: %s)", s, strings.Join(logLevelNames, ", "))
+}
+
+// logLevelEnv is the environment variable which sets the log level, unless log_level is set in the source.
+const logLevelEnv = "GPR_LOG_LEVEL"
+
+// Logger writes structured log lines (in logfmt) at or above a level.
+type Logger struct {
+	mu    sync.Mutex
+	out   io.Writer
+	level LogLevel
+}
+
+// NewLogger returns a logger which writes to w.
+func NewLogger(w io.Writer, level LogLevel) *Logger {
+	return &Logger{out: w, level: level}
+}
+
+// NewSourceLogger returns a logger which writes to w, with the level from the log_level of the source
+// or the GPR_LOG_LEVEL environment variable (defaulting to info).
+func NewSourceLogger(s *Source, w io.Writer) *Logger {
+	level, err := ParseLogLevel(s.LogLevel)
+	if s.LogLevel == "" {
+		level, err = ParseLogLevel(os.Getenv(logLevelEnv))
+	}
+	if err != nil {
+		level = LogLevelInfo
+	}
+	return NewLogger(w, level)
+}
+
+// logger is used by the resource, and is set by the commands.
+var logger = NewLogger(os.Stderr, LogLevelInfo)
+
+// SetLogger sets the logger used by the resource.
+func SetLogger(l *Logger) {
+	logger = l
+}
+
+// Enabled returns true if lines at the given level are written.
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level >= l.level
+}
+
+// Debug writes a log line with the message and key-value pairs.
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LogLevelDebug, msg, keyvals)
+}
+
+// Info writes a log line with the message and key-value pairs.
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log(LogLevelInfo, msg, keyvals)
+}
+
+// Warn writes a log line with the message and key-value pairs.
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LogLevelWarn, msg, keyvals)
+}
+
+// Error writes a log line with the message and key-value pairs.
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log(LogLevelError, msg, keyvals)
+}
+
+func (l *Logger) log(level LogLevel, msg string, keyvals []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "time=%s level=%s msg=%s", time.Now().UTC().Format(time.RFC3339), level, logfmtValue(msg))
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(missing)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		fmt.Fprintf(&b, " %v=%s", keyvals[i], logfmtValue(fmt.Sprint(value)))
+	}
+	b.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.out, Redact(b.String()))
+}
+
+// logfmtValue quotes a value if it is empty or contains spaces, quotes or equal signs.
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// debugTransport logs requests to the Github API (without credentials) when debug logging is enabled.
+type debugTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !logger.Enabled(LogLevelDebug) {
+		return t.base.RoundTrip(req)
+	}
+
+	keyvals := []interface{}{"method", req.Method, "url", sanitizeURL(req.URL)}
+	if rateLimitResource(req) == "graphql" && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			var graphql struct {
+				Query     string          `json:"query"`
+				Variables json.RawMessage `json:"variables"`
+			}
+			if err := json.NewDecoder(body).Decode(&graphql); err == nil {
+				keyvals = append(keyvals, "query", strings.Join(strings.Fields(graphql.Query), " "), "variables", string(graphql.Variables))
+			}
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	keyvals = append(keyvals, "duration", time.Since(start).Round(time.Millisecond))
+	if err != nil {
+		logger.Debug("github api request failed", append(keyvals, "error", err)...)
+		return nil, err
+	}
+	logger.Debug("github api request", append(keyvals, "status", resp.StatusCode)...)
+	return resp, nil
+}
+
+// sanitizeURL returns the URL without credentials (user info and token query parameters).
+func sanitizeURL(u *url.URL) string {
+	sanitized := *u
+	sanitized.User = nil
+	query := sanitized.Query()
+	for key := range query {
+		if k := strings.ToLower(key); strings.Contains(k, "token") || strings.Contains(k, "secret") {
+			query.Set(key, "REDACTED")
+		}
+	}
+	sanitized.RawQuery = query.Encode()
+	return sanitized.String()
+}
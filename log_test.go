@@ -0,0 +1,140 @@
+package resource_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+	"github.com/telia-oss/github-pr-resource/fakes"
+)
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		description string
+		level       resource.LogLevel
+		log         func(l *resource.Logger)
+		expected    string
+	}{
+		{
+			description: "lines below the level are not written",
+			level:       resource.LogLevelInfo,
+			log: func(l *resource.Logger) {
+				l.Debug("hidden")
+				l.Warn("shown", "pr", 1)
+			},
+			expected: `level=warn msg=shown pr=1`,
+		},
+		{
+			description: "values are quoted when needed",
+			level:       resource.LogLevelDebug,
+			log: func(l *resource.Logger) {
+				l.Debug("skipping pull request", "reason", "a=b", "empty", "")
+			},
+			expected: `level=debug msg="skipping pull request" reason="a=b" empty=""`,
+		},
+		{
+			description: "missing values are marked",
+			level:       resource.LogLevelDebug,
+			log: func(l *resource.Logger) {
+				l.Error("failed", "key")
+			},
+			expected: `level=error msg=failed key=(missing)`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			tc.log(resource.NewLogger(&b, tc.level))
+
+			lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+			require.Len(t, lines, 1)
+			assert.Regexp(t, `^time=\S+ `, lines[0])
+			assert.True(t, strings.HasSuffix(lines[0], tc.expected), lines[0])
+		})
+	}
+}
+
+func TestNewSourceLogger(t *testing.T) {
+	defer os.Unsetenv("GPR_LOG_LEVEL")
+
+	tests := []struct {
+		description string
+		logLevel    string
+		env         string
+		expected    bool
+	}{
+		{
+			description: "info by default",
+			expected:    false,
+		},
+		{
+			description: "from the environment",
+			env:         "debug",
+			expected:    true,
+		},
+		{
+			description: "the source takes precedence",
+			logLevel:    "info",
+			env:         "debug",
+			expected:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			os.Setenv("GPR_LOG_LEVEL", tc.env)
+			l := resource.NewSourceLogger(&resource.Source{LogLevel: tc.logLevel}, &bytes.Buffer{})
+			assert.Equal(t, tc.expected, l.Enabled(resource.LogLevelDebug))
+		})
+	}
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", LogLevel: "verbose"}
+	assert.EqualError(t, source.Validate(), `invalid log_level: unknown log level "verbose" (must be one of: debug, info, warn, error)`)
+}
+
+func TestDebugLogging(t *testing.T) {
+	var b bytes.Buffer
+	resource.SetLogger(resource.NewLogger(&b, resource.LogLevelDebug))
+	defer resource.SetLogger(resource.NewLogger(os.Stderr, resource.LogLevelInfo))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"repository":{"pullRequests":{"edges":[],"pageInfo":{"hasNextPage":false}}}}}`))
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+	_, err = client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{}, time.Time{})
+	require.NoError(t, err)
+
+	// GraphQL queries are logged, without credentials
+	assert.Contains(t, b.String(), `msg="github api request" method=POST url=`+server.URL+`/graphql query="query(`)
+	assert.Contains(t, b.String(), `\"repositoryOwner\":\"itsdalmo\"`)
+	assert.Contains(t, b.String(), `status=200`)
+	assert.NotContains(t, b.String(), "oauthtoken")
+
+	// Check explains why pull requests are skipped
+	b.Reset()
+	draft := createTestPR(1, "master", false, false, 0, nil, true, githubv4.PullRequestStateOpen)
+	github := new(fakes.FakeGithub)
+	github.ListPullRequestsReturns([]*resource.PullRequest{draft}, nil)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", IgnoreDrafts: true}
+	_, err = resource.Check(resource.CheckRequest{Source: source}, github)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `msg="skipping pull request" pr=1 commit=oid1 reason=draft`)
+}
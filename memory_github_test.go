@@ -0,0 +1,86 @@
+package resource_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+	"github.com/telia-oss/github-pr-resource/fakes"
+)
+
+func TestMemoryGithub(t *testing.T) {
+	commit := func(oid string, date time.Time) resource.CommitObject {
+		return resource.CommitObject{ID: oid, OID: oid, CommittedDate: githubv4.DateTime{Time: date}, Message: "commit " + oid}
+	}
+	now := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	github := fakes.NewMemoryGithub("itsdalmo", "test-repository")
+	github.AddPullRequest(1, "master", "feature-1", commit("oid1", now))
+	github.AddPullRequest(2, "master", "feature-2", commit("oid2", now.Add(time.Minute)))
+	github.PullRequests[2].IsDraft = true
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", IgnoreDrafts: true}
+	versions, err := resource.Check(resource.CheckRequest{Source: source}, github)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, "1", versions[0].PR)
+	assert.Equal(t, "oid1", versions[0].Commit)
+
+	// New commits (and pull requests which are ready for review) produce new versions
+	require.NoError(t, github.Push(1, commit("oid3", now.Add(2*time.Minute))))
+	require.NoError(t, github.MarkReadyForReview("2"))
+	versions, err = resource.Check(resource.CheckRequest{Source: source, Version: versions[0]}, github)
+	require.NoError(t, err)
+	var commits []string
+	for _, v := range versions {
+		commits = append(commits, v.Commit)
+	}
+	assert.Equal(t, []string{"oid2", "oid3"}, commits)
+
+	// Put changes the state of the pull request
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+	git := new(fakes.FakeGit)
+	git.RevParseReturns("oid3", nil)
+	_, err = resource.Get(resource.GetRequest{Source: source, Version: versions[1]}, github, git, dir)
+	require.NoError(t, err)
+
+	_, err = resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+		Status:    "success",
+		Comment:   "all good",
+		AddLabels: []string{"ci-passed"},
+	}}, github, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []fakes.MemoryStatus{{
+		Context:     "concourse-ci/status",
+		State:       "success",
+		TargetURL:   github.Statuses["oid3"][0].TargetURL,
+		Description: "Concourse CI build success",
+	}}, github.Statuses["oid3"])
+	latest, err := github.GetLatestComment("1")
+	require.NoError(t, err)
+	assert.Equal(t, "all good", latest)
+	assert.Equal(t, []string{"ci-passed"}, github.PullRequests[1].Labels)
+
+	// Comments made by the viewer can be cleaned up
+	_, err = github.PostComment("1", "flaky")
+	require.NoError(t, err)
+	github.PullRequests[1].Comments = append(github.PullRequests[1].Comments, fakes.MemoryComment{ID: 100, Author: "someone", Body: "lgtm"})
+	require.NoError(t, github.DeletePreviousComments("1", nil))
+	require.Len(t, github.PullRequests[1].Comments, 1)
+	assert.Equal(t, "lgtm", github.PullRequests[1].Comments[0].Body)
+
+	// Merged pull requests are no longer listed
+	sha, err := github.MergePullRequest("1", "oid3", "squash", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, sha, github.Branches["master"])
+	pulls, err := github.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, pulls, 1)
+	assert.Equal(t, 2, pulls[0].Number)
+}
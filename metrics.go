@@ -0,0 +1,245 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metricsPrefix is the prefix of the names of all metrics.
+const metricsPrefix = "github_pr_resource"
+
+// MetricsConfig configures where metrics about the runs of the resource are sent.
+type MetricsConfig struct {
+	Statsd      string            `json:"statsd"`
+	Pushgateway string            `json:"pushgateway"`
+	Tags        map[string]string `json:"tags"`
+}
+
+// Validate the metrics configuration.
+func (c *MetricsConfig) Validate() error {
+	if c.Statsd == "" && c.Pushgateway == "" {
+		return errors.New("metrics must set statsd or pushgateway")
+	}
+	if c.Statsd != "" {
+		if _, _, err := net.SplitHostPort(c.Statsd); err != nil {
+			return errors.New("metrics statsd must be an address (e.g. localhost:8125)")
+		}
+	}
+	if c.Pushgateway != "" && !isAbsoluteURL(c.Pushgateway) {
+		return errors.New("metrics pushgateway must be an absolute URL (e.g. http://pushgateway:9091)")
+	}
+	return nil
+}
+
+// Metrics are recorded during a run of the resource, and sent when flushed. A nil *Metrics
+// (metrics are not configured) discards everything.
+type Metrics struct {
+	config  *MetricsConfig
+	tags    map[string]string
+	metrics []metric
+	client  *http.Client
+}
+
+type metric struct {
+	name  string
+	value float64
+	tags  map[string]string
+}
+
+// NewMetrics returns the metrics for a step (check, get or put), or nil if metrics are not configured.
+// Metrics are tagged with the repository, the step and the pipeline (when it is known), as well as the
+// tags in the configuration.
+func NewMetrics(s *Source, step string) *Metrics {
+	if s.Metrics == nil {
+		return nil
+	}
+	tags := map[string]string{"repository": s.Repository, "step": step}
+	if pipeline := os.Getenv("BUILD_PIPELINE_NAME"); pipeline != "" {
+		tags["pipeline"] = pipeline
+	}
+	for k, v := range s.Metrics.Tags {
+		tags[k] = v
+	}
+	return &Metrics{
+		config: s.Metrics,
+		tags:   tags,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Gauge records the value of a metric. Tags are given as key-value pairs.
+func (m *Metrics) Gauge(name string, value float64, tags ...string) {
+	if m == nil {
+		return
+	}
+	t := make(map[string]string)
+	for i := 0; i+1 < len(tags); i += 2 {
+		t[tags[i]] = tags[i+1]
+	}
+	m.metrics = append(m.metrics, metric{name: name, value: value, tags: t})
+}
+
+// Duration records the duration of the step (in seconds) and whether it succeeded.
+func (m *Metrics) Duration(d time.Duration, err error) {
+	success := 1.0
+	if err != nil {
+		success = 0
+	}
+	m.Gauge("duration_seconds", d.Seconds())
+	m.Gauge("success", success)
+}
+
+// AddAPIUsage records the calls to the Github API and the remaining rate limits (when they are known).
+func (m *Metrics) AddAPIUsage(u APIUsage) {
+	for _, api := range []struct {
+		name      string
+		calls     int
+		remaining int
+	}{{"rest", u.RESTCalls, u.RESTRemaining}, {"graphql", u.GraphQLCalls, u.GraphQLRemaining}} {
+		m.Gauge("api_calls", float64(api.calls), "api", api.name)
+		if api.remaining >= 0 {
+			m.Gauge("rate_limit_remaining", float64(api.remaining), "api", api.name)
+		}
+	}
+}
+
+// Flush sends the recorded metrics.
+func (m *Metrics) Flush() error {
+	if m == nil || len(m.metrics) == 0 {
+		return nil
+	}
+	if m.config.Statsd != "" {
+		if err := m.sendStatsd(); err != nil {
+			return fmt.Errorf("failed to send metrics to statsd: %s", err)
+		}
+	}
+	if m.config.Pushgateway != "" {
+		if err := m.pushGateway(); err != nil {
+			return fmt.Errorf("failed to push metrics to pushgateway: %s", err)
+		}
+	}
+	m.metrics = nil
+	return nil
+}
+
+// sendStatsd sends the metrics as gauges over UDP, with tags in the DogStatsD format (which is
+// also supported by e.g. Telegraf).
+func (m *Metrics) sendStatsd() error {
+	conn, err := net.Dial("udp", m.config.Statsd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var b bytes.Buffer
+	for _, metric := range m.metrics {
+		var tags []string
+		for _, k := range sortedKeys(m.tags, metric.tags) {
+			tags = append(tags, statsdTag(k)+":"+statsdTag(mergedTag(m.tags, metric.tags, k)))
+		}
+		fmt.Fprintf(&b, "%s.%s:%s|g|#%s\n", metricsPrefix, metric.name, formatMetricValue(metric.value), strings.Join(tags, ","))
+	}
+	_, err = conn.Write(b.Bytes())
+	return err
+}
+
+// pushGateway replaces the metrics in the Prometheus Pushgateway group for the tags of the step.
+func (m *Metrics) pushGateway() error {
+	u := strings.TrimSuffix(m.config.Pushgateway, "/") + "/metrics/job/" + metricsPrefix
+	for _, k := range sortedKeys(m.tags, nil) {
+		// Values are base64 encoded, since they can contain slashes (e.g. the repository)
+		u += "/" + prometheusLabel(k) + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(m.tags[k]))
+	}
+
+	// Metrics with the same name must be written together (after their type)
+	var names []string
+	byName := make(map[string][]metric)
+	for _, metric := range m.metrics {
+		if _, ok := byName[metric.name]; !ok {
+			names = append(names, metric.name)
+		}
+		byName[metric.name] = append(byName[metric.name], metric)
+	}
+
+	var b bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&b, "# TYPE %s_%s gauge\n", metricsPrefix, name)
+		for _, metric := range byName[name] {
+			var labels []string
+			for _, k := range sortedKeys(metric.tags, nil) {
+				labels = append(labels, prometheusLabel(k)+"="+strconv.Quote(metric.tags[k]))
+			}
+			series := metricsPrefix + "_" + name
+			if len(labels) > 0 {
+				series += "{" + strings.Join(labels, ",") + "}"
+			}
+			fmt.Fprintf(&b, "%s %s\n", series, formatMetricValue(metric.value))
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPut, u, &b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of both maps, sorted.
+func sortedKeys(a, b map[string]string) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergedTag returns the value of a tag, where tags of the metric take precedence.
+func mergedTag(tags, metricTags map[string]string, k string) string {
+	if v, ok := metricTags[k]; ok {
+		return v
+	}
+	return tags[k]
+}
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// statsdTag replaces the characters which are reserved in statsd tags.
+func statsdTag(s string) string {
+	return strings.NewReplacer(",", "_", "|", "_", "#", "_", ":", "_", "\n", "_").Replace(s)
+}
+
+// prometheusLabel replaces the characters which are not allowed in Prometheus label names.
+func prometheusLabel(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,136 @@
+package resource_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestMetricsStatsd(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	os.Setenv("BUILD_PIPELINE_NAME", "pipeline")
+	defer os.Unsetenv("BUILD_PIPELINE_NAME")
+
+	source := resource.Source{
+		Repository: "itsdalmo/test-repository",
+		Metrics:    &resource.MetricsConfig{Statsd: conn.LocalAddr().String(), Tags: map[string]string{"team": "platform"}},
+	}
+	metrics := resource.NewMetrics(&source, "check")
+	metrics.Duration(1500*time.Millisecond, nil)
+	metrics.AddAPIUsage(resource.APIUsage{RESTCalls: 1, RESTRemaining: -1, GraphQLCalls: 2, GraphQLRemaining: 4998})
+	metrics.Gauge("versions", 3)
+	require.NoError(t, metrics.Flush())
+
+	b := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(b)
+	require.NoError(t, err)
+
+	tags := "pipeline:pipeline,repository:itsdalmo/test-repository,step:check,team:platform"
+	assert.Equal(t, strings.Join([]string{
+		"github_pr_resource.duration_seconds:1.5|g|#" + tags,
+		"github_pr_resource.success:1|g|#" + tags,
+		"github_pr_resource.api_calls:1|g|#api:rest," + tags,
+		"github_pr_resource.api_calls:2|g|#api:graphql," + tags,
+		"github_pr_resource.rate_limit_remaining:4998|g|#api:graphql," + tags,
+		"github_pr_resource.versions:3|g|#" + tags,
+	}, "\n")+"\n", string(b[:n]))
+}
+
+func TestMetricsPushgateway(t *testing.T) {
+	var (
+		path string
+		body string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		path, body = r.URL.Path, string(b)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository: "itsdalmo/test-repository",
+		Metrics:    &resource.MetricsConfig{Pushgateway: server.URL + "/"},
+	}
+	metrics := resource.NewMetrics(&source, "put")
+	metrics.Duration(2*time.Second, errors.New("failed"))
+	metrics.AddAPIUsage(resource.APIUsage{RESTCalls: 3, RESTRemaining: 4990, GraphQLCalls: 0, GraphQLRemaining: -1})
+	require.NoError(t, metrics.Flush())
+
+	assert.Equal(t, "/metrics/job/github_pr_resource/repository@base64/aXRzZGFsbW8vdGVzdC1yZXBvc2l0b3J5/step@base64/cHV0", path)
+	assert.Equal(t, `# TYPE github_pr_resource_duration_seconds gauge
+github_pr_resource_duration_seconds 2
+# TYPE github_pr_resource_success gauge
+github_pr_resource_success 0
+# TYPE github_pr_resource_api_calls gauge
+github_pr_resource_api_calls{api="rest"} 3
+github_pr_resource_api_calls{api="graphql"} 0
+# TYPE github_pr_resource_rate_limit_remaining gauge
+github_pr_resource_rate_limit_remaining{api="rest"} 4990
+`, body)
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	metrics := resource.NewMetrics(&resource.Source{Repository: "itsdalmo/test-repository"}, "check")
+	assert.Nil(t, metrics)
+	metrics.Duration(time.Second, nil)
+	metrics.Gauge("versions", 1)
+	assert.NoError(t, metrics.Flush())
+}
+
+func TestMetricsConfigValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		config      resource.MetricsConfig
+		expectError string
+	}{
+		{
+			description: "statsd",
+			config:      resource.MetricsConfig{Statsd: "localhost:8125"},
+		},
+		{
+			description: "pushgateway",
+			config:      resource.MetricsConfig{Pushgateway: "http://pushgateway:9091"},
+		},
+		{
+			description: "a sink is required",
+			config:      resource.MetricsConfig{Tags: map[string]string{"team": "platform"}},
+			expectError: "metrics must set statsd or pushgateway",
+		},
+		{
+			description: "statsd requires a port",
+			config:      resource.MetricsConfig{Statsd: "localhost"},
+			expectError: "metrics statsd must be an address (e.g. localhost:8125)",
+		},
+		{
+			description: "pushgateway must be a URL",
+			config:      resource.MetricsConfig{Pushgateway: "pushgateway:9091"},
+			expectError: "metrics pushgateway must be an absolute URL (e.g. http://pushgateway:9091)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", Metrics: &tc.config}
+			if tc.expectError != "" {
+				assert.EqualError(t, source.Validate(), tc.expectError)
+				return
+			}
+			assert.NoError(t, source.Validate())
+		})
+	}
+}
@@ -1,25 +1,51 @@
 package resource
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shurcooL/githubv4"
 )
 
+// apiVersionPattern matches a Github Enterprise Server version, e.g. "3.4".
+var apiVersionPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
 // Source represents the configuration for the resource.
 type Source struct {
 	Repository              string                      `json:"repository"`
 	AccessToken             string                      `json:"access_token"`
+	AccessTokenFile         string                      `json:"access_token_file"`
+	AppID                   int64                       `json:"app_id"`
+	AppPrivateKey           string                      `json:"app_private_key"`
+	AppInstallationID       int64                       `json:"app_installation_id"`
 	V3Endpoint              string                      `json:"v3_endpoint"`
 	V4Endpoint              string                      `json:"v4_endpoint"`
+	APIEndpoint             string                      `json:"api_endpoint"`
+	GithubAPIVersion        string                      `json:"github_api_version"`
+	APIMode                 string                      `json:"api_mode"`
 	Paths                   []string                    `json:"paths"`
 	IgnorePaths             []string                    `json:"ignore_paths"`
 	DisableCISkip           bool                        `json:"disable_ci_skip"`
 	DisableGitLFS           bool                        `json:"disable_git_lfs"`
 	SkipSSLVerification     bool                        `json:"skip_ssl_verification"`
+	CACerts                 string                      `json:"ca_certs"`
+	HTTPProxy               string                      `json:"http_proxy"`
+	HTTPSProxy              string                      `json:"https_proxy"`
+	NoProxy                 string                      `json:"no_proxy"`
+	HTTPTimeout             string                      `json:"http_timeout"`
+	MaxRetries              *int                        `json:"max_retries"`
+	RetryBackoff            string                      `json:"retry_backoff"`
+	APIConcurrency          int                         `json:"api_concurrency"`
+	LogLevel                string                      `json:"log_level"`
 	DisableForks            bool                        `json:"disable_forks"`
 	IgnoreDrafts            bool                        `json:"ignore_drafts"`
 	GitCryptKey             string                      `json:"git_crypt_key"`
@@ -27,21 +53,367 @@ type Source struct {
 	RequiredReviewApprovals int                         `json:"required_review_approvals"`
 	Labels                  []string                    `json:"labels"`
 	States                  []githubv4.PullRequestState `json:"states"`
+	SubmoduleCredentials    []SubmoduleCredential       `json:"submodule_credentials"`
+	ExpandEnv               []string                    `json:"expand_env"`
+	State                   *StateConfig                `json:"state"`
+	CheckFetch              []string                    `json:"check_fetch"`
+	MaxVersions             int                         `json:"max_versions"`
+	MaxBehindBy             *int                        `json:"max_behind_by"`
+	RequireSignedCommits    bool                        `json:"require_signed_commits"`
+	RequireLinearHistory    bool                        `json:"require_linear_history"`
+	PruneVersions           bool                        `json:"prune_versions"`
+	IncludeUpdatedAt        bool                        `json:"include_updated_at"`
+	OrderBy                 string                      `json:"order_by"`
+	StatusMap               map[string]string           `json:"status_map"`
+	ContextPrefix           string                      `json:"context_prefix"`
+	WhenReadyToMerge        bool                        `json:"when_ready_to_merge"`
+	ReadyContexts           []string                    `json:"ready_contexts"`
+	UpstreamCompatible      bool                        `json:"upstream_compatible"`
+	Metrics                 *MetricsConfig              `json:"metrics"`
+	Tracing                 *TracingConfig              `json:"tracing"`
+
+	// unknownOptions are the options in the JSON which are not supported (reported by Validate).
+	unknownOptions []string
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown options are recorded (instead of being ignored)
+// so that typos are reported by Validate, and nested objects are decoded strictly.
+func (s *Source) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, option := range sourceOptions() {
+		known[option] = true
+	}
+	var unknown []string
+	for key := range raw {
+		// Keys are matched case-insensitively by encoding/json
+		if !known[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+			delete(raw, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	type source Source
+	var v source
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&v); err != nil {
+		return err
+	}
+	*s = Source(v)
+	s.unknownOptions = unknown
+	return nil
+}
+
+// suggestOption returns the supported option which is closest to an unknown option,
+// or an empty string if none of them are close.
+func suggestOption(option string) string {
+	var suggestion string
+	best := 3
+	for _, known := range sourceOptions() {
+		if d := editDistance(strings.ToLower(option), known); d < best {
+			suggestion, best = known, d
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			current[j] = previous[j-1]
+			if a[i-1] != b[j-1] {
+				current[j]++
+			}
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// isAbsoluteURL returns true if s is a URL with a scheme and host.
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// secrets returns the credentials in the source.
+func (s *Source) secrets() []string {
+	secrets := []string{s.AccessToken, s.AppPrivateKey, s.GitCryptKey}
+	for _, c := range s.SubmoduleCredentials {
+		secrets = append(secrets, c.Password, c.PrivateKey)
+	}
+	if s.State != nil {
+		secrets = append(secrets, s.State.SecretAccessKey, s.State.SessionToken)
+	}
+	if s.Tracing != nil {
+		for _, v := range s.Tracing.Headers {
+			secrets = append(secrets, v)
+		}
+	}
+	return secrets
+}
+
+// retryPolicy returns the number of attempts, the initial delay between attempts and the timeout of each
+// attempt for requests to the Github API. Zero values are replaced with defaults by the retryTransport.
+func (s *Source) retryPolicy() (attempts int, delay, timeout time.Duration) {
+	if s.MaxRetries != nil {
+		attempts = *s.MaxRetries + 1
+	}
+	delay, _ = time.ParseDuration(s.RetryBackoff)
+	timeout, _ = time.ParseDuration(s.HTTPTimeout)
+	return attempts, delay, timeout
+}
+
+// Endpoints returns the URLs of the V3 (with a trailing slash) and V4 Github APIs. Unless they are
+// configured explicitly, they are derived from api_endpoint, which can be the URL of a Github Enterprise
+// server or of either of its APIs. Empty strings are returned for github.com.
+func (s *Source) Endpoints() (v3, v4 string) {
+	if s.APIEndpoint == "" {
+		v3, v4 = s.V3Endpoint, s.V4Endpoint
+		if v3 != "" && !strings.HasSuffix(v3, "/") {
+			v3 += "/"
+		}
+		return v3, v4
+	}
+
+	u, err := url.Parse(s.APIEndpoint)
+	if err != nil || u.Host == "github.com" || u.Host == "api.github.com" {
+		return "", ""
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	for _, suffix := range []string{"/api/v3", "/api/graphql", "/api"} {
+		if strings.HasSuffix(u.Path, suffix) {
+			u.Path = strings.TrimSuffix(u.Path, suffix)
+			break
+		}
+	}
+	base := strings.TrimSuffix(u.String(), "/")
+	return base + "/api/v3/", base + "/api/graphql"
+}
+
+// unauthenticated returns true if no credentials are set, in which case public repositories are accessed
+// anonymously (through the REST API, since the GraphQL API requires authentication).
+func (s *Source) unauthenticated() bool {
+	return s.AccessToken == "" && s.AccessTokenFile == "" && s.AppID == 0
 }
 
-// Validate the source configuration.
+// fetches returns true if check should fetch the given (optional) data for pull requests,
+// which is everything unless check_fetch is set.
+func (s *Source) fetches(data string) bool {
+	return s.CheckFetch == nil || contains(s.CheckFetch, data)
+}
+
+// SubmoduleCredential used to fetch submodules hosted on other (private) servers, either over HTTPS
+// (username and password or token) or SSH (private key, and the known hosts used to verify the host key).
+type SubmoduleCredential struct {
+	Host                    string `json:"host"`
+	Username                string `json:"username"`
+	Password                string `json:"password"`
+	PrivateKey              string `json:"private_key"`
+	KnownHosts              string `json:"known_hosts"`
+	SkipHostKeyVerification bool   `json:"skip_host_key_verification"`
+}
+
+// Validate the source configuration. All problems are reported at once.
 func (s *Source) Validate() error {
-	if s.AccessToken == "" {
-		return errors.New("access_token must be set")
+	var problems []string
+	problem := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	for _, option := range s.unknownOptions {
+		if suggestion := suggestOption(option); suggestion != "" {
+			problem("unknown option \"%s\" (did you mean \"%s\"?)", option, suggestion)
+		} else {
+			problem("unknown option \"%s\"", option)
+		}
+	}
+	credentials := 0
+	for _, set := range []bool{s.AccessToken != "", s.AccessTokenFile != "", s.AppID != 0} {
+		if set {
+			credentials++
+		}
+	}
+	// Without credentials, public repositories are accessed anonymously through the REST API
+	if credentials == 0 && s.APIMode == apiModeGraphQL {
+		problem("api_mode cannot be graphql without access_token, access_token_file or app_id (the GraphQL API requires authentication)")
+	}
+	if credentials == 0 && s.WhenReadyToMerge {
+		problem("when_ready_to_merge requires access_token, access_token_file or app_id")
+	}
+	if credentials > 1 {
+		problem("only one of access_token, access_token_file or app_id can be set")
+	}
+	if s.AppID != 0 && s.AppPrivateKey == "" {
+		problem("app_private_key must be set together with app_id")
+	}
+	if s.AppID == 0 && (s.AppPrivateKey != "" || s.AppInstallationID != 0) {
+		problem("app_private_key and app_installation_id can only be set together with app_id")
 	}
 	if s.Repository == "" {
-		return errors.New("repository must be set")
+		problem("repository must be set")
+	} else if parts := strings.Split(s.Repository, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		problem("repository must be in the format owner/repository")
+	}
+	if s.APIEndpoint != "" {
+		if s.V3Endpoint != "" || s.V4Endpoint != "" {
+			problem("api_endpoint cannot be combined with v3_endpoint or v4_endpoint")
+		}
+		if !isAbsoluteURL(s.APIEndpoint) {
+			problem("api_endpoint must be an absolute URL (e.g. https://github.example.com)")
+		}
 	}
 	if s.V3Endpoint != "" && s.V4Endpoint == "" {
-		return errors.New("v4_endpoint must be set together with v3_endpoint")
+		problem("v4_endpoint must be set together with v3_endpoint")
 	}
 	if s.V4Endpoint != "" && s.V3Endpoint == "" {
-		return errors.New("v3_endpoint must be set together with v4_endpoint")
+		problem("v3_endpoint must be set together with v4_endpoint")
+	}
+	for _, endpoint := range []struct{ name, value, example string }{
+		{"v3_endpoint", s.V3Endpoint, "https://github.example.com/api/v3/"},
+		{"v4_endpoint", s.V4Endpoint, "https://github.example.com/api/graphql"},
+	} {
+		if endpoint.value != "" && !isAbsoluteURL(endpoint.value) {
+			problem("%s must be an absolute URL (e.g. %s)", endpoint.name, endpoint.example)
+		}
+	}
+	for _, proxy := range []struct{ name, value string }{{"http_proxy", s.HTTPProxy}, {"https_proxy", s.HTTPSProxy}} {
+		if proxy.value == "" {
+			continue
+		}
+		if _, err := url.Parse(proxy.value); err != nil {
+			problem("%s must be a valid URL: %s", proxy.name, err)
+		}
+	}
+	if _, err := tlsConfig(s); err != nil {
+		problem("%s", err)
+	}
+	for _, patterns := range []struct {
+		name  string
+		value []string
+	}{{"paths", s.Paths}, {"ignore_paths", s.IgnorePaths}} {
+		for _, pattern := range patterns.value {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				problem("%s value \"%s\" is not a valid pattern: %s", patterns.name, pattern, err)
+			}
+		}
+	}
+	for _, d := range []struct{ name, value string }{{"http_timeout", s.HTTPTimeout}, {"retry_backoff", s.RetryBackoff}} {
+		if d.value == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d.value); err != nil || v <= 0 {
+			problem("%s must be a positive duration (e.g. 30s)", d.name)
+		}
+	}
+	if s.MaxRetries != nil && *s.MaxRetries < 0 {
+		problem("max_retries must not be negative")
+	}
+	if s.LogLevel != "" {
+		if _, err := ParseLogLevel(s.LogLevel); err != nil {
+			problem("invalid log_level: %s", err)
+		}
+	}
+	if s.APIConcurrency < 0 {
+		problem("api_concurrency must not be negative")
+	}
+	if s.GithubAPIVersion != "" && !apiVersionPattern.MatchString(s.GithubAPIVersion) {
+		problem("github_api_version must be a Github Enterprise Server version (e.g. 3.4)")
+	}
+	for _, data := range s.CheckFetch {
+		if data != "labels" && data != "reviews" {
+			problem("check_fetch value \"%s\" must be one of: labels, reviews", data)
+		}
+	}
+	if len(s.Labels) > 0 && !s.fetches("labels") {
+		problem("check_fetch must include labels when labels is set")
+	}
+	if s.RequiredReviewApprovals > 0 && !s.fetches("reviews") {
+		problem("check_fetch must include reviews when required_review_approvals is set")
+	}
+	if s.RequiredReviewApprovals < 0 {
+		problem("required_review_approvals must not be negative")
+	}
+	if s.WhenReadyToMerge && !s.fetches("reviews") {
+		problem("check_fetch must include reviews when when_ready_to_merge is set")
+	}
+	if len(s.ReadyContexts) > 0 && !s.WhenReadyToMerge {
+		problem("ready_contexts can only be set when when_ready_to_merge is set")
+	}
+	if s.UpstreamCompatible && s.IncludeUpdatedAt {
+		problem("include_updated_at cannot be set when upstream_compatible is set (the version would not be compatible)")
+	}
+	if s.APIMode != "" && !contains([]string{apiModeGraphQL, apiModeREST}, s.APIMode) {
+		problem("unknown api_mode: %s (must be graphql or rest)", s.APIMode)
+	}
+	if s.APIMode == apiModeREST && s.WhenReadyToMerge {
+		problem("when_ready_to_merge is not supported when api_mode is rest")
+	}
+	if s.OrderBy != "" && !contains([]string{"authored", "committed", "pushed"}, s.OrderBy) {
+		problem("unknown order_by: %s (must be authored, committed or pushed)", s.OrderBy)
+	}
+	if s.MaxVersions < 0 {
+		problem("max_versions must not be negative")
+	}
+	if s.MaxBehindBy != nil && *s.MaxBehindBy < 0 {
+		problem("max_behind_by must not be negative")
+	}
+	if s.State != nil {
+		if err := s.State.Validate(); err != nil {
+			problem("%s", err)
+		}
+	}
+	statuses := make([]string, 0, len(s.StatusMap))
+	for from := range s.StatusMap {
+		statuses = append(statuses, from)
+	}
+	sort.Strings(statuses)
+	for _, from := range statuses {
+		if to := s.StatusMap[from]; !contains([]string{"success", "pending", "failure", "error"}, to) {
+			problem("status_map value for %s must be success, pending, failure or error: %s", from, to)
+		}
+	}
+	if s.Metrics != nil {
+		if err := s.Metrics.Validate(); err != nil {
+			problem("%s", err)
+		}
+	}
+	if s.Tracing != nil {
+		if err := s.Tracing.Validate(); err != nil {
+			problem("%s", err)
+		}
+	}
+	for _, c := range s.SubmoduleCredentials {
+		if c.Host == "" || (c.Password == "") == (c.PrivateKey == "") {
+			problem("submodule_credentials must set host and either password or private_key")
+			break
+		}
+		if c.PrivateKey != "" && (c.KnownHosts == "") == !c.SkipHostKeyVerification {
+			problem("submodule_credentials with a private_key must set either known_hosts or skip_host_key_verification")
+			break
+		}
 	}
 	for _, state := range s.States {
 		switch state {
@@ -49,10 +421,18 @@ func (s *Source) Validate() error {
 		case githubv4.PullRequestStateClosed:
 		case githubv4.PullRequestStateMerged:
 		default:
-			return errors.New(fmt.Sprintf("states value \"%s\" must be one of: OPEN, MERGED, CLOSED", state))
+			problem("states value \"%s\" must be one of: OPEN, MERGED, CLOSED", state)
 		}
 	}
-	return nil
+
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New(problems[0])
+	default:
+		return fmt.Errorf("%d problems: %s", len(problems), strings.Join(problems, "; "))
+	}
 }
 
 // Metadata output from get/put steps.
@@ -63,6 +443,70 @@ func (m *Metadata) Add(name, value string) {
 	*m = append(*m, &MetadataField{Name: name, Value: value})
 }
 
+// upstreamMetadata are the names of the metadata written by get in telia-oss/github-pr-resource.
+var upstreamMetadata = []string{"pr", "title", "url", "head_name", "head_sha", "base_name", "base_sha", "message", "author", "author_email", "state"}
+
+// upstream returns the fields of the Metadata which are also written by telia-oss/github-pr-resource.
+func (m Metadata) upstream() Metadata {
+	var fields Metadata
+	for _, f := range m {
+		if contains(upstreamMetadata, f.Name) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// envNames maps metadata names to their variable name in metadata.env when it
+// differs from the upper-cased metadata name.
+var envNames = map[string]string{
+	"pr":           "PR_NUMBER",
+	"title":        "PR_TITLE",
+	"url":          "PR_URL",
+	"author":       "PR_AUTHOR",
+	"author_email": "PR_AUTHOR_EMAIL",
+	"author_name":  "PR_AUTHOR_NAME",
+	"state":        "PR_STATE",
+}
+
+// Env renders the Metadata as single-quoted shell variable assignments, which
+// can be sourced from a shell or read as a dotenv file.
+func (m Metadata) Env() []byte {
+	var b strings.Builder
+	for _, f := range m {
+		value := strings.Replace(f.Value, "'", `'\''`, -1)
+		fmt.Fprintf(&b, "%s='%s'\n", envName(f.Name), value)
+	}
+	return []byte(b.String())
+}
+
+// LookupEnv returns the value of the MetadataField with the given variable name (as used in metadata.env).
+func (m Metadata) LookupEnv(name string) (string, bool) {
+	for _, f := range m {
+		if envName(f.Name) == name {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+func envName(name string) string {
+	if n, ok := envNames[name]; ok {
+		return n
+	}
+	return strings.ToUpper(name)
+}
+
+// Get the value of a MetadataField, or an empty string if it does not exist.
+func (m Metadata) Get(name string) string {
+	for _, f := range m {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return ""
+}
+
 // MetadataField ...
 type MetadataField struct {
 	Name  string `json:"name"`
@@ -71,30 +515,52 @@ type MetadataField struct {
 
 // Version communicated with Concourse.
 type Version struct {
-	PR                  string                    `json:"pr"`
-	Commit              string                    `json:"commit"`
-	CommittedDate       time.Time                 `json:"committed,omitempty"`
+	PR            string    `json:"pr"`
+	Commit        string    `json:"commit"`
+	CommittedDate time.Time `json:"committed,omitempty"`
+	// UpdatedDate is the last time the pull request was updated (only set with include_updated_at).
+	UpdatedDate         *time.Time                `json:"updated,omitempty"`
 	ApprovedReviewCount string                    `json:"approved_review_count"`
 	State               githubv4.PullRequestState `json:"state"`
 }
 
-// NewVersion constructs a new Version.
+// NewVersion constructs a new Version. The date is in UTC, so that the same version is
+// always encoded the same way.
 func NewVersion(p *PullRequest) Version {
 	return Version{
 		PR:                  strconv.Itoa(p.Number),
 		Commit:              p.Tip.OID,
-		CommittedDate:       p.UpdatedDate().Time,
+		CommittedDate:       p.UpdatedDate().Time.UTC(),
 		ApprovedReviewCount: strconv.Itoa(p.ApprovedReviewCount),
 		State:               p.State,
 	}
 }
 
+// date returns the date used to order versions, and to filter subsequent checks.
+func (v Version) date() time.Time {
+	if v.UpdatedDate != nil {
+		return *v.UpdatedDate
+	}
+	return v.CommittedDate
+}
+
 // PullRequest represents a pull request and includes the tip (commit).
 type PullRequest struct {
 	PullRequestObject
 	Tip                 CommitObject
 	ApprovedReviewCount int
 	Labels              []LabelObject
+	RequestedReviewers  []RequestedReviewerObject
+	// Files changed by the pull request, if they were fetched along with it.
+	Files []string
+	// ForcePushedDate is the last time the tip was force pushed to the pull request (if it was).
+	ForcePushedDate githubv4.DateTime
+	// ReviewDecision, ChangesRequestedCount, ApprovedDate and Contexts are set if the readiness of
+	// the pull request to be merged was fetched along with it.
+	ReviewDecision        githubv4.PullRequestReviewDecision
+	ChangesRequestedCount int
+	ApprovedDate          githubv4.DateTime
+	Contexts              []CommitContext
 }
 
 // PullRequestObject represents the GraphQL commit node.
@@ -109,17 +575,25 @@ type PullRequestObject struct {
 	Repository  struct {
 		URL string
 	}
+	// HeadRepository is nil if the repository of the head branch (i.e. the fork) has been deleted.
+	HeadRepository *struct {
+		NameWithOwner string
+	}
 	IsCrossRepository bool
 	IsDraft           bool
 	State             githubv4.PullRequestState
 	ClosedAt          githubv4.DateTime
 	MergedAt          githubv4.DateTime
+	UpdatedAt         githubv4.DateTime
 }
 
-// UpdatedDate returns the last time a PR was updated, either by commit
-// or being closed/merged.
+// UpdatedDate returns the last time a PR was updated, either by commit (or force push
+// of an existing commit) or being closed/merged.
 func (p *PullRequest) UpdatedDate() githubv4.DateTime {
 	date := p.Tip.CommittedDate
+	if p.ForcePushedDate.After(date.Time) {
+		date = p.ForcePushedDate
+	}
 	switch p.State {
 	case githubv4.PullRequestStateClosed:
 		date = p.ClosedAt
@@ -129,21 +603,139 @@ func (p *PullRequest) UpdatedDate() githubv4.DateTime {
 	return date
 }
 
+// orderDate returns the date used to order the versions of pull requests, which is when the tip was authored,
+// committed or pushed (the default) depending on order_by, or when the pull request was closed/merged.
+func (p *PullRequest) orderDate(orderBy string) githubv4.DateTime {
+	if p.State == githubv4.PullRequestStateClosed || p.State == githubv4.PullRequestStateMerged {
+		return p.UpdatedDate()
+	}
+	switch orderBy {
+	case "authored":
+		return p.Tip.AuthoredDate
+	case "committed":
+		return p.Tip.CommittedDate
+	}
+	return p.UpdatedDate()
+}
+
+// readyToMerge returns true if the pull request is approved (and no changes are requested), and the given
+// contexts of the tip (or all of them, if none are given) are successful. Otherwise the reason is returned.
+func (p *PullRequest) readyToMerge(contexts []string) (bool, string) {
+	switch {
+	case p.ChangesRequestedCount > 0 || p.ReviewDecision == githubv4.PullRequestReviewDecisionChangesRequested:
+		return false, "changes requested"
+	case p.ApprovedReviewCount == 0 || p.ReviewDecision == githubv4.PullRequestReviewDecisionReviewRequired:
+		return false, "not approved"
+	}
+	for _, name := range contexts {
+		if c := p.context(name); c == nil || c.State != "success" {
+			return false, "context " + name + " is not successful"
+		}
+	}
+	if len(contexts) == 0 {
+		for _, c := range p.Contexts {
+			if c.State != "success" {
+				return false, "context " + c.Name + " is not successful"
+			}
+		}
+	}
+	return true, ""
+}
+
+// readyDate returns the time the pull request became ready to merge, which is when it was last approved
+// or the last of the given contexts (or all of them, if none are given) completed.
+func (p *PullRequest) readyDate(contexts []string) githubv4.DateTime {
+	date := p.ApprovedDate
+	for _, c := range p.Contexts {
+		if (len(contexts) == 0 || contains(contexts, c.Name)) && c.Date.After(date.Time) {
+			date = c.Date
+		}
+	}
+	return date
+}
+
+// context returns the status or check run of the tip with the given name, if it has one.
+func (p *PullRequest) context(name string) *CommitContext {
+	for i := range p.Contexts {
+		if p.Contexts[i].Name == name {
+			return &p.Contexts[i]
+		}
+	}
+	return nil
+}
+
+// CommitContext is a status or check run of a commit, with the state of a status (success, pending, failure
+// or error), and the time it was last updated.
+type CommitContext struct {
+	Name  string
+	State string
+	Date  githubv4.DateTime
+}
+
+// CommitComparison is the number of commits a pull request is ahead of and behind its base.
+type CommitComparison struct {
+	AheadBy  int
+	BehindBy int
+}
+
+// PullRequestCommit is a commit of a pull request, with the details needed to tell whether it
+// conforms to branch protection rules.
+type PullRequestCommit struct {
+	OID      string
+	Verified bool
+	// VerificationReason is the reason the signature is (not) verified, e.g. "unsigned" or "valid".
+	VerificationReason string
+	Parents            int
+}
+
+// CommitStatus is a status of a commit.
+type CommitStatus struct {
+	Context     string
+	State       string
+	TargetURL   string
+	Description string
+}
+
 // CommitObject represents the GraphQL commit node.
 // https://developer.github.com/v4/object/commit/
 type CommitObject struct {
 	ID            string
 	OID           string
 	CommittedDate githubv4.DateTime
+	AuthoredDate  githubv4.DateTime
 	Message       string
 	Author        struct {
 		User struct {
 			Login string
 		}
+		Name  string
 		Email string
 	}
 }
 
+// ForcePushEvents represents the GraphQL HeadRefForcePushedEvent nodes of a pull request timeline.
+// https://developer.github.com/v4/object/headrefforcepushedevent/
+type ForcePushEvents struct {
+	Nodes []struct {
+		HeadRefForcePushedEvent struct {
+			CreatedAt   githubv4.DateTime
+			AfterCommit struct {
+				OID string
+			}
+		} `graphql:"... on HeadRefForcePushedEvent"`
+	}
+}
+
+// PushedDate returns the last time the commit was force pushed, if it was.
+func (e ForcePushEvents) PushedDate(oid string) githubv4.DateTime {
+	for i := len(e.Nodes) - 1; i >= 0; i-- {
+		if event := e.Nodes[i].HeadRefForcePushedEvent; event.AfterCommit.OID == oid {
+			return event.CreatedAt
+		}
+	}
+	return githubv4.DateTime{}
+}
+
 // ChangedFileObject represents the GraphQL FilesChanged node.
 // https://developer.github.com/v4/object/pullrequestchangedfile/
 type ChangedFileObject struct {
@@ -155,3 +747,186 @@ type ChangedFileObject struct {
 type LabelObject struct {
 	Name string
 }
+
+// CommentObject represents the GraphQL issue comment node.
+type CommentObject struct {
+	ID          string
+	DatabaseId  int64
+	Body        string
+	IsMinimized bool
+}
+
+// IssueObject represents the GraphQL issue node.
+// https://developer.github.com/v4/object/issue/
+type IssueObject struct {
+	ID     string              `json:"id"`
+	Number int                 `json:"number"`
+	Title  string              `json:"title"`
+	URL    string              `json:"url"`
+	State  githubv4.IssueState `json:"state"`
+}
+
+// RequestedReviewerObject represents the GraphQL RequestedReviewer union,
+// which is either a user or a team.
+// https://developer.github.com/v4/union/requestedreviewer/
+type RequestedReviewerObject struct {
+	Typename string `graphql:"__typename"`
+	User     struct {
+		Login string
+	} `graphql:"... on User"`
+	Team struct {
+		Slug string
+	} `graphql:"... on Team"`
+}
+
+// Name returns the login of a requested user or the slug of a requested team.
+func (r RequestedReviewerObject) Name() string {
+	if r.Typename == "Team" {
+		return r.Team.Slug
+	}
+	return r.User.Login
+}
+
+// PullRequestDetailsObject represents the GraphQL pull request node with the
+// extended set of fields written to pr.json when full_metadata is enabled.
+// https://developer.github.com/v4/object/pullrequest/
+type PullRequestDetailsObject struct {
+	ID             string                              `json:"id"`
+	Number         int                                 `json:"number"`
+	Title          string                              `json:"title"`
+	Body           string                              `json:"body"`
+	URL            string                              `json:"url"`
+	State          githubv4.PullRequestState           `json:"state"`
+	IsDraft        bool                                `json:"isDraft"`
+	Locked         bool                                `json:"locked"`
+	Mergeable      githubv4.MergeableState             `json:"mergeable"`
+	ReviewDecision *githubv4.PullRequestReviewDecision `json:"reviewDecision"`
+	Additions      int                                 `json:"additions"`
+	Deletions      int                                 `json:"deletions"`
+	ChangedFiles   int                                 `json:"changedFiles"`
+	CreatedAt      githubv4.DateTime                   `json:"createdAt"`
+	UpdatedAt      githubv4.DateTime                   `json:"updatedAt"`
+	ClosedAt       *githubv4.DateTime                  `json:"closedAt"`
+	MergedAt       *githubv4.DateTime                  `json:"mergedAt"`
+	BaseRefName    string                              `json:"baseRefName"`
+	BaseRefOid     string                              `json:"baseRefOid"`
+	HeadRefName    string                              `json:"headRefName"`
+	HeadRefOid     string                              `json:"headRefOid"`
+	MergeCommit    *struct {
+		Oid string `json:"oid"`
+	} `json:"mergeCommit"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	HeadRepository *struct {
+		NameWithOwner string `json:"nameWithOwner"`
+		URL           string `json:"url"`
+	} `json:"headRepository"`
+	Milestone *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		URL    string `json:"url"`
+	} `json:"milestone"`
+	AutoMergeRequest *struct {
+		EnabledAt   githubv4.DateTime               `json:"enabledAt"`
+		MergeMethod githubv4.PullRequestMergeMethod `json:"mergeMethod"`
+		EnabledBy   struct {
+			Login string `json:"login"`
+		} `json:"enabledBy"`
+	} `json:"autoMergeRequest"`
+	Assignees struct {
+		Nodes []struct {
+			Login string `json:"login"`
+		} `json:"nodes"`
+	} `graphql:"assignees(first:100)" json:"assignees"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `graphql:"labels(first:100)" json:"labels"`
+	ProjectCards struct {
+		Nodes []struct {
+			Project struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"project"`
+			Column *struct {
+				Name string `json:"name"`
+			} `json:"column"`
+		} `json:"nodes"`
+	} `graphql:"projectCards(first:100)" json:"projectCards"`
+	ClosingIssuesReferences struct {
+		Nodes []struct {
+			Number int                 `json:"number"`
+			Title  string              `json:"title"`
+			URL    string              `json:"url"`
+			State  githubv4.IssueState `json:"state"`
+		} `json:"nodes"`
+	} `graphql:"closingIssuesReferences(first:100)" json:"closingIssuesReferences"`
+}
+
+// CheckRun to create or update through the Checks API.
+// https://developer.github.com/v3/checks/runs/
+type CheckRun struct {
+	Name        string
+	HeadBranch  string
+	Status      string
+	Conclusion  string
+	DetailsURL  string
+	Title       string
+	Summary     string
+	Annotations []CheckRunAnnotation
+}
+
+// Release to create through the Releases API.
+// https://developer.github.com/v3/repos/releases/
+type Release struct {
+	TagName    string
+	Name       string
+	Body       string
+	Draft      bool
+	Prerelease bool
+}
+
+// Deployment to create (or update the status of) through the Deployments API.
+// https://developer.github.com/v3/repos/deployments/
+type Deployment struct {
+	Environment    string
+	Description    string
+	Transient      bool
+	Production     bool
+	State          string
+	EnvironmentURL string
+	LogURL         string
+}
+
+// Project (v2) to add a pull request to, and the field values to set on its item.
+// https://docs.github.com/en/issues/planning-and-tracking-with-projects/automating-your-project/using-the-api-to-manage-projects
+type Project struct {
+	Owner  string
+	Number int
+	Fields map[string]string
+}
+
+// Suggestion is a suggested change, which replaces the lines StartLine through Line (inclusive)
+// of the file at Path with the replacement lines.
+type Suggestion struct {
+	Path        string
+	StartLine   int
+	Line        int
+	Replacement []string
+}
+
+// CheckRunAnnotation represents an annotation in the output of a check run.
+// https://developer.github.com/v3/checks/runs/#annotations-object
+type CheckRunAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	StartColumn     int    `json:"start_column,omitempty"`
+	EndColumn       int    `json:"end_column,omitempty"`
+	AnnotationLevel string `json:"annotation_level"`
+	Message         string `json:"message"`
+	Title           string `json:"title,omitempty"`
+	RawDetails      string `json:"raw_details,omitempty"`
+}
@@ -0,0 +1,142 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestSourceEndpoints(t *testing.T) {
+	tests := []struct {
+		description string
+		source      resource.Source
+		v3          string
+		v4          string
+	}{
+		{
+			description: "github.com by default",
+		},
+		{
+			description: "explicit endpoints get a trailing slash for v3",
+			source:      resource.Source{V3Endpoint: "https://github.example.com/api/v3", V4Endpoint: "https://github.example.com/api/graphql"},
+			v3:          "https://github.example.com/api/v3/",
+			v4:          "https://github.example.com/api/graphql",
+		},
+		{
+			description: "derived from the server URL",
+			source:      resource.Source{APIEndpoint: "https://github.example.com/"},
+			v3:          "https://github.example.com/api/v3/",
+			v4:          "https://github.example.com/api/graphql",
+		},
+		{
+			description: "derived from the v3 API URL",
+			source:      resource.Source{APIEndpoint: "https://github.example.com/api/v3/"},
+			v3:          "https://github.example.com/api/v3/",
+			v4:          "https://github.example.com/api/graphql",
+		},
+		{
+			description: "derived from the v4 API URL",
+			source:      resource.Source{APIEndpoint: "https://github.example.com/api/graphql"},
+			v3:          "https://github.example.com/api/v3/",
+			v4:          "https://github.example.com/api/graphql",
+		},
+		{
+			description: "github.com is recognized",
+			source:      resource.Source{APIEndpoint: "https://api.github.com"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			v3, v4 := tc.source.Endpoints()
+			assert.Equal(t, tc.v3, v3)
+			assert.Equal(t, tc.v4, v4)
+		})
+	}
+}
+
+func TestSourceValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		source      string
+		expectError string
+	}{
+		{
+			description: "valid source",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "paths": ["terraform/*"]}`,
+		},
+		{
+			description: "keys are matched case-insensitively",
+			source:      `{"Repository": "itsdalmo/test-repository", "access_token": "oauthtoken"}`,
+		},
+		{
+			description: "unknown options are reported with a suggestion",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "ignore_pathss": ["docs/*"]}`,
+			expectError: `unknown option "ignore_pathss" (did you mean "ignore_paths"?)`,
+		},
+		{
+			description: "unknown options without a close match",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "webhook_token": "x"}`,
+			expectError: `unknown option "webhook_token"`,
+		},
+		{
+			description: "unknown options in nested objects are rejected",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "state": {"urll": "s3://bucket"}}`,
+			expectError: `json: unknown field "urll"`,
+		},
+		{
+			description: "invalid patterns are rejected",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "paths": ["terraform/[a-"]}`,
+			expectError: `paths value "terraform/[a-" is not a valid pattern: syntax error in pattern`,
+		},
+		{
+			description: "endpoints must be absolute URLs",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "v3_endpoint": "github.example.com/api/v3", "v4_endpoint": "https://github.example.com/api/graphql"}`,
+			expectError: `v3_endpoint must be an absolute URL (e.g. https://github.example.com/api/v3/)`,
+		},
+		{
+			description: "app options require app_id",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "app_installation_id": 1}`,
+			expectError: `app_private_key and app_installation_id can only be set together with app_id`,
+		},
+		{
+			description: "order_by must be known",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "order_by": "created"}`,
+			expectError: `unknown order_by: created (must be authored, committed or pushed)`,
+		},
+		{
+			description: "submodule credentials use either a password or a private key",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "submodule_credentials": [{"host": "gitlab.example.com", "password": "token", "private_key": "key"}]}`,
+			expectError: `submodule_credentials must set host and either password or private_key`,
+		},
+		{
+			description: "submodule credentials with a private key verify the host key by default",
+			source:      `{"repository": "itsdalmo/test-repository", "access_token": "oauthtoken", "submodule_credentials": [{"host": "gitlab.example.com", "private_key": "key"}]}`,
+			expectError: `submodule_credentials with a private_key must set either known_hosts or skip_host_key_verification`,
+		},
+		{
+			description: "all problems are reported at once",
+			source:      `{"repository": "test-repository", "ignore_pathss": [], "api_mode": "graphql", "max_versions": -1}`,
+			expectError: `4 problems: unknown option "ignore_pathss" (did you mean "ignore_paths"?); ` +
+				`api_mode cannot be graphql without access_token, access_token_file or app_id (the GraphQL API requires authentication); repository must be in the format owner/repository; ` +
+				`max_versions must not be negative`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var source resource.Source
+			err := json.Unmarshal([]byte(tc.source), &source)
+			if err == nil {
+				err = source.Validate()
+			}
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,69 +2,451 @@ package resource
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shurcooL/githubv4"
 )
 
 // Put (business logic)
 func Put(request PutRequest, manager Github, inputDir string) (*PutResponse, error) {
+	if err := request.Params.mapStatuses(request.Source.StatusMap, inputDir); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %s", err)
+	}
 	if err := request.Params.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid parameters: %s", err)
 	}
-	path := filepath.Join(inputDir, request.Params.Path, ".git", "resource")
 
-	// Version available after a GET step.
-	var version Version
-	content, err := ioutil.ReadFile(filepath.Join(path, "version.json"))
+	if request.Source.unauthenticated() {
+		return nil, errors.New("put requires access_token, access_token_file or app_id")
+	}
+
+	// Record the changes made through the Github API if specified (including those made before put fails)
+	if p := request.Params; p.AuditFile != "" || p.AuditLog {
+		audit = &auditLog{}
+		if p.AuditLog {
+			audit.output = os.Stderr
+		}
+		defer func() {
+			if p.AuditFile != "" {
+				if err := audit.write(filepath.Join(inputDir, p.AuditFile)); err != nil {
+					logger.Warn("failed to write audit file", "path", p.AuditFile, "error", err)
+				}
+			}
+			audit = nil
+		}()
+	}
+
+	// Verify that the token has the access required by the parameters, before doing anything
+	info, err := manager.GetTokenInfo()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read version from path: %s", err)
+		return nil, fmt.Errorf("failed to verify access token: %s", err)
 	}
-	if err := json.Unmarshal(content, &version); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal version from file: %s", err)
+	if err := checkTokenAccess(info, putRequirements(request.Params)); err != nil {
+		return nil, err
 	}
 
-	// Metadata available after a GET step.
-	var metadata Metadata
-	content, err = ioutil.ReadFile(filepath.Join(path, "metadata.json"))
+	path, err := inputPath(request.Params, inputDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read metadata from path: %s", err)
+		return nil, err
+	}
+
+	var version Version
+	var metadata Metadata
+	if p := request.Params; p.PRNumber != "" || p.VersionFile != "" {
+		// Version specified without a GET step.
+		version, metadata, err = versionFromParams(p, request.Source, manager, inputDir)
+		if err != nil {
+			return nil, err
+		}
+	} else if content, err := ioutil.ReadFile(filepath.Join(inputDir, path, ".git", "resource", "version.json")); err != nil {
+		if !os.IsNotExist(err) || p.Version == nil {
+			return nil, missingVersionError(inputDir, err)
+		}
+		// Fall back to the version given as a parameter.
+		logger.Warn("no version found in path, using the version parameter", "path", path, "pr", p.Version.PR)
+		version, metadata, err = versionFromParams(PutParameters{PRNumber: p.Version.PR, Commit: p.Version.Commit}, request.Source, manager, inputDir)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		path := filepath.Join(inputDir, path, ".git", "resource")
+
+		// Version available after a GET step.
+		if err := json.Unmarshal(content, &version); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal version from file: %s", err)
+		}
+
+		// Metadata available after a GET step.
+		content, err = ioutil.ReadFile(filepath.Join(path, "metadata.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read metadata from path: %s", err)
+		}
+		if err := json.Unmarshal(content, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata from file: %s", err)
+		}
+	}
+
+	if request.Source.UpstreamCompatible {
+		metadata = metadata.upstream()
+	}
+
+	// Fail before doing anything if the pull request has new commits since the version
+	if request.Params.FailIfOutdated && version.Commit != "" {
+		details, err := manager.GetPullRequestDetails(version.PR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request details: %s", err)
+		}
+		if details.HeadRefOid != version.Commit {
+			return nil, fmt.Errorf("version is outdated: the head of the pull request is %s, not %s", details.HeadRefOid, version.Commit)
+		}
+	}
+
+	// Variables are expanded in most parameters
+	expand := newExpander(request, metadata)
+
+	// Statuses and check runs are set on the commit from the version, unless specified
+	statusCommit := version.Commit
+	if p := request.Params; p.CommitSHA != "" {
+		statusCommit = p.CommitSHA
+	} else if p.CommitFile != "" {
+		content, err := readInputFile(filepath.Join(inputDir, p.CommitFile), maxShortInputFileSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit file: %s", err)
+		}
+		statusCommit = strings.TrimSpace(string(content))
 	}
-	if err := json.Unmarshal(content, &metadata); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metadata from file: %s", err)
+
+	// Prefix the contexts of statuses and check runs if specified
+	baseContext := request.Params.BaseContext
+	if prefix := request.Source.ContextPrefix; prefix != "" {
+		if baseContext == "" {
+			baseContext = "concourse-ci"
+		}
+		baseContext = prefix + baseContext
 	}
 
-	// Set status if specified
+	// Set statuses if specified
+	statuses := request.Params.Statuses
 	if p := request.Params; p.Status != "" {
-		description := p.Description
+		statuses = append([]StatusParameters{{
+			Context:         p.Context,
+			Status:          p.Status,
+			TargetURL:       p.TargetURL,
+			Description:     p.Description,
+			DescriptionFile: p.DescriptionFile,
+		}}, statuses...)
+	}
+	for _, s := range statuses {
+		description := s.Description
 
 		// Set description from a file
-		if p.DescriptionFile != "" {
-			content, err := ioutil.ReadFile(filepath.Join(inputDir, p.DescriptionFile))
+		if s.DescriptionFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, s.DescriptionFile), maxShortInputFileSize)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read description file: %s", err)
 			}
 			description = string(content)
 		}
 
-		if err := manager.UpdateCommitStatus(version.Commit, p.BaseContext, safeExpandEnv(p.Context), p.Status, safeExpandEnv(p.TargetURL), description); err != nil {
+		context := expand.all(s.Context)
+		targetURL := expand.all(s.TargetURL)
+		description = expand.all(description)
+
+		// Truncate descriptions exceeding the maximum length, and keep the full description in the metadata
+		if utf8.RuneCountInString(description) > maxStatusDescriptionLength {
+			name := context
+			if name == "" {
+				name = "status"
+			}
+			metadata.Add(name+"_full_description", description)
+
+			suffix := expand.all(request.Params.DescriptionTruncationSuffix)
+			if suffix == "" {
+				suffix = truncatedDescriptionSuffix
+			}
+			description = truncateDescription(description, suffix)
+		}
+
+		if targetURL == "" {
+			targetURL = buildURL()
+		}
+		unchanged := false
+		if request.Params.SkipUnchangedStatus {
+			existing, err := manager.GetCommitStatus(statusCommit, baseContext, context)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get status: %s", err)
+			}
+			expected := description
+			if expected == "" {
+				expected = defaultStatusDescription(s.Status)
+			}
+			unchanged = existing != nil && existing.State == strings.ToLower(s.Status) && existing.TargetURL == targetURL && existing.Description == expected
+		}
+		if unchanged {
+			logger.Info("status is unchanged, skipping", "context", context, "status", s.Status)
+		} else if err := manager.UpdateCommitStatus(statusCommit, baseContext, context, s.Status, targetURL, description); err != nil {
 			return nil, fmt.Errorf("failed to set status: %s", err)
 		}
+		if targetURL != "" {
+			name := context
+			if name == "" {
+				name = "status"
+			}
+			metadata.Add(name+"_target_url", targetURL)
+		}
+	}
+
+	// Truncate content exceeding the maximum length, and upload the full content as a gist if specified
+	overflow := func(content string, max int, filename string) (string, error) {
+		if len(content) <= max {
+			return content, nil
+		}
+		message := truncatedMessage
+		if request.Params.Overflow == "gist" {
+			url, err := manager.CreateGist(fmt.Sprintf("%s#%s", request.Source.Repository, version.PR), filename, content)
+			if err != nil {
+				return "", fmt.Errorf("failed to create gist: %s", err)
+			}
+			message = fmt.Sprintf(gistMessage, url)
+		}
+		return truncate(content, max, message), nil
+	}
+
+	// Create or update a check run if specified
+	if c := request.Params.CheckRun; c != nil {
+		run := CheckRun{
+			Name:       request.Source.ContextPrefix + c.Name,
+			HeadBranch: metadata.Get("head_name"),
+			Status:     strings.ToLower(c.Status),
+			Conclusion: strings.ToLower(c.Conclusion),
+			DetailsURL: expand.env(c.DetailsURL),
+			Title:      c.Title,
+			Summary:    expand.env(c.Summary),
+		}
+		if run.Status == "" && run.Conclusion != "" {
+			run.Status = "completed"
+		}
+		if run.DetailsURL == "" {
+			run.DetailsURL = buildURL()
+		}
+		if run.Title == "" {
+			run.Title = c.Name
+		}
+
+		// Set summary from a file
+		if c.SummaryFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, c.SummaryFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read check run summary file: %s", err)
+			}
+			run.Summary = expand.env(string(content))
+		}
+
+		// Load annotations from a file
+		if c.AnnotationsFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, c.AnnotationsFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read check run annotations file: %s", err)
+			}
+			run.Annotations, err = ParseAnnotations(content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse check run annotations: %s", err)
+			}
+		}
+
+		run.Summary, err = overflow(run.Summary, maxCheckRunSummaryLength, "summary.md")
+		if err != nil {
+			return nil, fmt.Errorf("failed to update check run: %s", err)
+		}
+
+		id, err := manager.UpdateCheckRun(statusCommit, run)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update check run: %s", err)
+		}
+		metadata.Add("check_run_id", strconv.FormatInt(id, 10))
+	}
+
+	// Create or update a deployment if specified
+	if d := request.Params.Deployment; d != nil {
+		deployment := Deployment{
+			Environment:    expand.all(d.Environment),
+			Description:    expand.env(d.Description),
+			Transient:      d.Transient,
+			Production:     d.Production,
+			State:          strings.ToLower(d.State),
+			EnvironmentURL: expand.all(d.EnvironmentURL),
+			LogURL:         expand.env(d.LogURL),
+		}
+		if deployment.LogURL == "" {
+			deployment.LogURL = buildURL()
+		}
+		if err := manager.UpdateDeployment(version.Commit, deployment); err != nil {
+			return nil, fmt.Errorf("failed to update deployment: %s", err)
+		}
+	}
+
+	// Reopen the pull request if specified (before commenting, so the comment shows up after the event)
+	if request.Params.Reopen {
+		if err := manager.ReopenPullRequest(version.PR); err != nil {
+			return nil, fmt.Errorf("failed to reopen pull request: %s", err)
+		}
+	}
+
+	// React to a comment if specified
+	if p := request.Params; p.ReactToComment != 0 || p.ReactToCommentFile != "" {
+		id := p.ReactToComment
+
+		// Read the comment ID from a file
+		if p.ReactToCommentFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, p.ReactToCommentFile), maxShortInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read react to comment file: %s", err)
+			}
+			id, err = strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse comment id: %s", err)
+			}
+		}
+
+		reaction := p.Reaction
+		if reaction == "" {
+			reaction = "+1"
+		}
+		if err := manager.AddCommentReaction(id, reaction); err != nil {
+			return nil, fmt.Errorf("failed to react to comment: %s", err)
+		}
+
+		// Tidy up the comment once it has been acknowledged
+		if p.DeleteReactedComment {
+			if err := manager.DeleteComment(id); err != nil {
+				return nil, fmt.Errorf("failed to delete comment: %s", err)
+			}
+		}
+		if p.MinimizeReactedComment {
+			if err := manager.MinimizeComment(id); err != nil {
+				return nil, fmt.Errorf("failed to minimize comment: %s", err)
+			}
+		}
+	}
+
+	// Only delete (or minimize) previous comments matching the filter
+	var filter *regexp.Regexp
+	switch p := request.Params; {
+	case p.DeletePreviousCommentsMatching != "":
+		filter = regexp.MustCompile(p.DeletePreviousCommentsMatching)
+	case p.CommentTag != "":
+		filter = regexp.MustCompile(regexp.QuoteMeta(commentMarker(p.CommentTag)))
 	}
 
 	// Delete previous comments if specified
 	if request.Params.DeletePreviousComments {
-		err = manager.DeletePreviousComments(version.PR)
+		err = manager.DeletePreviousComments(version.PR, filter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to delete previous comments: %s", err)
 		}
 	}
 
-	// Set comment if specified
-	if p := request.Params; p.Comment != "" {
-		err = manager.PostComment(version.PR, safeExpandEnv(p.Comment))
+	// Minimize previous comments if specified
+	if request.Params.MinimizePreviousComments {
+		err = manager.MinimizePreviousComments(version.PR, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to minimize previous comments: %s", err)
+		}
+	}
+
+	// Post comments, or update the comment with the same tag if specified
+	postComment := func(comment string) error {
+		var err error
+		marker := ""
+		if tag := request.Params.CommentTag; tag != "" {
+			marker = commentMarker(tag)
+			max := maxCommentLength - len(marker) - 2
+
+			// Only replace the section of the comment if specified, keeping the other sections
+			render := func(content string) string { return content }
+			if section := request.Params.Section; section != "" {
+				previous, err := manager.FindComment(version.PR, marker)
+				if err != nil {
+					return err
+				}
+				sections := parseNamedSections(previous)
+				render = func(content string) string { return sections.with(section, content).String() }
+				max -= len(render(""))
+			}
+			comment, err = overflow(comment, max, "comment.md")
+			comment = render(comment) + "\n\n" + marker
+		} else {
+			comment, err = overflow(comment, maxCommentLength, "comment.md")
+		}
+		if err != nil {
+			return err
+		}
+
+		// Skip the comment if one was posted for the same context within the cooldown, and otherwise
+		// record when it was posted
+		if p := request.Params; p.CommentCooldown != "" {
+			key := cooldownKey(p, baseContext)
+			previous, err := manager.FindComment(version.PR, cooldownMarkerPrefix(key))
+			if err != nil {
+				return err
+			}
+			cooldown, _ := time.ParseDuration(p.CommentCooldown)
+			if posted, ok := parseCooldownMarker(previous, key); ok && time.Since(posted) < cooldown {
+				logger.Info("comment was posted within the cooldown, skipping", "pr", version.PR, "context", key, "posted", posted)
+				return nil
+			}
+			comment += "\n" + cooldownMarker(key, time.Now())
+		}
+
+		// Skip the comment if it is identical to the last one we posted
+		if request.Params.SkipDuplicateComments {
+			latest, err := manager.GetLatestComment(version.PR)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(latest) == strings.TrimSpace(comment) {
+				return nil
+			}
+		}
+
+		var commentURL string
+		if marker != "" {
+			commentURL, err = manager.UpsertComment(version.PR, marker, comment)
+		} else {
+			commentURL, err = manager.PostComment(version.PR, comment)
+		}
+		if err != nil {
+			return err
+		}
+		metadata.Add("comment_url", commentURL)
+		return nil
+	}
+
+	// Set comment if specified, followed by any collapsible sections
+	if p := request.Params; p.Comment != "" || len(p.CommentSections) > 0 {
+		comment := expand.env(p.Comment)
+		for _, section := range p.CommentSections {
+			content, err := readCommentFiles(inputDir, section.File)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read comment section file: %s", err)
+			}
+			if comment != "" {
+				comment += "\n\n"
+			}
+			comment += commentSection(expand.env(section.Summary), content, section)
+		}
+		err = postComment(comment)
 		if err != nil {
 			return nil, fmt.Errorf("failed to post comment: %s", err)
 		}
@@ -72,81 +454,1395 @@ func Put(request PutRequest, manager Github, inputDir string) (*PutResponse, err
 
 	// Set comment from a file
 	if p := request.Params; p.CommentFile != "" {
-		content, err := ioutil.ReadFile(filepath.Join(inputDir, p.CommentFile))
+		comment, err := readCommentFiles(inputDir, p.CommentFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read comment file: %s", err)
 		}
-		comment := string(content)
 		if comment != "" {
-			err = manager.PostComment(version.PR, safeExpandEnv(comment))
+			err = postComment(expand.env(comment))
 			if err != nil {
 				return nil, fmt.Errorf("failed to post comment: %s", err)
 			}
 		}
 	}
 
-	return &PutResponse{
-		Version:  version,
-		Metadata: metadata,
-	}, nil
-}
+	// Render a comment template if specified
+	if p := request.Params; p.CommentTemplate != "" || p.CommentTemplateFile != "" {
+		text := p.CommentTemplate
 
-// PutRequest ...
-type PutRequest struct {
-	Source Source        `json:"source"`
-	Params PutParameters `json:"params"`
-}
+		// Read the template from a file
+		if p.CommentTemplateFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, p.CommentTemplateFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read comment template file: %s", err)
+			}
+			text = string(content)
+		}
 
-// PutResponse ...
-type PutResponse struct {
-	Version  Version  `json:"version"`
-	Metadata Metadata `json:"metadata,omitempty"`
-}
+		comment, err := renderTemplate(text, newTemplateData(request, version, metadata), inputDir)
+		if err != nil {
+			return nil, err
+		}
+		if comment != "" {
+			if err := postComment(comment); err != nil {
+				return nil, fmt.Errorf("failed to post comment: %s", err)
+			}
+		}
+	}
 
-// PutParameters for the resource.
-type PutParameters struct {
-	Path                   string `json:"path"`
-	BaseContext            string `json:"base_context"`
-	Context                string `json:"context"`
-	TargetURL              string `json:"target_url"`
-	DescriptionFile        string `json:"description_file"`
-	Description            string `json:"description"`
-	Status                 string `json:"status"`
-	CommentFile            string `json:"comment_file"`
-	Comment                string `json:"comment"`
-	DeletePreviousComments bool   `json:"delete_previous_comments"`
-}
+	// Comment on the commit of the version if specified
+	if p := request.Params; p.CommitComment != "" || p.CommitCommentFile != "" {
+		comment := p.CommitComment
 
-// Validate the put parameters.
-func (p *PutParameters) Validate() error {
-	if p.Status == "" {
-		return nil
+		// Set comment from a file
+		if p.CommitCommentFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, p.CommitCommentFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read commit comment file: %s", err)
+			}
+			comment = string(content)
+		}
+
+		comment, err = overflow(expand.env(comment), maxCommentLength, "comment.md")
+		if err != nil {
+			return nil, fmt.Errorf("failed to post commit comment: %s", err)
+		}
+		commentURL, err := manager.CreateCommitComment(version.Commit, comment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to post commit comment: %s", err)
+		}
+		metadata.Add("commit_comment_url", commentURL)
 	}
-	// Make sure we are setting an allowed status
-	var allowedStatus bool
 
-	status := strings.ToLower(p.Status)
-	allowed := []string{"success", "pending", "failure", "error"}
+	// Update the title and/or body of the pull request if specified
+	if p := request.Params; p.Title != "" || p.Body != "" || p.BodyFile != "" {
+		body := p.Body
 
-	for _, a := range allowed {
-		if status == a {
-			allowedStatus = true
+		// Set body from a file
+		if p.BodyFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, p.BodyFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read body file: %s", err)
+			}
+			body = string(content)
+		}
+		body = expand.all(body)
+
+		if body != "" && (p.BodyMode == "append" || p.BodyMode == "prepend") {
+			details, err := manager.GetPullRequestDetails(version.PR)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pull request details: %s", err)
+			}
+			if p.BodyMode == "append" {
+				body = details.Body + "\n\n" + body
+			} else {
+				body = body + "\n\n" + details.Body
+			}
+		}
+
+		if err := manager.UpdatePullRequest(version.PR, expand.all(p.Title), body); err != nil {
+			return nil, fmt.Errorf("failed to update pull request: %s", err)
 		}
 	}
 
-	if !allowedStatus {
-		return fmt.Errorf("unknown status: %s", p.Status)
+	// Set milestone if specified
+	if p := request.Params; p.Milestone != "" {
+		if err := manager.SetMilestone(version.PR, expand.all(p.Milestone), p.CreateMilestone); err != nil {
+			return nil, fmt.Errorf("failed to set milestone: %s", err)
+		}
 	}
 
-	return nil
-}
+	// Add and remove labels if specified, including those for the status (if any)
+	if p := request.Params; len(p.AddLabels) > 0 || len(p.RemoveLabels) > 0 || len(p.StatusLabels) > 0 {
+		add, remove := p.AddLabels, p.RemoveLabels
+		if l, ok := p.StatusLabels[strings.ToLower(p.Status)]; ok {
+			add = append(append([]string{}, add...), l.Add...)
+			remove = append(append([]string{}, remove...), l.Remove...)
+		}
+		if len(remove) > 0 {
+			if err := manager.RemoveLabels(version.PR, remove); err != nil {
+				return nil, fmt.Errorf("failed to remove labels: %s", err)
+			}
+		}
+		if len(add) > 0 {
+			if err := manager.AddLabels(version.PR, add); err != nil {
+				return nil, fmt.Errorf("failed to add labels: %s", err)
+			}
+		}
+	}
 
-func safeExpandEnv(s string) string {
-	return os.Expand(s, func(v string) string {
-		switch v {
-		case "BUILD_ID", "BUILD_NAME", "BUILD_JOB_NAME", "BUILD_PIPELINE_NAME", "BUILD_TEAM_NAME", "ATC_EXTERNAL_URL":
-			return os.Getenv(v)
+	// Add assignees if specified
+	if p := request.Params; len(p.Assignees) > 0 {
+		var assignees []string
+		for _, a := range p.Assignees {
+			// $AUTHOR is the author of the pull request (not the commit)
+			if a == "$AUTHOR" || a == "${AUTHOR}" {
+				details, err := manager.GetPullRequestDetails(version.PR)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get pull request details: %s", err)
+				}
+				a = details.Author.Login
+			}
+			assignees = append(assignees, a)
 		}
-		return "$" + v
-	})
+		if err := manager.AddAssignees(version.PR, assignees); err != nil {
+			return nil, fmt.Errorf("failed to add assignees: %s", err)
+		}
+	}
+
+	// Add the pull request to a project if specified
+	if pr := request.Params.Project; pr != nil {
+		fields := make(map[string]string, len(pr.Fields))
+		for name, value := range pr.Fields {
+			fields[name] = expand.all(value)
+		}
+		project := Project{
+			Owner:  pr.Owner,
+			Number: pr.Number,
+			Fields: fields,
+		}
+		if err := manager.AddToProject(version.PR, project); err != nil {
+			return nil, fmt.Errorf("failed to add pull request to project: %s", err)
+		}
+	}
+
+	// Submit a review if specified
+	if p := request.Params; p.Review != "" {
+		body := p.ReviewBody
+
+		// Set review body from a file
+		if p.ReviewBodyFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, p.ReviewBodyFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read review body file: %s", err)
+			}
+			body = string(content)
+		}
+
+		if err := manager.CreateReview(version.PR, version.Commit, strings.ToUpper(p.Review), expand.env(body)); err != nil {
+			return nil, fmt.Errorf("failed to submit review: %s", err)
+		}
+	}
+
+	// Suggest changes from a diff if specified
+	if p := request.Params; p.SuggestionsFile != "" {
+		diff, err := readInputFile(filepath.Join(inputDir, p.SuggestionsFile), maxInputFileSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read suggestions file: %s", err)
+		}
+		suggestions, err := ParseSuggestions(diff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse suggestions: %s", err)
+		}
+		if len(suggestions) > 0 {
+			if err := manager.CreateSuggestions(version.PR, version.Commit, expand.env(p.SuggestionsBody), suggestions); err != nil {
+				return nil, fmt.Errorf("failed to post suggestions: %s", err)
+			}
+		}
+	}
+
+	// Reply in a review thread if specified
+	if r := request.Params.ReviewReply; r != nil {
+		body := r.Body
+		if r.BodyFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, r.BodyFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read review reply body file: %s", err)
+			}
+			body = string(content)
+		}
+
+		id := r.CommentID
+		switch {
+		case r.CommentIDFile != "":
+			content, err := readInputFile(filepath.Join(inputDir, r.CommentIDFile), maxShortInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read review reply comment id file: %s", err)
+			}
+			id, err = strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse comment id: %s", err)
+			}
+		case r.Path != "":
+			id, err = manager.FindReviewThread(version.PR, r.Path, r.Line)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find review thread: %s", err)
+			}
+		}
+
+		if err := manager.ReplyToReviewComment(version.PR, id, expand.env(body)); err != nil {
+			return nil, fmt.Errorf("failed to reply to review comment: %s", err)
+		}
+	}
+
+	// Dismiss approving reviews if specified
+	if p := request.Params; p.DismissReviews {
+		message := expand.all(p.DismissReviewsMessage)
+		if message == "" {
+			message = "Dismissed by Concourse CI"
+		}
+		if err := manager.DismissReviews(version.PR, message); err != nil {
+			return nil, fmt.Errorf("failed to dismiss reviews: %s", err)
+		}
+	}
+
+	// Convert between draft and ready for review if specified (before requesting reviewers, so they are notified)
+	if request.Params.MarkReady {
+		if err := manager.MarkReadyForReview(version.PR); err != nil {
+			return nil, fmt.Errorf("failed to mark pull request as ready for review: %s", err)
+		}
+	}
+	if request.Params.MarkDraft {
+		if err := manager.ConvertToDraft(version.PR); err != nil {
+			return nil, fmt.Errorf("failed to convert pull request to draft: %s", err)
+		}
+	}
+
+	// Request reviewers if specified, including the code owners of the changed files
+	if p := request.Params; len(p.RequestReviewers) > 0 || len(p.RequestTeamReviewers) > 0 || p.RequestCodeowners {
+		reviewers, teams := p.RequestReviewers, p.RequestTeamReviewers
+		if p.RequestCodeowners {
+			users, owningTeams, err := codeownersReviewers(manager, version)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve codeowners: %s", err)
+			}
+			reviewers = append(append([]string{}, reviewers...), users...)
+			teams = append(append([]string{}, teams...), owningTeams...)
+		}
+		if len(reviewers) > 0 || len(teams) > 0 {
+			if err := manager.RequestReviewers(version.PR, reviewers, teams); err != nil {
+				return nil, fmt.Errorf("failed to request reviewers: %s", err)
+			}
+		}
+	}
+
+	// Merge the pull request if specified
+	if m := request.Params.Merge; m != nil {
+		if m.RequireMergeable || m.RequireApproved {
+			details, err := manager.GetPullRequestDetails(version.PR)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pull request details: %s", err)
+			}
+			if m.RequireMergeable && details.Mergeable != githubv4.MergeableStateMergeable {
+				return nil, fmt.Errorf("pull request is not mergeable: %s", details.Mergeable)
+			}
+			if m.RequireApproved && (details.ReviewDecision == nil || *details.ReviewDecision != githubv4.PullRequestReviewDecisionApproved) {
+				return nil, errors.New("pull request has not been approved")
+			}
+		}
+
+		message := m.CommitMessage
+
+		// Set commit message from a file
+		if m.CommitMessageFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, m.CommitMessageFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read merge commit message file: %s", err)
+			}
+			message = string(content)
+		}
+
+		title := expand.all(m.CommitTitle)
+		message = expand.all(message)
+		sha, err := manager.MergePullRequest(version.PR, version.Commit, strings.ToLower(m.Method), title, message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge pull request: %s", err)
+		}
+		metadata.Add("merge_commit_sha", sha)
+	}
+
+	// Enable auto-merge if specified
+	if p := request.Params; p.EnableAutoMerge {
+		if err := manager.EnableAutoMerge(version.PR, strings.ToUpper(p.AutoMergeMethod)); err != nil {
+			return nil, fmt.Errorf("failed to enable auto-merge: %s", err)
+		}
+	}
+
+	// Update the branch with the base branch if specified
+	if p := request.Params; p.UpdateBranch {
+		if err := manager.UpdateBranch(version.PR, version.Commit, strings.ToUpper(p.UpdateBranchMethod)); err != nil {
+			// Explain the failure if the branch cannot be updated because the fork has been deleted
+			if details, detailsErr := manager.GetPullRequestDetails(version.PR); detailsErr == nil && details.HeadRepository == nil {
+				return nil, errors.New("failed to update branch: the head repository of the pull request has been deleted")
+			}
+			return nil, fmt.Errorf("failed to update branch: %s", err)
+		}
+	}
+
+	// Delete the head branch of a merged pull request if specified
+	if request.Params.DeleteBranch {
+		details, err := manager.GetPullRequestDetails(version.PR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request details: %s", err)
+		}
+		if details.State != githubv4.PullRequestStateMerged {
+			return nil, fmt.Errorf("refusing to delete branch of pull request that has not been merged: %s", details.State)
+		}
+		// Branches in forks are left alone, since we (most likely) do not have access to them.
+		if details.HeadRepository != nil && strings.EqualFold(details.HeadRepository.NameWithOwner, request.Source.Repository) {
+			if err := manager.DeleteBranch(details.HeadRefName); err != nil {
+				return nil, fmt.Errorf("failed to delete branch: %s", err)
+			}
+		}
+	}
+
+	// Tag the merge commit (and create a release) if specified
+	if t := request.Params.Tag; t != nil {
+		details, err := manager.GetPullRequestDetails(version.PR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request details: %s", err)
+		}
+		if details.State != githubv4.PullRequestStateMerged || details.MergeCommit == nil {
+			return nil, fmt.Errorf("refusing to tag pull request that has not been merged: %s", details.State)
+		}
+
+		name := expand.all(t.Name)
+		message := expand.all(t.Message)
+		if err := manager.CreateTag(name, details.MergeCommit.Oid, message); err != nil {
+			return nil, fmt.Errorf("failed to create tag: %s", err)
+		}
+
+		if t.Release {
+			release := Release{
+				TagName:    name,
+				Name:       expand.all(t.ReleaseName),
+				Body:       expand.all(t.ReleaseNotes),
+				Draft:      t.Draft,
+				Prerelease: t.Prerelease,
+			}
+			if release.Name == "" {
+				release.Name = name
+			}
+
+			// Set release notes from a file
+			if t.ReleaseNotesFile != "" {
+				content, err := readInputFile(filepath.Join(inputDir, t.ReleaseNotesFile), maxInputFileSize)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read release notes file: %s", err)
+				}
+				release.Body = string(content)
+			}
+
+			if err := manager.CreateRelease(release); err != nil {
+				return nil, fmt.Errorf("failed to create release: %s", err)
+			}
+		}
+	}
+
+	// Send a repository_dispatch event if specified. The payload identifies the pull request, and can be
+	// extended with a JSON object from a file and values which can use the variables from metadata.env.
+	if d := request.Params.Dispatch; d != nil {
+		payload := map[string]interface{}{"pr": version.PR, "commit": version.Commit}
+		if d.ClientPayloadFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, d.ClientPayloadFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read dispatch client payload file: %s", err)
+			}
+			var values map[string]interface{}
+			if err := json.Unmarshal(content, &values); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal dispatch client payload file (must be a JSON object): %s", err)
+			}
+			for k, v := range values {
+				payload[k] = v
+			}
+		}
+		for k, v := range d.ClientPayload {
+			payload[k] = expand.all(v)
+		}
+		// The API rejects payloads with more top-level properties
+		if len(payload) > maxDispatchPayloadProperties {
+			return nil, fmt.Errorf("dispatch client payload has %d top-level properties (at most %d are allowed)", len(payload), maxDispatchPayloadProperties)
+		}
+		if err := manager.Dispatch(expand.all(d.EventType), payload); err != nil {
+			return nil, fmt.Errorf("failed to send repository dispatch: %s", err)
+		}
+	}
+
+	// Comment on (and close) the issues linked to the pull request if specified
+	if p := request.Params; p.LinkedIssuesComment != "" || p.LinkedIssuesCommentFile != "" || p.CloseLinkedIssues {
+		comment := p.LinkedIssuesComment
+		if p.LinkedIssuesCommentFile != "" {
+			content, err := readInputFile(filepath.Join(inputDir, p.LinkedIssuesCommentFile), maxInputFileSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read linked issues comment file: %s", err)
+			}
+			comment = string(content)
+		}
+		comment = expand.all(comment)
+
+		if p.CloseLinkedIssues {
+			details, err := manager.GetPullRequestDetails(version.PR)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pull request details: %s", err)
+			}
+			if details.State != githubv4.PullRequestStateMerged {
+				return nil, fmt.Errorf("refusing to close linked issues of pull request that has not been merged: %s", details.State)
+			}
+		}
+
+		issues, err := manager.GetLinkedIssues(version.PR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get linked issues: %s", err)
+		}
+		for _, issue := range issues {
+			if comment != "" {
+				if err := manager.CommentOnIssue(issue.ID, comment); err != nil {
+					return nil, fmt.Errorf("failed to comment on issue #%d: %s", issue.Number, err)
+				}
+			}
+			if p.CloseLinkedIssues && issue.State != githubv4.IssueStateClosed {
+				if err := manager.CloseIssue(issue.ID); err != nil {
+					return nil, fmt.Errorf("failed to close issue #%d: %s", issue.Number, err)
+				}
+			}
+		}
+	}
+
+	// Lock or unlock the conversation if specified
+	if p := request.Params; p.Lock != nil {
+		if *p.Lock {
+			err = manager.LockPullRequest(version.PR, p.LockReason)
+		} else {
+			err = manager.UnlockPullRequest(version.PR)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock or unlock pull request: %s", err)
+		}
+	}
+
+	// Close the pull request if specified (after commenting, so the comment can explain why)
+	if request.Params.Close {
+		if err := manager.ClosePullRequest(version.PR); err != nil {
+			return nil, fmt.Errorf("failed to close pull request: %s", err)
+		}
+	}
+
+	if !request.Source.UpstreamCompatible {
+		manager.APIUsage().AddTo(&metadata)
+	}
+	return &PutResponse{
+		Version:  version,
+		Metadata: metadata,
+	}, nil
+}
+
+// PutRequest ...
+type PutRequest struct {
+	Source Source        `json:"source"`
+	Params PutParameters `json:"params"`
+}
+
+// PutResponse ...
+type PutResponse struct {
+	Version  Version  `json:"version"`
+	Metadata Metadata `json:"metadata,omitempty"`
+}
+
+// PutParameters for the resource.
+type PutParameters struct {
+	Path                           string                     `json:"path"`
+	PRNumber                       string                     `json:"pr_number"`
+	Commit                         string                     `json:"commit"`
+	VersionFile                    string                     `json:"version_file"`
+	Version                        *Version                   `json:"version"`
+	CommitSHA                      string                     `json:"commit_sha"`
+	CommitFile                     string                     `json:"commit_file"`
+	Vars                           map[string]string          `json:"vars"`
+	BaseContext                    string                     `json:"base_context"`
+	Context                        string                     `json:"context"`
+	TargetURL                      string                     `json:"target_url"`
+	DescriptionFile                string                     `json:"description_file"`
+	Description                    string                     `json:"description"`
+	DescriptionTruncationSuffix    string                     `json:"description_truncation_suffix"`
+	Status                         string                     `json:"status"`
+	StatusFile                     string                     `json:"status_file"`
+	SkipUnchangedStatus            bool                       `json:"skip_unchanged_status"`
+	FailIfOutdated                 bool                       `json:"fail_if_outdated"`
+	Statuses                       []StatusParameters         `json:"statuses"`
+	CommentFile                    string                     `json:"comment_file"`
+	CommentSections                []CommentSectionParameters `json:"comment_sections"`
+	Comment                        string                     `json:"comment"`
+	CommentTemplate                string                     `json:"comment_template"`
+	CommentTemplateFile            string                     `json:"comment_template_file"`
+	CommentTag                     string                     `json:"comment_tag"`
+	Section                        string                     `json:"section"`
+	SkipDuplicateComments          bool                       `json:"skip_duplicate_comments"`
+	CommentCooldown                string                     `json:"comment_cooldown"`
+	CommitComment                  string                     `json:"commit_comment"`
+	CommitCommentFile              string                     `json:"commit_comment_file"`
+	Overflow                       string                     `json:"overflow"`
+	ReactToComment                 int64                      `json:"react_to_comment"`
+	ReactToCommentFile             string                     `json:"react_to_comment_file"`
+	Reaction                       string                     `json:"reaction"`
+	DeleteReactedComment           bool                       `json:"delete_reacted_comment"`
+	MinimizeReactedComment         bool                       `json:"minimize_reacted_comment"`
+	DeletePreviousComments         bool                       `json:"delete_previous_comments"`
+	DeletePreviousCommentsMatching string                     `json:"delete_previous_comments_matching"`
+	MinimizePreviousComments       bool                       `json:"minimize_previous_comments"`
+	CheckRun                       *CheckRunParameters        `json:"check_run"`
+	Deployment                     *DeploymentParameters      `json:"deployment"`
+	RequestReviewers               []string                   `json:"request_reviewers"`
+	RequestTeamReviewers           []string                   `json:"request_team_reviewers"`
+	RequestCodeowners              bool                       `json:"request_codeowners"`
+	Title                          string                     `json:"title"`
+	Body                           string                     `json:"body"`
+	BodyFile                       string                     `json:"body_file"`
+	BodyMode                       string                     `json:"body_mode"`
+	Milestone                      string                     `json:"milestone"`
+	CreateMilestone                bool                       `json:"create_milestone"`
+	Assignees                      []string                   `json:"assignees"`
+	AddLabels                      []string                   `json:"add_labels"`
+	RemoveLabels                   []string                   `json:"remove_labels"`
+	StatusLabels                   map[string]LabelParameters `json:"status_labels"`
+	Project                        *ProjectParameters         `json:"project"`
+	Review                         string                     `json:"review"`
+	ReviewBody                     string                     `json:"review_body"`
+	ReviewBodyFile                 string                     `json:"review_body_file"`
+	ReviewReply                    *ReviewReplyParameters     `json:"review_reply"`
+	SuggestionsFile                string                     `json:"suggestions_file"`
+	SuggestionsBody                string                     `json:"suggestions_body"`
+	DismissReviews                 bool                       `json:"dismiss_reviews"`
+	DismissReviewsMessage          string                     `json:"dismiss_reviews_message"`
+	Merge                          *MergeParameters           `json:"merge"`
+	EnableAutoMerge                bool                       `json:"enable_auto_merge"`
+	AutoMergeMethod                string                     `json:"auto_merge_method"`
+	UpdateBranch                   bool                       `json:"update_branch"`
+	UpdateBranchMethod             string                     `json:"update_branch_method"`
+	MarkReady                      bool                       `json:"mark_ready"`
+	MarkDraft                      bool                       `json:"mark_draft"`
+	Close                          bool                       `json:"close"`
+	Reopen                         bool                       `json:"reopen"`
+	Lock                           *bool                      `json:"lock"`
+	LockReason                     string                     `json:"lock_reason"`
+	DeleteBranch                   bool                       `json:"delete_branch"`
+	LinkedIssuesComment            string                     `json:"linked_issues_comment"`
+	LinkedIssuesCommentFile        string                     `json:"linked_issues_comment_file"`
+	CloseLinkedIssues              bool                       `json:"close_linked_issues"`
+	Tag                            *TagParameters             `json:"tag"`
+	Dispatch                       *DispatchParameters        `json:"dispatch"`
+	AuditFile                      string                     `json:"audit_file"`
+	AuditLog                       bool                       `json:"audit_log"`
+}
+
+// MergeParameters for merging the pull request.
+type MergeParameters struct {
+	Method            string `json:"method"`
+	CommitTitle       string `json:"commit_title"`
+	CommitMessage     string `json:"commit_message"`
+	CommitMessageFile string `json:"commit_message_file"`
+	RequireMergeable  bool   `json:"require_mergeable"`
+	RequireApproved   bool   `json:"require_approved"`
+}
+
+// mapStatuses reads the status from status_file (if set), and translates statuses using the status_map
+// from the source. The description of a translated status is annotated with the original status.
+func (p *PutParameters) mapStatuses(statusMap map[string]string, inputDir string) error {
+	if p.StatusFile != "" {
+		if p.Status != "" {
+			return errors.New("status and status_file are mutually exclusive")
+		}
+		content, err := readInputFile(filepath.Join(inputDir, p.StatusFile), maxShortInputFileSize)
+		if err != nil {
+			return fmt.Errorf("failed to read status file: %s", err)
+		}
+		p.Status = strings.TrimSpace(string(content))
+	}
+
+	mapStatus := func(status, description string, hasDescriptionFile bool) (string, string) {
+		for from, to := range statusMap {
+			if !strings.EqualFold(from, status) {
+				continue
+			}
+			if !hasDescriptionFile {
+				if description == "" {
+					description = fmt.Sprintf("Concourse CI build %s", strings.ToLower(status))
+				} else {
+					description = fmt.Sprintf("%s: %s", strings.ToLower(status), description)
+				}
+			}
+			return to, description
+		}
+		return status, description
+	}
+	p.Status, p.Description = mapStatus(p.Status, p.Description, p.DescriptionFile != "")
+
+	statuses := make([]StatusParameters, len(p.Statuses))
+	for i, s := range p.Statuses {
+		s.Status, s.Description = mapStatus(s.Status, s.Description, s.DescriptionFile != "")
+		statuses[i] = s
+	}
+	if p.Statuses != nil {
+		p.Statuses = statuses
+	}
+	return nil
+}
+
+// inputPath returns the path of the input with the version, which is discovered if path is not set
+// and there is exactly one input from a get step of this resource.
+func inputPath(p PutParameters, inputDir string) (string, error) {
+	if p.Path != "" || p.PRNumber != "" || p.VersionFile != "" {
+		return p.Path, nil
+	}
+	if _, err := os.Stat(filepath.Join(inputDir, ".git", "resource", "version.json")); err == nil {
+		return "", nil
+	}
+	_, resources := resourceInputs(inputDir)
+	if len(resources) > 1 {
+		return "", fmt.Errorf("path must be set, since there are several inputs from a get step of this resource: %s", strings.Join(resources, ", "))
+	}
+	if len(resources) == 1 {
+		logger.Debug("discovered path", "path", resources[0])
+		return resources[0], nil
+	}
+	return "", nil
+}
+
+// resourceInputs returns the names of all inputs, and of those which were fetched by a get step of this resource.
+func resourceInputs(inputDir string) (inputs, resources []string) {
+	entries, err := ioutil.ReadDir(inputDir)
+	if err != nil {
+		return nil, nil
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		inputs = append(inputs, e.Name())
+		if _, err := os.Stat(filepath.Join(inputDir, e.Name(), ".git", "resource", "version.json")); err == nil {
+			resources = append(resources, e.Name())
+		}
+	}
+	return inputs, resources
+}
+
+// missingVersionError explains why the version could not be read from the path, by listing the inputs
+// which were fetched by a get step of this resource (or all inputs, if there are none).
+func missingVersionError(inputDir string, err error) error {
+	msg := fmt.Sprintf("failed to read version from path: %s", err)
+	if !os.IsNotExist(err) {
+		return errors.New(msg)
+	}
+
+	inputs, resources := resourceInputs(inputDir)
+	switch {
+	case len(resources) == 1:
+		msg += fmt.Sprintf(" (did you mean path: %s?)", resources[0])
+	case len(resources) > 1:
+		msg += fmt.Sprintf(" (path must be one of the inputs from a get step of this resource: %s)", strings.Join(resources, ", "))
+	case len(inputs) > 0:
+		msg += fmt.Sprintf(" (none of the inputs are from a get step of this resource: %s)", strings.Join(inputs, ", "))
+	default:
+		msg += " (there are no inputs, use pr_number or version_file without a get step)"
+	}
+	return errors.New(msg)
+}
+
+// versionFromParams looks up the version and metadata of the pull request given by the
+// pr_number and commit (or version_file) parameters. The version is the same as the one
+// returned by check (which would otherwise be a new version).
+func versionFromParams(p PutParameters, source Source, manager Github, inputDir string) (Version, Metadata, error) {
+	version := Version{PR: p.PRNumber, Commit: p.Commit}
+	if p.VersionFile != "" {
+		content, err := readInputFile(filepath.Join(inputDir, p.VersionFile), maxShortInputFileSize)
+		if err != nil {
+			return Version{}, nil, fmt.Errorf("failed to read version file: %s", err)
+		}
+		if err := json.Unmarshal(content, &version); err != nil {
+			return Version{}, nil, fmt.Errorf("failed to unmarshal version from file: %s", err)
+		}
+	}
+
+	// Default to the head of the pull request
+	if version.Commit == "" {
+		details, err := manager.GetPullRequestDetails(version.PR)
+		if err != nil {
+			return Version{}, nil, fmt.Errorf("failed to get pull request details: %s", err)
+		}
+		version.Commit = details.HeadRefOid
+	}
+
+	pull, err := manager.GetPullRequest(version.PR, version.Commit)
+	if err != nil {
+		return Version{}, nil, fmt.Errorf("failed to retrieve pull request: %s", err)
+	}
+
+	// The approving reviews and the readiness to merge are only fetched when listing pull requests,
+	// so the pull request is listed the same way check does. Unless readiness is needed, only the
+	// pull requests updated since this one are listed.
+	listed := pull
+	if source.fetches("reviews") || source.WhenReadyToMerge {
+		since := pull.UpdatedAt.Add(-time.Second)
+		if source.WhenReadyToMerge {
+			since = time.Time{}
+		}
+		pulls, err := manager.ListPullRequests([]githubv4.PullRequestState{pull.State}, PullRequestFields{
+			Reviews:   true,
+			Readiness: source.WhenReadyToMerge,
+		}, since)
+		if err != nil {
+			return Version{}, nil, fmt.Errorf("failed to list pull requests: %s", err)
+		}
+		for _, p := range pulls {
+			if p.Number == pull.Number && p.Tip.OID == pull.Tip.OID {
+				listed = p
+			}
+		}
+	}
+	return newVersion(listed, source), newMetadata(pull, ""), nil
+}
+
+// TagParameters for tagging the merge commit of a pull request.
+type TagParameters struct {
+	Name             string `json:"name"`
+	Message          string `json:"message"`
+	Release          bool   `json:"release"`
+	ReleaseName      string `json:"release_name"`
+	ReleaseNotes     string `json:"release_notes"`
+	ReleaseNotesFile string `json:"release_notes_file"`
+	Draft            bool   `json:"draft"`
+	Prerelease       bool   `json:"prerelease"`
+}
+
+// DispatchParameters for sending a repository_dispatch event (e.g. to trigger a Github Actions workflow).
+type DispatchParameters struct {
+	EventType         string            `json:"event_type"`
+	ClientPayload     map[string]string `json:"client_payload"`
+	ClientPayloadFile string            `json:"client_payload_file"`
+}
+
+// StatusParameters for setting one of multiple commit statuses.
+type StatusParameters struct {
+	Context         string `json:"context"`
+	Status          string `json:"status"`
+	TargetURL       string `json:"target_url"`
+	Description     string `json:"description"`
+	DescriptionFile string `json:"description_file"`
+}
+
+// CheckRunParameters for creating or updating a check run.
+type CheckRunParameters struct {
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	Conclusion      string `json:"conclusion"`
+	DetailsURL      string `json:"details_url"`
+	Title           string `json:"title"`
+	Summary         string `json:"summary"`
+	SummaryFile     string `json:"summary_file"`
+	AnnotationsFile string `json:"annotations_file"`
+}
+
+// DeploymentParameters for creating a deployment (or updating its status).
+type DeploymentParameters struct {
+	Environment    string `json:"environment"`
+	State          string `json:"state"`
+	Description    string `json:"description"`
+	EnvironmentURL string `json:"environment_url"`
+	LogURL         string `json:"log_url"`
+	Transient      bool   `json:"transient_environment"`
+	Production     bool   `json:"production_environment"`
+}
+
+// CommentSectionParameters for a collapsible section in a comment.
+type CommentSectionParameters struct {
+	File     string `json:"file"`
+	Summary  string `json:"summary"`
+	Language string `json:"language"`
+	Open     bool   `json:"open"`
+}
+
+// LabelParameters for labels to add to (and remove from) the pull request.
+type LabelParameters struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// ProjectParameters for adding the pull request to a project (v2).
+type ProjectParameters struct {
+	Owner  string            `json:"owner"`
+	Number int               `json:"number"`
+	Fields map[string]string `json:"fields"`
+}
+
+// ReviewReplyParameters for replying in a review thread, which is identified either
+// by the ID of a comment in the thread or by the path and line it is on.
+type ReviewReplyParameters struct {
+	CommentID     int64  `json:"comment_id"`
+	CommentIDFile string `json:"comment_id_file"`
+	Path          string `json:"path"`
+	Line          int    `json:"line"`
+	Body          string `json:"body"`
+	BodyFile      string `json:"body_file"`
+}
+
+// Validate the review reply parameters.
+func (p *ReviewReplyParameters) Validate() error {
+	if p.Body == "" && p.BodyFile == "" {
+		return errors.New("review_reply.body or review_reply.body_file must be set")
+	}
+	n := 0
+	for _, set := range []bool{p.CommentID != 0, p.CommentIDFile != "", p.Path != ""} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New("exactly one of review_reply.comment_id, review_reply.comment_id_file or review_reply.path must be set")
+	}
+	if p.Path != "" && p.Line <= 0 {
+		return errors.New("review_reply.line must be set together with review_reply.path")
+	}
+	return nil
+}
+
+// Validate the deployment parameters.
+func (p *DeploymentParameters) Validate() error {
+	if p.Environment == "" {
+		return errors.New("deployment.environment must be set")
+	}
+	if !contains([]string{"error", "failure", "inactive", "in_progress", "queued", "pending", "success"}, strings.ToLower(p.State)) {
+		return fmt.Errorf("unknown deployment state: %s", p.State)
+	}
+	return nil
+}
+
+// Validate the check run parameters.
+func (p *CheckRunParameters) Validate() error {
+	if p.Name == "" {
+		return errors.New("check_run.name must be set")
+	}
+	if p.Status != "" && !contains([]string{"queued", "in_progress", "completed"}, strings.ToLower(p.Status)) {
+		return fmt.Errorf("unknown check run status: %s", p.Status)
+	}
+	if p.Conclusion != "" && !contains([]string{"success", "failure", "neutral", "cancelled", "skipped", "timed_out", "action_required"}, strings.ToLower(p.Conclusion)) {
+		return fmt.Errorf("unknown check run conclusion: %s", p.Conclusion)
+	}
+	if strings.EqualFold(p.Status, "completed") && p.Conclusion == "" {
+		return errors.New("check_run.conclusion must be set for completed check runs")
+	}
+	return nil
+}
+
+// Validate the put parameters.
+func (p *PutParameters) Validate() error {
+	if p.CheckRun != nil {
+		if err := p.CheckRun.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, section := range p.CommentSections {
+		if section.File == "" {
+			return errors.New("comment_sections[].file must be set")
+		}
+	}
+	for status := range p.StatusLabels {
+		if !contains([]string{"success", "pending", "failure", "error"}, status) {
+			return fmt.Errorf("unknown status in status_labels: %s", status)
+		}
+	}
+	if len(p.StatusLabels) > 0 && p.Status == "" {
+		return errors.New("status must be set when using status_labels")
+	}
+	if p.Project != nil && p.Project.Number <= 0 {
+		return errors.New("project.number must be set")
+	}
+	if p.ReviewReply != nil {
+		if err := p.ReviewReply.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Deployment != nil {
+		if err := p.Deployment.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Merge != nil && p.Merge.Method != "" && !contains([]string{"merge", "squash", "rebase"}, strings.ToLower(p.Merge.Method)) {
+		return fmt.Errorf("unknown merge method: %s", p.Merge.Method)
+	}
+	if p.DeletePreviousCommentsMatching != "" {
+		if _, err := regexp.Compile(p.DeletePreviousCommentsMatching); err != nil {
+			return fmt.Errorf("invalid delete_previous_comments_matching: %s", err)
+		}
+	}
+	if p.Overflow != "" && !contains([]string{"truncate", "gist"}, p.Overflow) {
+		return fmt.Errorf("unknown overflow: %s", p.Overflow)
+	}
+	if p.BodyMode != "" && !contains([]string{"replace", "append", "prepend"}, p.BodyMode) {
+		return fmt.Errorf("unknown body_mode: %s", p.BodyMode)
+	}
+	if p.Reaction != "" && !contains([]string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"}, p.Reaction) {
+		return fmt.Errorf("unknown reaction: %s", p.Reaction)
+	}
+	if p.DeletePreviousComments && p.MinimizePreviousComments {
+		return errors.New("delete_previous_comments and minimize_previous_comments are mutually exclusive")
+	}
+	if p.DeleteReactedComment && p.MinimizeReactedComment {
+		return errors.New("delete_reacted_comment and minimize_reacted_comment are mutually exclusive")
+	}
+	if (p.DeleteReactedComment || p.MinimizeReactedComment) && p.ReactToComment == 0 && p.ReactToCommentFile == "" {
+		return errors.New("react_to_comment or react_to_comment_file must be set to delete or minimize the comment")
+	}
+	if p.SkipDuplicateComments && (p.DeletePreviousComments || p.MinimizePreviousComments) {
+		return errors.New("skip_duplicate_comments cannot be combined with delete_previous_comments or minimize_previous_comments")
+	}
+	if p.Section != "" && p.CommentTag == "" {
+		return errors.New("comment_tag must be set to update a section of the comment")
+	}
+	if p.CommentCooldown != "" {
+		if d, err := time.ParseDuration(p.CommentCooldown); err != nil || d <= 0 {
+			return fmt.Errorf("comment_cooldown must be a positive duration (e.g. 1h): %s", p.CommentCooldown)
+		}
+		if p.DeletePreviousComments {
+			return errors.New("comment_cooldown cannot be combined with delete_previous_comments")
+		}
+	}
+	if p.Close && p.Reopen {
+		return errors.New("close and reopen are mutually exclusive")
+	}
+	if p.UpdateBranchMethod != "" && !contains([]string{"merge", "rebase"}, strings.ToLower(p.UpdateBranchMethod)) {
+		return fmt.Errorf("unknown update branch method: %s", p.UpdateBranchMethod)
+	}
+	if p.MarkReady && p.MarkDraft {
+		return errors.New("mark_ready and mark_draft are mutually exclusive")
+	}
+	if p.AutoMergeMethod != "" && !contains([]string{"merge", "squash", "rebase"}, strings.ToLower(p.AutoMergeMethod)) {
+		return fmt.Errorf("unknown auto merge method: %s", p.AutoMergeMethod)
+	}
+	if p.Review != "" {
+		review := strings.ToLower(p.Review)
+		if !contains([]string{"approve", "request_changes", "comment"}, review) {
+			return fmt.Errorf("unknown review: %s", p.Review)
+		}
+		if review != "approve" && p.ReviewBody == "" && p.ReviewBodyFile == "" {
+			return fmt.Errorf("review_body or review_body_file must be set for review: %s", p.Review)
+		}
+	}
+	if p.LockReason != "" && !contains([]string{"off-topic", "too heated", "resolved", "spam"}, p.LockReason) {
+		return fmt.Errorf("unknown lock_reason: %s", p.LockReason)
+	}
+	if p.Tag != nil && p.Tag.Name == "" {
+		return errors.New("tag.name must be set")
+	}
+	if p.Dispatch != nil && p.Dispatch.EventType == "" {
+		return errors.New("dispatch.event_type must be set")
+	}
+	if p.Commit != "" && p.PRNumber == "" {
+		return errors.New("commit requires pr_number")
+	}
+	if p.PRNumber != "" && p.VersionFile != "" {
+		return errors.New("pr_number and version_file are mutually exclusive")
+	}
+	if p.Version != nil && p.Version.PR == "" {
+		return errors.New("version.pr must be set")
+	}
+	for _, s := range p.Statuses {
+		if s.Context == "" {
+			return errors.New("statuses[].context must be set")
+		}
+		if !contains([]string{"success", "pending", "failure", "error"}, strings.ToLower(s.Status)) {
+			return fmt.Errorf("unknown status: %s", s.Status)
+		}
+	}
+	if p.Status == "" {
+		return nil
+	}
+	// Make sure we are setting an allowed status
+	var allowedStatus bool
+
+	status := strings.ToLower(p.Status)
+	allowed := []string{"success", "pending", "failure", "error"}
+
+	for _, a := range allowed {
+		if status == a {
+			allowedStatus = true
+		}
+	}
+
+	if !allowedStatus {
+		return fmt.Errorf("unknown status: %s", p.Status)
+	}
+
+	return nil
+}
+
+const (
+	// maxCommentLength is the maximum length of a comment allowed by Github.
+	maxCommentLength = 65536
+
+	// maxCheckRunSummaryLength is the maximum length of a check run summary allowed by Github.
+	maxCheckRunSummaryLength = 65535
+
+	// maxStatusDescriptionLength is the maximum length (in characters) of a status description allowed by Github.
+	maxStatusDescriptionLength = 140
+
+	// maxDispatchPayloadProperties is the maximum number of top-level properties of the client payload of a
+	// repository_dispatch event allowed by Github.
+	maxDispatchPayloadProperties = 10
+
+	// truncatedDescriptionSuffix is appended to status descriptions that have been truncated (by default).
+	truncatedDescriptionSuffix = "..."
+
+	// truncatedMessage is appended to content that has been truncated.
+	truncatedMessage = "\n\n**This comment has been truncated, since it exceeded the maximum length allowed by Github.**"
+
+	// gistMessage is appended to content that has been truncated and uploaded as a gist.
+	gistMessage = "\n\n**This comment has been truncated, the full content is available [here](%s).**"
+)
+
+// truncate content to at most max bytes (without splitting UTF-8 characters), including the message.
+func truncate(content string, max int, message string) string {
+	if len(content) <= max {
+		return content
+	}
+	i := max - len(message)
+	for i > 0 && !utf8.RuneStart(content[i]) {
+		i--
+	}
+	return content[:i] + message
+}
+
+// truncateDescription to the maximum number of characters allowed by Github, ending with the suffix.
+func truncateDescription(description, suffix string) string {
+	runes := []rune(description)
+	if len(runes) <= maxStatusDescriptionLength {
+		return description
+	}
+	n := maxStatusDescriptionLength - utf8.RuneCountInString(suffix)
+	if n < 0 {
+		return string([]rune(suffix)[:maxStatusDescriptionLength])
+	}
+	return string(runes[:n]) + suffix
+}
+
+// commentSection wraps content in a collapsible <details> block with the summary (which defaults to the name
+// of the file), and optionally in a code block.
+func commentSection(summary, content string, p CommentSectionParameters) string {
+	if summary == "" {
+		summary = p.File
+	}
+	content = strings.TrimRight(content, "\n")
+	if p.Language != "" {
+		content = fmt.Sprintf("```%s\n%s\n```", p.Language, content)
+	}
+
+	details := "<details>"
+	if p.Open {
+		details = "<details open>"
+	}
+	return fmt.Sprintf("%s\n<summary>%s</summary>\n\n%s\n\n</details>", details, summary, content)
+}
+
+// codeownersReviewers resolves the CODEOWNERS file of the base branch against the files changed by the
+// pull request, and returns the owning users and teams (by slug). The author of the pull request and
+// owners identified by email are skipped, since Github does not accept them as reviewers.
+func codeownersReviewers(manager Github, version Version) ([]string, []string, error) {
+	details, err := manager.GetPullRequestDetails(version.PR)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	codeowners := Codeowners{}
+	for _, p := range CodeownersPaths {
+		content, err := manager.GetFileContent(p, details.BaseRefOid)
+		if err != nil {
+			continue
+		}
+		codeowners, err = ParseCodeowners(content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %s", p, err)
+		}
+		break
+	}
+
+	files, err := manager.GetChangedFiles(version.PR, version.Commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var users, teams []string
+	seen := make(map[string]bool)
+	for _, f := range files {
+		for _, owner := range codeowners.Owners(f.Path) {
+			if seen[owner] || !strings.HasPrefix(owner, "@") {
+				continue
+			}
+			seen[owner] = true
+
+			name := strings.TrimPrefix(owner, "@")
+			if i := strings.Index(name, "/"); i >= 0 {
+				teams = append(teams, name[i+1:])
+			} else if !strings.EqualFold(name, details.Author.Login) {
+				users = append(users, name)
+			}
+		}
+	}
+	return users, teams, nil
+}
+
+const (
+	// maxInputFileSize is the size limit of files (e.g. comment_file) read by put, which guards
+	// against running out of memory when a parameter accidentally points to e.g. a large log file.
+	maxInputFileSize = 10 << 20
+
+	// maxShortInputFileSize is the size limit of files which contain a single value (e.g. commit_file).
+	maxShortInputFileSize = 64 << 10
+)
+
+// readInputFile reads a file, and returns an error instead of reading it if it is larger than the limit.
+func readInputFile(path string, limit int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() && info.Size() > limit {
+		return nil, fmt.Errorf("%s is %d bytes, which exceeds the limit of %d bytes", path, info.Size(), limit)
+	}
+	// The size is checked while reading as well, since the file might not be a regular file (or still be growing)
+	content, err := ioutil.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("%s exceeds the limit of %d bytes", path, limit)
+	}
+	return content, nil
+}
+
+// readCommentFiles reads the file(s) matching the pattern. If the pattern matches multiple
+// files, their content is concatenated with the (relative) path of each file as a header.
+func readCommentFiles(inputDir, pattern string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(inputDir, pattern))
+	if err != nil {
+		return "", err
+	}
+	if len(files) <= 1 {
+		content, err := readInputFile(filepath.Join(inputDir, pattern), maxInputFileSize)
+		if len(files) == 1 {
+			content, err = readInputFile(files[0], maxInputFileSize)
+		}
+		return string(content), err
+	}
+
+	var b strings.Builder
+	for i, f := range files {
+		content, err := readInputFile(f, maxInputFileSize)
+		if err != nil {
+			return "", err
+		}
+		if b.Len()+len(content) > maxInputFileSize {
+			return "", fmt.Errorf("files matching %s exceed the limit of %d bytes", pattern, maxInputFileSize)
+		}
+		name, err := filepath.Rel(inputDir, f)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s", name, strings.TrimRight(string(content), "\n"))
+	}
+	return b.String(), nil
+}
+
+// commentMarker returns a hidden (HTML comment) marker used to find a comment with the given tag.
+func commentMarker(tag string) string {
+	return fmt.Sprintf("<!-- github-pr-resource: %s -->", tag)
+}
+
+// namedSection is a section of a comment which is updated independently of the other sections.
+type namedSection struct {
+	name    string
+	content string
+}
+
+// namedSections of a comment, in order.
+type namedSections []namedSection
+
+// sectionMarkers returns the hidden (HTML comment) markers around the section with the given name.
+func sectionMarkers(name string) (string, string) {
+	return fmt.Sprintf("<!-- github-pr-resource-section: %s -->", name), fmt.Sprintf("<!-- /github-pr-resource-section: %s -->", name)
+}
+
+// parseNamedSections returns the sections of a comment. Anything outside of the sections is ignored.
+func parseNamedSections(comment string) namedSections {
+	const prefix = "<!-- github-pr-resource-section: "
+	var sections namedSections
+	for {
+		i := strings.Index(comment, prefix)
+		if i < 0 {
+			return sections
+		}
+		comment = comment[i+len(prefix):]
+		j := strings.Index(comment, " -->")
+		if j < 0 {
+			return sections
+		}
+		name := comment[:j]
+		comment = comment[j+len(" -->"):]
+
+		_, end := sectionMarkers(name)
+		k := strings.Index(comment, end)
+		if k < 0 {
+			return sections
+		}
+		sections = append(sections, namedSection{name: name, content: strings.Trim(comment[:k], "\n")})
+		comment = comment[k+len(end):]
+	}
+}
+
+// with returns the sections with the content of the named section replaced, or the section appended.
+func (s namedSections) with(name, content string) namedSections {
+	sections := append(namedSections(nil), s...)
+	for i := range sections {
+		if sections[i].name == name {
+			sections[i].content = content
+			return sections
+		}
+	}
+	return append(sections, namedSection{name: name, content: content})
+}
+
+// String renders the sections (with their markers) as a comment.
+func (s namedSections) String() string {
+	parts := make([]string, len(s))
+	for i, section := range s {
+		start, end := sectionMarkers(section.name)
+		parts[i] = start + "\n" + section.content + "\n" + end
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// cooldownKey returns the context of comments for comment_cooldown, which is the comment tag or the status context.
+func cooldownKey(p PutParameters, baseContext string) string {
+	if p.CommentTag != "" {
+		return p.CommentTag
+	}
+	base, context := baseContext, p.Context
+	if base == "" {
+		base = "concourse-ci"
+	}
+	if context == "" {
+		context = "status"
+	}
+	return base + "/" + context
+}
+
+// cooldownMarkerPrefix returns the start of the hidden marker with the time a comment was posted for a context.
+func cooldownMarkerPrefix(key string) string {
+	return fmt.Sprintf("<!-- github-pr-resource-cooldown: %s ", key)
+}
+
+// cooldownMarker returns a hidden (HTML comment) marker with the time a comment was posted for a context.
+func cooldownMarker(key string, posted time.Time) string {
+	return cooldownMarkerPrefix(key) + posted.UTC().Format(time.RFC3339) + " -->"
+}
+
+// parseCooldownMarker returns the time in the cooldown marker for a context in a comment, if it has one.
+func parseCooldownMarker(comment, key string) (time.Time, bool) {
+	prefix := cooldownMarkerPrefix(key)
+	i := strings.LastIndex(comment, prefix)
+	if i < 0 {
+		return time.Time{}, false
+	}
+	rest := comment[i+len(prefix):]
+	end := strings.Index(rest, " -->")
+	if end < 0 {
+		return time.Time{}, false
+	}
+	posted, err := time.Parse(time.RFC3339, rest[:end])
+	return posted, err == nil
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// buildURL returns the URL of the current build in Concourse (or an empty string if it is unknown).
+func buildURL() string {
+	atc := strings.TrimSuffix(os.Getenv("ATC_EXTERNAL_URL"), "/")
+	if atc == "" {
+		return ""
+	}
+	team, pipeline, job, build := os.Getenv("BUILD_TEAM_NAME"), os.Getenv("BUILD_PIPELINE_NAME"), os.Getenv("BUILD_JOB_NAME"), os.Getenv("BUILD_NAME")
+	if team == "" || pipeline == "" || job == "" || build == "" {
+		// One-off builds do not belong to a pipeline.
+		return atc + "/builds/" + os.Getenv("BUILD_ID")
+	}
+	return fmt.Sprintf("%s/teams/%s/pipelines/%s/jobs/%s/builds/%s",
+		atc, url.PathEscape(team), url.PathEscape(pipeline), url.PathEscape(job), url.PathEscape(build))
+}
+
+// buildEnv is the build metadata (environment variables) which is always expanded.
+// See: https://concourse-ci.org/implementing-resource-types.html#resource-metadata
+var buildEnv = []string{"BUILD_ID", "BUILD_NAME", "BUILD_JOB_NAME", "BUILD_PIPELINE_NAME", "BUILD_TEAM_NAME", "ATC_EXTERNAL_URL"}
+
+// metadataAliases are alternative names for metadata fields that can be used in parameters.
+var metadataAliases = map[string]string{
+	"pr_number":   "pr",
+	"head_branch": "head_name",
+	"base_branch": "base_name",
+}
+
+// expander expands variables in parameters. Variables which are not known are left as-is.
+type expander struct {
+	allowed  []string
+	vars     map[string]string
+	metadata Metadata
+}
+
+func newExpander(request PutRequest, metadata Metadata) *expander {
+	return &expander{
+		allowed:  append(append([]string{}, buildEnv...), request.Source.ExpandEnv...),
+		vars:     request.Params.Vars,
+		metadata: metadata,
+	}
+}
+
+// env expands the custom vars and the allowed environment variables.
+func (e *expander) env(s string) string {
+	return os.Expand(s, func(v string) string {
+		if value, ok := e.lookupEnv(v); ok {
+			return value
+		}
+		return "$" + v
+	})
+}
+
+// all works like env, but also expands the variables from metadata.env (e.g. $PR_TITLE)
+// and the metadata fields by name (e.g. ${head_sha} or ${pr_number}).
+func (e *expander) all(s string) string {
+	return os.Expand(s, func(v string) string {
+		if value, ok := e.lookupEnv(v); ok {
+			return value
+		}
+		if value, ok := e.metadata.LookupEnv(v); ok {
+			return value
+		}
+		name := v
+		if alias, ok := metadataAliases[v]; ok {
+			name = alias
+		}
+		for _, f := range e.metadata {
+			if f.Name == name {
+				return f.Value
+			}
+		}
+		return "$" + v
+	})
+}
+
+func (e *expander) lookupEnv(v string) (string, bool) {
+	if value, ok := e.vars[v]; ok {
+		return value, true
+	}
+	if contains(e.allowed, v) {
+		return os.Getenv(v), true
+	}
+	return "", false
 }
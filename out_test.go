@@ -1,8 +1,12 @@
 package resource_test
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -144,6 +148,24 @@ func TestPut(t *testing.T) {
 			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 		},
 
+		{
+			description: "we can update a tagged comment on the pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Comment:    "coverage: 80%",
+				CommentTag: "coverage",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
 		{
 			description: "we can delete previous comments made on the pull request",
 			source: resource.Source{
@@ -160,84 +182,191 @@ func TestPut(t *testing.T) {
 			},
 			pullRequest: createTestPR(1, "master", false, false, 0, []string{}, false, githubv4.PullRequestStateOpen),
 		},
-	}
 
-	for _, tc := range tests {
-		t.Run(tc.description, func(t *testing.T) {
-			github := new(fakes.FakeGithub)
-			github.GetPullRequestReturns(tc.pullRequest, nil)
-
-			git := new(fakes.FakeGit)
-			git.RevParseReturns("sha", nil)
-
-			dir := createTestDirectory(t)
-			defer os.RemoveAll(dir)
+		{
+			description: "we can delete previous comments matching a pattern",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				DeletePreviousComments:         true,
+				DeletePreviousCommentsMatching: "^Terraform plan",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, []string{}, false, githubv4.PullRequestStateOpen),
+		},
 
-			// Run get so we have version and metadata for the put request
-			// (This is tested in in_test.go)
-			getInput := resource.GetRequest{Source: tc.source, Version: tc.version, Params: resource.GetParameters{}}
-			_, err := resource.Get(getInput, github, git, dir)
-			require.NoError(t, err)
+		{
+			description: "we can minimize previous comments with the same tag",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				MinimizePreviousComments: true,
+				Comment:                  "new plan",
+				CommentTag:               "plan",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, []string{}, false, githubv4.PullRequestStateOpen),
+		},
 
-			putInput := resource.PutRequest{Source: tc.source, Params: tc.parameters}
-			output, err := resource.Put(putInput, github, dir)
+		{
+			description: "we can react to a comment",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				ReactToComment: 123,
+				Reaction:       "rocket",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-			// Validate output
-			if assert.NoError(t, err) {
-				assert.Equal(t, tc.version, output.Version)
-			}
+		{
+			description: "we can minimize a comment after reacting to it",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				ReactToComment:         123,
+				Reaction:               "eyes",
+				MinimizeReactedComment: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-			// Validate method calls put on Github.
-			if tc.parameters.Status != "" {
-				if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
-					commit, baseContext, context, status, targetURL, description := github.UpdateCommitStatusArgsForCall(0)
-					assert.Equal(t, tc.version.Commit, commit)
-					assert.Equal(t, tc.parameters.BaseContext, baseContext)
-					assert.Equal(t, tc.parameters.Context, context)
-					assert.Equal(t, tc.parameters.TargetURL, targetURL)
-					assert.Equal(t, tc.parameters.Description, description)
-					assert.Equal(t, tc.parameters.Status, status)
-				}
-			}
+		{
+			description: "we can delete a comment after reacting to it",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				ReactToComment:       123,
+				Reaction:             "+1",
+				DeleteReactedComment: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-			if tc.parameters.Comment != "" {
-				if assert.Equal(t, 1, github.PostCommentCallCount()) {
-					pr, comment := github.PostCommentArgsForCall(0)
-					assert.Equal(t, tc.version.PR, pr)
-					assert.Equal(t, tc.parameters.Comment, comment)
-				}
-			}
+		{
+			description: "we can set the milestone of the pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Milestone:       "v1.2.0",
+				CreateMilestone: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-			if tc.parameters.DeletePreviousComments {
-				if assert.Equal(t, 1, github.DeletePreviousCommentsCallCount()) {
-					pr := github.DeletePreviousCommentsArgsForCall(0)
-					assert.Equal(t, tc.version.PR, pr)
-				}
-			}
-		})
-	}
-}
+		{
+			description: "we can request reviewers on the pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				RequestReviewers:     []string{"reviewer1", "reviewer2"},
+				RequestTeamReviewers: []string{"team1"},
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-func TestVariableSubstitution(t *testing.T) {
+		{
+			description: "we can approve the pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Review: "approve",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-	var (
-		variableName  = "BUILD_JOB_NAME"
-		variableValue = "my-job"
-		variableURL   = "https://concourse-ci.org/"
-	)
+		{
+			description: "we can request changes on the pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Review:     "request_changes",
+				ReviewBody: "please fix the build",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-	tests := []struct {
-		description       string
-		source            resource.Source
-		version           resource.Version
-		parameters        resource.PutParameters
-		expectedComment   string
-		expectedTargetURL string
-		pullRequest       *resource.PullRequest
-	}{
+		{
+			description: "we can dismiss approving reviews",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				DismissReviews:        true,
+				DismissReviewsMessage: "policy check failed",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
 		{
-			description: "we can substitute environment variables for Comment",
+			description: "we can enable auto-merge on the pull request",
 			source: resource.Source{
 				Repository:  "itsdalmo/test-repository",
 				AccessToken: "oauthtoken",
@@ -248,14 +377,14 @@ func TestVariableSubstitution(t *testing.T) {
 				CommittedDate: time.Time{},
 			},
 			parameters: resource.PutParameters{
-				Comment: fmt.Sprintf("$%s", variableName),
+				EnableAutoMerge: true,
+				AutoMergeMethod: "squash",
 			},
-			expectedComment: variableValue,
-			pullRequest:     createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 		},
 
 		{
-			description: "we can substitute environment variables for TargetURL",
+			description: "we can close the pull request with a comment",
 			source: resource.Source{
 				Repository:  "itsdalmo/test-repository",
 				AccessToken: "oauthtoken",
@@ -266,15 +395,14 @@ func TestVariableSubstitution(t *testing.T) {
 				CommittedDate: time.Time{},
 			},
 			parameters: resource.PutParameters{
-				Status:    "failure",
-				TargetURL: fmt.Sprintf("%s$%s", variableURL, variableName),
+				Comment: "closing stale pull request",
+				Close:   true,
 			},
-			expectedTargetURL: fmt.Sprintf("%s%s", variableURL, variableValue),
-			pullRequest:       createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 		},
 
 		{
-			description: "we do not substitute variables other then concourse build metadata",
+			description: "we can reopen the pull request with a comment",
 			source: resource.Source{
 				Repository:  "itsdalmo/test-repository",
 				AccessToken: "oauthtoken",
@@ -285,51 +413,2751 @@ func TestVariableSubstitution(t *testing.T) {
 				CommittedDate: time.Time{},
 			},
 			parameters: resource.PutParameters{
-				Comment: "$THIS_IS_NOT_SUBSTITUTED",
+				Comment: "reopening pull request",
+				Reopen:  true,
 			},
-			expectedComment: "$THIS_IS_NOT_SUBSTITUTED",
-			pullRequest:     createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateClosed),
 		},
-	}
 
-	for _, tc := range tests {
-		t.Run(tc.description, func(t *testing.T) {
-			github := new(fakes.FakeGithub)
-			github.GetPullRequestReturns(tc.pullRequest, nil)
+		{
+			description: "we can update the branch with the base branch",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				UpdateBranch:       true,
+				UpdateBranchMethod: "rebase",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-			git := new(fakes.FakeGit)
-			git.RevParseReturns("sha", nil)
+		{
+			description: "we can mark a draft pull request as ready for review",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				MarkReady: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 
-			dir := createTestDirectory(t)
-			defer os.RemoveAll(dir)
+		{
+			description: "we can convert the pull request to a draft",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				MarkDraft: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+	}
 
-			// Run get so we have version and metadata for the put request
-			getInput := resource.GetRequest{Source: tc.source, Version: tc.version, Params: resource.GetParameters{}}
-			_, err := resource.Get(getInput, github, git, dir)
-			require.NoError(t, err)
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(tc.pullRequest, nil)
 
-			oldValue := os.Getenv(variableName)
-			defer os.Setenv(variableName, oldValue)
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
 
-			os.Setenv(variableName, variableValue)
+			getForPut(t, github, tc.source, tc.version, dir)
 
 			putInput := resource.PutRequest{Source: tc.source, Params: tc.parameters}
-			_, err = resource.Put(putInput, github, dir)
+			output, err := resource.Put(putInput, github, dir)
+
+			// Validate output
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.version, output.Version)
+			}
 
-			if tc.parameters.TargetURL != "" {
+			// Validate method calls put on Github.
+			if tc.parameters.Status != "" {
 				if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
-					_, _, _, _, targetURL, _ := github.UpdateCommitStatusArgsForCall(0)
-					assert.Equal(t, tc.expectedTargetURL, targetURL)
+					commit, baseContext, context, status, targetURL, description := github.UpdateCommitStatusArgsForCall(0)
+					assert.Equal(t, tc.version.Commit, commit)
+					assert.Equal(t, tc.parameters.BaseContext, baseContext)
+					assert.Equal(t, tc.parameters.Context, context)
+					assert.Equal(t, tc.parameters.TargetURL, targetURL)
+					assert.Equal(t, tc.parameters.Description, description)
+					assert.Equal(t, tc.parameters.Status, status)
 				}
 			}
 
-			if tc.parameters.Comment != "" {
+			if tc.parameters.Comment != "" && tc.parameters.CommentTag == "" {
 				if assert.Equal(t, 1, github.PostCommentCallCount()) {
-					_, comment := github.PostCommentArgsForCall(0)
-					assert.Equal(t, tc.expectedComment, comment)
+					pr, comment := github.PostCommentArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.parameters.Comment, comment)
+				}
+			}
+
+			if tc.parameters.CommentTag != "" {
+				marker := fmt.Sprintf("<!-- github-pr-resource: %s -->", tc.parameters.CommentTag)
+				if assert.Equal(t, 1, github.UpsertCommentCallCount()) {
+					pr, m, comment := github.UpsertCommentArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, marker, m)
+					assert.Equal(t, tc.parameters.Comment+"\n\n"+marker, comment)
+				}
+				assert.Equal(t, 0, github.PostCommentCallCount())
+			}
+
+			if len(tc.parameters.RequestReviewers) > 0 || len(tc.parameters.RequestTeamReviewers) > 0 {
+				if assert.Equal(t, 1, github.RequestReviewersCallCount()) {
+					pr, reviewers, teamReviewers := github.RequestReviewersArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.parameters.RequestReviewers, reviewers)
+					assert.Equal(t, tc.parameters.RequestTeamReviewers, teamReviewers)
+				}
+			}
+
+			if tc.parameters.Review != "" {
+				if assert.Equal(t, 1, github.CreateReviewCallCount()) {
+					pr, commit, event, body := github.CreateReviewArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.version.Commit, commit)
+					assert.Equal(t, strings.ToUpper(tc.parameters.Review), event)
+					assert.Equal(t, tc.parameters.ReviewBody, body)
+				}
+			}
+
+			if tc.parameters.EnableAutoMerge {
+				if assert.Equal(t, 1, github.EnableAutoMergeCallCount()) {
+					pr, method := github.EnableAutoMergeArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, strings.ToUpper(tc.parameters.AutoMergeMethod), method)
+				}
+			}
+
+			if tc.parameters.Close {
+				if assert.Equal(t, 1, github.ClosePullRequestCallCount()) {
+					assert.Equal(t, tc.version.PR, github.ClosePullRequestArgsForCall(0))
+				}
+			}
+
+			if tc.parameters.MinimizePreviousComments {
+				if assert.Equal(t, 1, github.MinimizePreviousCommentsCallCount()) {
+					pr, filter := github.MinimizePreviousCommentsArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					if tc.parameters.CommentTag != "" {
+						assert.True(t, filter.MatchString(fmt.Sprintf("<!-- github-pr-resource: %s -->", tc.parameters.CommentTag)))
+					} else {
+						assert.Nil(t, filter)
+					}
+				}
+				assert.Equal(t, 0, github.DeletePreviousCommentsCallCount())
+			}
+
+			if tc.parameters.ReactToComment != 0 {
+				if assert.Equal(t, 1, github.AddCommentReactionCallCount()) {
+					id, reaction := github.AddCommentReactionArgsForCall(0)
+					assert.Equal(t, tc.parameters.ReactToComment, id)
+					assert.Equal(t, tc.parameters.Reaction, reaction)
+				}
+			}
+
+			if tc.parameters.DeleteReactedComment {
+				if assert.Equal(t, 1, github.DeleteCommentCallCount()) {
+					assert.Equal(t, tc.parameters.ReactToComment, github.DeleteCommentArgsForCall(0))
+				}
+			} else {
+				assert.Equal(t, 0, github.DeleteCommentCallCount())
+			}
+
+			if tc.parameters.MinimizeReactedComment {
+				if assert.Equal(t, 1, github.MinimizeCommentCallCount()) {
+					assert.Equal(t, tc.parameters.ReactToComment, github.MinimizeCommentArgsForCall(0))
+				}
+			} else {
+				assert.Equal(t, 0, github.MinimizeCommentCallCount())
+			}
+
+			if tc.parameters.Milestone != "" {
+				if assert.Equal(t, 1, github.SetMilestoneCallCount()) {
+					pr, milestone, create := github.SetMilestoneArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.parameters.Milestone, milestone)
+					assert.Equal(t, tc.parameters.CreateMilestone, create)
+				}
+			}
+
+			if tc.parameters.DismissReviews {
+				if assert.Equal(t, 1, github.DismissReviewsCallCount()) {
+					pr, message := github.DismissReviewsArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.parameters.DismissReviewsMessage, message)
+				}
+			}
+
+			if tc.parameters.Reopen {
+				if assert.Equal(t, 1, github.ReopenPullRequestCallCount()) {
+					assert.Equal(t, tc.version.PR, github.ReopenPullRequestArgsForCall(0))
+				}
+			}
+
+			if tc.parameters.UpdateBranch {
+				if assert.Equal(t, 1, github.UpdateBranchCallCount()) {
+					pr, commit, method := github.UpdateBranchArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.version.Commit, commit)
+					assert.Equal(t, strings.ToUpper(tc.parameters.UpdateBranchMethod), method)
+				}
+			} else {
+				assert.Equal(t, 0, github.UpdateBranchCallCount())
+			}
+
+			if tc.parameters.MarkReady {
+				if assert.Equal(t, 1, github.MarkReadyForReviewCallCount()) {
+					assert.Equal(t, tc.version.PR, github.MarkReadyForReviewArgsForCall(0))
+				}
+			} else {
+				assert.Equal(t, 0, github.MarkReadyForReviewCallCount())
+			}
+
+			if tc.parameters.MarkDraft {
+				if assert.Equal(t, 1, github.ConvertToDraftCallCount()) {
+					assert.Equal(t, tc.version.PR, github.ConvertToDraftArgsForCall(0))
+				}
+			} else {
+				assert.Equal(t, 0, github.ConvertToDraftCallCount())
+			}
+
+			if tc.parameters.DeletePreviousComments {
+				if assert.Equal(t, 1, github.DeletePreviousCommentsCallCount()) {
+					pr, filter := github.DeletePreviousCommentsArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					if tc.parameters.DeletePreviousCommentsMatching != "" {
+						assert.Equal(t, tc.parameters.DeletePreviousCommentsMatching, filter.String())
+					} else {
+						assert.Nil(t, filter)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPutCheckRun(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	annotations := `[{"path":"main.go","start_line":1,"end_line":2,"annotation_level":"warning","message":"unused variable"}]`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "annotations.json"), []byte(annotations), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "summary.md"), []byte("# Lint"), 0644))
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		CheckRun: &resource.CheckRunParameters{
+			Name:            "lint",
+			Conclusion:      "NEUTRAL",
+			DetailsURL:      "https://ci.example.com",
+			SummaryFile:     "summary.md",
+			AnnotationsFile: "annotations.json",
+		},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.UpdateCheckRunCallCount()) {
+		commit, run := github.UpdateCheckRunArgsForCall(0)
+		assert.Equal(t, version.Commit, commit)
+		assert.Equal(t, resource.CheckRun{
+			Name:       "lint",
+			HeadBranch: "pr1",
+			Status:     "completed",
+			Conclusion: "neutral",
+			DetailsURL: "https://ci.example.com",
+			Title:      "lint",
+			Summary:    "# Lint",
+			Annotations: []resource.CheckRunAnnotation{
+				{Path: "main.go", StartLine: 1, EndLine: 2, AnnotationLevel: "warning", Message: "unused variable"},
+			},
+		}, run)
+	}
+
+	// Invalid parameters are rejected
+	putInput.Params.CheckRun.Conclusion = "passed"
+	_, err = resource.Put(putInput, github, dir)
+	assert.EqualError(t, err, "invalid parameters: unknown check run conclusion: passed")
+}
+
+func TestPutMerge(t *testing.T) {
+	approved := githubv4.PullRequestReviewDecisionApproved
+	changesRequested := githubv4.PullRequestReviewDecisionChangesRequested
+
+	tests := []struct {
+		description    string
+		parameters     resource.MergeParameters
+		details        *resource.PullRequestDetailsObject
+		expectedTitle  string
+		expectedMethod string
+		expectedErr    string
+	}{
+		{
+			description:    "we can merge the pull request",
+			parameters:     resource.MergeParameters{},
+			expectedMethod: "",
+		},
+		{
+			description: "commit title is expanded with metadata",
+			parameters: resource.MergeParameters{
+				Method:      "SQUASH",
+				CommitTitle: "${PR_TITLE} (#${PR_NUMBER})",
+			},
+			expectedTitle:  "pr1 title (#1)",
+			expectedMethod: "squash",
+		},
+		{
+			description: "mergeable and approved pull requests are merged",
+			parameters: resource.MergeParameters{
+				RequireMergeable: true,
+				RequireApproved:  true,
+			},
+			details: &resource.PullRequestDetailsObject{
+				Mergeable:      githubv4.MergeableStateMergeable,
+				ReviewDecision: &approved,
+			},
+		},
+		{
+			description: "conflicting pull requests are not merged",
+			parameters: resource.MergeParameters{
+				RequireMergeable: true,
+			},
+			details: &resource.PullRequestDetailsObject{
+				Mergeable: githubv4.MergeableStateConflicting,
+			},
+			expectedErr: "pull request is not mergeable: CONFLICTING",
+		},
+		{
+			description: "pull requests that are not approved are not merged",
+			parameters: resource.MergeParameters{
+				RequireApproved: true,
+			},
+			details: &resource.PullRequestDetailsObject{
+				Mergeable:      githubv4.MergeableStateMergeable,
+				ReviewDecision: &changesRequested,
+			},
+			expectedErr: "pull request has not been approved",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetPullRequestDetailsReturns(tc.details, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			parameters := tc.parameters
+			putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{Merge: &parameters}}
+			_, err := resource.Put(putInput, github, dir)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Equal(t, 0, github.MergePullRequestCallCount())
+				return
+			}
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.MergePullRequestCallCount()) {
+				pr, commit, method, title, _ := github.MergePullRequestArgsForCall(0)
+				assert.Equal(t, version.PR, pr)
+				assert.Equal(t, version.Commit, commit)
+				assert.Equal(t, tc.expectedMethod, method)
+				assert.Equal(t, tc.expectedTitle, title)
+			}
+		})
+	}
+}
+
+func TestPutDeleteBranch(t *testing.T) {
+	tests := []struct {
+		description    string
+		parameters     resource.PutParameters
+		state          githubv4.PullRequestState
+		headRepository string
+		expectDeleted  bool
+		expectedErr    string
+	}{
+		{
+			description:    "we can delete the branch of a merged pull request",
+			parameters:     resource.PutParameters{DeleteBranch: true},
+			state:          githubv4.PullRequestStateMerged,
+			headRepository: "itsdalmo/test-repository",
+			expectDeleted:  true,
+		},
+		{
+			description:    "we can merge and delete the branch",
+			parameters:     resource.PutParameters{Merge: &resource.MergeParameters{}, DeleteBranch: true},
+			state:          githubv4.PullRequestStateMerged,
+			headRepository: "itsdalmo/test-repository",
+			expectDeleted:  true,
+		},
+		{
+			description:    "branches in forks are not deleted",
+			parameters:     resource.PutParameters{DeleteBranch: true},
+			state:          githubv4.PullRequestStateMerged,
+			headRepository: "someone/test-repository",
+		},
+		{
+			description:    "branches of open pull requests are not deleted",
+			parameters:     resource.PutParameters{DeleteBranch: true},
+			state:          githubv4.PullRequestStateOpen,
+			headRepository: "itsdalmo/test-repository",
+			expectedErr:    "refusing to delete branch of pull request that has not been merged: OPEN",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			details := &resource.PullRequestDetailsObject{State: tc.state, HeadRefName: "feature"}
+			details.HeadRepository = &struct {
+				NameWithOwner string `json:"nameWithOwner"`
+				URL           string `json:"url"`
+			}{NameWithOwner: tc.headRepository}
+
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetPullRequestDetailsReturns(details, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			putInput := resource.PutRequest{Source: source, Params: tc.parameters}
+			_, err := resource.Put(putInput, github, dir)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tc.expectDeleted {
+				if assert.Equal(t, 1, github.DeleteBranchCallCount()) {
+					assert.Equal(t, "feature", github.DeleteBranchArgsForCall(0))
 				}
+			} else {
+				assert.Equal(t, 0, github.DeleteBranchCallCount())
+			}
+		})
+	}
+}
+
+func TestPutUpdateBranchDeletedFork(t *testing.T) {
+	tests := []struct {
+		description    string
+		headRepository bool
+		expectedErr    string
+	}{
+		{
+			description:    "update branch failures are returned as is",
+			headRepository: true,
+			expectedErr:    "failed to update branch: conflict",
+		},
+		{
+			description: "update branch explains that the fork has been deleted",
+			expectedErr: "failed to update branch: the head repository of the pull request has been deleted",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			pull := createTestPR(1, "master", false, true, 0, nil, false, githubv4.PullRequestStateOpen)
+			details := &resource.PullRequestDetailsObject{State: githubv4.PullRequestStateOpen}
+			if tc.headRepository {
+				details.HeadRepository = &struct {
+					NameWithOwner string `json:"nameWithOwner"`
+					URL           string `json:"url"`
+				}{NameWithOwner: "someone/test-repository"}
+			} else {
+				pull.HeadRepository = nil
 			}
 
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(pull, nil)
+			github.GetPullRequestDetailsReturns(details, nil)
+			github.UpdateBranchReturns(errors.New("conflict"))
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "1", Commit: "commit1"}
+
+			// Get works without the fork, since the pull request is fetched from the base repository
+			getForPut(t, github, source, version, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{UpdateBranch: true}}, github, dir)
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPutUpdatePullRequest(t *testing.T) {
+	tests := []struct {
+		description   string
+		parameters    resource.PutParameters
+		currentBody   string
+		expectedTitle string
+		expectedBody  string
+	}{
+		{
+			description:   "we can set the title",
+			parameters:    resource.PutParameters{Title: "${PR_TITLE} [skip ci]"},
+			expectedTitle: "pr1 title [skip ci]",
+		},
+		{
+			description:  "we can replace the body",
+			parameters:   resource.PutParameters{Body: "new body"},
+			currentBody:  "old body",
+			expectedBody: "new body",
+		},
+		{
+			description:  "we can append to the body",
+			parameters:   resource.PutParameters{Body: "preview: https://pr-${PR_NUMBER}.example.com", BodyMode: "append"},
+			currentBody:  "old body",
+			expectedBody: "old body\n\npreview: https://pr-1.example.com",
+		},
+		{
+			description:  "we can prepend to the body",
+			parameters:   resource.PutParameters{Body: "checklist", BodyMode: "prepend"},
+			currentBody:  "old body",
+			expectedBody: "checklist\n\nold body",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetPullRequestDetailsReturns(&resource.PullRequestDetailsObject{Body: tc.currentBody}, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			putInput := resource.PutRequest{Source: source, Params: tc.parameters}
+			_, err := resource.Put(putInput, github, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.UpdatePullRequestCallCount()) {
+				pr, title, body := github.UpdatePullRequestArgsForCall(0)
+				assert.Equal(t, version.PR, pr)
+				assert.Equal(t, tc.expectedTitle, title)
+				assert.Equal(t, tc.expectedBody, body)
+			}
 		})
 	}
 }
+
+func TestPutAssignees(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	details := &resource.PullRequestDetailsObject{}
+	details.Author.Login = "pr-author"
+	github.GetPullRequestDetailsReturns(details, nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		Assignees: []string{"$AUTHOR", "triage-bot"},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.AddAssigneesCallCount()) {
+		pr, assignees := github.AddAssigneesArgsForCall(0)
+		assert.Equal(t, version.PR, pr)
+		assert.Equal(t, []string{"pr-author", "triage-bot"}, assignees)
+	}
+}
+
+func TestPutDeployment(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		Deployment: &resource.DeploymentParameters{
+			Environment:    "pr-${PR_NUMBER}",
+			State:          "SUCCESS",
+			EnvironmentURL: "https://pr-${PR_NUMBER}.example.com",
+			LogURL:         "https://ci.example.com",
+			Transient:      true,
+		},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.UpdateDeploymentCallCount()) {
+		commit, deployment := github.UpdateDeploymentArgsForCall(0)
+		assert.Equal(t, version.Commit, commit)
+		assert.Equal(t, resource.Deployment{
+			Environment:    "pr-1",
+			State:          "success",
+			EnvironmentURL: "https://pr-1.example.com",
+			LogURL:         "https://ci.example.com",
+			Transient:      true,
+		}, deployment)
+	}
+
+	// Invalid parameters are rejected
+	putInput.Params.Deployment.State = "deployed"
+	_, err = resource.Put(putInput, github, dir)
+	assert.EqualError(t, err, "invalid parameters: unknown deployment state: deployed")
+}
+
+func TestPutStatuses(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		BaseContext: "ci",
+		Status:      "pending",
+		Context:     "e2e",
+		Statuses: []resource.StatusParameters{
+			{Context: "unit", Status: "success", Description: "unit tests passed"},
+			{Context: "lint", Status: "failure", TargetURL: "https://lint.example.com"},
+		},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	expected := [][]string{
+		{"e2e", "pending", "", ""},
+		{"unit", "success", "", "unit tests passed"},
+		{"lint", "failure", "https://lint.example.com", ""},
+	}
+	if assert.Equal(t, len(expected), github.UpdateCommitStatusCallCount()) {
+		for i, e := range expected {
+			commit, baseContext, context, status, targetURL, description := github.UpdateCommitStatusArgsForCall(i)
+			assert.Equal(t, version.Commit, commit)
+			assert.Equal(t, "ci", baseContext)
+			assert.Equal(t, e, []string{context, status, targetURL, description})
+		}
+	}
+
+	// Statuses without a context are rejected
+	putInput.Params.Statuses = []resource.StatusParameters{{Status: "success"}}
+	_, err = resource.Put(putInput, github, dir)
+	assert.EqualError(t, err, "invalid parameters: statuses[].context must be set")
+}
+
+func TestPutStatusMap(t *testing.T) {
+	tests := []struct {
+		description string
+		parameters  resource.PutParameters
+		statusFile  string
+		expected    [][]string
+		expectedErr string
+	}{
+		{
+			description: "statuses are translated with an annotated description",
+			parameters: resource.PutParameters{
+				Status:      "Skipped",
+				Description: "no changes",
+				Statuses:    []resource.StatusParameters{{Context: "lint", Status: "warning"}},
+			},
+			expected: [][]string{{"success", "skipped: no changes"}, {"success", "Concourse CI build warning"}},
+		},
+		{
+			description: "the status can be read from a file",
+			parameters:  resource.PutParameters{StatusFile: "result/status"},
+			statusFile:  "aborted\n",
+			expected:    [][]string{{"error", "Concourse CI build aborted"}},
+		},
+		{
+			description: "standard statuses are not changed",
+			parameters:  resource.PutParameters{StatusFile: "result/status"},
+			statusFile:  "success",
+			expected:    [][]string{{"success", ""}},
+		},
+		{
+			description: "statuses which are not mapped are rejected",
+			parameters:  resource.PutParameters{StatusFile: "result/status"},
+			statusFile:  "flaky",
+			expectedErr: "invalid parameters: unknown status: flaky",
+		},
+		{
+			description: "status and status_file are mutually exclusive",
+			parameters:  resource.PutParameters{Status: "success", StatusFile: "result/status"},
+			statusFile:  "success",
+			expectedErr: "invalid parameters: status and status_file are mutually exclusive",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				StatusMap:   map[string]string{"skipped": "success", "warning": "success", "aborted": "error"},
+			}
+			require.NoError(t, source.Validate())
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, filepath.Join(dir, "pull-request"))
+			if tc.statusFile != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, "result"), 0755))
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "result", "status"), []byte(tc.statusFile), 0644))
+			}
+
+			tc.parameters.Path = "pull-request"
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if assert.Equal(t, len(tc.expected), github.UpdateCommitStatusCallCount()) {
+				for i, e := range tc.expected {
+					_, _, _, status, _, description := github.UpdateCommitStatusArgsForCall(i)
+					assert.Equal(t, e, []string{status, description})
+				}
+			}
+		})
+	}
+}
+
+func TestPutSkipUnchangedStatus(t *testing.T) {
+	tests := []struct {
+		description   string
+		skipUnchanged bool
+		existing      *resource.CommitStatus
+		expectUpdate  bool
+	}{
+		{
+			description:  "statuses are always set by default",
+			existing:     &resource.CommitStatus{State: "success", TargetURL: "https://ci.example.com", Description: "Concourse CI build success"},
+			expectUpdate: true,
+		},
+		{
+			description:   "unchanged statuses are skipped",
+			skipUnchanged: true,
+			existing:      &resource.CommitStatus{State: "success", TargetURL: "https://ci.example.com", Description: "Concourse CI build success"},
+		},
+		{
+			description:   "statuses with a different state are set",
+			skipUnchanged: true,
+			existing:      &resource.CommitStatus{State: "pending", TargetURL: "https://ci.example.com", Description: "Concourse CI build success"},
+			expectUpdate:  true,
+		},
+		{
+			description:   "statuses with a different target url are set",
+			skipUnchanged: true,
+			existing:      &resource.CommitStatus{State: "success", TargetURL: "https://ci.example.com/builds/1", Description: "Concourse CI build success"},
+			expectUpdate:  true,
+		},
+		{
+			description:   "new statuses are set",
+			skipUnchanged: true,
+			expectUpdate:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetCommitStatusReturns(tc.existing, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			getForPut(t, github, source, resource.Version{PR: "pr1", Commit: "commit1"}, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+				Status:              "success",
+				TargetURL:           "https://ci.example.com",
+				SkipUnchangedStatus: tc.skipUnchanged,
+			}}, github, dir)
+			require.NoError(t, err)
+
+			if tc.skipUnchanged && assert.Equal(t, 1, github.GetCommitStatusCallCount()) {
+				commit, baseContext, context := github.GetCommitStatusArgsForCall(0)
+				assert.Equal(t, []string{"commit1", "", ""}, []string{commit, baseContext, context})
+			}
+			if tc.expectUpdate {
+				assert.Equal(t, 1, github.UpdateCommitStatusCallCount())
+			} else {
+				assert.Equal(t, 0, github.UpdateCommitStatusCallCount())
+			}
+		})
+	}
+}
+
+func TestPutFailIfOutdated(t *testing.T) {
+	tests := []struct {
+		description    string
+		failIfOutdated bool
+		head           string
+		expectedErr    string
+	}{
+		{
+			description: "outdated versions are used by default",
+			head:        "commit2",
+		},
+		{
+			description:    "current versions are used",
+			failIfOutdated: true,
+			head:           "commit1",
+		},
+		{
+			description:    "outdated versions fail",
+			failIfOutdated: true,
+			head:           "commit2",
+			expectedErr:    "version is outdated: the head of the pull request is commit2, not commit1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetPullRequestDetailsReturns(&resource.PullRequestDetailsObject{HeadRefOid: tc.head}, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			getForPut(t, github, source, resource.Version{PR: "pr1", Commit: "commit1"}, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+				Status:         "success",
+				Comment:        "all good",
+				FailIfOutdated: tc.failIfOutdated,
+			}}, github, dir)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Equal(t, 0, github.UpdateCommitStatusCallCount())
+				assert.Equal(t, 0, github.PostCommentCallCount())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, github.UpdateCommitStatusCallCount())
+		})
+	}
+}
+
+func TestPutStatusDescriptionTruncation(t *testing.T) {
+	long := strings.Repeat("ø", 150)
+
+	tests := []struct {
+		description         string
+		suffix              string
+		statusDescription   string
+		expectedDescription string
+		expectedMetadata    bool
+	}{
+		{
+			description:         "short descriptions are not truncated",
+			statusDescription:   "build failed",
+			expectedDescription: "build failed",
+		},
+		{
+			description:         "long descriptions are truncated",
+			statusDescription:   long,
+			expectedDescription: strings.Repeat("ø", 137) + "...",
+			expectedMetadata:    true,
+		},
+		{
+			description:         "long descriptions are truncated with a custom suffix",
+			suffix:              " (see build log)",
+			statusDescription:   long,
+			expectedDescription: strings.Repeat("ø", 124) + " (see build log)",
+			expectedMetadata:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+				Status:                      "failure",
+				Description:                 tc.statusDescription,
+				DescriptionTruncationSuffix: tc.suffix,
+			}}
+			output, err := resource.Put(putInput, github, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				_, _, _, _, _, description := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, tc.expectedDescription, description)
+			}
+			if tc.expectedMetadata {
+				assert.Equal(t, tc.statusDescription, output.Metadata.Get("status_full_description"))
+			} else {
+				assert.Equal(t, "", output.Metadata.Get("status_full_description"))
+			}
+		})
+	}
+}
+
+func TestVariableSubstitution(t *testing.T) {
+
+	var (
+		variableName  = "BUILD_JOB_NAME"
+		variableValue = "my-job"
+		variableURL   = "https://concourse-ci.org/"
+	)
+
+	tests := []struct {
+		description         string
+		source              resource.Source
+		version             resource.Version
+		parameters          resource.PutParameters
+		expectedComment     string
+		expectedTargetURL   string
+		expectedDescription string
+		pullRequest         *resource.PullRequest
+	}{
+
+		{
+			description: "we can substitute environment variables for Comment",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Comment: fmt.Sprintf("$%s", variableName),
+			},
+			expectedComment: variableValue,
+			pullRequest:     createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can substitute environment variables for TargetURL",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Status:    "failure",
+				TargetURL: fmt.Sprintf("%s$%s", variableURL, variableName),
+			},
+			expectedTargetURL: fmt.Sprintf("%s%s", variableURL, variableValue),
+			pullRequest:       createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can substitute metadata for TargetURL",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Status:    "success",
+				TargetURL: "https://preview.example.com/${pr_number}/${head_sha}",
+			},
+			expectedTargetURL: "https://preview.example.com/1/oid1",
+			pullRequest:       createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can substitute metadata for Description",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Status:      "success",
+				Description: "Tested PR #${pr_number} @ ${head_sha} by ${author} against ${base_branch}",
+			},
+			expectedDescription: "Tested PR #1 @ oid1 by login1 against master",
+			pullRequest:         createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we do not substitute variables other then concourse build metadata",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Comment: "$THIS_IS_NOT_SUBSTITUTED",
+			},
+			expectedComment: "$THIS_IS_NOT_SUBSTITUTED",
+			pullRequest:     createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(tc.pullRequest, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			getForPut(t, github, tc.source, tc.version, dir)
+
+			oldValue := os.Getenv(variableName)
+			defer os.Setenv(variableName, oldValue)
+
+			os.Setenv(variableName, variableValue)
+
+			putInput := resource.PutRequest{Source: tc.source, Params: tc.parameters}
+			_, err := resource.Put(putInput, github, dir)
+			require.NoError(t, err)
+
+			if tc.parameters.Status != "" {
+				if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+					_, _, _, _, targetURL, description := github.UpdateCommitStatusArgsForCall(0)
+					assert.Equal(t, tc.expectedTargetURL, targetURL)
+					assert.Equal(t, tc.expectedDescription, description)
+				}
+			}
+
+			if tc.parameters.Comment != "" {
+				if assert.Equal(t, 1, github.PostCommentCallCount()) {
+					_, comment := github.PostCommentArgsForCall(0)
+					assert.Equal(t, tc.expectedComment, comment)
+				}
+			}
+
+		})
+	}
+}
+
+func TestExpandEnvAndVars(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	oldValue := os.Getenv("BUILD_CREATED_BY")
+	defer os.Setenv("BUILD_CREATED_BY", oldValue)
+	os.Setenv("BUILD_CREATED_BY", "alice")
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		ExpandEnv:   []string{"BUILD_CREATED_BY"},
+	}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		Comment: "Triggered by $BUILD_CREATED_BY in ${environment} ($HOME)",
+		Vars:    map[string]string{"environment": "staging"},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.PostCommentCallCount()) {
+		_, comment := github.PostCommentArgsForCall(0)
+		assert.Equal(t, "Triggered by alice in staging ($HOME)", comment)
+	}
+}
+
+func TestPutCommentTemplate(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	oldValue := os.Getenv("BUILD_JOB_NAME")
+	defer os.Setenv("BUILD_JOB_NAME", oldValue)
+	os.Setenv("BUILD_JOB_NAME", "test")
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "coverage.txt"), []byte("total: 80%"), 0644))
+	template := `{{ .Env.BUILD_JOB_NAME }} for #{{ .Metadata.pr }} @ {{ .Version.Commit }}
+{{ range $pkg, $coverage := .Vars }}{{ $pkg }}: {{ $coverage }}
+{{ end }}{{ if .Vars }}{{ file "coverage.txt" }}{{ end }}`
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		CommentTemplate: template,
+		Vars:            map[string]string{"api": "90%", "web": "70%"},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.PostCommentCallCount()) {
+		_, comment := github.PostCommentArgsForCall(0)
+		assert.Equal(t, "test for #1 @ commit1\napi: 90%\nweb: 70%\ntotal: 80%", comment)
+	}
+
+	// Templates that fail to render are reported
+	putInput.Params.CommentTemplate = `{{ file "missing.txt" }}`
+	_, err = resource.Put(putInput, github, dir)
+	assert.Error(t, err)
+}
+
+func TestPutCommentFiles(t *testing.T) {
+	truncated := "\n\n**This comment has been truncated, since it exceeded the maximum length allowed by Github.**"
+	gist := "\n\n**This comment has been truncated, the full content is available [here](https://gist.github.com/1).**"
+
+	tests := []struct {
+		description string
+		files       map[string]string
+		commentFile string
+		overflow    string
+		expected    string
+	}{
+		{
+			description: "a single file is posted as-is",
+			files:       map[string]string{"output/comment.txt": "hello\n"},
+			commentFile: "output/comment.txt",
+			expected:    "hello\n",
+		},
+		{
+			description: "multiple files are concatenated with headers",
+			files:       map[string]string{"output/a.log": "a\n", "output/b.log": "b\n"},
+			commentFile: "output/*.log",
+			expected:    "### output/a.log\n\na\n\n### output/b.log\n\nb",
+		},
+		{
+			description: "long comments are truncated",
+			files:       map[string]string{"output/long.txt": strings.Repeat("x", 70000)},
+			commentFile: "output/long.txt",
+			expected:    strings.Repeat("x", 65536-len(truncated)) + truncated,
+		},
+		{
+			description: "long comments can be uploaded as a gist",
+			files:       map[string]string{"output/long.txt": strings.Repeat("x", 70000)},
+			commentFile: "output/long.txt",
+			overflow:    "gist",
+			expected:    strings.Repeat("x", 65536-len(gist)) + gist,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.CreateGistReturns("https://gist.github.com/1", nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "output"), 0755))
+			for name, content := range tc.files {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+
+			putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{CommentFile: tc.commentFile, Overflow: tc.overflow}}
+			_, err := resource.Put(putInput, github, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.PostCommentCallCount()) {
+				_, comment := github.PostCommentArgsForCall(0)
+				assert.Equal(t, len(tc.expected), len(comment))
+				assert.Equal(t, tc.expected, comment)
+			}
+
+			if tc.overflow == "gist" {
+				if assert.Equal(t, 1, github.CreateGistCallCount()) {
+					description, filename, content := github.CreateGistArgsForCall(0)
+					assert.Equal(t, "itsdalmo/test-repository#pr1", description)
+					assert.Equal(t, "comment.md", filename)
+					assert.Equal(t, tc.files[tc.commentFile], content)
+				}
+			}
+		})
+	}
+}
+
+func TestPutInputFileSizeLimit(t *testing.T) {
+	tests := []struct {
+		description string
+		file        string
+		size        int64
+		params      resource.PutParameters
+		expectError string
+	}{
+		{
+			description: "comment files are limited to 10 MiB",
+			file:        "output/build.log",
+			size:        2 << 30,
+			params:      resource.PutParameters{CommentFile: "output/build.log"},
+			expectError: "build.log is 2147483648 bytes, which exceeds the limit of 10485760 bytes",
+		},
+		{
+			description: "commit files are limited to 64 KiB",
+			file:        "output/commit",
+			size:        65537,
+			params:      resource.PutParameters{CommitFile: "output/commit", Status: "success"},
+			expectError: "commit is 65537 bytes, which exceeds the limit of 65536 bytes",
+		},
+		{
+			description: "the total size of multiple comment files is limited",
+			file:        "output/*.log",
+			size:        6 << 20,
+			params:      resource.PutParameters{CommentFile: "output/*.log"},
+			expectError: "files matching output/*.log exceed the limit of 10485760 bytes",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			// Create (sparse) files of the given size
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "output"), 0755))
+			names := []string{tc.file}
+			if strings.Contains(tc.file, "*") {
+				names = []string{"output/a.log", "output/b.log"}
+			}
+			for _, name := range names {
+				f, err := os.Create(filepath.Join(dir, name))
+				require.NoError(t, err)
+				require.NoError(t, f.Truncate(tc.size))
+				require.NoError(t, f.Close())
+			}
+
+			putInput := resource.PutRequest{Source: source, Params: tc.params}
+			_, err := resource.Put(putInput, github, dir)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expectError)
+			}
+			assert.Equal(t, 0, github.PostCommentCallCount())
+			assert.Equal(t, 0, github.UpdateCommitStatusCallCount())
+		})
+	}
+}
+
+func TestPutDefaultBuildURL(t *testing.T) {
+	tests := []struct {
+		description string
+		env         map[string]string
+		expected    string
+	}{
+		{
+			description: "build url is empty outside of Concourse",
+			env:         map[string]string{},
+			expected:    "",
+		},
+		{
+			description: "one-off builds link to the build",
+			env: map[string]string{
+				"ATC_EXTERNAL_URL": "https://ci.example.com/",
+				"BUILD_ID":         "42",
+			},
+			expected: "https://ci.example.com/builds/42",
+		},
+		{
+			description: "pipeline builds link to the job build",
+			env: map[string]string{
+				"ATC_EXTERNAL_URL":    "https://ci.example.com",
+				"BUILD_ID":            "42",
+				"BUILD_TEAM_NAME":     "main",
+				"BUILD_PIPELINE_NAME": "pull requests",
+				"BUILD_JOB_NAME":      "test",
+				"BUILD_NAME":          "7",
+			},
+			expected: "https://ci.example.com/teams/main/pipelines/pull%20requests/jobs/test/builds/7",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			for _, name := range []string{"ATC_EXTERNAL_URL", "BUILD_ID", "BUILD_TEAM_NAME", "BUILD_PIPELINE_NAME", "BUILD_JOB_NAME", "BUILD_NAME"} {
+				oldValue := os.Getenv(name)
+				defer os.Setenv(name, oldValue)
+				os.Setenv(name, tc.env[name])
+			}
+
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+				CheckRun: &resource.CheckRunParameters{Name: "test", Status: "in_progress"},
+			}}
+			_, err := resource.Put(putInput, github, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.UpdateCheckRunCallCount()) {
+				_, run := github.UpdateCheckRunArgsForCall(0)
+				assert.Equal(t, tc.expected, run.DetailsURL)
+			}
+		})
+	}
+}
+
+func TestPutWithoutGet(t *testing.T) {
+	tests := []struct {
+		description string
+		parameters  resource.PutParameters
+		versionFile string
+	}{
+		{
+			description: "we can specify the pull request and commit",
+			parameters:  resource.PutParameters{PRNumber: "1", Commit: "oid1", Status: "success"},
+		},
+		{
+			description: "commit defaults to the head of the pull request",
+			parameters:  resource.PutParameters{PRNumber: "1", Status: "success"},
+		},
+		{
+			description: "we can read the version from a file",
+			parameters:  resource.PutParameters{VersionFile: "version/version.json", Status: "success"},
+			versionFile: `{"pr":"1","commit":"oid1"}`,
+		},
+		{
+			description: "the version parameter is used if there is no version in the path",
+			parameters:  resource.PutParameters{Path: "pull-request", Version: &resource.Version{PR: "1", Commit: "oid1"}, Status: "success"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetPullRequestDetailsReturns(&resource.PullRequestDetailsObject{HeadRefOid: "oid1"}, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			if tc.versionFile != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, "version"), 0755))
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, tc.parameters.VersionFile), []byte(tc.versionFile), 0644))
+			}
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			output, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			require.NoError(t, err)
+
+			assert.Equal(t, "1", output.Version.PR)
+			assert.Equal(t, "oid1", output.Version.Commit)
+			assert.Equal(t, "pr1", output.Metadata.Get("head_name"))
+
+			if assert.Equal(t, 1, github.GetPullRequestCallCount()) {
+				pr, commit := github.GetPullRequestArgsForCall(0)
+				assert.Equal(t, "1", pr)
+				assert.Equal(t, "oid1", commit)
+			}
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				commit, _, _, _, _, _ := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, "oid1", commit)
+			}
+		})
+	}
+}
+
+func TestPutVersionMatchesCheck(t *testing.T) {
+	now := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+
+	// The reviews and readiness are only part of the listed pull request
+	listed := createTestPR(1, "master", false, false, 1, nil, false, githubv4.PullRequestStateOpen)
+	listed.Tip.AuthoredDate = githubv4.DateTime{Time: now.Add(-2 * time.Minute)}
+	listed.Tip.CommittedDate = githubv4.DateTime{Time: now.Add(-time.Minute)}
+	listed.UpdatedAt = githubv4.DateTime{Time: now.Add(time.Minute)}
+	listed.ApprovedDate = githubv4.DateTime{Time: now}
+	listed.Contexts = []resource.CommitContext{{Name: "ci", State: "success", Date: githubv4.DateTime{Time: now.Add(30 * time.Second)}}}
+	pull := *listed
+	pull.ApprovedReviewCount = 0
+	pull.ApprovedDate = githubv4.DateTime{}
+	pull.Contexts = nil
+
+	tests := []struct {
+		description string
+		source      resource.Source
+	}{
+		{
+			description: "default",
+			source:      resource.Source{},
+		},
+		{
+			description: "order_by",
+			source:      resource.Source{OrderBy: "authored"},
+		},
+		{
+			description: "include_updated_at",
+			source:      resource.Source{IncludeUpdatedAt: true},
+		},
+		{
+			description: "when_ready_to_merge",
+			source:      resource.Source{WhenReadyToMerge: true},
+		},
+		{
+			description: "without reviews",
+			source:      resource.Source{CheckFetch: []string{"labels"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsStub = func(_ []githubv4.PullRequestState, fields resource.PullRequestFields, _ time.Time) ([]*resource.PullRequest, error) {
+				if !fields.Reviews {
+					p := pull
+					return []*resource.PullRequest{&p}, nil
+				}
+				return []*resource.PullRequest{listed}, nil
+			}
+			github.GetPullRequestReturns(&pull, nil)
+
+			source := tc.source
+			source.Repository = "itsdalmo/test-repository"
+			source.AccessToken = "oauthtoken"
+			require.NoError(t, source.Validate())
+
+			versions, err := resource.Check(resource.CheckRequest{Source: source}, github)
+			require.NoError(t, err)
+			require.Len(t, versions, 1)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+			output, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{PRNumber: "1", Commit: "oid1"}}, github, dir)
+			require.NoError(t, err)
+			assert.Equal(t, versions[0], output.Version)
+		})
+	}
+}
+
+func TestPutMissingVersion(t *testing.T) {
+	tests := []struct {
+		description string
+		inputs      map[string]bool
+		expected    string
+	}{
+		{
+			description: "suggests the input from a get step",
+			inputs:      map[string]bool{"pull-request": true, "tasks": false},
+			expected:    "(did you mean path: pull-request?)",
+		},
+		{
+			description: "lists the inputs from get steps",
+			inputs:      map[string]bool{"pr-1": true, "pr-2": true},
+			expected:    "(path must be one of the inputs from a get step of this resource: pr-1, pr-2)",
+		},
+		{
+			description: "lists all inputs if none are from a get step",
+			inputs:      map[string]bool{"source": false, "tasks": false},
+			expected:    "(none of the inputs are from a get step of this resource: source, tasks)",
+		},
+		{
+			description: "explains how to put without inputs",
+			expected:    "(there are no inputs, use pr_number or version_file without a get step)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			for name, fromGet := range tc.inputs {
+				path := filepath.Join(dir, name)
+				if fromGet {
+					path = filepath.Join(path, ".git", "resource")
+				}
+				require.NoError(t, os.MkdirAll(path, 0755))
+				if fromGet {
+					require.NoError(t, ioutil.WriteFile(filepath.Join(path, "version.json"), []byte(`{"pr":"1"}`), 0644))
+				}
+			}
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{Path: "pr", Status: "success"}}, new(fakes.FakeGithub), dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to read version from path")
+			assert.True(t, strings.HasSuffix(err.Error(), tc.expected), err.Error())
+		})
+	}
+}
+
+func TestPutDiscoverPath(t *testing.T) {
+	tests := []struct {
+		description string
+		inputs      []string
+		expectedErr string
+	}{
+		{
+			description: "the input from a get step is used when path is not set",
+			inputs:      []string{"pull-request", "tasks"},
+		},
+		{
+			description: "path must be set when there are several inputs from a get step",
+			inputs:      []string{"pr-1", "pr-2", "tasks"},
+			expectedErr: "path must be set, since there are several inputs from a get step of this resource: pr-1, pr-2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			for _, input := range tc.inputs {
+				if input == "tasks" {
+					require.NoError(t, os.MkdirAll(filepath.Join(dir, input), 0755))
+					continue
+				}
+				getForPut(t, github, source, resource.Version{PR: "1", Commit: "commit1"}, filepath.Join(dir, input))
+			}
+
+			output, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{Status: "success"}}, github, dir)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "commit1", output.Version.Commit)
+			assert.Equal(t, 1, github.UpdateCommitStatusCallCount())
+		})
+	}
+}
+
+func TestPutStatusOnCommit(t *testing.T) {
+	tests := []struct {
+		description string
+		parameters  resource.PutParameters
+		commitFile  string
+		expected    string
+	}{
+		{
+			description: "statuses are set on the commit from the version",
+			parameters:  resource.PutParameters{Status: "success"},
+			expected:    "commit1",
+		},
+		{
+			description: "we can set statuses on another commit",
+			parameters:  resource.PutParameters{Status: "success", CommitSHA: "merge-queue-sha"},
+			expected:    "merge-queue-sha",
+		},
+		{
+			description: "we can read the commit from a file",
+			parameters:  resource.PutParameters{Status: "success", CommitFile: "commit.txt"},
+			commitFile:  "squashed-sha\n",
+			expected:    "squashed-sha",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			if tc.commitFile != "" {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, tc.parameters.CommitFile), []byte(tc.commitFile), 0644))
+			}
+
+			output, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			require.NoError(t, err)
+			assert.Equal(t, version, output.Version)
+
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				commit, _, _, _, _, _ := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, tc.expected, commit)
+			}
+		})
+	}
+}
+
+func TestPutTag(t *testing.T) {
+	tests := []struct {
+		description     string
+		parameters      resource.TagParameters
+		state           githubv4.PullRequestState
+		expectedTag     string
+		expectedRelease *resource.Release
+		expectedErr     string
+	}{
+		{
+			description: "we can tag the merge commit",
+			parameters:  resource.TagParameters{Name: "pr-${PR_NUMBER}"},
+			state:       githubv4.PullRequestStateMerged,
+			expectedTag: "pr-1",
+		},
+		{
+			description: "we can create a release",
+			parameters:  resource.TagParameters{Name: "v1.0.0", Message: "Release v1.0.0", Release: true, ReleaseNotes: "${PR_TITLE}", Prerelease: true},
+			state:       githubv4.PullRequestStateMerged,
+			expectedTag: "v1.0.0",
+			expectedRelease: &resource.Release{
+				TagName:    "v1.0.0",
+				Name:       "v1.0.0",
+				Body:       "pr1 title",
+				Prerelease: true,
+			},
+		},
+		{
+			description: "pull requests that are not merged are not tagged",
+			parameters:  resource.TagParameters{Name: "v1.0.0"},
+			state:       githubv4.PullRequestStateOpen,
+			expectedErr: "refusing to tag pull request that has not been merged: OPEN",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			details := &resource.PullRequestDetailsObject{State: tc.state}
+			if tc.state == githubv4.PullRequestStateMerged {
+				details.MergeCommit = &struct {
+					Oid string `json:"oid"`
+				}{Oid: "merge-sha"}
+			}
+
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetPullRequestDetailsReturns(details, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			parameters := tc.parameters
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{Tag: &parameters}}, github, dir)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Equal(t, 0, github.CreateTagCallCount())
+				return
+			}
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.CreateTagCallCount()) {
+				name, commit, message := github.CreateTagArgsForCall(0)
+				assert.Equal(t, tc.expectedTag, name)
+				assert.Equal(t, "merge-sha", commit)
+				assert.Equal(t, tc.parameters.Message, message)
+			}
+			if tc.expectedRelease != nil {
+				if assert.Equal(t, 1, github.CreateReleaseCallCount()) {
+					assert.Equal(t, *tc.expectedRelease, github.CreateReleaseArgsForCall(0))
+				}
+			} else {
+				assert.Equal(t, 0, github.CreateReleaseCallCount())
+			}
+		})
+	}
+}
+
+func TestPutDispatch(t *testing.T) {
+	tests := []struct {
+		description     string
+		parameters      resource.DispatchParameters
+		payloadFile     string
+		expectedPayload map[string]interface{}
+		expectedErr     string
+	}{
+		{
+			description:     "the payload identifies the pull request",
+			parameters:      resource.DispatchParameters{EventType: "publish"},
+			expectedPayload: map[string]interface{}{"pr": "pr1", "commit": "commit1"},
+		},
+		{
+			description: "the payload can be extended with a file and values using metadata",
+			parameters: resource.DispatchParameters{
+				EventType:         "publish",
+				ClientPayload:     map[string]string{"title": "${PR_TITLE}", "target": "staging"},
+				ClientPayloadFile: "payload.json",
+			},
+			payloadFile:     `{"target": "production", "artifacts": ["a", "b"]}`,
+			expectedPayload: map[string]interface{}{"pr": "pr1", "commit": "commit1", "title": "pr1 title", "target": "staging", "artifacts": []interface{}{"a", "b"}},
+		},
+		{
+			description: "the payload file must be a json object",
+			parameters:  resource.DispatchParameters{EventType: "publish", ClientPayloadFile: "payload.json"},
+			payloadFile: `["a", "b"]`,
+			expectedErr: "failed to unmarshal dispatch client payload file (must be a JSON object)",
+		},
+		{
+			description: "the payload cannot have more than 10 top-level properties",
+			parameters:  resource.DispatchParameters{EventType: "publish", ClientPayloadFile: "payload.json"},
+			payloadFile: `{"a":1,"b":2,"c":3,"d":4,"e":5,"f":6,"g":7,"h":8,"i":9}`,
+			expectedErr: "dispatch client payload has 11 top-level properties (at most 10 are allowed)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			if tc.payloadFile != "" {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "payload.json"), []byte(tc.payloadFile), 0644))
+			}
+
+			parameters := tc.parameters
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{Dispatch: &parameters}}, github, dir)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				assert.Equal(t, 0, github.DispatchCallCount())
+				return
+			}
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.DispatchCallCount()) {
+				eventType, payload := github.DispatchArgsForCall(0)
+				assert.Equal(t, "publish", eventType)
+				assert.Equal(t, tc.expectedPayload, payload)
+			}
+		})
+	}
+}
+
+func TestPutLock(t *testing.T) {
+	locked, unlocked := true, false
+
+	tests := []struct {
+		description    string
+		parameters     resource.PutParameters
+		expectLocked   bool
+		expectUnlocked bool
+	}{
+		{
+			description:  "we can lock the conversation",
+			parameters:   resource.PutParameters{Lock: &locked, LockReason: "resolved"},
+			expectLocked: true,
+		},
+		{
+			description:    "we can unlock the conversation",
+			parameters:     resource.PutParameters{Lock: &unlocked},
+			expectUnlocked: true,
+		},
+		{
+			description: "lock is left alone by default",
+			parameters:  resource.PutParameters{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			require.NoError(t, err)
+
+			if tc.expectLocked {
+				if assert.Equal(t, 1, github.LockPullRequestCallCount()) {
+					pr, reason := github.LockPullRequestArgsForCall(0)
+					assert.Equal(t, version.PR, pr)
+					assert.Equal(t, tc.parameters.LockReason, reason)
+				}
+			} else {
+				assert.Equal(t, 0, github.LockPullRequestCallCount())
+			}
+			if tc.expectUnlocked {
+				if assert.Equal(t, 1, github.UnlockPullRequestCallCount()) {
+					assert.Equal(t, version.PR, github.UnlockPullRequestArgsForCall(0))
+				}
+			} else {
+				assert.Equal(t, 0, github.UnlockPullRequestCallCount())
+			}
+		})
+	}
+}
+
+func TestPutSkipDuplicateComments(t *testing.T) {
+	tests := []struct {
+		description   string
+		parameters    resource.PutParameters
+		latestComment string
+		expectPosted  bool
+	}{
+		{
+			description:   "identical comments are skipped",
+			parameters:    resource.PutParameters{Comment: "build failed", SkipDuplicateComments: true},
+			latestComment: "build failed\n",
+			expectPosted:  false,
+		},
+		{
+			description:   "changed comments are posted",
+			parameters:    resource.PutParameters{Comment: "build succeeded", SkipDuplicateComments: true},
+			latestComment: "build failed",
+			expectPosted:  true,
+		},
+		{
+			description:  "comments are posted if there is no previous comment",
+			parameters:   resource.PutParameters{Comment: "build failed", SkipDuplicateComments: true},
+			expectPosted: true,
+		},
+		{
+			description:   "duplicates are posted by default",
+			parameters:    resource.PutParameters{Comment: "build failed"},
+			latestComment: "build failed",
+			expectPosted:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetLatestCommentReturns(tc.latestComment, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			require.NoError(t, err)
+
+			if tc.parameters.SkipDuplicateComments {
+				assert.Equal(t, 1, github.GetLatestCommentCallCount())
+			} else {
+				assert.Equal(t, 0, github.GetLatestCommentCallCount())
+			}
+			if tc.expectPosted {
+				if assert.Equal(t, 1, github.PostCommentCallCount()) {
+					pr, comment := github.PostCommentArgsForCall(0)
+					assert.Equal(t, version.PR, pr)
+					assert.Equal(t, tc.parameters.Comment, comment)
+				}
+			} else {
+				assert.Equal(t, 0, github.PostCommentCallCount())
+			}
+		})
+	}
+}
+
+func TestPutReviewReply(t *testing.T) {
+	tests := []struct {
+		description       string
+		parameters        resource.ReviewReplyParameters
+		files             map[string]string
+		expectFindThread  bool
+		expectedCommentID int64
+		expectedBody      string
+	}{
+		{
+			description:       "we can reply to a review comment by id",
+			parameters:        resource.ReviewReplyParameters{CommentID: 42, Body: "reply"},
+			expectedCommentID: 42,
+			expectedBody:      "reply",
+		},
+		{
+			description:       "we can reply to a review comment by id from a file",
+			parameters:        resource.ReviewReplyParameters{CommentIDFile: "id.txt", BodyFile: "reply.md"},
+			files:             map[string]string{"id.txt": "43\n", "reply.md": "reply from file"},
+			expectedCommentID: 43,
+			expectedBody:      "reply from file",
+		},
+		{
+			description:       "we can reply to the review thread on a path and line",
+			parameters:        resource.ReviewReplyParameters{Path: "main.go", Line: 12, Body: "reply"},
+			expectFindThread:  true,
+			expectedCommentID: 44,
+			expectedBody:      "reply",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.FindReviewThreadReturns(44, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			for name, content := range tc.files {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			parameters := tc.parameters
+			putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{ReviewReply: &parameters}}
+			_, err := resource.Put(putInput, github, dir)
+			require.NoError(t, err)
+
+			if tc.expectFindThread {
+				if assert.Equal(t, 1, github.FindReviewThreadCallCount()) {
+					pr, path, line := github.FindReviewThreadArgsForCall(0)
+					assert.Equal(t, version.PR, pr)
+					assert.Equal(t, tc.parameters.Path, path)
+					assert.Equal(t, tc.parameters.Line, line)
+				}
+			} else {
+				assert.Equal(t, 0, github.FindReviewThreadCallCount())
+			}
+			if assert.Equal(t, 1, github.ReplyToReviewCommentCallCount()) {
+				pr, id, body := github.ReplyToReviewCommentArgsForCall(0)
+				assert.Equal(t, version.PR, pr)
+				assert.Equal(t, tc.expectedCommentID, id)
+				assert.Equal(t, tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestPutProject(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	parameters := resource.PutParameters{
+		Project: &resource.ProjectParameters{
+			Number: 7,
+			Fields: map[string]string{"Status": "In Review", "Branch": "${HEAD_NAME}"},
+		},
+	}
+	_, err := resource.Put(resource.PutRequest{Source: source, Params: parameters}, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.AddToProjectCallCount()) {
+		pr, project := github.AddToProjectArgsForCall(0)
+		assert.Equal(t, version.PR, pr)
+		assert.Equal(t, resource.Project{
+			Number: 7,
+			Fields: map[string]string{"Status": "In Review", "Branch": "pr1"},
+		}, project)
+	}
+}
+
+func TestPutSuggestions(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	diff := "--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-func main()  {\n+func main() {\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "gofmt.diff"), []byte(diff), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "empty.diff"), []byte{}, 0644))
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		SuggestionsFile: "gofmt.diff",
+		SuggestionsBody: "Please run gofmt",
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.CreateSuggestionsCallCount()) {
+		pr, commit, body, suggestions := github.CreateSuggestionsArgsForCall(0)
+		assert.Equal(t, version.PR, pr)
+		assert.Equal(t, version.Commit, commit)
+		assert.Equal(t, "Please run gofmt", body)
+		assert.Equal(t, []resource.Suggestion{{Path: "main.go", StartLine: 1, Line: 1, Replacement: []string{"func main() {"}}}, suggestions)
+	}
+
+	// An empty diff does not post a review
+	putInput.Params.SuggestionsFile = "empty.diff"
+	_, err = resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, github.CreateSuggestionsCallCount())
+}
+
+func TestPutCreatedMetadata(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+	github.PostCommentReturns("https://github.com/itsdalmo/test-repository/pull/1#issuecomment-1", nil)
+	github.UpdateCheckRunReturns(42, nil)
+	github.MergePullRequestReturns("merge-sha", nil)
+	github.APIUsageReturns(resource.APIUsage{RESTCalls: 7, RESTRemaining: 4993, GraphQLCalls: 2, GraphQLRemaining: -1})
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		Status:    "success",
+		Context:   "unit",
+		TargetURL: "https://ci.example.com/builds/1",
+		Comment:   "all good",
+		CheckRun:  &resource.CheckRunParameters{Name: "lint", Conclusion: "success"},
+		Merge:     &resource.MergeParameters{},
+	}}
+	output, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	// Metadata from the get step is kept
+	assert.Equal(t, "pr1", output.Metadata.Get("head_name"))
+
+	assert.Equal(t, "https://ci.example.com/builds/1", output.Metadata.Get("unit_target_url"))
+	assert.Equal(t, "https://github.com/itsdalmo/test-repository/pull/1#issuecomment-1", output.Metadata.Get("comment_url"))
+	assert.Equal(t, "42", output.Metadata.Get("check_run_id"))
+	assert.Equal(t, "merge-sha", output.Metadata.Get("merge_commit_sha"))
+
+	assert.Equal(t, "7", output.Metadata.Get("api_rest_calls"))
+	assert.Equal(t, "4993", output.Metadata.Get("api_rest_remaining"))
+	assert.Equal(t, "2", output.Metadata.Get("api_graphql_calls"))
+	assert.Equal(t, "unknown", output.Metadata.Get("api_graphql_remaining"))
+}
+
+func TestPutLabels(t *testing.T) {
+	statusLabels := map[string]resource.LabelParameters{
+		"success": {Add: []string{"ci-passed"}, Remove: []string{"needs-work"}},
+		"failure": {Add: []string{"needs-work"}, Remove: []string{"ci-passed"}},
+	}
+
+	tests := []struct {
+		description    string
+		parameters     resource.PutParameters
+		expectedAdd    []string
+		expectedRemove []string
+	}{
+		{
+			description:    "we can add and remove labels",
+			parameters:     resource.PutParameters{AddLabels: []string{"bot"}, RemoveLabels: []string{"wip"}},
+			expectedAdd:    []string{"bot"},
+			expectedRemove: []string{"wip"},
+		},
+		{
+			description:    "labels for a successful status",
+			parameters:     resource.PutParameters{Status: "success", StatusLabels: statusLabels},
+			expectedAdd:    []string{"ci-passed"},
+			expectedRemove: []string{"needs-work"},
+		},
+		{
+			description:    "labels for a failed status are added to the other labels",
+			parameters:     resource.PutParameters{Status: "FAILURE", StatusLabels: statusLabels, AddLabels: []string{"bot"}},
+			expectedAdd:    []string{"bot", "needs-work"},
+			expectedRemove: []string{"ci-passed"},
+		},
+		{
+			description: "no labels for a status without a mapping",
+			parameters:  resource.PutParameters{Status: "pending", StatusLabels: statusLabels},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			require.NoError(t, err)
+
+			if tc.expectedAdd != nil {
+				if assert.Equal(t, 1, github.AddLabelsCallCount()) {
+					pr, labels := github.AddLabelsArgsForCall(0)
+					assert.Equal(t, version.PR, pr)
+					assert.Equal(t, tc.expectedAdd, labels)
+				}
+			} else {
+				assert.Equal(t, 0, github.AddLabelsCallCount())
+			}
+			if tc.expectedRemove != nil {
+				if assert.Equal(t, 1, github.RemoveLabelsCallCount()) {
+					pr, labels := github.RemoveLabelsArgsForCall(0)
+					assert.Equal(t, version.PR, pr)
+					assert.Equal(t, tc.expectedRemove, labels)
+				}
+			} else {
+				assert.Equal(t, 0, github.RemoveLabelsCallCount())
+			}
+		})
+	}
+
+	// Labels can only be mapped to valid statuses
+	putInput := resource.PutRequest{
+		Source: resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+		Params: resource.PutParameters{Status: "success", StatusLabels: map[string]resource.LabelParameters{"passed": {}}},
+	}
+	_, err := resource.Put(putInput, new(fakes.FakeGithub), "")
+	assert.EqualError(t, err, "invalid parameters: unknown status in status_labels: passed")
+}
+
+func TestPutCommentSections(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "test.txt"), []byte("--- FAIL: TestPut\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "coverage.md"), []byte("| pkg | 80% |\n"), 0644))
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		Comment: "Build failed",
+		CommentSections: []resource.CommentSectionParameters{
+			{File: "test.txt", Summary: "Test output (click to expand)", Language: "text"},
+			{File: "coverage.md", Open: true},
+		},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	expected := "Build failed\n\n" +
+		"<details>\n<summary>Test output (click to expand)</summary>\n\n```text\n--- FAIL: TestPut\n```\n\n</details>\n\n" +
+		"<details open>\n<summary>coverage.md</summary>\n\n| pkg | 80% |\n\n</details>"
+	if assert.Equal(t, 1, github.PostCommentCallCount()) {
+		_, comment := github.PostCommentArgsForCall(0)
+		assert.Equal(t, expected, comment)
+	}
+
+	// Sections must have a file
+	putInput.Params.CommentSections = []resource.CommentSectionParameters{{Summary: "Test output"}}
+	_, err = resource.Put(putInput, github, dir)
+	assert.EqualError(t, err, "invalid parameters: comment_sections[].file must be set")
+}
+
+func TestPutRequestCodeowners(t *testing.T) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+	details := &resource.PullRequestDetailsObject{BaseRefOid: "base-sha"}
+	details.Author.Login = "author"
+	github.GetPullRequestDetailsReturns(details, nil)
+	github.GetFileContentStub = func(path, ref string) ([]byte, error) {
+		if path != "CODEOWNERS" {
+			return nil, errors.New("404 Not Found")
+		}
+		return []byte("* @author\n*.md @docs docs@example.com\n/terraform/ @org/platform @ops\n"), nil
+	}
+	github.GetChangedFilesReturns([]resource.ChangedFileObject{
+		{Path: "main.go"},
+		{Path: "README.md"},
+		{Path: "terraform/main.tf"},
+		{Path: "docs/index.md"},
+	}, nil)
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+	getForPut(t, github, source, version, dir)
+
+	putInput := resource.PutRequest{Source: source, Params: resource.PutParameters{
+		RequestCodeowners: true,
+		RequestReviewers:  []string{"reviewer"},
+	}}
+	_, err := resource.Put(putInput, github, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 2, github.GetFileContentCallCount()) {
+		path, ref := github.GetFileContentArgsForCall(0)
+		assert.Equal(t, ".github/CODEOWNERS", path)
+		assert.Equal(t, "base-sha", ref)
+	}
+	if assert.Equal(t, 1, github.RequestReviewersCallCount()) {
+		pr, users, teams := github.RequestReviewersArgsForCall(0)
+		assert.Equal(t, version.PR, pr)
+		assert.Equal(t, []string{"reviewer", "docs", "ops"}, users)
+		assert.Equal(t, []string{"platform"}, teams)
+	}
+}
+
+func TestPutLinkedIssues(t *testing.T) {
+	tests := []struct {
+		description     string
+		parameters      resource.PutParameters
+		state           githubv4.PullRequestState
+		expectedComment string
+		expectedClosed  []string
+		expectedError   string
+	}{
+		{
+			description:     "we can comment on linked issues",
+			parameters:      resource.PutParameters{LinkedIssuesComment: "Released in ${PR_TITLE}"},
+			state:           githubv4.PullRequestStateOpen,
+			expectedComment: "Released in pr1 title",
+		},
+		{
+			description:     "we can close linked issues of a merged pull request",
+			parameters:      resource.PutParameters{LinkedIssuesComment: "Released", CloseLinkedIssues: true},
+			state:           githubv4.PullRequestStateMerged,
+			expectedComment: "Released",
+			expectedClosed:  []string{"issue1"},
+		},
+		{
+			description:   "linked issues of open pull requests are not closed",
+			parameters:    resource.PutParameters{CloseLinkedIssues: true},
+			state:         githubv4.PullRequestStateOpen,
+			expectedError: "refusing to close linked issues of pull request that has not been merged: OPEN",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetPullRequestDetailsReturns(&resource.PullRequestDetailsObject{State: tc.state}, nil)
+			github.GetLinkedIssuesReturns([]resource.IssueObject{
+				{ID: "issue1", Number: 1, State: githubv4.IssueStateOpen},
+				{ID: "issue2", Number: 2, State: githubv4.IssueStateClosed},
+			}, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Equal(t, 0, github.CloseIssueCallCount())
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expectedComment != "" {
+				if assert.Equal(t, 2, github.CommentOnIssueCallCount()) {
+					for i, id := range []string{"issue1", "issue2"} {
+						issueID, comment := github.CommentOnIssueArgsForCall(i)
+						assert.Equal(t, id, issueID)
+						assert.Equal(t, tc.expectedComment, comment)
+					}
+				}
+			}
+			if assert.Equal(t, len(tc.expectedClosed), github.CloseIssueCallCount()) {
+				for i, id := range tc.expectedClosed {
+					assert.Equal(t, id, github.CloseIssueArgsForCall(i))
+				}
+			}
+		})
+	}
+}
+
+func TestPutTokenAccess(t *testing.T) {
+	tests := []struct {
+		description string
+		tokenInfo   *resource.TokenInfo
+		parameters  resource.PutParameters
+		expectError string
+	}{
+		{
+			description: "unknown token access is not checked",
+			parameters:  resource.PutParameters{Status: "success", Comment: "comment"},
+		},
+		{
+			description: "status requires the repo:status scope",
+			tokenInfo:   &resource.TokenInfo{Private: true, Scopes: []string{"read:org"}},
+			parameters:  resource.PutParameters{Status: "success"},
+			expectError: "token lacks repo:status scope required for `status` param",
+		},
+		{
+			description: "public_repo scope implies repo:status and repo_deployment for public repositories",
+			tokenInfo:   &resource.TokenInfo{Scopes: []string{"public_repo"}},
+			parameters:  resource.PutParameters{Status: "success", Deployment: &resource.DeploymentParameters{Environment: "staging", State: "SUCCESS"}},
+		},
+		{
+			description: "public_repo scope does not imply repo:status for private repositories",
+			tokenInfo:   &resource.TokenInfo{Private: true, Scopes: []string{"public_repo"}},
+			parameters:  resource.PutParameters{Status: "success"},
+			expectError: "token lacks repo:status scope required for `status` param",
+		},
+		{
+			description: "repo scope implies repo:status",
+			tokenInfo:   &resource.TokenInfo{Private: true, Scopes: []string{"repo"}},
+			parameters:  resource.PutParameters{Status: "success", Comment: "comment"},
+		},
+		{
+			description: "public_repo scope is enough for public repositories",
+			tokenInfo:   &resource.TokenInfo{Scopes: []string{"public_repo"}},
+			parameters:  resource.PutParameters{Comment: "comment"},
+		},
+		{
+			description: "repo scope is required for private repositories",
+			tokenInfo:   &resource.TokenInfo{Private: true, Scopes: []string{"public_repo", "repo:status"}},
+			parameters:  resource.PutParameters{Comment: "comment"},
+			expectError: "token lacks repo scope required for `comment` param",
+		},
+		{
+			description: "check runs require authenticating as a github app",
+			tokenInfo:   &resource.TokenInfo{Scopes: []string{"repo"}},
+			parameters:  resource.PutParameters{CheckRun: &resource.CheckRunParameters{Name: "test", Status: "completed", Conclusion: "success"}},
+			expectError: "`check_run` param requires authenticating as a Github App",
+		},
+		{
+			description: "app permissions are checked",
+			tokenInfo:   &resource.TokenInfo{Permissions: map[string]string{"statuses": "read", "pull_requests": "write"}},
+			parameters:  resource.PutParameters{Comment: "comment", Status: "success"},
+			expectError: "app installation lacks statuses:write permission required for `status` param",
+		},
+		{
+			description: "fine-grained token permissions are not checked",
+			tokenInfo:   &resource.TokenInfo{FineGrained: true},
+			parameters:  resource.PutParameters{Comment: "comment", Status: "success"},
+		},
+		{
+			description: "check runs are not supported with fine-grained tokens",
+			tokenInfo:   &resource.TokenInfo{FineGrained: true},
+			parameters:  resource.PutParameters{CheckRun: &resource.CheckRunParameters{Name: "test", Status: "completed", Conclusion: "success"}},
+			expectError: "`check_run` param requires authenticating as a Github App",
+		},
+		{
+			description: "any of the app permissions are sufficient",
+			tokenInfo:   &resource.TokenInfo{Permissions: map[string]string{"pull_requests": "write"}},
+			parameters:  resource.PutParameters{Comment: "comment"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.GetTokenInfoReturns(tc.tokenInfo, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			version := resource.Version{PR: "pr1", Commit: "commit1"}
+
+			getForPut(t, github, source, version, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.Equal(t, 0, github.UpdateCommitStatusCallCount())
+				assert.Equal(t, 0, github.PostCommentCallCount())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPutCommentCooldown(t *testing.T) {
+	marker := func(context string, ago time.Duration) string {
+		return fmt.Sprintf("<!-- github-pr-resource-cooldown: %s %s -->", context, time.Now().Add(-ago).UTC().Format(time.RFC3339))
+	}
+
+	tests := []struct {
+		description string
+		tag         string
+		previous    string
+		context     string
+		expectPost  bool
+	}{
+		{
+			description: "comments are posted if there is no previous comment",
+			context:     "concourse-ci/status",
+			expectPost:  true,
+		},
+		{
+			description: "comments are skipped within the cooldown",
+			previous:    "build failed\n" + marker("concourse-ci/status", 10*time.Minute),
+			context:     "concourse-ci/status",
+		},
+		{
+			description: "comments are posted after the cooldown",
+			previous:    "build failed\n" + marker("concourse-ci/status", 2*time.Hour),
+			context:     "concourse-ci/status",
+			expectPost:  true,
+		},
+		{
+			description: "the comment tag is used as the context",
+			tag:         "lint",
+			previous:    "lint failed\n" + marker("lint", 10*time.Minute),
+			context:     "lint",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.FindCommentReturns(tc.previous, nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			getForPut(t, github, source, resource.Version{PR: "pr1", Commit: "commit1"}, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+				Comment:         "build failed",
+				CommentTag:      tc.tag,
+				CommentCooldown: "1h",
+			}}, github, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.FindCommentCallCount()) {
+				pr, text := github.FindCommentArgsForCall(0)
+				assert.Equal(t, "pr1", pr)
+				assert.Equal(t, "<!-- github-pr-resource-cooldown: "+tc.context+" ", text)
+			}
+
+			var comments []string
+			for i := 0; i < github.PostCommentCallCount(); i++ {
+				_, comment := github.PostCommentArgsForCall(i)
+				comments = append(comments, comment)
+			}
+			for i := 0; i < github.UpsertCommentCallCount(); i++ {
+				_, _, comment := github.UpsertCommentArgsForCall(i)
+				comments = append(comments, comment)
+			}
+			if !tc.expectPost {
+				assert.Empty(t, comments)
+				return
+			}
+			if assert.Len(t, comments, 1) {
+				assert.Contains(t, comments[0], "build failed\n<!-- github-pr-resource-cooldown: "+tc.context+" ")
+			}
+		})
+	}
+}
+
+func TestPutContextPrefix(t *testing.T) {
+	tests := []struct {
+		description string
+		baseContext string
+		expected    string
+	}{
+		{
+			description: "the prefix is added to the default base context",
+			expected:    "concourse/team-a/concourse-ci",
+		},
+		{
+			description: "the prefix is added to the base context",
+			baseContext: "ci",
+			expected:    "concourse/team-a/ci",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", ContextPrefix: "concourse/team-a/"}
+			getForPut(t, github, source, resource.Version{PR: "pr1", Commit: "commit1"}, dir)
+
+			_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+				Status:      "success",
+				BaseContext: tc.baseContext,
+				Context:     "unit-test",
+				CheckRun:    &resource.CheckRunParameters{Name: "lint", Conclusion: "success"},
+			}}, github, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				_, baseContext, context, _, _, _ := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, tc.expected, baseContext)
+				assert.Equal(t, "unit-test", context)
+			}
+			if assert.Equal(t, 1, github.UpdateCheckRunCallCount()) {
+				_, run := github.UpdateCheckRunArgsForCall(0)
+				assert.Equal(t, "concourse/team-a/lint", run.Name)
+				assert.Equal(t, "lint", run.Title)
+			}
+		})
+	}
+}
+
+func TestPutCommitComment(t *testing.T) {
+	tests := []struct {
+		description string
+		params      resource.PutParameters
+		file        string
+		expected    string
+	}{
+		{
+			description: "commit comments are posted on the commit of the version",
+			params:      resource.PutParameters{CommitComment: "sha256: abc"},
+			expected:    "sha256: abc",
+		},
+		{
+			description: "commit comments can be read from a file",
+			params:      resource.PutParameters{CommitCommentFile: "provenance/comment.md"},
+			file:        "built by concourse",
+			expected:    "built by concourse",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), nil)
+			github.CreateCommitCommentReturns("https://github.com/itsdalmo/test-repository/commit/commit1#commitcomment-1", nil)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+			getForPut(t, github, source, resource.Version{PR: "pr1", Commit: "commit1"}, dir)
+
+			if tc.file != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, "provenance"), os.ModePerm))
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "provenance", "comment.md"), []byte(tc.file), 0644))
+			}
+
+			output, err := resource.Put(resource.PutRequest{Source: source, Params: tc.params}, github, dir)
+			require.NoError(t, err)
+
+			if assert.Equal(t, 1, github.CreateCommitCommentCallCount()) {
+				commit, comment := github.CreateCommitCommentArgsForCall(0)
+				assert.Equal(t, "commit1", commit)
+				assert.Equal(t, tc.expected, comment)
+			}
+			assert.Equal(t, 0, github.PostCommentCallCount())
+			assert.Equal(t, "https://github.com/itsdalmo/test-repository/commit/commit1#commitcomment-1", output.Metadata.Get("commit_comment_url"))
+		})
+	}
+}
+
+func TestPutMinimizeTaggedComment(t *testing.T) {
+	github := fakes.NewMemoryGithub("itsdalmo", "test-repository")
+	github.AddPullRequest(1, "master", "feature-1", resource.CommitObject{OID: "oid1"})
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	put := func(comment string) {
+		_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+			PRNumber:                 "1",
+			Comment:                  comment,
+			CommentTag:               "report",
+			MinimizePreviousComments: true,
+		}}, github, dir)
+		require.NoError(t, err)
+	}
+	put("first")
+	put("second")
+
+	// The tagged comment is minimized, so the new content is posted as a new comment instead of updating it
+	comments := github.PullRequests[1].Comments
+	require.Len(t, comments, 2)
+	assert.Equal(t, "first\n\n<!-- github-pr-resource: report -->", comments[0].Body)
+	assert.NotEmpty(t, comments[0].Minimized)
+	assert.Equal(t, "second\n\n<!-- github-pr-resource: report -->", comments[1].Body)
+	assert.Empty(t, comments[1].Minimized)
+}
+
+func TestPutNamedCommentSections(t *testing.T) {
+	github := fakes.NewMemoryGithub("itsdalmo", "test-repository")
+	github.AddPullRequest(1, "master", "feature-1", resource.CommitObject{OID: "oid1"})
+	github.PullRequests[1].Comments = append(github.PullRequests[1].Comments, fakes.MemoryComment{ID: 100, Author: "someone", Body: "lgtm"})
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"}
+	put := func(section, comment string) {
+		_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{
+			PRNumber:   "1",
+			Comment:    comment,
+			CommentTag: "report",
+			Section:    section,
+		}}, github, dir)
+		require.NoError(t, err)
+	}
+	put("coverage", "Coverage: 80%")
+	put("lint", "Lint: 2 warnings")
+	put("coverage", "Coverage: 85%")
+
+	comments := github.PullRequests[1].Comments
+	require.Len(t, comments, 2)
+	assert.Equal(t, "lgtm", comments[0].Body)
+	assert.Equal(t, `<!-- github-pr-resource-section: coverage -->
+Coverage: 85%
+<!-- /github-pr-resource-section: coverage -->
+
+<!-- github-pr-resource-section: lint -->
+Lint: 2 warnings
+<!-- /github-pr-resource-section: lint -->
+
+<!-- github-pr-resource: report -->`, comments[1].Body)
+
+	_, err := resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{PRNumber: "1", Comment: "hi", Section: "lint"}}, github, dir)
+	assert.EqualError(t, err, "invalid parameters: comment_tag must be set to update a section of the comment")
+}
+
+// getForPut runs get (with a fake git client) for the version in dir, which provides the version and
+// metadata for a put request.
+func getForPut(t *testing.T, github resource.Github, source resource.Source, version resource.Version, dir string) {
+	git := new(fakes.FakeGit)
+	git.RevParseReturns("sha", nil)
+	_, err := resource.Get(resource.GetRequest{Source: source, Version: version}, github, git, dir)
+	require.NoError(t, err)
+}
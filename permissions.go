@@ -0,0 +1,177 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// TokenInfo describes the access granted to the token used to authenticate with Github.
+type TokenInfo struct {
+	// Private is true if the repository is private.
+	Private bool
+	// Scopes of a (classic) personal access token, or nil for other tokens.
+	Scopes []string
+	// Permissions of a Github App installation, or nil for other tokens.
+	Permissions map[string]string
+	// FineGrained is true for fine-grained personal access tokens, whose permissions cannot be
+	// verified in advance.
+	FineGrained bool
+}
+
+// fineGrainedTokenPrefix is the prefix of fine-grained personal access tokens.
+const fineGrainedTokenPrefix = "github_pat_"
+
+// repoScope is a placeholder for the scope required to write to the repository, which is
+// "repo" for private repositories and "public_repo" for public ones.
+const repoScope = "<repo>"
+
+// tokenRequirement is the access required by a parameter, as a (classic) OAuth scope and the
+// equivalent Github App permissions (any of which suffice).
+type tokenRequirement struct {
+	param       string
+	scope       string
+	permissions []string
+}
+
+// putRequirements returns the access required by the put parameters.
+func putRequirements(p PutParameters) []tokenRequirement {
+	var requirements []tokenRequirement
+	require := func(set bool, param, scope string, permissions ...string) {
+		if set {
+			requirements = append(requirements, tokenRequirement{param: param, scope: scope, permissions: permissions})
+		}
+	}
+
+	require(p.Status != "", "status", "repo:status", "statuses")
+	require(len(p.Statuses) > 0, "statuses", "repo:status", "statuses")
+	require(p.Deployment != nil, "deployment", "repo_deployment", "deployments")
+	require(p.CheckRun != nil, "check_run", "", "checks")
+
+	comment := p.Comment != "" || p.CommentFile != "" || p.CommentTemplate != "" || p.CommentTemplateFile != "" || len(p.CommentSections) > 0
+	require(comment, "comment", repoScope, "issues", "pull_requests")
+	require(comment && p.Overflow == "gist", "overflow", "gist")
+	require(p.CommitComment != "" || p.CommitCommentFile != "", "commit_comment", repoScope, "contents")
+	require(p.ReactToComment != 0 || p.ReactToCommentFile != "", "react_to_comment", repoScope, "issues", "pull_requests")
+	require(p.DeletePreviousComments || p.DeletePreviousCommentsMatching != "" || p.MinimizePreviousComments, "delete_previous_comments", repoScope, "issues", "pull_requests")
+	require(len(p.AddLabels) > 0 || len(p.RemoveLabels) > 0 || len(p.StatusLabels) > 0, "add_labels", repoScope, "issues", "pull_requests")
+	require(len(p.Assignees) > 0, "assignees", repoScope, "issues", "pull_requests")
+	require(p.Milestone != "", "milestone", repoScope, "issues", "pull_requests")
+	require(p.LinkedIssuesComment != "" || p.LinkedIssuesCommentFile != "" || p.CloseLinkedIssues, "linked_issues_comment", repoScope, "issues")
+
+	require(len(p.RequestReviewers) > 0 || len(p.RequestTeamReviewers) > 0 || p.RequestCodeowners, "request_reviewers", repoScope, "pull_requests")
+	require(p.Title != "" || p.Body != "" || p.BodyFile != "", "title", repoScope, "pull_requests")
+	require(p.Review != "" || p.ReviewReply != nil || p.SuggestionsFile != "" || p.DismissReviews, "review", repoScope, "pull_requests")
+	require(p.MarkReady || p.MarkDraft, "mark_ready", repoScope, "pull_requests")
+	require(p.Close || p.Reopen || p.Lock != nil, "close", repoScope, "pull_requests")
+	require(p.Project != nil, "project", "project", "organization_projects")
+
+	require(p.Merge != nil || p.EnableAutoMerge, "merge", repoScope, "contents")
+	require(p.UpdateBranch, "update_branch", repoScope, "contents")
+	require(p.DeleteBranch, "delete_branch", repoScope, "contents")
+	require(p.Tag != nil, "tag", repoScope, "contents")
+	require(p.Dispatch != nil, "dispatch", repoScope, "contents")
+	return requirements
+}
+
+// checkTokenAccess returns an error describing the first requirement which is not met by the token.
+// Tokens for which the access is unknown (e.g. fine-grained personal access tokens) are not checked.
+func checkTokenAccess(info *TokenInfo, requirements []tokenRequirement) error {
+	if info == nil {
+		return nil
+	}
+	for _, r := range requirements {
+		switch {
+		case info.Permissions != nil:
+			if len(r.permissions) == 0 {
+				return fmt.Errorf("`%s` param is not supported when authenticating as a Github App", r.param)
+			}
+			if !hasPermission(info.Permissions, r.permissions) {
+				return fmt.Errorf("app installation lacks %s:write permission required for `%s` param", r.permissions[0], r.param)
+			}
+		case info.FineGrained:
+			if len(r.permissions) > 0 && r.scope == "" {
+				return fmt.Errorf("`%s` param requires authenticating as a Github App", r.param)
+			}
+		case info.Scopes != nil:
+			scope := r.scope
+			if scope == repoScope {
+				scope = "public_repo"
+				if info.Private {
+					scope = "repo"
+				}
+			}
+			if scope == "" {
+				return fmt.Errorf("`%s` param requires authenticating as a Github App", r.param)
+			}
+			if !hasScope(info.Scopes, scope, info.Private) {
+				return fmt.Errorf("token lacks %s scope required for `%s` param", scope, r.param)
+			}
+		}
+	}
+	return nil
+}
+
+// hasScope returns true if the scopes include the given scope, or a scope that implies it (public_repo
+// grants access to the statuses and deployments of public repositories).
+// https://docs.github.com/en/developers/apps/building-oauth-apps/scopes-for-oauth-apps
+func hasScope(scopes []string, scope string, private bool) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+		switch s {
+		case "repo":
+			if strings.HasPrefix(scope, "repo:") || scope == "repo_deployment" || scope == "public_repo" {
+				return true
+			}
+		case "public_repo":
+			if !private && (scope == "repo:status" || scope == "repo_deployment") {
+				return true
+			}
+		case "admin:org":
+			if scope == "read:org" || scope == "write:org" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasPermission returns true if the app installation has write access for any of the given permissions.
+func hasPermission(granted map[string]string, permissions []string) bool {
+	for _, p := range permissions {
+		if granted[p] == "write" || granted[p] == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// permissionsTransport turns responses which were forbidden due to missing permissions of a fine-grained
+// personal access token (or Github App installation) into errors which name the permissions that are required.
+type permissionsTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *permissionsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, err
+	}
+	accepted := resp.Header.Get("X-Accepted-GitHub-Permissions")
+	if accepted == "" {
+		return resp, nil
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	var message struct {
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &message)
+	return nil, fmt.Errorf("%s (the token requires the following permissions: %s)", message.Message, strings.Replace(accepted, "; ", ", ", -1))
+}
@@ -0,0 +1,27 @@
+package resource
+
+import (
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc returns the proxy configuration for the Github API clients, where the proxies configured in
+// the source take precedence over the environment (HTTP_PROXY, HTTPS_PROXY and NO_PROXY).
+func proxyFunc(s *Source) func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	if s.HTTPProxy != "" {
+		config.HTTPProxy = s.HTTPProxy
+	}
+	if s.HTTPSProxy != "" {
+		config.HTTPSProxy = s.HTTPSProxy
+	}
+	if s.NoProxy != "" {
+		config.NoProxy = s.NoProxy
+	}
+	proxy := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
@@ -0,0 +1,58 @@
+package resource_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientProxy(t *testing.T) {
+	tests := []struct {
+		description string
+		noProxy     string
+		expectProxy bool
+	}{
+		{
+			description: "requests are sent through the proxy",
+			expectProxy: true,
+		},
+		{
+			description: "hosts in no_proxy are not proxied",
+			noProxy:     "github.example.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var proxied []string
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				proxied = append(proxied, r.Method+" "+r.URL.String())
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{}`))
+			}))
+			defer proxy.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				APIEndpoint: "http://github.example.com",
+				HTTPProxy:   proxy.URL,
+				NoProxy:     tc.noProxy,
+			})
+			require.NoError(t, err)
+
+			_, err = client.PostComment("1", "comment")
+			if tc.expectProxy {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"POST http://github.example.com/api/v3/repos/itsdalmo/test-repository/issues/1/comments"}, proxied)
+			} else {
+				assert.Error(t, err)
+				assert.Empty(t, proxied)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package resource
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secrets in logs and errors.
+const redacted = "REDACTED"
+
+var (
+	// secrets which are known to the resource (e.g. the access token), and are redacted wherever they appear.
+	secretsMu sync.Mutex
+	secrets   []string
+
+	// secretPatterns match credentials which are not known in advance (e.g. tokens of submodules).
+	secretPatterns = []struct {
+		pattern     *regexp.Regexp
+		replacement string
+	}{
+		// Passwords in URLs, e.g. https://x-oauth-basic:<token>@github.com
+		{regexp.MustCompile(`(://[^/@\s:]*:)[^/@\s]+@`), "${1}" + redacted + "@"},
+		// Authorization headers
+		{regexp.MustCompile(`(?i)(authorization:\s*)(\w+\s+)?[^\s"']+`), "${1}${2}" + redacted},
+		// Github tokens, see https://github.blog/2021-04-05-behind-githubs-new-authentication-token-formats/
+		{regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`), redacted},
+	}
+)
+
+// addSecret registers a secret which is redacted from logs and errors.
+func addSecret(secret string) {
+	// Short values are ignored, since they are not real credentials (and would redact too much)
+	secret = strings.TrimSpace(secret)
+	if len(secret) < 8 {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, s := range secrets {
+		if s == secret {
+			return
+		}
+	}
+	secrets = append(secrets, secret)
+}
+
+// Redact replaces the known secrets, and anything that looks like credentials, in s.
+func Redact(s string) string {
+	secretsMu.Lock()
+	for _, secret := range secrets {
+		s = strings.Replace(s, secret, redacted, -1)
+	}
+	secretsMu.Unlock()
+	for _, p := range secretPatterns {
+		s = p.pattern.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// redactingWriter redacts secrets from everything written to it.
+type redactingWriter struct {
+	w io.Writer
+}
+
+// NewRedactingWriter returns a writer which redacts secrets (see Redact) before writing to w. Each write is
+// redacted separately, so it should be used for writers which receive whole lines (e.g. log output).
+func NewRedactingWriter(w io.Writer) io.Writer {
+	if _, ok := w.(*redactingWriter); ok {
+		return w
+	}
+	return &redactingWriter{w: w}
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,244 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v28/github"
+	"github.com/shurcooL/githubv4"
+)
+
+// API modes (api_mode). In the default (automatic) mode, the REST API is used once a GraphQL
+// request fails because the endpoint is not available.
+const (
+	apiModeGraphQL = "graphql"
+	apiModeREST    = "rest"
+)
+
+// isGraphQLUnavailable returns true if a GraphQL request failed because the endpoint does not exist
+// (e.g. it has been disabled or blocked on a Github Enterprise Server installation).
+func isGraphQLUnavailable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// fallbackToREST returns true if a failed GraphQL request should be made with the REST API instead,
+// in which case the REST API is used for the remaining requests as well.
+func (m *GithubClient) fallbackToREST(err error) bool {
+	if m.apiMode != "" || !isGraphQLUnavailable(err) {
+		return false
+	}
+	logger.Warn("the GraphQL API is not available, falling back to the REST API", "error", err)
+	m.rest = true
+	return true
+}
+
+// listPullRequestsREST implements ListPullRequests with the REST API. Force pushes and the readiness
+// of pull requests to be merged are not available.
+func (m *GithubClient) listPullRequestsREST(prStates []githubv4.PullRequestState, fields PullRequestFields, since time.Time) ([]*PullRequest, error) {
+	if fields.Readiness {
+		return nil, errors.New("when_ready_to_merge is not supported by the REST API")
+	}
+
+	wanted := make(map[githubv4.PullRequestState]bool)
+	for _, s := range prStates {
+		wanted[s] = true
+	}
+	state := "all"
+	switch {
+	case !wanted[githubv4.PullRequestStateClosed] && !wanted[githubv4.PullRequestStateMerged]:
+		state = "open"
+	case !wanted[githubv4.PullRequestStateOpen]:
+		state = "closed"
+	}
+
+	opt := &github.PullRequestListOptions{
+		State:       state,
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: pullRequestsPageSize},
+	}
+
+	var response []*PullRequest
+Pages:
+	for {
+		pulls, res, err := m.V3.PullRequests.List(context.TODO(), m.Owner, m.Repository, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pulls {
+			pull := pullRequestFromREST(p)
+			// Pull requests which have not been updated since cannot have new commits (nor be closed or merged)
+			if !since.IsZero() && !pull.UpdatedAt.After(since) {
+				break Pages
+			}
+			if !wanted[pull.State] {
+				continue
+			}
+
+			commit, _, err := m.V3.Repositories.GetCommit(context.TODO(), m.Owner, m.Repository, p.GetHead().GetSHA())
+			if err != nil {
+				return nil, err
+			}
+
+			pr := &PullRequest{
+				PullRequestObject: pull,
+				Tip:               commitFromREST(commit),
+				Labels:            labelsFromREST(p.Labels),
+			}
+			if fields.Reviews {
+				if pr.ApprovedReviewCount, err = m.approvedReviewCount(p.GetNumber()); err != nil {
+					return nil, err
+				}
+			}
+			if fields.Files {
+				if pr.Files, err = m.ListModifiedFiles(p.GetNumber()); err != nil {
+					return nil, err
+				}
+			}
+			response = append(response, pr)
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opt.Page = res.NextPage
+	}
+	return response, nil
+}
+
+// getPullRequestREST implements GetPullRequest with the REST API.
+func (m *GithubClient) getPullRequestREST(pr int, commitRef string) (*PullRequest, error) {
+	p, _, err := m.V3.PullRequests.Get(context.TODO(), m.Owner, m.Repository, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]RequestedReviewerObject, 0, len(p.RequestedReviewers)+len(p.RequestedTeams))
+	for _, u := range p.RequestedReviewers {
+		var r RequestedReviewerObject
+		r.Typename = "User"
+		r.User.Login = u.GetLogin()
+		reviewers = append(reviewers, r)
+	}
+	for _, t := range p.RequestedTeams {
+		var r RequestedReviewerObject
+		r.Typename = "Team"
+		r.Team.Slug = t.GetSlug()
+		reviewers = append(reviewers, r)
+	}
+
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		commits, res, err := m.V3.PullRequests.ListCommits(context.TODO(), m.Owner, m.Repository, pr, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range commits {
+			if c.GetSHA() == commitRef {
+				return &PullRequest{
+					PullRequestObject:  pullRequestFromREST(p),
+					Tip:                commitFromREST(c),
+					Labels:             labelsFromREST(p.Labels),
+					RequestedReviewers: reviewers,
+				}, nil
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opt.Page = res.NextPage
+	}
+	return nil, &CommitNotFoundError{Ref: commitRef}
+}
+
+// approvedReviewCount returns the number of approving reviews of a pull request.
+func (m *GithubClient) approvedReviewCount(pr int) (int, error) {
+	var count int
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, res, err := m.V3.PullRequests.ListReviews(context.TODO(), m.Owner, m.Repository, pr, opt)
+		if err != nil {
+			return 0, err
+		}
+		for _, r := range reviews {
+			if r.GetState() == "APPROVED" {
+				count++
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opt.Page = res.NextPage
+	}
+	return count, nil
+}
+
+// pullRequestFromREST converts a pull request from the REST API.
+func pullRequestFromREST(p *github.PullRequest) PullRequestObject {
+	pull := PullRequestObject{
+		ID:          p.GetNodeID(),
+		Number:      p.GetNumber(),
+		Title:       p.GetTitle(),
+		URL:         p.GetHTMLURL(),
+		BaseRefName: p.GetBase().GetRef(),
+		HeadRefName: p.GetHead().GetRef(),
+		IsDraft:     p.GetDraft(),
+		State:       githubv4.PullRequestStateOpen,
+		ClosedAt:    githubv4.DateTime{Time: p.GetClosedAt()},
+		MergedAt:    githubv4.DateTime{Time: p.GetMergedAt()},
+		UpdatedAt:   githubv4.DateTime{Time: p.GetUpdatedAt()},
+	}
+	pull.Repository.URL = p.GetBase().GetRepo().GetHTMLURL()
+	if head := p.GetHead().GetRepo(); head != nil {
+		pull.HeadRepository = &struct{ NameWithOwner string }{NameWithOwner: head.GetFullName()}
+		pull.IsCrossRepository = head.GetFullName() != p.GetBase().GetRepo().GetFullName()
+	} else {
+		pull.IsCrossRepository = true
+	}
+	switch {
+	case p.MergedAt != nil:
+		pull.State = githubv4.PullRequestStateMerged
+	case p.GetState() == "closed":
+		pull.State = githubv4.PullRequestStateClosed
+	}
+	return pull
+}
+
+// commitFromREST converts a commit from the REST API.
+func commitFromREST(c *github.RepositoryCommit) CommitObject {
+	commit := CommitObject{
+		ID:            c.GetNodeID(),
+		OID:           c.GetSHA(),
+		CommittedDate: githubv4.DateTime{Time: c.GetCommit().GetCommitter().GetDate()},
+		AuthoredDate:  githubv4.DateTime{Time: c.GetCommit().GetAuthor().GetDate()},
+		Message:       c.GetCommit().GetMessage(),
+	}
+	commit.Author.User.Login = c.GetAuthor().GetLogin()
+	commit.Author.Name = c.GetCommit().GetAuthor().GetName()
+	commit.Author.Email = c.GetCommit().GetAuthor().GetEmail()
+	return commit
+}
+
+// labelsFromREST converts the labels of a pull request from the REST API.
+func labelsFromREST(labels []*github.Label) []LabelObject {
+	result := make([]LabelObject, 0, len(labels))
+	for _, l := range labels {
+		result = append(result, LabelObject{Name: l.GetName()})
+	}
+	return result
+}
+
+// changedFilesREST implements GetChangedFiles with the REST API.
+func (m *GithubClient) changedFilesREST(pr int) ([]ChangedFileObject, error) {
+	files, err := m.ListModifiedFiles(pr)
+	if err != nil {
+		return nil, err
+	}
+	cfo := make([]ChangedFileObject, 0, len(files))
+	for _, f := range files {
+		cfo = append(cfo, ChangedFileObject{Path: f})
+	}
+	return cfo, nil
+}
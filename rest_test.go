@@ -0,0 +1,175 @@
+package resource_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestRESTFallback(t *testing.T) {
+	responses := map[string]string{
+		"/repos/itsdalmo/test-repository/pulls": `[
+			{"node_id":"PR_1","number":1,"title":"open","html_url":"https://github.com/itsdalmo/test-repository/pull/1","state":"open","updated_at":"2020-01-02T00:00:00Z",
+			 "labels":[{"name":"enhancement"}],
+			 "head":{"ref":"pr1","sha":"oid1","repo":{"full_name":"itsdalmo/test-repository"}},
+			 "base":{"ref":"master","repo":{"full_name":"itsdalmo/test-repository","html_url":"https://github.com/itsdalmo/test-repository"}}},
+			{"node_id":"PR_2","number":2,"title":"merged","state":"closed","updated_at":"2020-01-01T00:00:00Z","merged_at":"2020-01-01T00:00:00Z",
+			 "head":{"ref":"pr2","sha":"oid2","repo":{"full_name":"fork/test-repository"}},
+			 "base":{"ref":"master","repo":{"full_name":"itsdalmo/test-repository"}}}
+		]`,
+		"/repos/itsdalmo/test-repository/pulls/1": `{"node_id":"PR_1","number":1,"state":"open",
+			"requested_reviewers":[{"login":"octocat"}],"requested_teams":[{"slug":"reviewers"}],
+			"head":{"ref":"pr1","sha":"oid1","repo":{"full_name":"itsdalmo/test-repository"}},
+			"base":{"ref":"master","repo":{"full_name":"itsdalmo/test-repository"}}}`,
+		"/repos/itsdalmo/test-repository/pulls/1/commits": `[{"sha":"oid1","commit":{"message":"commit 1","committer":{"date":"2020-01-02T00:00:00Z"}}}]`,
+		"/repos/itsdalmo/test-repository/pulls/1/reviews": `[{"state":"APPROVED"},{"state":"COMMENTED"}]`,
+		"/repos/itsdalmo/test-repository/pulls/2/reviews": `[]`,
+		"/repos/itsdalmo/test-repository/commits/oid1":    `{"node_id":"C_1","sha":"oid1","author":{"login":"octocat"},"commit":{"message":"commit 1","author":{"name":"Octo Cat","date":"2020-01-01T12:00:00Z"},"committer":{"date":"2020-01-02T00:00:00Z"}}}`,
+		"/repos/itsdalmo/test-repository/commits/oid2":    `{"node_id":"C_2","sha":"oid2","commit":{"message":"commit 2","committer":{"date":"2019-12-31T00:00:00Z"}}}`,
+	}
+
+	tests := []struct {
+		description string
+		apiMode     string
+	}{
+		{
+			description: "falls back to the rest api when graphql is not available",
+		},
+		{
+			description: "uses the rest api when api_mode is rest",
+			apiMode:     "rest",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var graphqlRequests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/graphql" {
+					graphqlRequests++
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"message":"Not Found"}`))
+					return
+				}
+				body, ok := responses[r.URL.Path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			source := resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+				APIMode:     tc.apiMode,
+			}
+			require.NoError(t, source.Validate())
+			client, err := resource.NewGithubClient(&source)
+			require.NoError(t, err)
+
+			states := []githubv4.PullRequestState{githubv4.PullRequestStateOpen}
+			pulls, err := client.ListPullRequests(states, resource.PullRequestFields{Reviews: true}, time.Time{})
+			require.NoError(t, err)
+			require.Len(t, pulls, 1)
+			assert.Equal(t, 1, pulls[0].Number)
+			assert.Equal(t, "pr1", pulls[0].HeadRefName)
+			assert.Equal(t, "oid1", pulls[0].Tip.OID)
+			assert.Equal(t, "octocat", pulls[0].Tip.Author.User.Login)
+			assert.Equal(t, "2020-01-02T00:00:00Z", pulls[0].Tip.CommittedDate.Format(time.RFC3339))
+			assert.Equal(t, 1, pulls[0].ApprovedReviewCount)
+			assert.Equal(t, []resource.LabelObject{{Name: "enhancement"}}, pulls[0].Labels)
+			assert.False(t, pulls[0].IsCrossRepository)
+
+			pulls, err = client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateMerged}, resource.PullRequestFields{}, time.Time{})
+			require.NoError(t, err)
+			require.Len(t, pulls, 1)
+			assert.Equal(t, githubv4.PullRequestStateMerged, pulls[0].State)
+			assert.True(t, pulls[0].IsCrossRepository)
+
+			pull, err := client.GetPullRequest("1", "oid1")
+			require.NoError(t, err)
+			assert.Equal(t, "commit 1", pull.Tip.Message)
+			var reviewers []string
+			for _, r := range pull.RequestedReviewers {
+				reviewers = append(reviewers, r.Name())
+			}
+			assert.Equal(t, []string{"octocat", "reviewers"}, reviewers)
+
+			_, err = client.GetPullRequest("1", "missing")
+			assert.IsType(t, &resource.CommitNotFoundError{}, err)
+
+			if tc.apiMode == "rest" {
+				assert.Equal(t, 0, graphqlRequests)
+			} else {
+				assert.Equal(t, 1, graphqlRequests)
+			}
+		})
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	var graphqlRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/graphql":
+			graphqlRequests++
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/repos/itsdalmo/test-repository":
+			w.Write([]byte(`{"private":false}`))
+		case "/repos/itsdalmo/private-repository":
+			w.WriteHeader(http.StatusNotFound)
+		case "/repos/itsdalmo/test-repository/pulls":
+			w.Write([]byte(`[{"number":1,"state":"open","updated_at":"2020-01-02T00:00:00Z","head":{"ref":"pr1","sha":"oid1"},"base":{"ref":"master"}}]`))
+		case "/repos/itsdalmo/test-repository/commits/oid1":
+			w.Write([]byte(`{"sha":"oid1","commit":{"message":"commit 1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository: "itsdalmo/test-repository",
+		V3Endpoint: server.URL + "/",
+		V4Endpoint: server.URL + "/graphql",
+	}
+	require.NoError(t, source.Validate())
+	client, err := resource.NewGithubClient(&source)
+	require.NoError(t, err)
+
+	token, err := client.Token()
+	require.NoError(t, err)
+	assert.Empty(t, token)
+
+	_, err = client.GetTokenInfo()
+	require.NoError(t, err)
+
+	pulls, err := client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, pulls, 1)
+	assert.Equal(t, "oid1", pulls[0].Tip.OID)
+	assert.Equal(t, 0, graphqlRequests)
+
+	private := source
+	private.Repository = "itsdalmo/private-repository"
+	client, err = resource.NewGithubClient(&private)
+	require.NoError(t, err)
+	_, err = client.GetTokenInfo()
+	assert.EqualError(t, err, "repository itsdalmo/private-repository does not exist or is not public (no credentials are set)")
+
+	_, err = resource.Put(resource.PutRequest{Source: source, Params: resource.PutParameters{Status: "success"}}, client, "")
+	assert.EqualError(t, err, "put requires access_token, access_token_file or app_id")
+}
@@ -0,0 +1,253 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// retry calls fn until it succeeds or the given number of attempts has been
+// exhausted, doubling the delay between each attempt. The last error is returned.
+func retry(attempts int, delay time.Duration, fn func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+const (
+	// apiAttempts is the number of attempts made for requests to the Github API.
+	apiAttempts = 4
+
+	// apiRetryDelay is the initial delay between attempts (unless Github sends a Retry-After header).
+	apiRetryDelay = time.Second
+
+	// apiMaxRetryAfter is the longest Retry-After we are willing to wait for.
+	apiMaxRetryAfter = time.Minute
+
+	// apiRateLimitReserve is the number of requests left in the rate limit at which we wait
+	// for the rate limit to reset, instead of exhausting it.
+	apiRateLimitReserve = 10
+
+	// apiMaxRateLimitWait is the longest we are willing to wait for the rate limit to reset.
+	apiMaxRateLimitWait = 15 * time.Minute
+)
+
+// retryTransport retries requests to the Github API which failed due to server errors (5xx)
+// or rate limits (403/429 with a Retry-After header or an exhausted rate limit), with exponential
+// backoff. It also waits for the rate limit to reset before it is exhausted, based on the X-RateLimit
+// headers (which are returned by both the V3 and V4 APIs).
+type retryTransport struct {
+	base http.RoundTripper
+
+	// attempts, delay (the initial backoff) and timeout (per attempt) default to apiAttempts,
+	// apiRetryDelay and no timeout respectively.
+	attempts int
+	delay    time.Duration
+	timeout  time.Duration
+
+	mu         sync.Mutex
+	rateLimits map[string]rateLimit
+	calls      map[string]int
+}
+
+// rateLimit is the state of the rate limit for a Github API resource (e.g. core or graphql).
+type rateLimit struct {
+	remaining int
+	reset     time.Time
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.waitForRateLimit(req)
+
+	attempts, delay := apiAttempts, apiRetryDelay
+	if t.attempts > 0 {
+		attempts = t.attempts
+	}
+	if t.delay > 0 {
+		delay = t.delay
+	}
+	for i := 1; ; i++ {
+		attempt := req
+		if i > 1 {
+			attempt = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attempt.Body = body
+			}
+		}
+
+		t.countCall(req)
+		resp, err := t.roundTrip(attempt)
+		t.updateRateLimit(req, resp)
+		wait, ok := retryAfter(req, resp, err, delay)
+		if !ok || i >= attempts || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		time.Sleep(wait)
+		delay *= 2
+	}
+}
+
+// roundTrip sends a single attempt of a request, which is cancelled if it (including reading the
+// response body) takes longer than the timeout.
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody cancels the context of a request when the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retryAfter returns how long to wait before retrying, and whether the request should be retried at all.
+func retryAfter(req *http.Request, resp *http.Response, err error, delay time.Duration) (time.Duration, bool) {
+	if _, ok := err.(*notRecordedError); ok {
+		return 0, false
+	}
+	if err != nil {
+		// Network errors (and timeouts) are only retried for requests without side effects.
+		return delay, isReadOnly(req)
+	}
+	limited := resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+	if limited && resp.Header.Get("Retry-After") == "" && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		// Primary rate limit, which resets at the given time
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		wait := time.Until(time.Unix(reset, 0))
+		if wait > apiMaxRateLimitWait {
+			return 0, false
+		}
+		return wait, true
+	}
+	rateLimited := limited && resp.Header.Get("Retry-After") != ""
+	if resp.StatusCode < 500 && !rateLimited {
+		return 0, false
+	}
+	// Server errors (e.g. a 502 from a proxy) are only retried for requests without side effects, since
+	// the change might have been made anyway (and would be made twice, e.g. posting a duplicate comment).
+	if !rateLimited && !isReadOnly(req) {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		wait := time.Duration(seconds) * time.Second
+		if wait > apiMaxRetryAfter {
+			return 0, false
+		}
+		return wait, true
+	}
+	return delay, true
+}
+
+// isReadOnly returns true for requests without side effects, i.e. V3 GET requests and V4 queries.
+func isReadOnly(req *http.Request) bool {
+	if req.Method == http.MethodGet {
+		return true
+	}
+	if rateLimitResource(req) != "graphql" || req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+	var graphql struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&graphql); err != nil {
+		return false
+	}
+	return !strings.HasPrefix(strings.TrimSpace(graphql.Query), "mutation")
+}
+
+// rateLimitResource returns the rate limit resource used by a request.
+func rateLimitResource(req *http.Request) string {
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		return "graphql"
+	}
+	return "core"
+}
+
+// countCall records a request (attempt) to the Github API.
+func (t *retryTransport) countCall(req *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.calls == nil {
+		t.calls = make(map[string]int)
+	}
+	t.calls[rateLimitResource(req)]++
+}
+
+// updateRateLimit records the state of the rate limit from the response headers.
+func (t *retryTransport) updateRateLimit(req *http.Request, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rateLimits == nil {
+		t.rateLimits = make(map[string]rateLimit)
+	}
+	t.rateLimits[rateLimitResource(req)] = rateLimit{remaining: remaining, reset: time.Unix(reset, 0)}
+}
+
+// waitForRateLimit waits for the rate limit to reset if (nearly) all requests have been used.
+func (t *retryTransport) waitForRateLimit(req *http.Request) {
+	t.mu.Lock()
+	limit, ok := t.rateLimits[rateLimitResource(req)]
+	t.mu.Unlock()
+	if !ok || limit.remaining > apiRateLimitReserve {
+		return
+	}
+	if wait := time.Until(limit.reset); wait > 0 && wait <= apiMaxRateLimitWait {
+		time.Sleep(wait)
+	}
+}
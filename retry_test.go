@@ -0,0 +1,291 @@
+package resource_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientRetries(t *testing.T) {
+	tests := []struct {
+		description      string
+		readOnly         bool
+		responses        []int
+		retryAfter       string
+		rateLimited      bool
+		maxRetries       *int
+		expectedRequests int
+		expectError      bool
+	}{
+		{
+			description:      "successful requests are not retried",
+			responses:        []int{http.StatusCreated},
+			expectedRequests: 1,
+		},
+		{
+			description:      "server errors are retried",
+			readOnly:         true,
+			responses:        []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusCreated},
+			retryAfter:       "0",
+			expectedRequests: 3,
+		},
+		{
+			description:      "server errors are not retried for requests which change something",
+			responses:        []int{http.StatusBadGateway, http.StatusCreated},
+			retryAfter:       "0",
+			expectedRequests: 1,
+			expectError:      true,
+		},
+		{
+			description:      "rate limits with a retry-after header are retried",
+			responses:        []int{http.StatusForbidden, http.StatusCreated},
+			retryAfter:       "0",
+			expectedRequests: 2,
+		},
+		{
+			description:      "exhausted rate limits are retried after the reset",
+			responses:        []int{http.StatusForbidden, http.StatusCreated},
+			rateLimited:      true,
+			expectedRequests: 2,
+		},
+		{
+			description:      "other client errors are not retried",
+			responses:        []int{http.StatusForbidden, http.StatusCreated},
+			expectedRequests: 1,
+			expectError:      true,
+		},
+		{
+			description:      "we give up eventually",
+			readOnly:         true,
+			responses:        []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusCreated},
+			retryAfter:       "0",
+			expectedRequests: 4,
+			expectError:      true,
+		},
+		{
+			description:      "the number of retries can be configured",
+			readOnly:         true,
+			responses:        []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusCreated},
+			retryAfter:       "0",
+			maxRetries:       intPtr(1),
+			expectedRequests: 2,
+			expectError:      true,
+		},
+		{
+			description:      "retries can be disabled",
+			readOnly:         true,
+			responses:        []int{http.StatusBadGateway, http.StatusCreated},
+			retryAfter:       "0",
+			maxRetries:       intPtr(0),
+			expectedRequests: 1,
+			expectError:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					body, err := ioutil.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, `{"body":"comment"}`, string(body))
+				}
+
+				status := tc.responses[requests]
+				requests++
+				if status != http.StatusCreated && tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				if status != http.StatusCreated && tc.rateLimited {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+				}
+				w.WriteHeader(status)
+				if r.Method == http.MethodGet {
+					w.Write([]byte(`[]`))
+				} else {
+					w.Write([]byte(`{}`))
+				}
+			}))
+			defer server.Close()
+
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+				MaxRetries:  tc.maxRetries,
+			})
+			require.NoError(t, err)
+
+			if tc.readOnly {
+				_, err = client.ListModifiedFiles(1)
+			} else {
+				_, err = client.PostComment("1", "comment")
+			}
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestGithubClientTimeout(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			// Hang until the request is cancelled (which is only noticed once the body is read)
+			ioutil.ReadAll(r.Body)
+			<-r.Context().Done()
+			return
+		}
+		if r.URL.Path == "/graphql" {
+			w.Write([]byte(`{"data":{"repository":{"pullRequests":{"edges":[],"pageInfo":{"hasNextPage":false}}}}}`))
+			return
+		}
+		w.Write([]byte(`[{"filename":"README.md"}]`))
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:   "itsdalmo/test-repository",
+		AccessToken:  "oauthtoken",
+		V3Endpoint:   server.URL + "/",
+		V4Endpoint:   server.URL + "/graphql",
+		HTTPTimeout:  "100ms",
+		RetryBackoff: "10ms",
+	}
+	require.NoError(t, source.Validate())
+	client, err := resource.NewGithubClient(&source)
+	require.NoError(t, err)
+
+	start := time.Now()
+	files, err := client.ListModifiedFiles(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md"}, files)
+	assert.Equal(t, 2, requests)
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	// GraphQL queries are retried as well
+	requests = 0
+	pulls, err := client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, pulls)
+	assert.Equal(t, 2, requests)
+}
+
+func TestSourceRetryPolicyValidation(t *testing.T) {
+	tests := []struct {
+		description string
+		source      resource.Source
+		expectError string
+	}{
+		{
+			description: "invalid http_timeout",
+			source:      resource.Source{HTTPTimeout: "soon"},
+			expectError: "http_timeout must be a positive duration (e.g. 30s)",
+		},
+		{
+			description: "invalid retry_backoff",
+			source:      resource.Source{RetryBackoff: "-1s"},
+			expectError: "retry_backoff must be a positive duration (e.g. 30s)",
+		},
+		{
+			description: "negative max_retries",
+			source:      resource.Source{MaxRetries: intPtr(-1)},
+			expectError: "max_retries must not be negative",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			tc.source.Repository = "itsdalmo/test-repository"
+			tc.source.AccessToken = "oauthtoken"
+			assert.EqualError(t, tc.source.Validate(), tc.expectError)
+		})
+	}
+}
+
+func TestGithubClientWaitsForRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Second).Truncate(time.Second).Add(time.Second)
+
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.PostComment("1", "comment")
+		require.NoError(t, err)
+	}
+	require.Len(t, requests, 2)
+	assert.False(t, requests[1].Before(reset), "second request should wait for the rate limit to reset")
+}
+
+func TestGithubClientAPIUsage(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/graphql" {
+			w.Header().Set("X-RateLimit-Remaining", "4900")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.Write([]byte(`{"data":{"repository":{"pullRequests":{"edges":[],"pageInfo":{"hasNextPage":false}}}}}`))
+			return
+		}
+		// The first request fails, and is retried
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+
+	_, err = client.ListModifiedFiles(1)
+	require.NoError(t, err)
+	_, err = client.ListPullRequests([]githubv4.PullRequestState{githubv4.PullRequestStateOpen}, resource.PullRequestFields{}, time.Time{})
+	require.NoError(t, err)
+
+	usage := client.APIUsage()
+	assert.Equal(t, resource.APIUsage{RESTCalls: 2, RESTRemaining: -1, GraphQLCalls: 1, GraphQLRemaining: 4900}, usage)
+	assert.Equal(t, "github api usage: 2 REST calls (unknown remaining), 1 GraphQL calls (4900 remaining)", usage.String())
+}
@@ -0,0 +1,364 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StateConfig configures an external store for state which is persisted between runs of the resource
+// (e.g. the check cursor), since the containers running the resource are short-lived.
+type StateConfig struct {
+	URL             string `json:"url"`
+	Region          string `json:"region"`
+	Endpoint        string `json:"endpoint"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	SessionToken    string `json:"session_token"`
+}
+
+// Validate the state configuration.
+func (c *StateConfig) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse state url: %s", err)
+	}
+	switch u.Scheme {
+	case "s3":
+		if u.Host == "" {
+			return errors.New("state url must specify a bucket (e.g. s3://bucket/prefix)")
+		}
+		if c.Region == "" && c.Endpoint == "" {
+			return errors.New("state region or endpoint must be set for s3")
+		}
+	case "redis", "rediss":
+		if u.Host == "" {
+			return errors.New("state url must specify a host (e.g. redis://host:6379/0)")
+		}
+	default:
+		return fmt.Errorf("unknown state url scheme: %s (must be s3, redis or rediss)", u.Scheme)
+	}
+	return nil
+}
+
+// StateStore persists state between runs of the resource.
+type StateStore interface {
+	// Get returns the value of a key, or nil if it does not exist.
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+}
+
+// NewStateStore returns the state store configured in the source, or nil if there is none.
+// Keys are scoped to the repository.
+func NewStateStore(s *Source) (StateStore, error) {
+	if s.State == nil {
+		return nil, nil
+	}
+	if err := s.State.Validate(); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(s.State.URL)
+
+	// The store is reached through the same proxies (and trusts the same CAs) as the Github API
+	config, err := tlsConfig(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "s3":
+		accessKeyID, secretAccessKey, sessionToken := s.State.AccessKeyID, s.State.SecretAccessKey, s.State.SessionToken
+		if accessKeyID == "" {
+			accessKeyID, secretAccessKey, sessionToken = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+		}
+		endpoint := s.State.Endpoint
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.State.Region)
+		}
+		region := s.State.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = config
+		t.Proxy = proxyFunc(s)
+		return &s3Store{
+			endpoint:        strings.TrimSuffix(endpoint, "/"),
+			bucket:          u.Host,
+			prefix:          path.Join(strings.TrimPrefix(u.Path, "/"), s.Repository),
+			region:          region,
+			accessKeyID:     accessKeyID,
+			secretAccessKey: secretAccessKey,
+			sessionToken:    sessionToken,
+			client:          &http.Client{Transport: t, Timeout: 30 * time.Second},
+		}, nil
+	default:
+		return &redisStore{url: u, prefix: "github-pr-resource:" + s.Repository + ":", tls: config}, nil
+	}
+}
+
+// checkCursorKey returns the key of the check cursor in the state store, which includes a hash of the
+// options that select (and date) the versions, so that a cursor is not shared by resources which filter
+// the pull requests of a repository differently.
+func checkCursorKey(s Source) string {
+	b, _ := json.Marshal([]interface{}{
+		s.BaseBranch, s.Labels, s.States, s.Paths, s.IgnorePaths, s.DisableCISkip, s.DisableForks, s.IgnoreDrafts,
+		s.RequiredReviewApprovals, s.MaxBehindBy, s.RequireSignedCommits, s.RequireLinearHistory, s.CheckFetch,
+		s.WhenReadyToMerge, s.ReadyContexts, s.OrderBy, s.IncludeUpdatedAt,
+	})
+	sum := sha256.Sum256(b)
+	return "check/cursor-" + hex.EncodeToString(sum[:8])
+}
+
+// LoadCheckCursor loads the last version returned by check as the cursor of the request, if the request
+// has no version (e.g. because the resource configuration changed).
+func LoadCheckCursor(store StateStore, request *CheckRequest) error {
+	if store == nil || request.Version.PR != "" {
+		return nil
+	}
+	b, err := store.Get(checkCursorKey(request.Source))
+	if err != nil || b == nil {
+		return err
+	}
+	var cursor Version
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return err
+	}
+	request.Cursor = &cursor
+	return nil
+}
+
+// SaveCheckCursor persists the last version returned by check.
+func SaveCheckCursor(store StateStore, source Source, response CheckResponse) error {
+	if store == nil || len(response) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(response[len(response)-1])
+	if err != nil {
+		return err
+	}
+	return store.Put(checkCursorKey(source), b)
+}
+
+// s3Store stores state as objects in an S3 (compatible) bucket.
+type s3Store struct {
+	endpoint        string
+	bucket          string
+	prefix          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	client          *http.Client
+}
+
+func (s *s3Store) Get(key string) ([]byte, error) {
+	resp, err := s.do("GET", key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get state from s3: %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (s *s3Store) Put(key string, value []byte) error {
+	resp, err := s.do("PUT", key, value)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to put state to s3: %s", resp.Status)
+	}
+	return nil
+}
+
+// do sends a (path-style) request for an object, signed with AWS Signature Version 4.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (s *s3Store) do(method, key string, body []byte) (*http.Response, error) {
+	u, err := url.Parse(s.endpoint + "/" + path.Join(s.bucket, s.prefix, key))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	if s.accessKeyID != "" {
+		req.Header.Set("Authorization", s.authorization(req, now))
+	}
+	return s.client.Do(req)
+}
+
+func (s *s3Store) authorization(req *http.Request, now time.Time) string {
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		if strings.HasPrefix(name, "X-Amz-") {
+			headers[strings.ToLower(name)] = req.Header.Get(name)
+		}
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	date := now.Format("20060102")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + s.secretAccessKey)
+	for _, part := range []string{date, s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKeyID, scope, signedHeaders, signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// redisStore stores state as keys in Redis, using a connection per operation (since the resource is short-lived).
+type redisStore struct {
+	url    *url.URL
+	prefix string
+	tls    *tls.Config
+}
+
+func (r *redisStore) Get(key string) ([]byte, error) {
+	return r.command("GET", r.prefix+key)
+}
+
+func (r *redisStore) Put(key string, value []byte) error {
+	_, err := r.command("SET", r.prefix+key, string(value))
+	return err
+}
+
+// command connects to redis, authenticates and selects the database (if specified in the url), and runs the command.
+func (r *redisStore) command(args ...string) ([]byte, error) {
+	var (
+		conn net.Conn
+		err  error
+	)
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if r.url.Scheme == "rediss" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", r.url.Host, r.tls)
+	} else {
+		conn, err = dialer.Dial("tcp", r.url.Host)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %s", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	if password, ok := r.url.User.Password(); ok {
+		// The username is only supported by Redis 6 (ACL), and is omitted for the default user
+		auth := []string{"AUTH", password}
+		if username := r.url.User.Username(); username != "" {
+			auth = []string{"AUTH", username, password}
+		}
+		if _, err := redisCommand(rw, auth...); err != nil {
+			return nil, err
+		}
+	}
+	if db := strings.TrimPrefix(r.url.Path, "/"); db != "" {
+		if _, err := redisCommand(rw, "SELECT", db); err != nil {
+			return nil, err
+		}
+	}
+	return redisCommand(rw, args...)
+}
+
+// redisCommand sends a command using the Redis serialization protocol and reads the (simple or bulk string) reply.
+// https://redis.io/docs/reference/protocol-spec/
+func redisCommand(rw *bufio.ReadWriter, args ...string) ([]byte, error) {
+	fmt.Fprintf(rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := rw.Flush(); err != nil {
+		return nil, err
+	}
+
+	line, err := rw.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply from redis")
+	}
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, fmt.Errorf("redis %s failed: %s", args[0], line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed reply from redis: %s", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(rw, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+	return nil, fmt.Errorf("unexpected reply from redis: %s", line)
+}
@@ -0,0 +1,209 @@
+package resource_test
+
+import (
+	"bufio"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+// fakeS3 is an in-memory S3 bucket (served over TLS with a self-signed certificate).
+func fakeS3(t *testing.T) *httptest.Server {
+	var (
+		mu      sync.Mutex
+		objects = make(map[string][]byte)
+	)
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access-key/"))
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Content-Sha256"))
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			b, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			objects[r.URL.Path] = b
+		case http.MethodGet:
+			b, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(b)
+		}
+	}))
+}
+
+// fakeRedis is an in-memory Redis server which supports AUTH, SELECT, GET and SET. The username
+// is only expected in AUTH if it is set (as for Redis 6 ACL users).
+func fakeRedis(t *testing.T, username, password string) net.Listener {
+	auth := []string{"AUTH", password}
+	if username != "" {
+		auth = []string{"AUTH", username, password}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var (
+		mu   sync.Mutex
+		keys = make(map[string]string)
+	)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				authenticated := password == ""
+				for {
+					args, err := readRedisCommand(r)
+					if err != nil {
+						return
+					}
+					mu.Lock()
+					switch {
+					case args[0] == "AUTH" && reflect.DeepEqual(args, auth):
+						authenticated = true
+						conn.Write([]byte("+OK\r\n"))
+					case args[0] == "AUTH":
+						conn.Write([]byte("-WRONGPASS invalid username-password pair or user is disabled.\r\n"))
+					case !authenticated:
+						conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+					case args[0] == "SELECT":
+						conn.Write([]byte("+OK\r\n"))
+					case args[0] == "SET":
+						keys[args[1]] = args[2]
+						conn.Write([]byte("+OK\r\n"))
+					case args[0] == "GET":
+						if v, ok := keys[args[1]]; ok {
+							conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+						} else {
+							conn.Write([]byte("$-1\r\n"))
+						}
+					default:
+						conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return l
+}
+
+func readRedisCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestStateStore(t *testing.T) {
+	s3 := fakeS3(t)
+	defer s3.Close()
+	redis := fakeRedis(t, "", "secret")
+	defer redis.Close()
+	redisACL := fakeRedis(t, "concourse", "secret")
+	defer redisACL.Close()
+
+	tests := []struct {
+		description string
+		state       *resource.StateConfig
+		caCerts     string
+	}{
+		{
+			description: "s3",
+			state: &resource.StateConfig{
+				URL:             "s3://bucket/prefix",
+				Endpoint:        s3.URL,
+				AccessKeyID:     "access-key",
+				SecretAccessKey: "secret-key",
+			},
+			caCerts: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s3.Certificate().Raw})),
+		},
+		{
+			description: "redis",
+			state: &resource.StateConfig{
+				URL: "redis://:secret@" + redis.Addr().String() + "/1",
+			},
+		},
+		{
+			description: "redis with a username",
+			state: &resource.StateConfig{
+				URL: "redis://concourse:secret@" + redisACL.Addr().String(),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			source := resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				State:       tc.state,
+				CACerts:     tc.caCerts,
+			}
+			require.NoError(t, source.Validate())
+
+			store, err := resource.NewStateStore(&source)
+			require.NoError(t, err)
+
+			// Nothing is loaded before a cursor has been saved
+			request := resource.CheckRequest{Source: source}
+			require.NoError(t, resource.LoadCheckCursor(store, &request))
+			assert.Nil(t, request.Cursor)
+
+			versions := resource.CheckResponse{
+				{PR: "1", Commit: "commit1", CommittedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{PR: "2", Commit: "commit2", CommittedDate: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+			}
+			require.NoError(t, resource.SaveCheckCursor(store, source, versions))
+
+			// The cursor is loaded separately from the version (and re-checked by check)
+			require.NoError(t, resource.LoadCheckCursor(store, &request))
+			assert.Equal(t, &versions[1], request.Cursor)
+			assert.Equal(t, resource.Version{}, request.Version)
+
+			// The cursor is not shared with resources which filter the pull requests differently
+			filtered := resource.CheckRequest{Source: source}
+			filtered.Source.BaseBranch = "develop"
+			require.NoError(t, resource.LoadCheckCursor(store, &filtered))
+			assert.Nil(t, filtered.Cursor)
+
+			// The version from Concourse takes precedence
+			request = resource.CheckRequest{Source: source, Version: versions[0]}
+			require.NoError(t, resource.LoadCheckCursor(store, &request))
+			assert.Nil(t, request.Cursor)
+		})
+	}
+}
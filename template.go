@@ -0,0 +1,60 @@
+package resource
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// TemplateData is available when rendering templates (e.g. comment_template).
+type TemplateData struct {
+	Version  Version
+	Metadata map[string]string
+	Env      map[string]string
+	Vars     map[string]string
+}
+
+// newTemplateData for a put request.
+func newTemplateData(request PutRequest, version Version, metadata Metadata) TemplateData {
+	data := TemplateData{
+		Version:  version,
+		Metadata: make(map[string]string),
+		Env:      make(map[string]string),
+		Vars:     request.Params.Vars,
+	}
+	for _, f := range metadata {
+		data.Metadata[f.Name] = f.Value
+	}
+	for _, name := range append(append([]string{}, buildEnv...), request.Source.ExpandEnv...) {
+		data.Env[name] = os.Getenv(name)
+	}
+	return data
+}
+
+// renderTemplate renders a text/template. The "file" function can be used to
+// inline the content of a file (relative to the input directory).
+func renderTemplate(text string, data TemplateData, inputDir string) (string, error) {
+	funcs := template.FuncMap{
+		"file": func(path string) (string, error) {
+			content, err := ioutil.ReadFile(filepath.Join(inputDir, path))
+			if err != nil {
+				return "", fmt.Errorf("failed to read file: %s", err)
+			}
+			return string(content), nil
+		},
+	}
+
+	tmpl, err := template.New("template").Funcs(funcs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %s", err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %s", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,58 @@
+package resource
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+)
+
+// systemCABundles are the usual locations of the system CA bundle, which git needs in addition to ca_certs.
+var systemCABundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt", // Debian/Ubuntu/Alpine
+	"/etc/pki/tls/certs/ca-bundle.crt",   // Fedora/RHEL
+	"/etc/ssl/ca-bundle.pem",             // OpenSUSE
+	"/etc/ssl/cert.pem",                  // Alpine (LibreSSL)
+}
+
+// tlsConfig returns the TLS configuration for the Github API clients, which trusts ca_certs
+// in addition to the system CAs.
+func tlsConfig(s *Source) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: s.SkipSSLVerification}
+	if s.CACerts == "" {
+		return config, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(s.CACerts)) {
+		return nil, errors.New("ca_certs does not contain any PEM encoded certificates")
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// writeCABundle writes ca_certs together with the system CA bundle to a temporary file, for use by git
+// (which only supports a single CA bundle).
+func writeCABundle(caCerts string) (string, error) {
+	var bundle []byte
+	for _, path := range systemCABundles {
+		if b, err := ioutil.ReadFile(path); err == nil {
+			bundle = append(b, '\n')
+			break
+		}
+	}
+	bundle = append(bundle, caCerts...)
+
+	f, err := ioutil.TempFile("", "ca-certs-*.pem")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(bundle); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,74 @@
+package resource_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientCACerts(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	caCerts := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		description         string
+		caCerts             string
+		skipSSLVerification bool
+		expectError         bool
+	}{
+		{
+			description: "unknown certificate authorities are rejected",
+			expectError: true,
+		},
+		{
+			description: "ca_certs are trusted",
+			caCerts:     caCerts,
+		},
+		{
+			description:         "ssl verification can be skipped",
+			skipSSLVerification: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			source := resource.Source{
+				Repository:          "itsdalmo/test-repository",
+				AccessToken:         "oauthtoken",
+				V3Endpoint:          server.URL + "/",
+				V4Endpoint:          server.URL + "/graphql",
+				CACerts:             tc.caCerts,
+				SkipSSLVerification: tc.skipSSLVerification,
+			}
+			require.NoError(t, source.Validate())
+
+			client, err := resource.NewGithubClient(&source)
+			require.NoError(t, err)
+
+			_, err = client.PostComment("1", "comment")
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSourceValidateCACerts(t *testing.T) {
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		CACerts:     "not a certificate",
+	}
+	assert.EqualError(t, source.Validate(), "ca_certs does not contain any PEM encoded certificates")
+}
@@ -0,0 +1,55 @@
+package resource
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// fileTokenSource reads the access token from a file every time it is used, so that tokens
+// which are rotated on disk (e.g. by a Vault agent) are picked up.
+type fileTokenSource struct {
+	path string
+}
+
+// Token implements oauth2.TokenSource.
+func (f *fileTokenSource) Token() (*oauth2.Token, error) {
+	b, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access token file: %s", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return nil, fmt.Errorf("access token file is empty: %s", f.path)
+	}
+	addSecret(token)
+	return &oauth2.Token{AccessToken: token}, nil
+}
+
+// unauthorizedRetryTransport retries a request once if it was rejected with 401 Unauthorized,
+// since the token might have been rotated while the request was in flight.
+type unauthorizedRetryTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *unauthorizedRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	attempt := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		attempt.Body = body
+	}
+	resp.Body.Close()
+	return t.base.RoundTrip(attempt)
+}
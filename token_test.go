@@ -0,0 +1,152 @@
+package resource_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientAccessTokenFile(t *testing.T) {
+	tokenFile := filepath.Join(createTestDirectory(t), "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token1\n"), 0600))
+
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+
+		// Rotate the token while the first request is in flight
+		if len(tokens) == 1 {
+			require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token2\n"), 0600))
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:      "itsdalmo/test-repository",
+		AccessTokenFile: tokenFile,
+		V3Endpoint:      server.URL + "/",
+		V4Endpoint:      server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+
+	_, err = client.PostComment("1", "comment")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, tokens)
+
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token3"), 0600))
+	token, err := client.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token3", token)
+}
+
+func TestGithubClientGetTokenInfo(t *testing.T) {
+	tests := []struct {
+		description string
+		token       string
+		status      int
+		scopes      []string
+		expected    *resource.TokenInfo
+		expectError string
+	}{
+		{
+			description: "scopes are parsed from the response",
+			status:      http.StatusOK,
+			scopes:      []string{"repo, read:org"},
+			expected:    &resource.TokenInfo{Private: true, Scopes: []string{"repo", "read:org"}},
+		},
+		{
+			description: "tokens without scopes are reported as such",
+			status:      http.StatusOK,
+			scopes:      []string{""},
+			expected:    &resource.TokenInfo{Private: true, Scopes: []string{}},
+		},
+		{
+			description: "scopes are unknown for other tokens",
+			status:      http.StatusOK,
+			expected:    &resource.TokenInfo{Private: true},
+		},
+		{
+			description: "fine-grained tokens are detected",
+			token:       "github_pat_abc",
+			status:      http.StatusOK,
+			expected:    &resource.TokenInfo{Private: true, FineGrained: true},
+		},
+		{
+			description: "repositories which are not selected for fine-grained tokens are reported",
+			token:       "github_pat_abc",
+			status:      http.StatusNotFound,
+			expectError: "repository itsdalmo/test-repository does not exist or has not been selected for the fine-grained token",
+		},
+		{
+			description: "inaccessible repositories are reported",
+			status:      http.StatusNotFound,
+			expectError: "repository itsdalmo/test-repository does not exist or the token does not have access to it",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/itsdalmo/test-repository", r.URL.Path)
+				for _, s := range tc.scopes {
+					w.Header().Set("X-OAuth-Scopes", s)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"private":true}`))
+			}))
+			defer server.Close()
+
+			token := "oauthtoken"
+			if tc.token != "" {
+				token = tc.token
+			}
+			client, err := resource.NewGithubClient(&resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: token,
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+			})
+			require.NoError(t, err)
+
+			info, err := client.GetTokenInfo()
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, info)
+			}
+		})
+	}
+}
+
+func TestGithubClientMissingPermissions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Accepted-GitHub-Permissions", "issues=write; pull_requests=write")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Resource not accessible by personal access token"}`))
+	}))
+	defer server.Close()
+
+	client, err := resource.NewGithubClient(&resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "github_pat_abc",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	})
+	require.NoError(t, err)
+
+	_, err = client.PostComment("1", "comment")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Resource not accessible by personal access token (the token requires the following permissions: issues=write, pull_requests=write)")
+}
@@ -0,0 +1,307 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TracingConfig configures the export of traces (spans for the step, requests to the Github API and git
+// operations) to an OpenTelemetry collector, using OTLP over HTTP with JSON encoding.
+type TracingConfig struct {
+	Endpoint string            `json:"endpoint"`
+	Headers  map[string]string `json:"headers"`
+}
+
+// Validate the tracing configuration.
+func (c *TracingConfig) Validate() error {
+	if !isAbsoluteURL(c.Endpoint) {
+		return errors.New("tracing endpoint must be an absolute URL (e.g. http://otel-collector:4318)")
+	}
+	return nil
+}
+
+// Span kinds and status codes in OTLP.
+const (
+	spanKindInternal = 1
+	spanKindClient   = 3
+
+	spanStatusError = 2
+)
+
+// Tracer records the spans of a step, which are exported when it is finished. A nil *Tracer
+// (tracing is not configured) discards everything.
+type Tracer struct {
+	config  *TracingConfig
+	traceID string
+	root    *span
+	client  *http.Client
+
+	mu    sync.Mutex
+	spans []*span
+}
+
+// span is an operation in a trace.
+type span struct {
+	tracer   *Tracer
+	id       string
+	parentID string
+	name     string
+	kind     int
+	start    time.Time
+	end      time.Time
+	attrs    map[string]interface{}
+	err      error
+}
+
+// tracer is used by the resource, and is set by the commands.
+var tracer *Tracer
+
+// SetTracer sets the tracer used by the resource.
+func SetTracer(t *Tracer) {
+	tracer = t
+}
+
+// NewTracer returns a tracer for a step (check, get or put) and starts its root span, or returns
+// nil if tracing is not configured.
+func NewTracer(s *Source, step string) *Tracer {
+	if s.Tracing == nil {
+		return nil
+	}
+	t := &Tracer{
+		config:  s.Tracing,
+		traceID: randomID(16),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	t.root = t.start(step, spanKindInternal, "", "repository", s.Repository)
+	for _, env := range []string{"BUILD_PIPELINE_NAME", "BUILD_JOB_NAME", "BUILD_NAME", "BUILD_ID"} {
+		if v := os.Getenv(env); v != "" {
+			t.root.attrs["concourse."+strings.ToLower(strings.TrimPrefix(env, "BUILD_"))] = v
+		}
+	}
+	return t
+}
+
+// Finish ends the root span of the step and exports all spans.
+func (t *Tracer) Finish(err error) error {
+	if t == nil {
+		return nil
+	}
+	t.root.End(err)
+
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+	return t.export(spans)
+}
+
+// startSpan starts a span (which is a child of the root span) on the tracer used by the resource.
+// Attributes are given as key-value pairs.
+func startSpan(name string, kind int, keyvals ...interface{}) *span {
+	if tracer == nil {
+		return nil
+	}
+	return tracer.start(name, kind, tracer.root.id, keyvals...)
+}
+
+func (t *Tracer) start(name string, kind int, parentID string, keyvals ...interface{}) *span {
+	s := &span{
+		tracer:   t,
+		id:       randomID(8),
+		parentID: parentID,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+		attrs:    make(map[string]interface{}),
+	}
+	s.SetAttributes(keyvals...)
+	return s
+}
+
+// SetAttributes sets attributes of the span, given as key-value pairs.
+func (s *span) SetAttributes(keyvals ...interface{}) {
+	if s == nil {
+		return
+	}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		s.attrs[fmt.Sprint(keyvals[i])] = keyvals[i+1]
+	}
+}
+
+// End ends the span, which failed if err is not nil.
+func (s *span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+	s.err = err
+	s.tracer.mu.Lock()
+	s.tracer.spans = append(s.tracer.spans, s)
+	s.tracer.mu.Unlock()
+}
+
+// export sends the spans to the collector.
+func (t *Tracer) export(spans []*span) error {
+	type keyValue struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	}
+	attributes := func(attrs map[string]interface{}) []keyValue {
+		kvs := make([]keyValue, 0, len(attrs))
+		for k, v := range attrs {
+			var value map[string]interface{}
+			switch v := v.(type) {
+			case int:
+				value = map[string]interface{}{"intValue": strconv.Itoa(v)}
+			case bool:
+				value = map[string]interface{}{"boolValue": v}
+			default:
+				value = map[string]interface{}{"stringValue": Redact(fmt.Sprint(v))}
+			}
+			kvs = append(kvs, keyValue{Key: k, Value: value})
+		}
+		return kvs
+	}
+
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, s := range spans {
+		otlp := map[string]interface{}{
+			"traceId":           t.traceID,
+			"spanId":            s.id,
+			"name":              s.name,
+			"kind":              s.kind,
+			"startTimeUnixNano": strconv.FormatInt(s.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.end.UnixNano(), 10),
+			"attributes":        attributes(s.attrs),
+		}
+		if s.parentID != "" {
+			otlp["parentSpanId"] = s.parentID
+		}
+		if s.err != nil {
+			otlp["status"] = map[string]interface{}{"code": spanStatusError, "message": Redact(s.err.Error())}
+		}
+		otlpSpans = append(otlpSpans, otlp)
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": attributes(map[string]interface{}{
+					"service.name":    "github-pr-resource",
+					"service.version": BuildVersion,
+				}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": "github-pr-resource"},
+				"spans": otlpSpans,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimSuffix(t.config.Endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.config.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export traces: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to export traces: %s", resp.Status)
+	}
+	return nil
+}
+
+// tracingTransport records a span for each request to the Github API.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if tracer == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	name := req.Method + " " + req.URL.Path
+	if rateLimitResource(req) == "graphql" {
+		name = "graphql " + graphqlOperation(req)
+	}
+	s := startSpan(name, spanKindClient, "http.method", req.Method, "http.url", sanitizeURL(req.URL))
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		s.End(err)
+		return nil, err
+	}
+	s.SetAttributes("http.status_code", resp.StatusCode)
+	if id := resp.Header.Get("X-GitHub-Request-Id"); id != "" {
+		s.SetAttributes("github.request_id", id)
+	}
+	if resp.StatusCode >= 400 {
+		err = errors.New(resp.Status)
+	}
+	s.End(err)
+	return resp, nil
+}
+
+// graphqlOperation returns the type and the first field of a GraphQL request (e.g. "mutation addComment"
+// or "query repository").
+func graphqlOperation(req *http.Request) string {
+	if req.GetBody == nil {
+		return "request"
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "request"
+	}
+	defer body.Close()
+	var graphql struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&graphql); err != nil {
+		return "request"
+	}
+
+	query := strings.TrimSpace(graphql.Query)
+	operation := "query"
+	if strings.HasPrefix(query, "mutation") {
+		operation = "mutation"
+	}
+	// The field follows the first brace, e.g. "mutation($input:AddCommentInput!){addComment(input:$input){...}}"
+	if i := strings.Index(query, "{"); i >= 0 {
+		field := strings.TrimSpace(query[i+1:])
+		if j := strings.IndexAny(field, "({ "); j > 0 {
+			return operation + " " + field[:j]
+		}
+	}
+	return operation
+}
+
+// randomID returns a random hex encoded ID of n bytes.
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
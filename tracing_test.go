@@ -0,0 +1,113 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestTracing(t *testing.T) {
+	var export struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Attributes   []struct {
+						Key   string                 `json:"key"`
+						Value map[string]interface{} `json:"value"`
+					} `json:"attributes"`
+					Status *struct {
+						Code    int    `json:"code"`
+						Message string `json:"message"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "Bearer collector-token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&export))
+	}))
+	defer collector.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-GitHub-Request-Id", "ABCD:1234")
+		switch {
+		case strings.Contains(string(b), "mutation"):
+			w.Write([]byte(`{"data":{"markPullRequestReadyForReview":{"clientMutationId":""}}}`))
+		case r.URL.Path == "/graphql":
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR_1"}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+		Tracing:     &resource.TracingConfig{Endpoint: collector.URL, Headers: map[string]string{"Authorization": "Bearer collector-token"}},
+	}
+	require.NoError(t, source.Validate())
+
+	tracer := resource.NewTracer(&source, "put")
+	resource.SetTracer(tracer)
+	defer resource.SetTracer(nil)
+
+	client, err := resource.NewGithubClient(&source)
+	require.NoError(t, err)
+	require.NoError(t, client.MarkReadyForReview("1"))
+	_, err = client.ListModifiedFiles(1)
+	require.Error(t, err)
+	require.NoError(t, tracer.Finish(nil))
+
+	require.Len(t, export.ResourceSpans, 1)
+	require.Len(t, export.ResourceSpans[0].ScopeSpans, 1)
+	spans := export.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 4)
+
+	root := spans[3]
+	assert.Equal(t, "put", root.Name)
+	assert.Empty(t, root.ParentSpanID)
+	assert.Nil(t, root.Status)
+
+	var names []string
+	for _, s := range spans[:3] {
+		names = append(names, s.Name)
+		assert.Equal(t, root.TraceID, s.TraceID)
+		assert.Equal(t, root.SpanID, s.ParentSpanID)
+		assert.Len(t, s.TraceID, 32)
+		assert.Len(t, s.SpanID, 16)
+	}
+	assert.Equal(t, []string{"graphql query repository", "graphql mutation markPullRequestReadyForReview", "GET /repos/itsdalmo/test-repository/pulls/1/files"}, names)
+
+	attributes := make(map[string]interface{})
+	for _, a := range spans[2].Attributes {
+		attributes[a.Key] = a.Value
+	}
+	assert.Equal(t, map[string]interface{}{"intValue": "404"}, attributes["http.status_code"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "ABCD:1234"}, attributes["github.request_id"])
+	require.NotNil(t, spans[2].Status)
+	assert.Equal(t, 2, spans[2].Status.Code)
+	assert.Equal(t, "404 Not Found", spans[2].Status.Message)
+}
+
+func TestTracingDisabled(t *testing.T) {
+	tracer := resource.NewTracer(&resource.Source{Repository: "itsdalmo/test-repository"}, "check")
+	assert.Nil(t, tracer)
+	assert.NoError(t, tracer.Finish(nil))
+}
@@ -0,0 +1,30 @@
+package resource
+
+import (
+	"regexp"
+	"strings"
+)
+
+var trailerRegexp = regexp.MustCompile(`^([A-Za-z0-9-]+)\s*:\s*(.*)$`)
+
+// ParseTrailers returns the unique values of the trailer with the given key
+// (case insensitive) found in the trailer blocks of the commit messages.
+func ParseTrailers(messages []string, key string) []string {
+	seen := make(map[string]bool)
+	values := []string{}
+	for _, message := range messages {
+		// Trailers are found in the last paragraph of the commit message.
+		paragraphs := strings.Split(strings.TrimSpace(message), "\n\n")
+		for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+			m := trailerRegexp.FindStringSubmatch(strings.TrimSpace(line))
+			if m == nil || !strings.EqualFold(m[1], key) {
+				continue
+			}
+			if !seen[m[2]] {
+				seen[m[2]] = true
+				values = append(values, m[2])
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// APIUsage describes the requests made to the Github API (including retries), and the remaining
+// rate limits as of the last response. Remaining is -1 if it is not known.
+type APIUsage struct {
+	RESTCalls        int
+	RESTRemaining    int
+	GraphQLCalls     int
+	GraphQLRemaining int
+}
+
+// String returns a summary of the usage for logging.
+func (u APIUsage) String() string {
+	return fmt.Sprintf("github api usage: %d REST calls (%s remaining), %d GraphQL calls (%s remaining)",
+		u.RESTCalls, remainingString(u.RESTRemaining), u.GraphQLCalls, remainingString(u.GraphQLRemaining))
+}
+
+// Log writes the usage to the log (at info level).
+func (u APIUsage) Log() {
+	logger.Info("github api usage",
+		"rest_calls", u.RESTCalls, "rest_remaining", remainingString(u.RESTRemaining),
+		"graphql_calls", u.GraphQLCalls, "graphql_remaining", remainingString(u.GraphQLRemaining))
+}
+
+// AddTo adds the usage to the metadata.
+func (u APIUsage) AddTo(metadata *Metadata) {
+	metadata.Add("api_rest_calls", strconv.Itoa(u.RESTCalls))
+	metadata.Add("api_rest_remaining", remainingString(u.RESTRemaining))
+	metadata.Add("api_graphql_calls", strconv.Itoa(u.GraphQLCalls))
+	metadata.Add("api_graphql_remaining", remainingString(u.GraphQLRemaining))
+}
+
+func remainingString(remaining int) string {
+	if remaining < 0 {
+		return "unknown"
+	}
+	return strconv.Itoa(remaining)
+}
+
+// usage returns the API usage of the requests sent through the transport.
+func (t *retryTransport) usage() APIUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := APIUsage{
+		RESTCalls:        t.calls["core"],
+		RESTRemaining:    -1,
+		GraphQLCalls:     t.calls["graphql"],
+		GraphQLRemaining: -1,
+	}
+	if limit, ok := t.rateLimits["core"]; ok {
+		u.RESTRemaining = limit.remaining
+	}
+	if limit, ok := t.rateLimits["graphql"]; ok {
+		u.GraphQLRemaining = limit.remaining
+	}
+	return u
+}